@@ -0,0 +1,151 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+type (
+	// actionInputs holds the workflow inputs, read from INPUT_* variables.
+	actionInputs struct {
+		username    string
+		token       string
+		readmePath  string
+		maxLines    int
+		lang        string
+		commit      bool
+		commitMsg   string
+		commitName  string
+		commitEmail string
+	}
+	// gitRunner enables testable git invocations.
+	gitRunner interface {
+		run(args ...string) error
+	}
+	// execGit implements gitRunner with the git binary of the runner.
+	execGit struct{}
+)
+
+func (g *execGit) run(args ...string) error {
+	cmd := exec.Command("git", args...)
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return nil
+}
+
+// readActionInputs maps INPUT_* variables to inputs with action defaults;
+// the username defaults to the owner of the repository running the workflow.
+func readActionInputs(getenv func(string) string) (actionInputs, error) {
+	input := func(name, fallback string) string {
+		if v := strings.TrimSpace(getenv("INPUT_" + name)); v != "" {
+			return v
+		}
+		return fallback
+	}
+	in := actionInputs{
+		username:    input("USERNAME", getenv("GITHUB_REPOSITORY_OWNER")),
+		token:       input("GH_TOKEN", getenv("GITHUB_TOKEN")),
+		readmePath:  input("README_PATH", "README.md"),
+		lang:        input("LANG", defaultLang),
+		commit:      input("COMMIT", "true") != "false",
+		commitMsg:   input("COMMIT_MSG", "Update README with the recent activity"),
+		commitName:  input("COMMIT_NAME", "github-actions[bot]"),
+		commitEmail: input("COMMIT_EMAIL", "41898282+github-actions[bot]@users.noreply.github.com"),
+	}
+	if in.username == "" {
+		return actionInputs{}, errors.New("missing input: username")
+	}
+	n, err := strconv.Atoi(input("MAX_LINES", "5"))
+	if err != nil || n <= 0 {
+		return actionInputs{}, fmt.Errorf("invalid input max_lines: %q", getenv("INPUT_MAX_LINES"))
+	}
+	in.maxLines = n
+	return in, nil
+}
+
+// runAction is the entrypoint used by the GitHub Action: it refreshes the
+// README activity section, writes step outputs and commits the change.
+func runAction(_ []string, stdout io.Writer) error {
+	in, err := readActionInputs(os.Getenv)
+	if err != nil {
+		return err
+	}
+	if err := loadConfig(); err != nil {
+		return err
+	}
+	if in.token != "" {
+		viper.Set("github_token", in.token)
+	}
+	events, err := fetchUserEvents(in.username)
+	if err != nil {
+		return err
+	}
+	icons := loadIcons(true, viper.GetStringMapString("icons"))
+	section := markdownSection(lookupCatalog(resolveLang(in.lang, "")), icons, events, in.maxLines)
+	changed, err := updateReadme(in.readmePath, section)
+	if err != nil {
+		return err
+	}
+	outputs := map[string]string{
+		"updated": strconv.FormatBool(changed),
+		"events":  strconv.Itoa(min(len(events), in.maxLines)),
+	}
+	if err := writeActionOutputs(os.Getenv("GITHUB_OUTPUT"), outputs); err != nil {
+		return err
+	}
+	if !changed {
+		return infof(stdout, "README is already up to date\n")
+	}
+	if in.commit {
+		if err := commitFiles(&execGit{}, in, in.readmePath); err != nil {
+			return err
+		}
+	}
+	return infof(stdout, "updated %s\n", in.readmePath)
+}
+
+// writeActionOutputs appends key=value step outputs to the GITHUB_OUTPUT
+// file; outside of a workflow (empty path) it does nothing.
+func writeActionOutputs(path string, outputs map[string]string) error {
+	if path == "" {
+		return nil
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("open GITHUB_OUTPUT: %w", err)
+	}
+	defer f.Close()
+	for _, key := range sortedKeys(outputs) {
+		if _, err := fmt.Fprintf(f, "%s=%s\n", key, outputs[key]); err != nil {
+			return fmt.Errorf("write GITHUB_OUTPUT: %w", err)
+		}
+	}
+	return nil
+}
+
+// commitFiles commits and pushes the generated files as the configured author.
+func commitFiles(git gitRunner, in actionInputs, paths ...string) error {
+	steps := [][]string{
+		{"config", "user.name", in.commitName},
+		{"config", "user.email", in.commitEmail},
+		append([]string{"add", "--"}, paths...),
+		{"commit", "-m", in.commitMsg},
+		{"push"},
+	}
+	for _, args := range steps {
+		if err := git.run(args...); err != nil {
+			return fmt.Errorf("commit generated files: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type mockGit struct {
+	calls  []string
+	failOn string
+}
+
+func (m *mockGit) run(args ...string) error {
+	m.calls = append(m.calls, strings.Join(args, " "))
+	if args[0] == m.failOn {
+		return fmt.Errorf("%s failed", args[0])
+	}
+	return nil
+}
+
+func TestUnitReadActionInputs(t *testing.T) {
+	testCases := []struct {
+		name    string
+		env     map[string]string
+		want    actionInputs
+		wantErr bool
+	}{
+		{
+			name: "defaults from workflow context",
+			env:  map[string]string{"GITHUB_REPOSITORY_OWNER": "octo", "GITHUB_TOKEN": "tok"},
+			want: actionInputs{
+				username: "octo", token: "tok", readmePath: "README.md", maxLines: 5, lang: "en",
+				commit: true, commitMsg: "Update README with the recent activity",
+				commitName: "github-actions[bot]", commitEmail: "41898282+github-actions[bot]@users.noreply.github.com",
+			},
+		},
+		{
+			name: "explicit inputs",
+			env: map[string]string{
+				"INPUT_USERNAME": "cat", "INPUT_MAX_LINES": "10", "INPUT_COMMIT": "false",
+				"INPUT_README_PATH": "docs/README.md", "INPUT_LANG": "fr",
+			},
+			want: actionInputs{
+				username: "cat", readmePath: "docs/README.md", maxLines: 10, lang: "fr",
+				commit: false, commitMsg: "Update README with the recent activity",
+				commitName: "github-actions[bot]", commitEmail: "41898282+github-actions[bot]@users.noreply.github.com",
+			},
+		},
+		{
+			name:    "missing username",
+			env:     map[string]string{},
+			wantErr: true,
+		},
+		{
+			name:    "invalid max lines",
+			env:     map[string]string{"INPUT_USERNAME": "cat", "INPUT_MAX_LINES": "many"},
+			wantErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			got, err := readActionInputs(func(k string) string { return tc.env[k] })
+			// Assert
+			if tc.wantErr {
+				assertNotNil(t, err)
+				return
+			}
+			assertNoError(t, err)
+			assertEqual(t, got, tc.want)
+		})
+	}
+}
+
+func TestUnitWriteActionOutputs(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "output")
+	os.WriteFile(path, []byte("previous=1\n"), 0o600)
+	// Act
+	err := writeActionOutputs(path, map[string]string{"updated": "true", "events": "5"})
+	// Assert
+	assertNoError(t, err)
+	byt, _ := os.ReadFile(path)
+	assertEqual(t, string(byt), "previous=1\nevents=5\nupdated=true\n")
+}
+
+func TestUnitCommitFiles(t *testing.T) {
+	in := actionInputs{commitName: "bot", commitEmail: "bot@example.com", commitMsg: "update"}
+	testCases := []struct {
+		name      string
+		failOn    string
+		wantCalls int
+		wantErr   bool
+	}{
+		{name: "commits and pushes", wantCalls: 5},
+		{name: "stops on commit failure", failOn: "commit", wantCalls: 4, wantErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			git := &mockGit{failOn: tc.failOn}
+			// Act
+			err := commitFiles(git, in, "README.md")
+			// Assert
+			if tc.wantErr {
+				assertNotNil(t, err)
+			} else {
+				assertNoError(t, err)
+			}
+			assertEqual(t, len(git.calls), tc.wantCalls)
+			assertEqual(t, git.calls[2], "add -- README.md")
+		})
+	}
+}
@@ -0,0 +1,326 @@
+// Package activityclient is a typed client for the JSON API of
+// go-github-activity serve, as described by its /openapi.json document.
+package activityclient
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type (
+	// Client calls a go-github-activity server.
+	Client struct {
+		BaseURL    string
+		APIKey     string
+		HTTPClient *http.Client
+	}
+	// Event is a GitHub event with its human-readable summary.
+	Event struct {
+		ID        string    `json:"id"`
+		Type      string    `json:"type"`
+		Actor     Actor     `json:"actor"`
+		Repo      Repo      `json:"repo"`
+		Payload   Payload   `json:"payload"`
+		Public    bool      `json:"public"`
+		CreatedAt time.Time `json:"created_at"`
+		Summary   string    `json:"summary"`
+		Labels    []string  `json:"labels,omitempty"`
+		Tickets   []Ticket  `json:"tickets,omitempty"`
+		// Source names the configured source of the event when there are
+		// several.
+		Source string `json:"source,omitempty"`
+		// Local marks commits found in a local clone, which may never have
+		// been pushed.
+		Local bool `json:"local,omitempty"`
+		// Raw is the event as served by GitHub, when archived raw.
+		Raw json.RawMessage `json:"raw,omitempty"`
+	}
+	// Actor is the user who triggered an event.
+	Actor struct {
+		ID           int      `json:"id"`
+		Login        string   `json:"login"`
+		DisplayLogin string   `json:"display_login"`
+		URL          string   `json:"url"`
+		AvatarURL    string   `json:"avatar_url"`
+		Profile      *Profile `json:"profile,omitempty"`
+	}
+	// Profile is the public profile of an actor, attached by enrichment.
+	Profile struct {
+		Login     string `json:"login"`
+		Name      string `json:"name"`
+		Company   string `json:"company"`
+		AvatarURL string `json:"avatar_url"`
+	}
+	// Repo is the repository of an event.
+	Repo struct {
+		ID   int       `json:"id"`
+		Name string    `json:"name"`
+		URL  string    `json:"url"`
+		Meta *RepoMeta `json:"meta,omitempty"`
+	}
+	// RepoMeta is the repository metadata attached by enrichment.
+	RepoMeta struct {
+		FullName    string `json:"full_name"`
+		Description string `json:"description"`
+		Stars       int    `json:"stargazers_count"`
+		Fork        bool   `json:"fork"`
+		Language    string `json:"language"`
+		Archived    bool   `json:"archived"`
+		Private     bool   `json:"private"`
+	}
+	// Payload is the type-specific data of an event.
+	Payload struct {
+		Action       string       `json:"action,omitempty"`
+		PushID       int64        `json:"push_id,omitempty"`
+		Size         int          `json:"size,omitempty"`
+		DistinctSize int          `json:"distinct_size,omitempty"`
+		Ref          string       `json:"ref,omitempty"`
+		RefType      string       `json:"ref_type,omitempty"`
+		Head         string       `json:"head,omitempty"`
+		Before       string       `json:"before,omitempty"`
+		Commits      []Commit     `json:"commits,omitempty"`
+		PullRequest  *PullRequest `json:"pull_request,omitempty"`
+		Issue        *Issue       `json:"issue,omitempty"`
+		Deployment   *Deployment  `json:"deployment,omitempty"`
+		Project      *ProjectItem `json:"project_item,omitempty"`
+		Discussion   *Discussion  `json:"discussion,omitempty"`
+		Sponsorship  *Sponsorship `json:"sponsorship,omitempty"`
+		WorkflowRun  *WorkflowRun `json:"workflow_run,omitempty"`
+		Alert        *Alert       `json:"alert,omitempty"`
+		Release      *Release     `json:"release,omitempty"`
+		Labels       []Label      `json:"labels,omitempty"`
+		Milestone    *Milestone   `json:"milestone,omitempty"`
+		// Checks is the combined check state of the head of a push.
+		Checks string `json:"checks,omitempty"`
+	}
+	// Commit is a commit of a push event.
+	Commit struct {
+		SHA    string `json:"sha"`
+		Author struct {
+			Email string `json:"email"`
+			Name  string `json:"name"`
+		} `json:"author"`
+		Message      string        `json:"message"`
+		Distinct     bool          `json:"distinct"`
+		URL          string        `json:"url"`
+		Verification *Verification `json:"verification,omitempty"`
+	}
+	// Verification is the signature status of a commit.
+	Verification struct {
+		Verified bool   `json:"verified"`
+		Reason   string `json:"reason"`
+	}
+	// PullRequest is the pull request of a pull request event.
+	PullRequest struct {
+		Number  int    `json:"number"`
+		Title   string `json:"title"`
+		HTMLURL string `json:"html_url"`
+		State   string `json:"state"`
+		Merged  bool   `json:"merged,omitempty"`
+	}
+	// Issue is the issue of an issue event.
+	Issue struct {
+		Number      int        `json:"number"`
+		Title       string     `json:"title"`
+		HTMLURL     string     `json:"html_url"`
+		State       string     `json:"state"`
+		User        *Actor     `json:"user,omitempty"`
+		PullRequest *struct{}  `json:"pull_request,omitempty"`
+		CreatedAt   *time.Time `json:"created_at,omitempty"`
+		ClosedAt    *time.Time `json:"closed_at,omitempty"`
+	}
+	// Deployment is the deployment of a deployment event.
+	Deployment struct {
+		ID          int64     `json:"id"`
+		Environment string    `json:"environment"`
+		Ref         string    `json:"ref"`
+		CreatedAt   time.Time `json:"created_at"`
+	}
+	// ProjectItem is the project item of a project event.
+	ProjectItem struct {
+		Project string `json:"project"`
+		URL     string `json:"url"`
+		Title   string `json:"title"`
+		Field   string `json:"field,omitempty"`
+		Value   string `json:"value,omitempty"`
+	}
+	// Discussion is the discussion of a discussion event.
+	Discussion struct {
+		Number  int    `json:"number"`
+		Title   string `json:"title"`
+		HTMLURL string `json:"html_url"`
+	}
+	// Sponsorship is the sponsorship of a sponsorship event.
+	Sponsorship struct {
+		Sponsor        string `json:"sponsor"`
+		Tier           string `json:"tier,omitempty"`
+		MonthlyDollars int    `json:"monthly_dollars,omitempty"`
+	}
+	// WorkflowRun is the run of a workflow run event.
+	WorkflowRun struct {
+		ID         int64     `json:"id"`
+		Name       string    `json:"name"`
+		Status     string    `json:"status"`
+		Conclusion string    `json:"conclusion"`
+		HTMLURL    string    `json:"html_url"`
+		HeadBranch string    `json:"head_branch"`
+		HeadSHA    string    `json:"head_sha"`
+		UpdatedAt  time.Time `json:"updated_at"`
+		Actor      *Actor    `json:"triggering_actor,omitempty"`
+		Repository *struct {
+			FullName string `json:"full_name"`
+			HTMLURL  string `json:"html_url"`
+			Private  bool   `json:"private"`
+		} `json:"repository,omitempty"`
+	}
+	// Alert is the security alert of an alert event.
+	Alert struct {
+		Number int `json:"number"`
+		// Advisory is the CVE or GHSA identifier of a security advisory.
+		Advisory string `json:"advisory,omitempty"`
+		Summary  string `json:"summary"`
+		Severity string `json:"severity,omitempty"`
+		Package  string `json:"package,omitempty"`
+		HTMLURL  string `json:"html_url"`
+	}
+	// Release is the release of a release event.
+	Release struct {
+		TagName    string `json:"tag_name"`
+		Name       string `json:"name"`
+		HTMLURL    string `json:"html_url"`
+		Prerelease bool   `json:"prerelease"`
+		// Bump is major, minor or patch when the tag is a semantic version
+		// above every earlier one of the repository.
+		Bump string `json:"bump,omitempty"`
+	}
+	// Label is a label of an issue or pull request.
+	Label struct {
+		Name string `json:"name"`
+	}
+	// Milestone is the milestone of an issue or pull request.
+	Milestone struct {
+		Title        string     `json:"title"`
+		State        string     `json:"state"`
+		OpenIssues   int        `json:"open_issues"`
+		ClosedIssues int        `json:"closed_issues"`
+		DueOn        *time.Time `json:"due_on,omitempty"`
+	}
+	// Ticket is an issue-tracker key referenced by an event.
+	Ticket struct {
+		Key string `json:"key"`
+		URL string `json:"url"`
+	}
+	// Error is a non-successful API response.
+	Error struct {
+		StatusCode int
+		Message    string
+	}
+)
+
+// New returns a client for the server at baseURL, e.g.
+// "http://localhost:8080"; apiKey may be empty when the API is open.
+func New(baseURL, apiKey string) *Client {
+	return &Client{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		APIKey:     apiKey,
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("activity API error %d: %s", e.StatusCode, e.Message)
+}
+
+// Health reports whether the server is up.
+func (c *Client) Health(ctx context.Context) error {
+	var status struct {
+		Status string `json:"status"`
+	}
+	return c.get(ctx, "/healthz", &status)
+}
+
+// ListActivity returns the latest events of a user, newest first.
+func (c *Client) ListActivity(ctx context.Context, user string) ([]Event, error) {
+	var events []Event
+	if err := c.get(ctx, "/users/"+url.PathEscape(user)+"/activity", &events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// StreamActivity calls fn with each new event of a user until ctx ends, the
+// stream closes or fn fails.
+func (c *Client) StreamActivity(ctx context.Context, user string, fn func(Event) error) error {
+	res, err := c.do(ctx, "/users/"+url.PathEscape(user)+"/stream")
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	sc := bufio.NewScanner(res.Body)
+	sc.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for sc.Scan() {
+		data, ok := strings.CutPrefix(sc.Text(), "data: ")
+		if !ok {
+			continue
+		}
+		var ev Event
+		if err := json.Unmarshal([]byte(data), &ev); err != nil {
+			return fmt.Errorf("decode event: %w", err)
+		}
+		if err := fn(ev); err != nil {
+			return err
+		}
+	}
+	if err := sc.Err(); err != nil && ctx.Err() == nil {
+		return fmt.Errorf("read stream: %w", err)
+	}
+	return nil
+}
+
+// get decodes the JSON response of an endpoint into v.
+func (c *Client) get(ctx context.Context, path string, v any) error {
+	res, err := c.do(ctx, path)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if err := json.NewDecoder(res.Body).Decode(v); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
+
+// do sends an authenticated GET request and turns error statuses into an
+// *Error.
+func (c *Client) do(ctx context.Context, path string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.BaseURL+path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("request error: %w", err)
+	}
+	if c.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.APIKey)
+	}
+	res, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request error: %w", err)
+	}
+	if res.StatusCode < 400 {
+		return res, nil
+	}
+	defer res.Body.Close()
+	var body struct {
+		Error string `json:"error"`
+	}
+	byt, _ := io.ReadAll(io.LimitReader(res.Body, 64*1024))
+	if json.Unmarshal(byt, &body) != nil || body.Error == "" {
+		body.Error = res.Status
+	}
+	return nil, &Error{StatusCode: res.StatusCode, Message: body.Error}
+}
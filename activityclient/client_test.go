@@ -0,0 +1,83 @@
+package activityclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestUnitListActivity(t *testing.T) {
+	testCases := []struct {
+		name       string
+		status     int
+		body       string
+		wantEvents int
+		wantStatus int
+	}{
+		{
+			name:       "events",
+			status:     http.StatusOK,
+			body:       `[{"id":"1","type":"WatchEvent","summary":"Starred octo/repo"}]`,
+			wantEvents: 1,
+		},
+		{name: "api error", status: http.StatusNotFound, body: `{"error":"no such user"}`, wantStatus: http.StatusNotFound},
+		{name: "plain error", status: http.StatusBadGateway, body: "oops", wantStatus: http.StatusBadGateway},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/users/octocat/activity" || r.Header.Get("Authorization") != "Bearer key" {
+					t.Errorf("unexpected request %s with %q", r.URL.Path, r.Header.Get("Authorization"))
+				}
+				w.WriteHeader(tc.status)
+				fmt.Fprint(w, tc.body)
+			}))
+			t.Cleanup(srv.Close)
+			c := New(srv.URL+"/", "key")
+			// Act
+			events, err := c.ListActivity(context.Background(), "octocat")
+			// Assert
+			if tc.wantStatus != 0 {
+				var apiErr *Error
+				if !errors.As(err, &apiErr) || apiErr.StatusCode != tc.wantStatus {
+					t.Fatalf("got error %v, want status %d", err, tc.wantStatus)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(events) != tc.wantEvents || events[0].Summary != "Starred octo/repo" {
+				t.Fatalf("got %+v", events)
+			}
+		})
+	}
+}
+
+func TestUnitStreamActivity(t *testing.T) {
+	// Arrange
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, ": ping\n\n")
+		fmt.Fprint(w, "id: 1\nevent: activity\ndata: {\"id\":\"1\"}\n\n")
+		fmt.Fprint(w, "id: 2\nevent: activity\ndata: {\"id\":\"2\"}\n\n")
+	}))
+	t.Cleanup(srv.Close)
+	var got []string
+	// Act
+	err := New(srv.URL, "").StreamActivity(context.Background(), "octocat", func(ev Event) error {
+		got = append(got, ev.ID)
+		return nil
+	})
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fmt.Sprint(got) != "[1 2]" {
+		t.Fatalf("got %v", got)
+	}
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+type (
+	// advisoriesProvider reads the security advisories published for a set
+	// of repositories, by default those the authenticated user watches.
+	advisoriesProvider struct {
+		hc    *client
+		base  string
+		repos []string
+	}
+	// apiAdvisory is a repository security advisory as served by the API.
+	apiAdvisory struct {
+		GHSAID      string    `json:"ghsa_id"`
+		CVEID       string    `json:"cve_id"`
+		Summary     string    `json:"summary"`
+		Severity    string    `json:"severity"`
+		HTMLURL     string    `json:"html_url"`
+		PublishedAt time.Time `json:"published_at"`
+	}
+)
+
+const (
+	// advisoryPages is the number of pages of the watched repositories read
+	// when no repository is configured.
+	advisoryPages = 10
+	// advisoryWorkers is the number of repositories whose advisories are
+	// fetched at once.
+	advisoryWorkers = 4
+)
+
+func newAdvisoriesProvider(hc *client, base string, repos []string) *advisoriesProvider {
+	return &advisoriesProvider{hc: hc, base: strings.TrimSuffix(base, "/"), repos: repos}
+}
+
+func (p *advisoriesProvider) name() string { return "github-advisories" }
+
+// events returns a SecurityAdvisoryEvent per published advisory, newest
+// first, warning when some repositories were skipped for the rate limit.
+// The login is not used: advisories concern repositories, not users.
+func (p *advisoriesProvider) events(string) ([]ghEvent, error) {
+	repos := p.repos
+	if len(repos) == 0 {
+		var err error
+		if repos, err = followedRepos(p.hc, p.base, "watched", advisoryPages); err != nil {
+			return nil, err
+		}
+	}
+	events, skipped, err := fetchAdvisories(p.hc, p.base, repos)
+	if err != nil {
+		return nil, err
+	}
+	if skipped > 0 {
+		err := infof(os.Stderr, "partial: %d of %d repositories skipped to preserve the rate limit\n", skipped, len(repos))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return events, nil
+}
+
+// runAdvisories lists the security advisories published for the followed
+// repositories, or watches them and notifies new ones through
+// advisories.rules, or to every notifier when there is none.
+func runAdvisories(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("advisories", flag.ContinueOnError)
+	watch := flags.Bool("watch", false, "poll the advisories and notify new ones through advisories.rules")
+	interval := flags.Duration("interval", 30*time.Minute, "with --watch, delay between two polls")
+	strict := flags.Bool("strict-budget", false, "fail instead of asking when the run would exceed the rate limit")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *interval < time.Second {
+		return fmt.Errorf("invalid interval: %s", *interval)
+	}
+	if err := loadConfig(); err != nil {
+		return err
+	}
+	repos := flags.Args()
+	if len(repos) == 0 {
+		repos = viper.GetStringSlice("advisories.repos")
+	}
+	p := newAdvisoriesProvider(configuredClient(), viper.GetString("api_url"), repos)
+	if !*watch {
+		return listAdvisories(stdout, p, *strict)
+	}
+	fetch := func() ([]ghEvent, error) { return p.events("") }
+	return watchLive(&poller{fetch: fetch}, func() (watchSettings, error) {
+		return loadWatchSettings("advisories.rules", *interval)
+	}, stdout)
+}
+
+// listAdvisories renders the advisories of the provider's repositories once
+// their cost, one request per repository, fits the rate limit, see
+// checkRunBudget. The watched repositories are listed first when none is
+// configured.
+func listAdvisories(stdout io.Writer, p *advisoriesProvider, strict bool) error {
+	if len(p.repos) == 0 {
+		repos, err := followedRepos(p.hc, p.base, "watched", advisoryPages)
+		if err != nil {
+			return err
+		}
+		if len(repos) == 0 {
+			return nil
+		}
+		p.repos = repos
+	}
+	if err := checkRunBudget(&repoFetcher{hc: p.hc, base: p.base}, len(p.repos), strict); err != nil {
+		return err
+	}
+	events, err := p.events("")
+	if err != nil {
+		return err
+	}
+	cat := lookupCatalog(resolveLang(viper.GetString("lang"), os.Getenv("LANG")))
+	return textRenderer{cat: cat, icons: loadIcons(false, nil)}.render(stdout, events)
+}
+
+// fetchAdvisories returns the advisories published for the repositories,
+// newest first, with trackRepos, and the number of repositories skipped to
+// preserve the rate limit. Repositories without visible advisories are
+// skipped too, but not counted.
+func fetchAdvisories(hc *client, base string, repos []string) ([]ghEvent, int, error) {
+	advisoriesOf := func(name string) ([]ghEvent, error) {
+		name = strings.Trim(name, "/")
+		var advisories []apiAdvisory
+		u := fmt.Sprintf("%s/repos/%s/security-advisories?state=published&per_page=50", base, name)
+		err := fetchJSON(hc, u, &advisories)
+		switch {
+		case isStatus(err, http.StatusForbidden) || isStatus(err, http.StatusNotFound):
+			return nil, nil
+		case err != nil:
+			return nil, fmt.Errorf("fetch security advisories of %s: %w", name, err)
+		}
+		events := make([]ghEvent, 0, len(advisories))
+		for _, a := range advisories {
+			events = append(events, a.event(name))
+		}
+		return events, nil
+	}
+	events, skipped, err := trackRepos(repos, advisoriesOf, advisoryWorkers, hc.budget, hc.clock)
+	if err != nil {
+		return nil, 0, err
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].CreatedAt.After(events[j].CreatedAt) })
+	return events, skipped, nil
+}
+
+// event returns the publication of the advisory. Published advisories are
+// public, identified by their CVE when they have one.
+func (a apiAdvisory) event(name string) ghEvent {
+	id := a.GHSAID
+	if a.CVEID != "" {
+		id = a.CVEID
+	}
+	return ghEvent{
+		ID: "advisory-" + a.GHSAID, Type: "SecurityAdvisoryEvent", Repo: repo{Name: name}, Public: true,
+		Payload: payload{Action: "published", Alert: &securityAlert{
+			Advisory: id, Summary: a.Summary, Severity: a.Severity, HTMLURL: a.HTMLURL,
+		}},
+		CreatedAt: a.PublishedAt,
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestUnitFetchAdvisories(t *testing.T) {
+	// Arrange
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assertEqual(t, r.URL.Query().Get("state"), "published")
+		switch r.URL.Path {
+		case "/repos/octo/api/security-advisories":
+			w.Write([]byte(`[
+				{"ghsa_id":"GHSA-aaaa","cve_id":"CVE-2025-1234","summary":"Path traversal","severity":"high",
+				 "published_at":"2025-03-02T10:00:00Z"},
+				{"ghsa_id":"GHSA-bbbb","summary":"Open redirect","severity":"",
+				 "published_at":"2025-03-04T10:00:00Z"}]`))
+		case "/repos/octo/private/security-advisories":
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	// Act
+	got, skipped, err := fetchAdvisories(newClient(""), srv.URL, []string{"octo/api", "octo/private", "octo/gone"})
+	// Assert
+	assertNoError(t, err)
+	assertEqual(t, skipped, 0)
+	assertEqual(t, len(got), 2)
+	assertEqual(t, got[0].ID, "advisory-GHSA-bbbb")
+	assertEqual(t, got[1].Payload.Alert.Advisory, "CVE-2025-1234")
+	assertEqual(t, got[1].Public, true)
+	cat := catalogs[defaultLang]
+	assertEqual(t, summarize(cat, got[0]), "Security advisory GHSA-bbbb published for octo/api")
+	assertEqual(t, summarize(cat, got[1]), "Security advisory CVE-2025-1234 (high severity) published for octo/api")
+}
+
+func TestUnitFetchAdvisoriesBudgetReserve(t *testing.T) {
+	// Arrange
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`[]`))
+	}))
+	t.Cleanup(srv.Close)
+	hc := newClient("")
+	hc.budget = &rateBudget{known: true, remaining: orgBudgetReserve, reset: time.Now().Add(time.Hour)}
+	// Act
+	got, skipped, err := fetchAdvisories(hc, srv.URL, []string{"octo/api", "octo/web", "octo/cli"})
+	// Assert
+	assertNoError(t, err)
+	assertEqual(t, len(got), 0)
+	assertEqual(t, skipped, 3)
+	assertEqual(t, requests, 0)
+}
+
+func TestUnitListAdvisoriesBudget(t *testing.T) {
+	testCases := []struct {
+		name      string
+		remaining int
+		wantErr   bool
+	}{
+		{name: "within the budget", remaining: 5000},
+		{name: "over the budget", remaining: orgBudgetReserve + 1, wantErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			fetched := 0
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/rate_limit":
+					fmt.Fprintf(w, `{"resources":{"core":{"remaining":%d,"reset":1700000000}}}`, tc.remaining)
+				case "/user/subscriptions":
+					w.Write([]byte(`[{"full_name":"octo/api"},{"full_name":"octo/web"}]`))
+				default:
+					fetched++
+					w.Write([]byte(`[]`))
+				}
+			}))
+			t.Cleanup(srv.Close)
+			p := newAdvisoriesProvider(newClient("token"), srv.URL, nil)
+			var out bytes.Buffer
+			// Act
+			err := listAdvisories(&out, p, true)
+			// Assert
+			if tc.wantErr {
+				var budgetErr *budgetError
+				assertEqual(t, errors.As(err, &budgetErr), true)
+				assertEqual(t, fetched, 0)
+				return
+			}
+			assertNoError(t, err)
+			assertEqual(t, fetched, 2)
+		})
+	}
+}
+
+func TestUnitAdvisoriesProviderFollowsWatched(t *testing.T) {
+	// Arrange
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/user/subscriptions":
+			w.Write([]byte(`[{"full_name":"octo/api"}]`))
+		case "/repos/octo/api/security-advisories":
+			w.Write([]byte(`[{"ghsa_id":"GHSA-aaaa","published_at":"2025-03-02T10:00:00Z"}]`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	p := newAdvisoriesProvider(newClient("token"), srv.URL+"/", nil)
+	// Act
+	got, err := p.events("octocat")
+	// Assert
+	assertNoError(t, err)
+	assertEqual(t, len(got), 1)
+	assertEqual(t, got[0].Repo.Name, "octo/api")
+}
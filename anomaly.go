@@ -0,0 +1,50 @@
+package main
+
+import (
+	"math"
+	"time"
+)
+
+// anomaly is a day whose activity deviates from its trailing average.
+type anomaly struct {
+	day    time.Time
+	count  int
+	mean   float64
+	stddev float64
+}
+
+// kind tells spikes from silences.
+func (a anomaly) kind() string {
+	if float64(a.count) > a.mean {
+		return "spike"
+	}
+	return "silence"
+}
+
+// detectAnomalies flags the days in [from, to] whose count deviates more
+// than sigma standard deviations from the average of the trailing days
+// before them. Days without a full trailing window of history since first,
+// and days with a perfectly flat history, are not evaluated.
+func detectAnomalies(counts map[time.Time]int, first, from, to time.Time, trailing int, sigma float64) []anomaly {
+	var found []anomaly
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		if day.AddDate(0, 0, -trailing).Before(first) {
+			continue
+		}
+		var sum, sumSq float64
+		for i := 1; i <= trailing; i++ {
+			n := float64(counts[day.AddDate(0, 0, -i)])
+			sum += n
+			sumSq += n * n
+		}
+		mean := sum / float64(trailing)
+		stddev := math.Sqrt(math.Max(sumSq/float64(trailing)-mean*mean, 0))
+		if stddev == 0 {
+			continue
+		}
+		if math.Abs(float64(counts[day])-mean) > sigma*stddev {
+			found = append(found, anomaly{day: day, count: counts[day], mean: mean, stddev: stddev})
+		}
+	}
+	return found
+}
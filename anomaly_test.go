@@ -0,0 +1,45 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUnitDetectAnomalies(t *testing.T) {
+	first := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	history := func(n int, pattern ...int) map[time.Time]int {
+		counts := map[time.Time]int{}
+		for i := 0; i < n; i++ {
+			counts[first.AddDate(0, 0, i)] = pattern[i%len(pattern)]
+		}
+		return counts
+	}
+	testCases := []struct {
+		name     string
+		counts   map[time.Time]int
+		last     int
+		wantKind string
+	}{
+		{name: "spike", counts: history(7, 4, 6), last: 30, wantKind: "spike"},
+		{name: "silence", counts: history(7, 9, 11), last: 0, wantKind: "silence"},
+		{name: "usual day", counts: history(7, 4, 6), last: 5},
+		{name: "flat history", counts: history(7, 5), last: 30},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			day := first.AddDate(0, 0, 7)
+			tc.counts[day] = tc.last
+			// Act
+			got := detectAnomalies(tc.counts, first, first, day, 6, 2)
+			// Assert
+			if tc.wantKind == "" {
+				assertEqual(t, len(got), 0)
+				return
+			}
+			assertEqual(t, len(got), 1)
+			assertEqual(t, got[0].day, day)
+			assertEqual(t, got[0].kind(), tc.wantKind)
+		})
+	}
+}
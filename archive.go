@@ -0,0 +1,338 @@
+package main
+
+import (
+	"bufio"
+	"crypto/cipher"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// defaultBatchSize is the number of events decoded at once when streaming
+// the archive.
+const defaultBatchSize = 1000
+
+// archive is an append-only JSON Lines store of a user's events, keeping
+// history beyond the 90 days served by the events API. With an aead, each
+// line is encrypted on its own.
+type archive struct {
+	path string
+	aead cipher.AEAD
+	// upgrade reads the lines written by earlier versions, in clear or
+	// encrypted without associated data, for archive compact to encrypt
+	// them again.
+	upgrade bool
+}
+
+// archiveCommands lists the subcommands maintaining the local archive.
+var archiveCommands = map[string]command{
+	"prune":     runPrune,
+	"compact":   runCompact,
+	"keygen":    runKeygen,
+	"export":    runExport,
+	"import":    runImport,
+	"check":     runCheck,
+	"reprocess": runReprocess,
+}
+
+// runArchive dispatches an archive subcommand.
+func runArchive(args []string, stdout io.Writer) error {
+	if len(args) > 0 {
+		if cmd, ok := archiveCommands[args[0]]; ok {
+			return cmd(args[1:], stdout)
+		}
+	}
+	return fmt.Errorf("usage: go-github-activity archive %s [flags]", strings.Join(sortedKeys(archiveCommands), "|"))
+}
+
+// openArchive returns the archive of a user in the app directory, in a
+// directory of its own for the active profile, migrated to the latest
+// schema.
+func openArchive(user string) (*archive, error) {
+	return openArchiveUpgrade(user, false)
+}
+
+// openArchiveUpgrade returns the archive of a user like openArchive; with
+// upgrade, it reads the lines of earlier versions so that compacting it
+// encrypts them again.
+func openArchiveUpgrade(user string, upgrade bool) (*archive, error) {
+	dir, err := appDir(&defaultUserHome{})
+	if err != nil {
+		return nil, err
+	}
+	aead, err := archiveCipher()
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, "archive", activeProfile, url.PathEscape(user)+".jsonl")
+	a := &archive{path: path, aead: aead, upgrade: upgrade}
+	if _, err := a.migrate(migrations); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// archivedEvents reads the events a user archived since t, newest first,
+// from the archives of every account of a person, without the private
+// sponsorships.
+func archivedEvents(user string, since time.Time) ([]ghEvent, error) {
+	events, err := archivedActivity(user, since)
+	return hideSponsorships(events), err
+}
+
+// archivedActivity reads the events a user archived since t like
+// archivedEvents, with the private sponsorships the digest counts.
+func archivedActivity(user string, since time.Time) ([]ghEvent, error) {
+	if err := loadConfig(); err != nil {
+		return nil, err
+	}
+	logins, err := identities(user)
+	if err != nil {
+		return nil, err
+	}
+	lists := make([][]ghEvent, 0, len(logins))
+	for _, login := range logins {
+		a, err := openArchive(login)
+		if err != nil {
+			return nil, err
+		}
+		events, err := a.loadSince(since)
+		if err != nil {
+			return nil, err
+		}
+		lists = append(lists, events)
+	}
+	return mergeEvents(lists...), nil
+}
+
+// archiveStart returns the time of the oldest event a user archived, from
+// the archives of every account of a person, or zero when there is none.
+// The archives are streamed rather than loaded.
+func archiveStart(user string) (time.Time, error) {
+	logins, err := identities(user)
+	if err != nil {
+		return time.Time{}, err
+	}
+	var start time.Time
+	for _, login := range logins {
+		a, err := openArchive(login)
+		if err != nil {
+			return time.Time{}, err
+		}
+		err = a.scan(archiveBatchSize(), func(batch []ghEvent) error {
+			for _, ev := range batch {
+				if start.IsZero() || ev.CreatedAt.Before(start) {
+					start = ev.CreatedAt
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return time.Time{}, err
+		}
+	}
+	return start, nil
+}
+
+// load reads every archived event, newest first. A missing archive is empty.
+func (a *archive) load() ([]ghEvent, error) {
+	return a.loadSince(time.Time{})
+}
+
+// loadSince reads the archived events created at or after t, newest first,
+// holding no more than one batch of older events in memory. With --redact,
+// the events are redacted.
+func (a *archive) loadSince(t time.Time) ([]ghEvent, error) {
+	var events []ghEvent
+	err := a.scan(archiveBatchSize(), func(batch []ghEvent) error {
+		events = append(events, eventsSince(batch, t)...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].CreatedAt.After(events[j].CreatedAt) })
+	return redactEvents(events), nil
+}
+
+// scan streams the archived events in batches of at most size events, in
+// the order they were archived. The batch is reused by the next call, so fn
+// must copy what it keeps. A missing archive is empty.
+func (a *archive) scan(size int, fn func(batch []ghEvent) error) error {
+	f, err := os.Open(a.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("open the archive: %w", err)
+	}
+	defer f.Close()
+	return a.scanEvents(f, size, fn)
+}
+
+// scanEvents decodes the archive lines read from r in batches of at most
+// size events.
+func (a *archive) scanEvents(r io.Reader, size int, fn func(batch []ghEvent) error) error {
+	batch := make([]ghEvent, 0, size)
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; sc.Scan(); line++ {
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+		batch = append(batch, ghEvent{})
+		if err := a.decode(sc.Bytes(), &batch[len(batch)-1]); err != nil {
+			return fmt.Errorf("decode archive line %d: %w", line, err)
+		}
+		if len(batch) == size {
+			if err := fn(batch); err != nil {
+				return err
+			}
+			batch = batch[:0]
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return fmt.Errorf("read the archive: %w", err)
+	}
+	if len(batch) > 0 {
+		return fn(batch)
+	}
+	return nil
+}
+
+// archiveBatchSize is the archive.batch_size setting, 1000 by default.
+func archiveBatchSize() int {
+	if n := viper.GetInt("archive.batch_size"); n > 0 {
+		return n
+	}
+	return defaultBatchSize
+}
+
+// add appends the events not archived yet and returns them.
+func (a *archive) add(events []ghEvent) ([]ghEvent, error) {
+	seen, err := a.ids()
+	if err != nil {
+		return nil, err
+	}
+	return a.appendNew(seen, events)
+}
+
+// unseen returns the events not archived yet, once each, without
+// appending them.
+func (a *archive) unseen(events []ghEvent) ([]ghEvent, error) {
+	seen, err := a.ids()
+	if err != nil {
+		return nil, err
+	}
+	var fresh []ghEvent
+	for _, ev := range events {
+		if !seen[ev.ID] {
+			seen[ev.ID] = true
+			fresh = append(fresh, ev)
+		}
+	}
+	return fresh, nil
+}
+
+// ids returns the set of archived event IDs.
+func (a *archive) ids() (map[string]bool, error) {
+	seen := map[string]bool{}
+	err := a.scan(archiveBatchSize(), func(batch []ghEvent) error {
+		for _, ev := range batch {
+			seen[ev.ID] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return seen, nil
+}
+
+// appendNew appends the events whose ID is not in seen, adds their IDs to
+// it and returns them.
+func (a *archive) appendNew(seen map[string]bool, events []ghEvent) ([]ghEvent, error) {
+	if err := os.MkdirAll(filepath.Dir(a.path), 0o700); err != nil {
+		return nil, fmt.Errorf("create archive directory: %w", err)
+	}
+	if _, err := os.Stat(a.path); errors.Is(err, fs.ErrNotExist) {
+		if err := a.setSchemaVersion(latestSchema(migrations)); err != nil {
+			return nil, err
+		}
+	}
+	f, err := os.OpenFile(a.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("open the archive: %w", err)
+	}
+	defer f.Close()
+	w := bufio.NewWriter(f)
+	var added []ghEvent
+	for _, ev := range events {
+		if seen[ev.ID] {
+			continue
+		}
+		seen[ev.ID] = true
+		line, err := a.encode(ev)
+		if err != nil {
+			return nil, err
+		}
+		w.Write(line)
+		added = append(added, ev)
+	}
+	if err := w.Flush(); err != nil {
+		return nil, fmt.Errorf("write the archive: %w", err)
+	}
+	return added, nil
+}
+
+// rewrite streams the archive into a temporary file, keeping the events for
+// which keep, free to modify them, returns true. The file replaces the
+// archive once complete, so an interrupted rewrite loses nothing. A missing
+// archive is left missing.
+func (a *archive) rewrite(keep func(ev *ghEvent) bool) error {
+	if _, err := os.Stat(a.path); errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(a.path), ".rewrite-*")
+	if err != nil {
+		return fmt.Errorf("create the rewritten archive: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+	w := bufio.NewWriter(tmp)
+	err = a.scan(archiveBatchSize(), func(batch []ghEvent) error {
+		for i := range batch {
+			if !keep(&batch[i]) {
+				continue
+			}
+			line, err := a.encode(batch[i])
+			if err != nil {
+				return err
+			}
+			w.Write(line)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("write the rewritten archive: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write the rewritten archive: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), a.path); err != nil {
+		return fmt.Errorf("replace the archive: %w", err)
+	}
+	return nil
+}
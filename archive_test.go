@@ -0,0 +1,211 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func TestUnitArchive(t *testing.T) {
+	// Arrange
+	a := &archive{path: filepath.Join(t.TempDir(), "archive", "octocat.jsonl")}
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	first := []ghEvent{{ID: "1", CreatedAt: now}, {ID: "2", CreatedAt: now.Add(time.Hour)}}
+	second := []ghEvent{{ID: "3", CreatedAt: now.Add(2 * time.Hour)}, {ID: "2", CreatedAt: now.Add(time.Hour)}}
+	// Act
+	empty, errEmpty := a.load()
+	added1, err1 := a.add(first)
+	added2, err2 := a.add(second)
+	events, err := a.load()
+	// Assert
+	assertNoError(t, errEmpty)
+	assertEqual(t, len(empty), 0)
+	assertNoError(t, err1)
+	assertNoError(t, err2)
+	assertNoError(t, err)
+	assertEqual(t, len(added1), 2)
+	assertEqual(t, len(added2), 1)
+	assertEqual(t, added2[0].ID, "3")
+	assertEqual(t, len(events), 3)
+	assertEqual(t, events[0].ID, "3")
+	assertEqual(t, events[2].ID, "1")
+}
+
+func TestUnitArchiveCorrupted(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "octocat.jsonl")
+	os.WriteFile(path, []byte("{\"id\":\"1\"}\nnot json\n"), 0o600)
+	a := &archive{path: path}
+	// Act
+	_, err := a.load()
+	// Assert
+	assertNotNil(t, err)
+}
+
+// eventStream lazily generates n archived events as JSON Lines, so large
+// datasets never sit in memory as a whole.
+type eventStream struct {
+	n, next int
+	start   time.Time
+	pending []byte
+}
+
+func (s *eventStream) Read(p []byte) (int, error) {
+	for len(s.pending) == 0 {
+		if s.next == s.n {
+			return 0, io.EOF
+		}
+		created := s.start.Add(time.Duration(s.next) * time.Minute).Format(time.RFC3339)
+		s.pending = fmt.Appendf(s.pending, `{"id":"%d","type":"PushEvent","repo":{"name":"octocat/repo-%d"},`+
+			`"payload":{"size":1},"created_at":"%s"}`+"\n", s.next, s.next%50, created)
+		s.next++
+	}
+	n := copy(p, s.pending)
+	s.pending = s.pending[n:]
+	return n, nil
+}
+
+func TestUnitScanEvents(t *testing.T) {
+	testCases := []struct {
+		name    string
+		events  int
+		size    int
+		batches int
+	}{
+		{name: "empty", events: 0, size: 10, batches: 0},
+		{name: "exact batches", events: 30, size: 10, batches: 3},
+		{name: "partial last batch", events: 25, size: 10, batches: 3},
+		{name: "single batch", events: 5, size: 10, batches: 1},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			stream := &eventStream{n: tc.events, start: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)}
+			var batches, total, largest int
+			// Act
+			err := (&archive{}).scanEvents(stream, tc.size, func(batch []ghEvent) error {
+				batches++
+				total += len(batch)
+				largest = max(largest, len(batch))
+				return nil
+			})
+			// Assert
+			assertNoError(t, err)
+			assertEqual(t, batches, tc.batches)
+			assertEqual(t, total, tc.events)
+			assertEqual(t, largest <= tc.size, true)
+		})
+	}
+}
+
+func TestUnitScanEventsStops(t *testing.T) {
+	// Arrange
+	stream := &eventStream{n: 100, start: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)}
+	stop := errors.New("stop")
+	calls := 0
+	// Act
+	err := (&archive{}).scanEvents(stream, 10, func([]ghEvent) error {
+		calls++
+		return stop
+	})
+	// Assert
+	assertEqual(t, errors.Is(err, stop), true)
+	assertEqual(t, calls, 1)
+}
+
+func TestUnitScanEventsMillion(t *testing.T) {
+	if testing.Short() {
+		t.Skip("decodes a million events")
+	}
+	// Arrange
+	const n = 1_000_000
+	stream := &eventStream{n: n, start: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)}
+	var before runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	var total, pushes int
+	var peak uint64
+	// Act
+	err := (&archive{}).scanEvents(stream, defaultBatchSize, func(batch []ghEvent) error {
+		total += len(batch)
+		for _, ev := range batch {
+			pushes += pushSize(ev)
+		}
+		if total%(100*defaultBatchSize) == 0 {
+			var m runtime.MemStats
+			runtime.ReadMemStats(&m)
+			peak = max(peak, m.HeapInuse)
+		}
+		return nil
+	})
+	// Assert
+	assertNoError(t, err)
+	assertEqual(t, total, n)
+	assertEqual(t, pushes, n)
+	if grown := int64(peak) - int64(before.HeapInuse); grown > 64<<20 {
+		t.Errorf("heap grew by %d MB while streaming, want at most 64 MB", grown>>20)
+	}
+}
+
+func TestUnitArchiveLoadSince(t *testing.T) {
+	// Arrange
+	viper.Set("archive.batch_size", 2)
+	t.Cleanup(viper.Reset)
+	a := &archive{path: filepath.Join(t.TempDir(), "octocat.jsonl")}
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	var events []ghEvent
+	for i := range 5 {
+		events = append(events, ghEvent{ID: fmt.Sprint(i), CreatedAt: now.AddDate(0, 0, i)})
+	}
+	_, errAdd := a.add(events)
+	// Act
+	recent, err := a.loadSince(now.AddDate(0, 0, 3))
+	// Assert
+	assertNoError(t, errAdd)
+	assertNoError(t, err)
+	assertEqual(t, len(recent), 2)
+	assertEqual(t, recent[0].ID, "4")
+	assertEqual(t, recent[1].ID, "3")
+}
+
+func TestUnitArchiveStart(t *testing.T) {
+	testCases := []struct {
+		name   string
+		events []ghEvent
+		want   time.Time
+	}{
+		{name: "no archive"},
+		{
+			name: "oldest event",
+			events: []ghEvent{
+				{ID: "2", CreatedAt: time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC)},
+				{ID: "1", CreatedAt: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)},
+				{ID: "3", CreatedAt: time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)},
+			},
+			want: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			t.Setenv("HOME", t.TempDir())
+			t.Cleanup(viper.Reset)
+			a, err := openArchive("octocat")
+			assertNoError(t, err)
+			_, err = a.add(tc.events)
+			assertNoError(t, err)
+			// Act
+			got, err := archiveStart("octocat")
+			// Assert
+			assertNoError(t, err)
+			assertEqual(t, got.Equal(tc.want), true)
+		})
+	}
+}
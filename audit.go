@@ -0,0 +1,129 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// auditEntry is an entry of the audit log of an organization.
+type auditEntry struct {
+	DocumentID string `json:"_document_id"`
+	Action     string `json:"action"`
+	Actor      string `json:"actor"`
+	Repo       string `json:"repo"`
+	Org        string `json:"org"`
+	Timestamp  int64  `json:"@timestamp"`
+}
+
+// auditPerPage is the page size of the audit log API.
+const auditPerPage = 100
+
+// runAudit lists, and optionally archives, the audit log of an
+// organization. Reading it requires an organization owner token with the
+// read:audit_log scope, on GitHub Enterprise Cloud.
+func runAudit(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("audit", flag.ContinueOnError)
+	phrase := flags.String("phrase", "", "audit log search phrase, e.g. action:repo.create")
+	pages := flags.Int("pages", 10, "maximum number of pages of 100 entries to read")
+	output := flags.String("output", "text", "output format: text or json")
+	archiveEntries := flags.Bool("archive", false, "append the entries to the archive of the organization")
+	var repoFilters repoFilterFlags
+	repoFilters.register(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: go-github-activity audit [flags] <org>")
+	}
+	if *output != "text" && *output != "json" {
+		return fmt.Errorf("unknown output format %q", *output)
+	}
+	if err := loadConfig(); err != nil {
+		return err
+	}
+	org := flags.Arg(0)
+	hc := configuredClient()
+	entries, err := fetchAuditLog(hc, viper.GetString("api_url"), org, *phrase, *pages)
+	if err != nil {
+		return err
+	}
+	events := make([]ghEvent, 0, len(entries))
+	for _, e := range entries {
+		events = append(events, e.event())
+	}
+	if events, err = repoFilters.apply(events); err != nil {
+		return err
+	}
+	if *archiveEntries {
+		a, err := openArchive(org)
+		if err != nil {
+			return err
+		}
+		added, err := a.add(events)
+		if err != nil {
+			return err
+		}
+		if *output == "text" {
+			if err := infof(stdout, "archived %d new audit entries\n", len(added)); err != nil {
+				return err
+			}
+		}
+	}
+	cat := lookupCatalog(resolveLang("", os.Getenv("LANG")))
+	if *output == "json" {
+		return jsonRenderer{cat: cat}.render(stdout, events)
+	}
+	return textRenderer{cat: cat, icons: loadIcons(false, nil)}.render(stdout, events)
+}
+
+// fetchAuditLog reads the audit log of an organization, newest first, up
+// to the given number of pages.
+func fetchAuditLog(hc *client, base, org, phrase string, pages int) ([]auditEntry, error) {
+	q := url.Values{}
+	if phrase != "" {
+		q.Set("phrase", phrase)
+	}
+	q.Set("per_page", fmt.Sprint(auditPerPage))
+	var all []auditEntry
+	p := newProgress("audit log of "+org, hc.budget)
+	defer p.done()
+	for page := 1; page <= pages; page++ {
+		q.Set("page", fmt.Sprint(page))
+		var entries []auditEntry
+		u := fmt.Sprintf("%s/orgs/%s/audit-log?%s", base, url.PathEscape(org), q.Encode())
+		if err := fetchJSON(hc, u, &entries); err != nil {
+			return nil, fmt.Errorf("fetch audit log of %s: %w", org, err)
+		}
+		all = append(all, entries...)
+		p.add(len(entries))
+		if len(entries) < auditPerPage {
+			break
+		}
+	}
+	return all, nil
+}
+
+// event normalizes an audit entry as an AuditLogEvent, so the filters,
+// archive and renderers apply to it. Entries without a repository are
+// attached to the organization.
+func (e auditEntry) event() ghEvent {
+	name := e.Repo
+	if name == "" {
+		name = e.Org
+	}
+	return ghEvent{
+		ID:        "audit-" + e.DocumentID,
+		Type:      "AuditLogEvent",
+		Actor:     actor{Login: e.Actor, DisplayLogin: e.Actor},
+		Repo:      repo{Name: name},
+		Payload:   payload{Action: e.Action},
+		CreatedAt: time.UnixMilli(e.Timestamp).UTC(),
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestUnitFetchAuditLog(t *testing.T) {
+	// Arrange
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		assertEqual(t, r.URL.Path, "/orgs/octo/audit-log")
+		assertEqual(t, r.URL.Query().Get("phrase"), "action:repo.create")
+		entries := make([]auditEntry, auditPerPage)
+		if r.URL.Query().Get("page") == "2" {
+			entries = entries[:3]
+		}
+		json.NewEncoder(w).Encode(entries)
+	}))
+	t.Cleanup(srv.Close)
+	// Act
+	entries, err := fetchAuditLog(newClient(""), srv.URL, "octo", "action:repo.create", 5)
+	// Assert
+	assertNoError(t, err)
+	assertEqual(t, calls, 2)
+	assertEqual(t, len(entries), auditPerPage+3)
+}
+
+func TestUnitAuditEntryEvent(t *testing.T) {
+	testCases := []struct {
+		name     string
+		entry    auditEntry
+		wantRepo string
+	}{
+		{
+			name:     "repository",
+			entry:    auditEntry{DocumentID: "d1", Action: "repo.create", Actor: "mona", Repo: "octo/a", Org: "octo"},
+			wantRepo: "octo/a",
+		},
+		{name: "organization", entry: auditEntry{DocumentID: "d2", Action: "org.add_member", Org: "octo"}, wantRepo: "octo"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			tc.entry.Timestamp = 1741600800000
+			// Act
+			ev := tc.entry.event()
+			// Assert
+			assertEqual(t, ev.ID, "audit-"+tc.entry.DocumentID)
+			assertEqual(t, ev.Type, "AuditLogEvent")
+			assertEqual(t, ev.Repo.Name, tc.wantRepo)
+			assertEqual(t, ev.CreatedAt, time.Date(2025, 3, 10, 10, 0, 0, 0, time.UTC))
+		})
+	}
+}
+
+func TestUnitSummarizeAuditEvent(t *testing.T) {
+	// Arrange
+	ev := auditEntry{Action: "repo.create", Actor: "mona", Repo: "octo/a"}.event()
+	// Act
+	got := summarize(lookupCatalog("en"), ev)
+	// Assert
+	assertEqual(t, got, "repo.create by mona in octo/a")
+}
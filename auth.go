@@ -0,0 +1,176 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+type (
+	// tokenStatus is what GitHub reports about a token.
+	tokenStatus struct {
+		login  string
+		scopes []string
+		// classic is false for fine-grained tokens, whose permissions are
+		// not reported.
+		classic bool
+	}
+	// operation is a feature with the classic token scopes it needs, any
+	// of which suffices.
+	operation struct {
+		name   string
+		scopes []string
+		hint   string
+	}
+)
+
+// operations lists the features that need more than a token without scope.
+var operations = []operation{
+	{name: "activity", hint: "public events need no scope"},
+	{name: "private", scopes: []string{"repo"}, hint: "add the repo scope to see private activity"},
+	{
+		name: "audit", scopes: []string{"read:audit_log", "admin:org"},
+		hint: "add the read:audit_log scope to read audit logs",
+	},
+	{
+		name: "security", scopes: []string{"security_events", "repo"},
+		hint: "add the security_events scope to read Dependabot and code scanning alerts",
+	},
+}
+
+// runAuth dispatches the auth subcommands.
+func runAuth(args []string, stdout io.Writer) error {
+	if len(args) == 0 || args[0] != "status" {
+		return errors.New("usage: go-github-activity auth status [flags]")
+	}
+	flags := flag.NewFlagSet("auth status", flag.ContinueOnError)
+	names := make([]string, 0, len(operations))
+	for _, op := range operations {
+		names = append(names, op.name)
+	}
+	required := flags.String("for", "", "operations to check (comma-separated): "+strings.Join(names, ", "))
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+	if err := loadConfig(); err != nil {
+		return err
+	}
+	token := viper.GetString("github_token")
+	if token == "" {
+		return errors.New("no token configured: set github_token, token_cmd, token_file or token_keychain " +
+			"in ~/.go-github-activity/config.yaml, or $GITHUB_TOKEN")
+	}
+	ops, err := selectOperations(*required)
+	if err != nil {
+		return err
+	}
+	st, err := checkToken(newClient(token), viper.GetString("api_url"))
+	if err != nil {
+		return err
+	}
+	return writeTokenStatus(stdout, st, ops)
+}
+
+// selectOperations returns the named operations, or all of them.
+func selectOperations(list string) ([]operation, error) {
+	if list == "" {
+		return operations, nil
+	}
+	var ops []operation
+	for _, name := range strings.Split(list, ",") {
+		i := slices.IndexFunc(operations, func(op operation) bool { return op.name == strings.TrimSpace(name) })
+		if i < 0 {
+			return nil, fmt.Errorf("unknown operation %q", name)
+		}
+		ops = append(ops, operations[i])
+	}
+	return ops, nil
+}
+
+// checkToken asks GitHub for the user of the token and reads its scopes.
+func checkToken(hc *client, base string) (tokenStatus, error) {
+	req, err := http.NewRequest(http.MethodGet, base+"/user", nil)
+	if err != nil {
+		return tokenStatus{}, fmt.Errorf("request error: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+hc.Token)
+	res, err := hc.Client.Do(req)
+	if err != nil {
+		return tokenStatus{}, fmt.Errorf("request error: %w", err)
+	}
+	defer res.Body.Close()
+	switch {
+	case res.StatusCode == http.StatusUnauthorized:
+		return tokenStatus{}, fmt.Errorf("the token is invalid, expired or revoked: "+
+			"create a new one at https://github.com/settings/tokens: %w",
+			&apiError{StatusCode: res.StatusCode, Status: res.Status})
+	case res.StatusCode >= 400:
+		return tokenStatus{}, &apiError{StatusCode: res.StatusCode, Status: res.Status}
+	}
+	var user struct {
+		Login string `json:"login"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&user); err != nil {
+		return tokenStatus{}, fmt.Errorf("decode response: %w", err)
+	}
+	st := tokenStatus{login: user.Login}
+	if header, ok := res.Header["X-Oauth-Scopes"]; ok {
+		st.classic = true
+		for _, s := range strings.Split(strings.Join(header, ","), ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				st.scopes = append(st.scopes, s)
+			}
+		}
+	}
+	return st, nil
+}
+
+// allows reports whether the token has one of the scopes of the operation.
+// The permissions of fine-grained tokens are unknown and assumed granted.
+func (st tokenStatus) allows(op operation) bool {
+	if !st.classic || len(op.scopes) == 0 {
+		return true
+	}
+	for _, s := range op.scopes {
+		if slices.Contains(st.scopes, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// writeTokenStatus prints the login, the scopes and one line per operation,
+// and fails when one of them is not allowed.
+func writeTokenStatus(w io.Writer, st tokenStatus, ops []operation) error {
+	scopes := strings.Join(st.scopes, ", ")
+	switch {
+	case !st.classic:
+		scopes = "not reported (fine-grained token)"
+	case scopes == "":
+		scopes = "none"
+	}
+	if _, err := fmt.Fprintf(w, "logged in as %s\nscopes: %s\n", st.login, scopes); err != nil {
+		return err
+	}
+	var missing []string
+	for _, op := range ops {
+		status := "ok"
+		if !st.allows(op) {
+			status, missing = "missing scope: "+op.hint, append(missing, op.name)
+		}
+		if _, err := fmt.Fprintf(w, "%-10s %s\n", op.name, status); err != nil {
+			return err
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("the token does not allow: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
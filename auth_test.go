@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestUnitCheckToken(t *testing.T) {
+	testCases := []struct {
+		name        string
+		status      int
+		scopes      []string
+		wantErr     bool
+		wantScopes  int
+		wantClassic bool
+	}{
+		{name: "classic", status: http.StatusOK, scopes: []string{"repo, read:org"}, wantScopes: 2, wantClassic: true},
+		{name: "classic without scope", status: http.StatusOK, scopes: []string{""}, wantClassic: true},
+		{name: "fine-grained", status: http.StatusOK},
+		{name: "revoked", status: http.StatusUnauthorized, wantErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assertEqual(t, r.URL.Path, "/user")
+				assertEqual(t, r.Header.Get("Authorization"), "Bearer ghp_x")
+				for _, s := range tc.scopes {
+					w.Header().Add("X-OAuth-Scopes", s)
+				}
+				w.WriteHeader(tc.status)
+				w.Write([]byte(`{"login": "octocat"}`))
+			}))
+			t.Cleanup(srv.Close)
+			// Act
+			st, err := checkToken(newClient("ghp_x"), srv.URL)
+			// Assert
+			if tc.wantErr {
+				assertNotNil(t, err)
+				return
+			}
+			assertNoError(t, err)
+			assertEqual(t, st.login, "octocat")
+			assertEqual(t, len(st.scopes), tc.wantScopes)
+			assertEqual(t, st.classic, tc.wantClassic)
+		})
+	}
+}
+
+func TestUnitSelectOperations(t *testing.T) {
+	// Act
+	all, errAll := selectOperations("")
+	some, errSome := selectOperations("audit, private")
+	_, errUnknown := selectOperations("admin")
+	// Assert
+	assertNoError(t, errAll)
+	assertEqual(t, len(all), len(operations))
+	assertNoError(t, errSome)
+	assertEqual(t, some[0].name, "audit")
+	assertEqual(t, some[1].name, "private")
+	assertNotNil(t, errUnknown)
+}
+
+func TestUnitWriteTokenStatus(t *testing.T) {
+	testCases := []struct {
+		name    string
+		status  tokenStatus
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "sufficient",
+			status: tokenStatus{login: "octocat", scopes: []string{"repo", "admin:org"}, classic: true},
+			want: "logged in as octocat\nscopes: repo, admin:org\nactivity   ok\nprivate    ok\naudit      ok\n" +
+				"security   ok\n",
+		},
+		{
+			name:   "missing scopes",
+			status: tokenStatus{login: "octocat", classic: true},
+			want: "logged in as octocat\nscopes: none\nactivity   ok\n" +
+				"private    missing scope: add the repo scope to see private activity\n" +
+				"audit      missing scope: add the read:audit_log scope to read audit logs\n" +
+				"security   missing scope: add the security_events scope to read Dependabot and code scanning alerts\n",
+			wantErr: true,
+		},
+		{
+			name:   "fine-grained",
+			status: tokenStatus{login: "octocat"},
+			want: "logged in as octocat\nscopes: not reported (fine-grained token)\n" +
+				"activity   ok\nprivate    ok\naudit      ok\nsecurity   ok\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			var buf bytes.Buffer
+			// Act
+			err := writeTokenStatus(&buf, tc.status, operations)
+			// Assert
+			assertEqual(t, err != nil, tc.wantErr)
+			assertEqual(t, buf.String(), tc.want)
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	// commitsPerPage is the page size of the commits API.
+	commitsPerPage = 100
+	// backfillPageDays is the number of days of commits a page is
+	// estimated to hold when checking the budget of a backfill.
+	backfillPageDays = 30
+)
+
+// runBackfill archives the commits of an author in some repositories over
+// a date range, beyond the retention of the events API.
+func runBackfill(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("backfill", flag.ContinueOnError)
+	repos := flags.String("repos", "", "repositories to walk as owner/name (comma-separated)")
+	authorLogin := flags.String("author", "", "commit author, defaults to the username")
+	since := flags.String("since", "", "first day as YYYY-MM-DD")
+	until := flags.String("until", "", "last day as YYYY-MM-DD, defaults to today")
+	strict := flags.Bool("strict-budget", false, "fail instead of asking when the run would exceed the rate limit")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 || *repos == "" || *since == "" {
+		return errors.New("usage: go-github-activity backfill --repos owner/name,... --since YYYY-MM-DD [flags] <username>")
+	}
+	if redact {
+		return errors.New("--redact cannot be used with backfill, which archives the commits as fetched")
+	}
+	user := flags.Arg(0)
+	if *authorLogin == "" {
+		*authorLogin = user
+	}
+	from, err := time.Parse(time.DateOnly, *since)
+	if err != nil {
+		return fmt.Errorf("parse since: %w", err)
+	}
+	to := time.Now()
+	if *until != "" {
+		if to, err = time.Parse(time.DateOnly, *until); err != nil {
+			return fmt.Errorf("parse until: %w", err)
+		}
+		to = to.AddDate(0, 0, 1)
+	}
+	if err := loadConfig(); err != nil {
+		return err
+	}
+	hc := configuredClient()
+	fetcher := &repoFetcher{hc: hc, base: viper.GetString("api_url")}
+	names := strings.Split(*repos, ",")
+	if err := checkRunBudget(fetcher, backfillRequests(len(names), from, to), *strict); err != nil {
+		return err
+	}
+	a, err := openArchive(user)
+	if err != nil {
+		return err
+	}
+	archived, err := a.load()
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		commits, err := fetcher.authorCommits(name, *authorLogin, from, to)
+		if err != nil {
+			return err
+		}
+		added, err := a.add(backfillEvents(archived, name, actor{Login: user, DisplayLogin: user}, commits))
+		if err != nil {
+			return err
+		}
+		if err := infof(stdout, "%s: archived %d of %d commits\n", name, len(added), len(commits)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// backfillRequests estimates the requests of a backfill of the repositories
+// from one time to another: a page of commits per backfillPageDays, and at
+// least one, per repository.
+func backfillRequests(repos int, from, to time.Time) int {
+	days := int(to.Sub(from).Hours() / 24)
+	return repos * max(1, (days+backfillPageDays-1)/backfillPageDays)
+}
+
+// authorCommits walks every page of the commits of an author in a
+// repository between two times.
+func (f *repoFetcher) authorCommits(name, login string, from, to time.Time) ([]apiCommit, error) {
+	base, err := f.repoURL(name, "commits")
+	if err != nil {
+		return nil, err
+	}
+	q := url.Values{}
+	q.Set("author", login)
+	q.Set("since", from.UTC().Format(time.RFC3339))
+	q.Set("until", to.UTC().Format(time.RFC3339))
+	q.Set("per_page", fmt.Sprint(commitsPerPage))
+	var all []apiCommit
+	p := newProgress(name, f.hc.budget)
+	defer p.done()
+	for page := 1; ; page++ {
+		q.Set("page", fmt.Sprint(page))
+		var commits []apiCommit
+		if err := fetchJSON(f.hc, base+"?"+q.Encode(), &commits); err != nil {
+			return nil, fmt.Errorf("fetch commits of %s: %w", name, err)
+		}
+		all = append(all, commits...)
+		p.add(len(commits))
+		if len(commits) < commitsPerPage {
+			return all, nil
+		}
+	}
+}
+
+// backfillEvents normalizes the commits as pushes, leaving out those
+// already part of an archived push.
+func backfillEvents(archived []ghEvent, repoName string, who actor, commits []apiCommit) []ghEvent {
+	known := map[string]bool{}
+	for _, ev := range archived {
+		for _, c := range ev.Payload.Commits {
+			known[c.SHA] = true
+		}
+	}
+	var events []ghEvent
+	for _, c := range commits {
+		if !known[c.SHA] {
+			events = append(events, c.event(repoName, who))
+		}
+	}
+	return events
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestUnitAuthorCommits(t *testing.T) {
+	// Arrange
+	var pages []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assertEqual(t, r.URL.Path, "/repos/octo/a/commits")
+		assertEqual(t, r.URL.Query().Get("author"), "octocat")
+		assertEqual(t, r.URL.Query().Get("since"), "2023-01-01T00:00:00Z")
+		page := r.URL.Query().Get("page")
+		pages = append(pages, page)
+		n := commitsPerPage
+		if page == "2" {
+			n = 1
+		}
+		commits := make([]apiCommit, n)
+		for i := range commits {
+			commits[i].SHA = fmt.Sprintf("%s-%d", page, i)
+		}
+		json.NewEncoder(w).Encode(commits)
+	}))
+	t.Cleanup(srv.Close)
+	f := &repoFetcher{hc: newClient(""), base: srv.URL}
+	from := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	// Act
+	commits, err := f.authorCommits("octo/a", "octocat", from, from.AddDate(1, 0, 0))
+	// Assert
+	assertNoError(t, err)
+	assertEqual(t, fmt.Sprint(pages), "[1 2]")
+	assertEqual(t, len(commits), commitsPerPage+1)
+}
+
+func TestUnitBackfillEvents(t *testing.T) {
+	// Arrange
+	archived := []ghEvent{{Type: "PushEvent", Payload: payload{Commits: []commit{{SHA: "known"}}}}}
+	var c1, c2 apiCommit
+	c1.SHA, c1.Commit.Message = "known", "Fix login"
+	c2.SHA, c2.Commit.Message = "new", "Add badges"
+	c2.Commit.Author.Date = time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)
+	// Act
+	events := backfillEvents(archived, "octo/a", actor{Login: "octocat"}, []apiCommit{c1, c2})
+	// Assert
+	assertEqual(t, len(events), 1)
+	assertEqual(t, events[0].ID, "commit-new")
+	assertEqual(t, events[0].Type, "PushEvent")
+	assertEqual(t, events[0].Repo.Name, "octo/a")
+	assertEqual(t, events[0].CreatedAt, c2.Commit.Author.Date)
+	assertEqual(t, pushSize(events[0]), 1)
+}
+
+func TestUnitBackfillRequests(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name  string
+		repos int
+		to    time.Time
+		want  int
+	}{
+		{name: "one day", repos: 3, to: from.AddDate(0, 0, 1), want: 3},
+		{name: "one month", repos: 3, to: from.AddDate(0, 0, 30), want: 3},
+		{name: "one year", repos: 2, to: from.AddDate(1, 0, 0), want: 26},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			got := backfillRequests(tc.repos, from, tc.to)
+			// Assert
+			assertEqual(t, got, tc.want)
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"strconv"
+	"time"
+)
+
+// badge follows the shields.io endpoint schema.
+// See https://shields.io/badges/endpoint-badge.
+type badge struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	Label         string `json:"label"`
+	Message       string `json:"message"`
+	Color         string `json:"color"`
+}
+
+// badgeColors maps shields named colors to the hex codes used in SVG output.
+var badgeColors = map[string]string{
+	"brightgreen": "#4c1",
+	"green":       "#97ca00",
+	"yellow":      "#dfb317",
+	"orange":      "#fe7d37",
+	"red":         "#e05d44",
+	"blue":        "#007ec6",
+	"lightgrey":   "#9f9f9f",
+}
+
+// runBadge writes an activity badge as shields endpoint JSON or as SVG.
+func runBadge(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("badge", flag.ContinueOnError)
+	metricName := flags.String("metric", "commits", "metric to count: commits, events, pull-requests, issues or stars")
+	days := flags.Int("days", 7, "size of the counting window in days")
+	label := flags.String("label", "", "badge label, defaults to \"<metric> this week\" or \"<metric> in N days\"")
+	color := flags.String("color", "", "badge color, defaults to a color scaled on the count")
+	svg := flags.Bool("svg", false, "write an SVG badge instead of endpoint JSON")
+	out := flags.String("out", "", "file to write instead of stdout")
+	var repoFilters repoFilterFlags
+	repoFilters.register(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: go-github-activity badge [flags] <username>")
+	}
+	events, err := fetchUserEvents(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	if events, err = repoFilters.apply(events); err != nil {
+		return err
+	}
+	b, err := newBadge(events, *metricName, *days, time.Now())
+	if err != nil {
+		return err
+	}
+	if *label != "" {
+		b.Label = *label
+	}
+	if *color != "" {
+		b.Color = *color
+	}
+	var byt []byte
+	if *svg {
+		byt = []byte(b.svg())
+	} else if byt, err = json.Marshal(b); err != nil {
+		return fmt.Errorf("encode badge: %w", err)
+	}
+	if *out == "" {
+		_, err = stdout.Write(byt)
+		return err
+	}
+	if err := os.WriteFile(*out, byt, 0o644); err != nil { //nolint:gosec // badges are published
+		return fmt.Errorf("write badge: %w", err)
+	}
+	return nil
+}
+
+// newBadge counts the metric over the last days and labels the result.
+func newBadge(events []ghEvent, metricName string, days int, now time.Time) (badge, error) {
+	if days <= 0 {
+		return badge{}, fmt.Errorf("invalid window: %d days", days)
+	}
+	n, err := countMetric(events, metricName, now.AddDate(0, 0, -days))
+	if err != nil {
+		return badge{}, err
+	}
+	label := fmt.Sprintf("%s in %d days", metricName, days)
+	if days == 7 {
+		label = metricName + " this week"
+	}
+	return badge{SchemaVersion: 1, Label: label, Message: strconv.Itoa(n), Color: scaleColor(n)}, nil
+}
+
+// scaleColor grades a count from grey (idle) to bright green (busy).
+func scaleColor(n int) string {
+	switch {
+	case n == 0:
+		return "lightgrey"
+	case n < 5:
+		return "yellow"
+	case n < 20:
+		return "green"
+	default:
+		return "brightgreen"
+	}
+}
+
+// svg renders the badge in the shields "flat" style. Text widths are
+// approximated from the character count.
+func (b badge) svg() string {
+	const charWidth, padding = 7, 10
+	lw := len([]rune(b.Label))*charWidth + padding
+	mw := len([]rune(b.Message))*charWidth + padding
+	color, ok := badgeColors[b.Color]
+	if !ok {
+		color = b.Color
+	}
+	label, message := html.EscapeString(b.Label), html.EscapeString(b.Message)
+	return fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%[1]d" height="20" role="img" `+
+		`aria-label="%[4]s: %[5]s">`+
+		`<title>%[4]s: %[5]s</title>`+
+		`<rect width="%[2]d" height="20" fill="#555"/>`+
+		`<rect x="%[2]d" width="%[3]d" height="20" fill="%[6]s"/>`+
+		`<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">`+
+		`<text x="%[7]d" y="14">%[4]s</text><text x="%[8]d" y="14">%[5]s</text></g></svg>`,
+		lw+mw, lw, mw, label, message, html.EscapeString(color), lw/2, lw+mw/2)
+}
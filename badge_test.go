@@ -0,0 +1,63 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUnitNewBadge(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	events := []ghEvent{
+		{Type: "PushEvent", Payload: payload{Size: 3}, CreatedAt: now.AddDate(0, 0, -1)},
+		{Type: "PushEvent", Payload: payload{Size: 4}, CreatedAt: now.AddDate(0, 0, -6)},
+		{Type: "PushEvent", Payload: payload{Size: 9}, CreatedAt: now.AddDate(0, 0, -8)},
+		{Type: "WatchEvent", CreatedAt: now.AddDate(0, 0, -2)},
+	}
+	testCases := []struct {
+		name    string
+		metric  string
+		days    int
+		want    badge
+		wantErr bool
+	}{
+		{
+			name: "commits this week", metric: "commits", days: 7,
+			want: badge{SchemaVersion: 1, Label: "commits this week", Message: "7", Color: "green"},
+		},
+		{
+			name: "events over a custom window", metric: "events", days: 30,
+			want: badge{SchemaVersion: 1, Label: "events in 30 days", Message: "4", Color: "yellow"},
+		},
+		{
+			name: "no activity", metric: "issues", days: 7,
+			want: badge{SchemaVersion: 1, Label: "issues this week", Message: "0", Color: "lightgrey"},
+		},
+		{name: "unknown metric", metric: "lines", days: 7, wantErr: true},
+		{name: "invalid window", metric: "commits", days: 0, wantErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			got, err := newBadge(events, tc.metric, tc.days, now)
+			// Assert
+			if tc.wantErr {
+				assertNotNil(t, err)
+				return
+			}
+			assertNoError(t, err)
+			assertEqual(t, got, tc.want)
+		})
+	}
+}
+
+func TestUnitBadgeSVG(t *testing.T) {
+	// Arrange
+	b := badge{SchemaVersion: 1, Label: "a<b", Message: "37", Color: "brightgreen"}
+	// Act
+	got := b.svg()
+	// Assert
+	assertEqual(t, strings.HasPrefix(got, "<svg "), true)
+	assertEqual(t, strings.Contains(got, "a&lt;b: 37"), true)
+	assertEqual(t, strings.Contains(got, `fill="#4c1"`), true)
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// The fetch pipeline decodes, filters, aggregates and renders events. Its
+// performance budget for the 10k-event corpus, on one core, is
+//
+//	decode JSON      50 ms
+//	filter            3 ms
+//	aggregate        30 ms
+//	shard totals      4 ms
+//	render text      20 ms
+//	render JSON      60 ms
+//
+// about twice the results of the first measurement. Run the benchmarks with
+// make benchmark; a change should not push a result above its budget.
+
+// benchCorpusSize is the number of events of the benchmark corpus.
+const benchCorpusSize = 10_000
+
+// benchCorpus returns a deterministic corpus of n events over 200 days,
+// newest first, mixing the event types in realistic proportions.
+func benchCorpus(n int) []ghEvent {
+	rng := rand.New(rand.NewPCG(1, 2))
+	types := []string{
+		"PushEvent", "PushEvent", "PushEvent", "PushEvent", "PullRequestEvent", "IssuesEvent",
+		"IssueCommentEvent", "WatchEvent", "CreateEvent", "PullRequestReviewEvent",
+	}
+	kinds := []string{"feat", "fix", "chore", "docs", "Update"}
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	events := make([]ghEvent, n)
+	for i := range events {
+		ev := ghEvent{
+			ID:        fmt.Sprint(i),
+			Type:      types[rng.IntN(len(types))],
+			Actor:     actor{Login: "octocat"},
+			Repo:      repo{Name: fmt.Sprintf("org%d/repo%d", rng.IntN(5), rng.IntN(50))},
+			CreatedAt: start.Add(time.Duration(n-i) * 200 * 24 * time.Hour / time.Duration(n)),
+		}
+		switch ev.Type {
+		case "PushEvent":
+			for j := range 1 + rng.IntN(4) {
+				ev.Payload.Commits = append(ev.Payload.Commits, commit{
+					SHA:     fmt.Sprintf("%040x", i*10+j),
+					Message: fmt.Sprintf("%s: change %d\n\nCo-authored-by: Mona <mona@example.com>", kinds[rng.IntN(5)], j),
+				})
+			}
+			ev.Payload.Size = len(ev.Payload.Commits)
+		case "PullRequestEvent":
+			ev.Payload.Action = "opened"
+			ev.Payload.PullRequest = &pullRequest{Number: i, Title: "Fix ABC-12 in the parser"}
+		case "IssuesEvent", "IssueCommentEvent":
+			ev.Payload.Action = "opened"
+			ev.Payload.Issue = &issue{Number: i, Title: "Broken footer", State: "open"}
+		}
+		events[i] = ev
+	}
+	return events
+}
+
+func BenchmarkDecodeEvents(b *testing.B) {
+	data, err := json.Marshal(benchCorpus(benchCorpusSize))
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		var events []ghEvent
+		if err := json.Unmarshal(data, &events); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkFilterEvents(b *testing.B) {
+	events := benchCorpus(benchCorpusSize)
+	filters := []eventFilter{onlyOwners([]string{"org1", "org2"}), onlyCommitKinds([]string{"fix"})}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		applyFilters(events, filters...)
+	}
+}
+
+func BenchmarkAggregateEvents(b *testing.B) {
+	events := benchCorpus(benchCorpusSize)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		if err := writeTotals(io.Discard, sumMetrics(events)); err != nil {
+			b.Fatal(err)
+		}
+		countBy(events, func(ev ghEvent) string { return ev.Repo.Name })
+		clusterSessions(events, 2*time.Hour)
+	}
+}
+
+func BenchmarkAggregateShards(b *testing.B) {
+	events := benchCorpus(benchCorpusSize)
+	for _, workers := range []int{1, max(2, runtime.NumCPU())} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			b.ReportAllocs()
+			for range b.N {
+				aggregateShards(events, byMonth, workers)
+			}
+		})
+	}
+}
+
+func BenchmarkRenderText(b *testing.B) {
+	events := benchCorpus(benchCorpusSize)
+	r := textRenderer{cat: lookupCatalog("en"), icons: loadIcons(true, nil), tickets: newTicketMatcher("", nil)}
+	var buf bytes.Buffer
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		buf.Reset()
+		if err := r.render(&buf, events); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkRenderJSON(b *testing.B) {
+	events := benchCorpus(benchCorpusSize)
+	r := jsonRenderer{cat: lookupCatalog("en"), tickets: newTicketMatcher("", nil)}
+	var buf bytes.Buffer
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		buf.Reset()
+		if err := r.render(&buf, events); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+type (
+	// bitbucketProvider fetches the pull requests of a Bitbucket Cloud
+	// account and its commits in some repositories, as Bitbucket has no
+	// events API.
+	bitbucketProvider struct {
+		hc    *client
+		base  string
+		repos []string
+	}
+	// bitbucketPage is a page of a Bitbucket collection.
+	bitbucketPage[T any] struct {
+		Values []T `json:"values"`
+	}
+	// bitbucketUser is a Bitbucket account.
+	bitbucketUser struct {
+		Nickname    string `json:"nickname"`
+		DisplayName string `json:"display_name"`
+		Links       struct {
+			HTML   bitbucketLink `json:"html"`
+			Avatar bitbucketLink `json:"avatar"`
+		} `json:"links"`
+	}
+	// bitbucketLink is a hypermedia link.
+	bitbucketLink struct {
+		Href string `json:"href"`
+	}
+	// bitbucketRepo is the repository of a pull request or commit.
+	bitbucketRepo struct {
+		FullName string `json:"full_name"`
+		Links    struct {
+			HTML bitbucketLink `json:"html"`
+		} `json:"links"`
+	}
+	// bitbucketPullRequest is a pull request of the Bitbucket API.
+	bitbucketPullRequest struct {
+		ID          int           `json:"id"`
+		Title       string        `json:"title"`
+		State       string        `json:"state"`
+		Author      bitbucketUser `json:"author"`
+		Destination struct {
+			Repository bitbucketRepo `json:"repository"`
+		} `json:"destination"`
+		Links struct {
+			HTML bitbucketLink `json:"html"`
+		} `json:"links"`
+		CreatedOn time.Time `json:"created_on"`
+		UpdatedOn time.Time `json:"updated_on"`
+	}
+	// bitbucketCommit is a commit of the Bitbucket API.
+	bitbucketCommit struct {
+		Hash    string    `json:"hash"`
+		Message string    `json:"message"`
+		Date    time.Time `json:"date"`
+		Author  struct {
+			Raw  string         `json:"raw"`
+			User *bitbucketUser `json:"user"`
+		} `json:"author"`
+		Repository bitbucketRepo `json:"repository"`
+		Links      struct {
+			HTML bitbucketLink `json:"html"`
+		} `json:"links"`
+	}
+)
+
+// defaultBitbucketURL is the Bitbucket Cloud API.
+const defaultBitbucketURL = "https://api.bitbucket.org/2.0"
+
+// newBitbucketProvider returns the provider of Bitbucket Cloud.
+func newBitbucketProvider(base, token string, repos []string) *bitbucketProvider {
+	return &bitbucketProvider{hc: newClient(token), base: strings.TrimSuffix(base, "/"), repos: repos}
+}
+
+func (p *bitbucketProvider) name() string { return "bitbucket" }
+
+func (p *bitbucketProvider) events(login string) ([]ghEvent, error) {
+	var prs bitbucketPage[bitbucketPullRequest]
+	u := fmt.Sprintf("%s/pullrequests/%s?state=OPEN&state=MERGED&state=DECLINED&pagelen=50",
+		p.base, url.PathEscape(login))
+	if err := fetchJSON(p.hc, u, &prs); err != nil {
+		return nil, fmt.Errorf("fetch Bitbucket pull requests: %w", err)
+	}
+	var events []ghEvent
+	for _, pr := range prs.Values {
+		events = append(events, pr.events()...)
+	}
+	for _, name := range p.repos {
+		var commits bitbucketPage[bitbucketCommit]
+		u := fmt.Sprintf("%s/repositories/%s/commits?pagelen=50", p.base, strings.Trim(name, "/"))
+		if err := fetchJSON(p.hc, u, &commits); err != nil {
+			return nil, fmt.Errorf("fetch Bitbucket commits of %s: %w", name, err)
+		}
+		for _, c := range commits.Values {
+			if c.Author.User != nil && strings.EqualFold(c.Author.User.Nickname, login) {
+				events = append(events, c.event())
+			}
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].CreatedAt.After(events[j].CreatedAt) })
+	return events, nil
+}
+
+// events returns the opening of the pull request and, once merged or
+// declined, its closing, as GitHub pull request events.
+func (pr bitbucketPullRequest) events() []ghEvent {
+	base := ghEvent{
+		Actor: pr.Author.actor(),
+		Repo: repo{
+			Name: pr.Destination.Repository.FullName, URL: pr.Destination.Repository.Links.HTML.Href,
+		},
+		Type: "PullRequestEvent",
+	}
+	ref := &pullRequest{Number: pr.ID, Title: pr.Title, HTMLURL: pr.Links.HTML.Href, State: "open"}
+	opened := base
+	opened.ID = fmt.Sprintf("bitbucket-%s-%d-opened", pr.Destination.Repository.FullName, pr.ID)
+	opened.Payload = payload{Action: "opened", PullRequest: ref}
+	opened.CreatedAt = pr.CreatedOn
+	if pr.State != "MERGED" && pr.State != "DECLINED" {
+		return []ghEvent{opened}
+	}
+	closed := base
+	closed.ID = fmt.Sprintf("bitbucket-%s-%d-closed", pr.Destination.Repository.FullName, pr.ID)
+	closedRef := *ref
+	closedRef.State, closedRef.Merged = "closed", pr.State == "MERGED"
+	closed.Payload = payload{Action: "closed", PullRequest: &closedRef}
+	closed.CreatedAt = pr.UpdatedOn
+	return []ghEvent{closed, opened}
+}
+
+// event returns a single-commit push event, with the ID of the commit
+// events of the search API so that a commit mirrored on GitHub counts once.
+func (c bitbucketCommit) event() ghEvent {
+	var who actor
+	if c.Author.User != nil {
+		who = c.Author.User.actor()
+	}
+	var a author
+	if addr, err := mail.ParseAddress(c.Author.Raw); err == nil {
+		a = author{Name: addr.Name, Email: addr.Address}
+	}
+	return ghEvent{
+		ID: "commit-" + c.Hash, Type: "PushEvent", Actor: who,
+		Repo: repo{Name: c.Repository.FullName, URL: c.Repository.Links.HTML.Href},
+		Payload: payload{Size: 1, Commits: []commit{{
+			SHA: c.Hash, Message: c.Message, URL: c.Links.HTML.Href, Distinct: true, Author: a,
+		}}},
+		CreatedAt: c.Date,
+	}
+}
+
+// actor returns the Bitbucket account as a GitHub actor.
+func (u bitbucketUser) actor() actor {
+	return actor{Login: u.Nickname, DisplayLogin: u.DisplayName, URL: u.Links.HTML.Href, AvatarURL: u.Links.Avatar.Href}
+}
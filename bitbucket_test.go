@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestUnitBitbucketPullRequestEvents(t *testing.T) {
+	created := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name       string
+		state      string
+		wantEvents int
+		wantMerged bool
+	}{
+		{name: "open", state: "OPEN", wantEvents: 1},
+		{name: "merged", state: "MERGED", wantEvents: 2, wantMerged: true},
+		{name: "declined", state: "DECLINED", wantEvents: 2},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			pr := bitbucketPullRequest{ID: 4, Title: "Add feature", State: tc.state, CreatedOn: created,
+				UpdatedOn: created.Add(time.Hour)}
+			pr.Destination.Repository.FullName = "team/app"
+			// Act
+			got := pr.events()
+			// Assert
+			assertEqual(t, len(got), tc.wantEvents)
+			opened := got[len(got)-1]
+			assertEqual(t, opened.ID, "bitbucket-team/app-4-opened")
+			assertEqual(t, opened.Payload.Action, "opened")
+			assertEqual(t, opened.CreatedAt, created)
+			if tc.wantEvents == 2 {
+				assertEqual(t, got[0].Payload.Action, "closed")
+				assertEqual(t, got[0].Payload.PullRequest.Merged, tc.wantMerged)
+				assertEqual(t, got[0].CreatedAt, created.Add(time.Hour))
+			}
+		})
+	}
+}
+
+func TestUnitBitbucketProviderEvents(t *testing.T) {
+	// Arrange
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assertEqual(t, r.Header.Get("Authorization"), "Bearer bbtoken")
+		switch r.URL.Path {
+		case "/pullrequests/octocat":
+			fmt.Fprint(w, `{"values":[{"id":1,"title":"Fix","state":"OPEN","created_on":"2025-03-01T00:00:00Z",
+				"author":{"nickname":"octocat"},"destination":{"repository":{"full_name":"team/app"}}}]}`)
+		case "/repositories/team/app/commits":
+			fmt.Fprint(w, `{"values":[
+				{"hash":"abc","message":"Fix crash","date":"2025-03-02T00:00:00Z",
+				 "author":{"raw":"Octo Cat <octo@example.com>","user":{"nickname":"OctoCat"}},
+				 "repository":{"full_name":"team/app"}},
+				{"hash":"def","message":"Other","date":"2025-03-03T00:00:00Z",
+				 "author":{"raw":"Mona <mona@example.com>","user":{"nickname":"mona"}}}
+			]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	p := newBitbucketProvider(srv.URL, "bbtoken", []string{"team/app"})
+	// Act
+	events, err := p.events("octocat")
+	// Assert
+	assertNoError(t, err)
+	assertEqual(t, len(events), 2)
+	assertEqual(t, events[0].ID, "commit-abc")
+	assertEqual(t, events[0].Payload.Commits[0].Author.Email, "octo@example.com")
+	assertEqual(t, events[0].Repo.Name, "team/app")
+	assertEqual(t, events[1].Type, "PullRequestEvent")
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type (
+	// fileCache stores API responses as JSON files that expire after ttl.
+	fileCache struct {
+		dir   string
+		ttl   time.Duration
+		clock clock
+	}
+	// cacheEntry is the on-disk envelope of a cached value.
+	cacheEntry struct {
+		FetchedAt time.Time       `json:"fetched_at"`
+		Data      json.RawMessage `json:"data"`
+	}
+)
+
+// cacheKeyReplacer turns API paths into flat file names.
+var cacheKeyReplacer = strings.NewReplacer("/", "_", "\\", "_", ":", "_", "..", "_")
+
+// newFileCache returns a cache rooted in the cache directory of the app.
+func newFileCache(ttl time.Duration) (*fileCache, error) {
+	dir, err := appDir(&defaultUserHome{})
+	if err != nil {
+		return nil, err
+	}
+	return &fileCache{dir: filepath.Join(dir, "cache"), ttl: ttl, clock: systemClock{}}, nil
+}
+
+func (c *fileCache) path(key string) string {
+	return filepath.Join(c.dir, cacheKeyReplacer.Replace(key)+".json")
+}
+
+// get decodes a fresh cached value into v and reports whether it was found.
+func (c *fileCache) get(key string, v any) bool {
+	byt, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return false
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(byt, &entry); err != nil {
+		return false
+	}
+	if c.clock.Now().Sub(entry.FetchedAt) > c.ttl || json.Unmarshal(entry.Data, v) != nil {
+		return false
+	}
+	logUsage(usageRecord{Time: c.clock.Now().UTC(), Endpoint: "/" + key, Cache: true})
+	return true
+}
+
+// put stores v under key.
+func (c *fileCache) put(key string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("encode cache entry: %w", err)
+	}
+	byt, err := json.Marshal(cacheEntry{FetchedAt: c.clock.Now(), Data: data})
+	if err != nil {
+		return fmt.Errorf("encode cache entry: %w", err)
+	}
+	if err := os.MkdirAll(c.dir, 0o700); err != nil {
+		return fmt.Errorf("create cache directory: %w", err)
+	}
+	if err := os.WriteFile(c.path(key), byt, 0o600); err != nil {
+		return fmt.Errorf("write cache entry: %w", err)
+	}
+	return nil
+}
+
+// cached returns the cached value for key, or calls fetch and caches it.
+func cached[T any](c *fileCache, key string, fetch func() (T, error)) (T, error) {
+	var v T
+	if c != nil && c.get(key, &v) {
+		return v, nil
+	}
+	v, err := fetch()
+	if err != nil {
+		return v, err
+	}
+	if c != nil {
+		if err := c.put(key, v); err != nil {
+			return v, err
+		}
+	}
+	return v, nil
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestUnitFileCache(t *testing.T) {
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name    string
+		elapsed time.Duration
+		wantHit bool
+	}{
+		{name: "fresh entry", elapsed: time.Hour, wantHit: true},
+		{name: "expired entry", elapsed: 25 * time.Hour, wantHit: false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			clk := &fakeClock{now: now}
+			c := &fileCache{dir: t.TempDir(), ttl: 24 * time.Hour, clock: clk}
+			c.put("repos/octo/repo", map[string]int{"Go": 1})
+			clk.advance(tc.elapsed)
+			// Act
+			var got map[string]int
+			hit := c.get("repos/octo/repo", &got)
+			// Assert
+			assertEqual(t, hit, tc.wantHit)
+			if tc.wantHit {
+				assertEqual(t, got["Go"], 1)
+			}
+		})
+	}
+}
+
+func TestUnitCached(t *testing.T) {
+	// Arrange
+	c := &fileCache{dir: t.TempDir(), ttl: time.Hour, clock: systemClock{}}
+	calls := 0
+	fetch := func() (string, error) {
+		calls++
+		return "value", nil
+	}
+	// Act
+	first, err := cached(c, "key", fetch)
+	second, _ := cached(c, "key", fetch)
+	_, failErr := cached(c, "other", func() (string, error) { return "", errors.New("boom") })
+	// Assert
+	assertNoError(t, err)
+	assertEqual(t, first, "value")
+	assertEqual(t, second, "value")
+	assertEqual(t, calls, 1)
+	assertNotNil(t, failErr)
+}
@@ -0,0 +1,282 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+type (
+	// chaosFault is a failure injected into a request by chaosTransport.
+	chaosFault int
+	// chaosTransport injects its faults into the requests in turn, then lets
+	// the following requests through, counting them all.
+	chaosTransport struct {
+		next    http.RoundTripper
+		faults  []chaosFault
+		latency time.Duration
+		mu      sync.Mutex
+		calls   int
+	}
+	// truncatedBody serves the start of a body, then fails as a connection
+	// closed before its end.
+	truncatedBody struct {
+		r io.Reader
+	}
+)
+
+const (
+	chaosNone chaosFault = iota
+	// chaosLatency delays the response by the latency of the transport.
+	chaosLatency
+	// chaosTruncate cuts the body in half.
+	chaosTruncate
+	// chaosReset resets the connection before the response.
+	chaosReset
+	// chaosReorder reverses the items of a page.
+	chaosReorder
+)
+
+func (t *chaosTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	fault := chaosNone
+	if t.calls < len(t.faults) {
+		fault = t.faults[t.calls]
+	}
+	t.calls++
+	t.mu.Unlock()
+	switch fault {
+	case chaosReset:
+		return nil, &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}
+	case chaosLatency:
+		select {
+		case <-time.After(t.latency):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+	res, err := t.next.RoundTrip(req)
+	if err != nil || (fault != chaosTruncate && fault != chaosReorder) {
+		return res, err
+	}
+	byt, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	if fault == chaosTruncate {
+		res.Body = truncatedBody{r: bytes.NewReader(byt[:len(byt)/2])}
+		return res, nil
+	}
+	var items []json.RawMessage
+	if err := json.Unmarshal(byt, &items); err != nil {
+		return nil, err
+	}
+	slices.Reverse(items)
+	if byt, err = json.Marshal(items); err != nil {
+		return nil, err
+	}
+	res.Body, res.ContentLength = io.NopCloser(bytes.NewReader(byt)), int64(len(byt))
+	res.Header.Del("Content-Length")
+	return res, nil
+}
+
+func (b truncatedBody) Read(p []byte) (int, error) {
+	n, err := b.r.Read(p)
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return n, err
+}
+
+func (truncatedBody) Close() error { return nil }
+
+// chaosClient returns a client going through the transport, retrying on a
+// fake clock.
+func chaosClient(t *chaosTransport) *client {
+	hc := newClient("")
+	hc.Client.Transport = t
+	hc.clock = &fakeClock{now: time.Now()}
+	return hc
+}
+
+// eventServer serves the events returned by events, newest first.
+func eventServer(t *testing.T, events func() []ghEvent) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		json.NewEncoder(w).Encode(events())
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// chaosEvents returns n events, newest first, one minute apart.
+func chaosEvents(n int) []ghEvent {
+	start := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	events := make([]ghEvent, n)
+	for i := range events {
+		at := start.Add(time.Duration(i) * time.Minute)
+		events[n-1-i] = ghEvent{ID: fmt.Sprint(i + 1), Type: "WatchEvent", CreatedAt: at}
+	}
+	return events
+}
+
+// eventIDs lists the IDs of the events.
+func eventIDs(events []ghEvent) []string {
+	ids := make([]string, 0, len(events))
+	for _, ev := range events {
+		ids = append(ids, ev.ID)
+	}
+	return ids
+}
+
+func TestUnitChaosClient(t *testing.T) {
+	testCases := []struct {
+		name      string
+		faults    []chaosFault
+		timeout   time.Duration
+		want      string
+		wantCalls int
+		wantCode  int
+	}{
+		{name: "latency", faults: []chaosFault{chaosLatency}, want: "[3 2 1]", wantCalls: 1},
+		{name: "truncated body", faults: []chaosFault{chaosTruncate}, want: "[3 2 1]", wantCalls: 2},
+		{name: "connection reset", faults: []chaosFault{chaosReset, chaosReset}, want: "[3 2 1]", wantCalls: 3},
+		{name: "out of order page", faults: []chaosFault{chaosReorder}, want: "[1 2 3]", wantCalls: 1},
+		{
+			name:      "mixed faults",
+			faults:    []chaosFault{chaosReset, chaosTruncate, chaosReorder},
+			want:      "[1 2 3]",
+			wantCalls: 3,
+		},
+		{
+			name:      "resets past the retries",
+			faults:    []chaosFault{chaosReset, chaosReset, chaosReset, chaosReset},
+			want:      "[]",
+			wantCalls: maxDrops + 1,
+			wantCode:  exitNetwork,
+		},
+		{
+			name:      "truncated past the retries",
+			faults:    []chaosFault{chaosTruncate, chaosTruncate, chaosTruncate, chaosTruncate},
+			want:      "[]",
+			wantCalls: maxDrops + 1,
+			wantCode:  exitFailure,
+		},
+		{
+			name:      "latency past the timeout",
+			faults:    []chaosFault{chaosLatency},
+			timeout:   10 * time.Millisecond,
+			want:      "[]",
+			wantCalls: 1,
+			wantCode:  exitNetwork,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			srv := eventServer(t, func() []ghEvent { return chaosEvents(3) })
+			tr := &chaosTransport{next: http.DefaultTransport, faults: tc.faults, latency: 50 * time.Millisecond}
+			hc := chaosClient(tr)
+			if tc.timeout > 0 {
+				hc.Client.Timeout = tc.timeout
+			}
+			// Act
+			events, err := fetchGitHubResponse(hc, eventsURL(srv.URL, "octocat"))
+			// Assert
+			assertEqual(t, exitCode(err), tc.wantCode)
+			assertEqual(t, fmt.Sprint(eventIDs(events)), tc.want)
+			assertEqual(t, tr.calls, tc.wantCalls)
+		})
+	}
+}
+
+func TestUnitChaosWatch(t *testing.T) {
+	// Arrange
+	var (
+		mu     sync.Mutex
+		served = chaosEvents(2)
+	)
+	srv := eventServer(t, func() []ghEvent {
+		mu.Lock()
+		defer mu.Unlock()
+		return served
+	})
+	tr := &chaosTransport{next: http.DefaultTransport, latency: 10 * time.Millisecond}
+	hc := chaosClient(tr)
+	p := &poller{fetch: func() ([]ghEvent, error) { return fetchGitHubResponse(hc, eventsURL(srv.URL, "octocat")) }}
+	polls := []struct {
+		events int
+		faults []chaosFault
+	}{
+		{events: 2, faults: []chaosFault{chaosLatency}},
+		{events: 5, faults: []chaosFault{chaosReset, chaosReorder}},
+		{events: 5, faults: []chaosFault{chaosTruncate, chaosTruncate}},
+		{events: 6, faults: []chaosFault{chaosReorder}},
+	}
+	var delivered []string
+	// Act
+	for _, poll := range polls {
+		mu.Lock()
+		served = chaosEvents(poll.events)
+		mu.Unlock()
+		tr.faults, tr.calls = poll.faults, 0
+		fresh, err := p.poll()
+		assertNoError(t, err)
+		delivered = append(delivered, eventIDs(fresh)...)
+	}
+	// Assert
+	assertEqual(t, fmt.Sprint(delivered), "[3 4 5 6]")
+}
+
+func TestUnitChaosSync(t *testing.T) {
+	// Arrange
+	var (
+		mu     sync.Mutex
+		served []ghEvent
+	)
+	srv := eventServer(t, func() []ghEvent {
+		mu.Lock()
+		defer mu.Unlock()
+		return served
+	})
+	tr := &chaosTransport{next: http.DefaultTransport}
+	hc := chaosClient(tr)
+	a := &archive{path: filepath.Join(t.TempDir(), "archive", "octocat.jsonl")}
+	syncs := []struct {
+		events int
+		faults []chaosFault
+	}{
+		{events: 3, faults: []chaosFault{chaosReorder}},
+		{events: 3, faults: []chaosFault{chaosTruncate, chaosReset}},
+		{events: 5, faults: []chaosFault{chaosReset, chaosReorder}},
+	}
+	var added []string
+	// Act
+	for _, s := range syncs {
+		mu.Lock()
+		served = chaosEvents(s.events)
+		mu.Unlock()
+		tr.faults, tr.calls = s.faults, 0
+		events, err := fetchGitHubResponse(hc, eventsURL(srv.URL, "octocat"))
+		assertNoError(t, err)
+		fresh, err := a.add(events)
+		assertNoError(t, err)
+		added = append(added, eventIDs(fresh)...)
+	}
+	archived, err := a.load()
+	// Assert
+	assertNoError(t, err)
+	assertEqual(t, fmt.Sprint(added), "[1 2 3 4 5]")
+	assertEqual(t, fmt.Sprint(eventIDs(archived)), "[5 4 3 2 1]")
+}
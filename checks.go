@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Check states of a pushed head, from its commit statuses and check runs.
+const (
+	checksSuccess = "success"
+	checksFailure = "failure"
+	checksPending = "pending"
+)
+
+// checksState returns the combined state of the commit statuses and check
+// runs of a commit, or "" when it has none. Final states are cached; pending
+// ones are fetched again on the next run.
+func (f *repoFetcher) checksState(name, sha string) (string, error) {
+	key := "checks/" + name + "/" + sha
+	var state string
+	if f.cache != nil && f.cache.get(key, &state) {
+		return state, nil
+	}
+	u, err := f.repoURL(name, "commits", sha, "status")
+	if err != nil {
+		return "", err
+	}
+	var status struct {
+		State    string `json:"state"`
+		Statuses []struct {
+			State string `json:"state"`
+		} `json:"statuses"`
+	}
+	if err := fetchJSON(f.hc, u, &status); err != nil {
+		return "", fmt.Errorf("fetch status of %.7s in %s: %w", sha, name, err)
+	}
+	if u, err = f.repoURL(name, "commits", sha, "check-runs?per_page=100"); err != nil {
+		return "", err
+	}
+	var runs struct {
+		CheckRuns []struct {
+			Status     string `json:"status"`
+			Conclusion string `json:"conclusion"`
+		} `json:"check_runs"`
+	}
+	if err := fetchJSON(f.hc, u, &runs); err != nil {
+		return "", fmt.Errorf("fetch check runs of %.7s in %s: %w", sha, name, err)
+	}
+	var states []string
+	if len(status.Statuses) > 0 {
+		states = append(states, status.State)
+	}
+	for _, run := range runs.CheckRuns {
+		if run.Status != "completed" {
+			states = append(states, checksPending)
+			continue
+		}
+		states = append(states, run.Conclusion)
+	}
+	state = combineChecks(states)
+	if f.cache != nil && state != checksPending {
+		if err := f.cache.put(key, state); err != nil {
+			return "", err
+		}
+	}
+	return state, nil
+}
+
+// combineChecks reduces statuses and check run conclusions to one state: a
+// failure wins over a pending check, which wins over successes. Neutral and
+// skipped checks count as successes.
+func combineChecks(states []string) string {
+	combined := ""
+	for _, s := range states {
+		switch s {
+		case "failure", "error", "timed_out", "cancelled", "action_required", "startup_failure":
+			return checksFailure
+		case checksPending, "queued", "in_progress", "waiting", "requested":
+			combined = checksPending
+		default:
+			if combined == "" {
+				combined = checksSuccess
+			}
+		}
+	}
+	return combined
+}
+
+// resolveChecks attaches the check state of its head commit to each push
+// event. Heads that no longer exist are left without one.
+func resolveChecks(events []ghEvent, checksOf func(repo, sha string) (string, error)) error {
+	for i := range events {
+		p := &events[i].Payload
+		if events[i].Type != "PushEvent" || p.Checks != "" {
+			continue
+		}
+		head := p.Head
+		if head == "" && len(p.Commits) > 0 {
+			head = p.Commits[len(p.Commits)-1].SHA
+		}
+		if head == "" {
+			continue
+		}
+		state, err := checksOf(events[i].Repo.Name, head)
+		switch {
+		case err == nil:
+			p.Checks = state
+		case !isStatus(err, http.StatusNotFound) && !isStatus(err, http.StatusUnprocessableEntity):
+			return err
+		}
+	}
+	return nil
+}
+
+// checksAnnotation marks a push line with the icon of its check state,
+// e.g. " ✅", or nothing when it was not checked.
+func checksAnnotation(icons iconSet, ev ghEvent) string {
+	if ev.Payload.Checks == "" {
+		return ""
+	}
+	return " " + icons.icon("checks:"+ev.Payload.Checks)
+}
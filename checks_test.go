@@ -0,0 +1,160 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestUnitCombineChecks(t *testing.T) {
+	testCases := []struct {
+		name   string
+		states []string
+		want   string
+	}{
+		{name: "no checks", want: ""},
+		{name: "green", states: []string{"success", "neutral", "skipped"}, want: checksSuccess},
+		{name: "pending", states: []string{"success", "pending"}, want: checksPending},
+		{name: "failed", states: []string{"pending", "success", "timed_out"}, want: checksFailure},
+		{name: "status error", states: []string{"error"}, want: checksFailure},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			got := combineChecks(tc.states)
+			// Assert
+			assertEqual(t, got, tc.want)
+		})
+	}
+}
+
+func TestUnitRepoFetcherChecksState(t *testing.T) {
+	testCases := []struct {
+		name      string
+		status    string
+		runs      string
+		want      string
+		wantCalls int
+	}{
+		{
+			name:   "final state cached",
+			status: `{"state":"pending","statuses":[]}`, runs: `{"check_runs":[{"status":"completed","conclusion":"success"}]}`,
+			want: checksSuccess, wantCalls: 2,
+		},
+		{
+			name:   "pending state fetched again",
+			status: `{"state":"pending","statuses":[{"state":"pending"}]}`, runs: `{"check_runs":[]}`,
+			want: checksPending, wantCalls: 4,
+		},
+		{
+			name:   "failing check run",
+			status: `{"state":"success","statuses":[{"state":"success"}]}`,
+			runs:   `{"check_runs":[{"status":"completed","conclusion":"failure"}]}`,
+			want:   checksFailure, wantCalls: 2,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			calls := 0
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				switch r.URL.Path {
+				case "/repos/octo/api/commits/abc/status":
+					w.Write([]byte(tc.status))
+				case "/repos/octo/api/commits/abc/check-runs":
+					w.Write([]byte(tc.runs))
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+			t.Cleanup(srv.Close)
+			cache := &fileCache{dir: t.TempDir(), ttl: time.Hour, clock: systemClock{}}
+			f := &repoFetcher{hc: newClient(""), base: srv.URL, cache: cache}
+			// Act
+			got, err := f.checksState("octo/api", "abc")
+			again, _ := f.checksState("octo/api", "abc")
+			// Assert
+			assertNoError(t, err)
+			assertEqual(t, got, tc.want)
+			assertEqual(t, again, tc.want)
+			assertEqual(t, calls, tc.wantCalls)
+		})
+	}
+}
+
+func TestUnitResolveChecks(t *testing.T) {
+	testCases := []struct {
+		name      string
+		ev        ghEvent
+		err       error
+		wantSHA   string
+		wantState string
+		wantErr   bool
+	}{
+		{
+			name:    "push head",
+			ev:      ghEvent{Type: "PushEvent", Payload: payload{Head: "h1", Commits: []commit{{SHA: "c1"}}}},
+			wantSHA: "h1", wantState: checksFailure,
+		},
+		{
+			name:    "last commit without head",
+			ev:      ghEvent{Type: "PushEvent", Payload: payload{Commits: []commit{{SHA: "c1"}, {SHA: "c2"}}}},
+			wantSHA: "c2", wantState: checksFailure,
+		},
+		{name: "not a push", ev: ghEvent{Type: "WatchEvent"}},
+		{
+			name: "deleted head",
+			ev:   ghEvent{Type: "PushEvent", Payload: payload{Head: "h1"}},
+			err:  &apiError{StatusCode: http.StatusNotFound}, wantSHA: "h1",
+		},
+		{
+			name: "server error",
+			ev:   ghEvent{Type: "PushEvent", Payload: payload{Head: "h1"}},
+			err:  errors.New("boom"), wantSHA: "h1", wantErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			var asked string
+			events := []ghEvent{tc.ev}
+			checksOf := func(_, sha string) (string, error) {
+				asked = sha
+				return checksFailure, tc.err
+			}
+			// Act
+			err := resolveChecks(events, checksOf)
+			// Assert
+			if tc.wantErr {
+				assertNotNil(t, err)
+				return
+			}
+			assertNoError(t, err)
+			assertEqual(t, asked, tc.wantSHA)
+			assertEqual(t, events[0].Payload.Checks, tc.wantState)
+		})
+	}
+}
+
+func TestUnitChecksAnnotation(t *testing.T) {
+	testCases := []struct {
+		name  string
+		state string
+		want  string
+	}{
+		{name: "unchecked", want: ""},
+		{name: "green", state: checksSuccess, want: " [green]"},
+		{name: "red", state: checksFailure, want: " [red]"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			got := checksAnnotation(asciiIcons, ghEvent{Payload: payload{Checks: tc.state}})
+			// Assert
+			assertEqual(t, got, tc.want)
+		})
+	}
+}
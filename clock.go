@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+	"time"
+)
+
+type (
+	// clock tells the time and waits for durations to pass. The code waiting
+	// on the time takes one, so that tests drive it without sleeping.
+	clock interface {
+		Now() time.Time
+		// After sends the time on the returned channel once d has passed.
+		After(d time.Duration) <-chan time.Time
+	}
+	// systemClock is the wall clock.
+	systemClock struct{}
+)
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// sleepContext waits for d on the clock and reports false if ctx ended
+// first.
+func sleepContext(ctx context.Context, c clock, d time.Duration) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-c.After(d):
+		return true
+	}
+}
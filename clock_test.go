@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock is a clock whose time only moves when waited on or advanced:
+// After moves it forward by the duration and fires at once, so that the
+// code under test never sleeps. It records the waits; with a limit, the
+// wait reaching it cancels the context of the test and never fires.
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	waits  []time.Duration
+	limit  int
+	cancel context.CancelFunc
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.waits = append(c.waits, d)
+	if c.limit > 0 && len(c.waits) >= c.limit {
+		c.cancel()
+		return nil
+	}
+	c.now = c.now.Add(d)
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+// advance moves the clock forward by d without waiting.
+func (c *fakeClock) advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func TestUnitSleepContext(t *testing.T) {
+	start := time.Date(2025, 3, 3, 23, 59, 0, 0, time.UTC)
+	testCases := []struct {
+		name     string
+		canceled bool
+		want     bool
+		wantNow  time.Time
+	}{
+		{name: "elapsed", want: true, wantNow: start.Add(2 * time.Minute)},
+		{name: "canceled", canceled: true, want: false, wantNow: start},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			clk := &fakeClock{now: start}
+			if tc.canceled {
+				clk.limit, clk.cancel = 1, cancel
+			}
+			// Act
+			got := sleepContext(ctx, clk, 2*time.Minute)
+			// Assert
+			assertEqual(t, got, tc.want)
+			assertEqual(t, clk.Now(), tc.wantNow)
+		})
+	}
+}
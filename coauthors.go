@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// coAuthorTrailer matches a "Co-authored-by: Name <email>" trailer line.
+var coAuthorTrailer = regexp.MustCompile(`(?mi)^co-authored-by:[ \t]*(.*?)[ \t]*<([^>\n]+)>[ \t]*$`)
+
+// coAuthors returns the distinct co-authors credited in a commit message,
+// identified by their e-mail address.
+func coAuthors(message string) []author {
+	var found []author
+	seen := map[string]bool{}
+	for _, m := range coAuthorTrailer.FindAllStringSubmatch(message, -1) {
+		email := strings.ToLower(m[2])
+		if seen[email] {
+			continue
+		}
+		seen[email] = true
+		found = append(found, author{Name: m[1], Email: email})
+	}
+	return found
+}
+
+// collaboratorsView prints how often commits were paired and the top
+// co-authors. For a person of the people setting, trailers crediting one of
+// their own emails are not pairing.
+func collaboratorsView(w io.Writer, events []ghEvent, opts statsOptions) error {
+	own, err := ownEmails(opts.user)
+	if err != nil {
+		return err
+	}
+	var commits, paired int
+	counts := map[string]int{}
+	names := map[string]string{}
+	for _, ev := range events {
+		if ev.Type != "PushEvent" {
+			continue
+		}
+		for _, c := range ev.Payload.Commits {
+			commits++
+			var authors []author
+			for _, a := range coAuthors(c.Message) {
+				if !own[a.Email] {
+					authors = append(authors, a)
+				}
+			}
+			paired += boolToInt(len(authors) > 0)
+			for _, a := range authors {
+				counts[a.Email]++
+				if a.Name != "" {
+					names[a.Email] = a.Name
+				}
+			}
+		}
+	}
+	if commits == 0 {
+		_, err := fmt.Fprintln(w, "no commits")
+		return err
+	}
+	share := float64(paired) * 100 / float64(commits)
+	if _, err := fmt.Fprintf(w, "paired commits: %d/%d (%.1f%%)\n", paired, commits, share); err != nil {
+		return err
+	}
+	emails := sortedKeys(counts)
+	sort.SliceStable(emails, func(i, j int) bool { return counts[emails[i]] > counts[emails[j]] })
+	for _, email := range emails {
+		name := names[email]
+		if name == "" {
+			name = email
+		}
+		if _, err := fmt.Fprintf(w, "%-30s %5d\n", name, counts[email]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
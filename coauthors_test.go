@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestUnitCoAuthors(t *testing.T) {
+	testCases := []struct {
+		name    string
+		message string
+		want    []author
+	}{
+		{name: "no trailer", message: "Fix login", want: nil},
+		{
+			name:    "trailers",
+			message: "Fix login\n\nCo-authored-by: Mona Lisa <Mona@example.com>\nco-authored-by:Hubot <hubot@example.com>",
+			want:    []author{{Name: "Mona Lisa", Email: "mona@example.com"}, {Name: "Hubot", Email: "hubot@example.com"}},
+		},
+		{
+			name:    "duplicate",
+			message: "Co-authored-by: Mona <mona@example.com>\nCo-authored-by: Mona Lisa <mona@example.com>",
+			want:    []author{{Name: "Mona", Email: "mona@example.com"}},
+		},
+		{name: "in body text", message: "Mention Co-authored-by: Mona <mona@example.com> inline", want: nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			got := coAuthors(tc.message)
+			// Assert
+			assertEqual(t, len(got), len(tc.want))
+			for i := range tc.want {
+				assertEqual(t, got[i], tc.want[i])
+			}
+		})
+	}
+}
+
+func TestUnitCollaboratorsView(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+	events := []ghEvent{
+		{Type: "PushEvent", Payload: payload{Commits: []commit{
+			{Message: "a\n\nCo-authored-by: Mona <mona@example.com>"},
+			{Message: "b\n\nCo-authored-by: Hubot <hubot@example.com>\nCo-authored-by: Mona <mona@example.com>"},
+			{Message: "c"},
+			{Message: "d"},
+		}}},
+	}
+	// Act
+	err := collaboratorsView(&buf, events, statsOptions{})
+	// Assert
+	assertNoError(t, err)
+	assertEqual(t, buf.String(), "paired commits: 2/4 (50.0%)\n"+
+		"Mona                               2\n"+
+		"Hubot                              1\n")
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// otherKind classifies the commit messages that do not follow conventional
+// commits.
+const otherKind = "other"
+
+// conventionalHeader matches the "type(scope)!: subject" header of a
+// conventional commit.
+var conventionalHeader = regexp.MustCompile(`^([A-Za-z]+)(?:\([^)]*\))?!?: \S`)
+
+// commitKinds lists the conventional commit types recognized.
+var commitKinds = map[string]bool{
+	"feat": true, "fix": true, "chore": true, "docs": true, "refactor": true, "test": true,
+	"style": true, "perf": true, "build": true, "ci": true, "revert": true,
+}
+
+// commitKind returns the conventional commit type of a message, e.g. "fix"
+// for "fix(api): handle timeouts", or "other" for non-conforming messages.
+func commitKind(message string) string {
+	m := conventionalHeader.FindStringSubmatch(strings.TrimSpace(message))
+	if m == nil {
+		return otherKind
+	}
+	if kind := strings.ToLower(m[1]); commitKinds[kind] {
+		return kind
+	}
+	return otherKind
+}
+
+// onlyCommitKinds keeps push events with at least one commit of the given
+// kinds.
+func onlyCommitKinds(kinds []string) eventFilter {
+	set := make(map[string]bool, len(kinds))
+	for _, k := range kinds {
+		set[strings.ToLower(strings.TrimSpace(k))] = true
+	}
+	return func(ev ghEvent) bool {
+		for _, c := range ev.Payload.Commits {
+			if ev.Type == "PushEvent" && set[commitKind(c.Message)] {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// commitKindsView prints the number and share of pushed commits per kind.
+func commitKindsView(w io.Writer, events []ghEvent, _ statsOptions) error {
+	counts := map[string]int{}
+	total := 0
+	for _, ev := range events {
+		if ev.Type != "PushEvent" {
+			continue
+		}
+		for _, c := range ev.Payload.Commits {
+			counts[commitKind(c.Message)]++
+			total++
+		}
+	}
+	kinds := sortedKeys(counts)
+	sort.SliceStable(kinds, func(i, j int) bool { return counts[kinds[i]] > counts[kinds[j]] })
+	for _, kind := range kinds {
+		share := float64(counts[kind]) * 100 / float64(total)
+		if _, err := fmt.Fprintf(w, "%-10s %5d %5.1f%%\n", kind, counts[kind], share); err != nil {
+			return err
+		}
+	}
+	return nil
+}
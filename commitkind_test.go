@@ -0,0 +1,63 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestUnitCommitKind(t *testing.T) {
+	testCases := []struct {
+		message string
+		want    string
+	}{
+		{message: "feat: add badges", want: "feat"},
+		{message: "fix(api): handle timeouts\n\nDetails", want: "fix"},
+		{message: "refactor!: drop the v1 client", want: "refactor"},
+		{message: "Docs(readme): typo", want: "docs"},
+		{message: "Fix login", want: "other"},
+		{message: "fix:missing space", want: "other"},
+		{message: "wip: try things", want: "other"},
+		{message: "", want: "other"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.message, func(t *testing.T) {
+			// Act
+			got := commitKind(tc.message)
+			// Assert
+			assertEqual(t, got, tc.want)
+		})
+	}
+}
+
+func TestUnitOnlyCommitKinds(t *testing.T) {
+	// Arrange
+	keep := onlyCommitKinds([]string{"fix", " Docs"})
+	push := func(messages ...string) ghEvent {
+		ev := ghEvent{Type: "PushEvent"}
+		for _, m := range messages {
+			ev.Payload.Commits = append(ev.Payload.Commits, commit{Message: m})
+		}
+		return ev
+	}
+	// Act & Assert
+	assertEqual(t, keep(push("feat: a", "fix: b")), true)
+	assertEqual(t, keep(push("docs: a")), true)
+	assertEqual(t, keep(push("feat: a", "Fix b")), false)
+	assertEqual(t, keep(ghEvent{Type: "WatchEvent"}), false)
+}
+
+func TestUnitCommitKindsView(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+	events := []ghEvent{
+		{Type: "PushEvent", Payload: payload{Commits: []commit{
+			{Message: "fix: a"}, {Message: "feat: b"}, {Message: "fix: c"}, {Message: "Update"},
+		}}},
+		{Type: "IssuesEvent"},
+	}
+	// Act
+	err := commitKindsView(&buf, events, statsOptions{})
+	// Assert
+	assertNoError(t, err)
+	assertEqual(t, buf.String(), "fix            2  50.0%\nfeat           1  25.0%\nother          1  25.0%\n")
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+)
+
+// metricDelta compares a metric between two equal-length windows.
+type metricDelta struct {
+	name     string
+	current  int
+	previous int
+}
+
+// eventsBetween keeps the events created in [from, to).
+func eventsBetween(events []ghEvent, from, to time.Time) []ghEvent {
+	kept := make([]ghEvent, 0, len(events))
+	for _, ev := range events {
+		if !ev.CreatedAt.Before(from) && ev.CreatedAt.Before(to) {
+			kept = append(kept, ev)
+		}
+	}
+	return kept
+}
+
+// compareWindows totals every metric over the last days before now and over
+// the equal-length window right before it.
+func compareWindows(events []ghEvent, now time.Time, days int) []metricDelta {
+	start := now.AddDate(0, 0, -days)
+	current := eventsBetween(events, start, now.Add(time.Nanosecond))
+	previous := eventsBetween(events, start.AddDate(0, 0, -days), start)
+	deltas := make([]metricDelta, 0, len(metrics))
+	for _, name := range sortedKeys(metrics) {
+		d := metricDelta{name: name}
+		for _, ev := range current {
+			d.current += metrics[name](ev)
+		}
+		for _, ev := range previous {
+			d.previous += metrics[name](ev)
+		}
+		deltas = append(deltas, d)
+	}
+	return deltas
+}
+
+// change formats the relative change, "n/a" when there was nothing before.
+func (d metricDelta) change() string {
+	if d.previous == 0 {
+		return "n/a"
+	}
+	return fmt.Sprintf("%+.0f%%", float64(d.current-d.previous)*100/float64(d.previous))
+}
+
+// writeComparison prints the deltas as a table.
+func writeComparison(w io.Writer, deltas []metricDelta) error {
+	tw := tabwriter.NewWriter(w, 0, 0, tablePadding, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "METRIC\tCURRENT\tPREVIOUS\tDELTA\tCHANGE\t")
+	for _, d := range deltas {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%+d\t%s\t\n", d.name, d.current, d.previous, d.current-d.previous, d.change())
+	}
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("write comparison: %w", err)
+	}
+	return nil
+}
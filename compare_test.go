@@ -0,0 +1,63 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUnitCompareWindows(t *testing.T) {
+	// Arrange
+	now := time.Date(2025, 3, 14, 12, 0, 0, 0, time.UTC)
+	events := []ghEvent{
+		{Type: "PushEvent", Payload: payload{Size: 6}, CreatedAt: now.AddDate(0, 0, -1)},
+		{Type: "PushEvent", Payload: payload{Size: 4}, CreatedAt: now.AddDate(0, 0, -9)},
+		{Type: "PullRequestEvent", Payload: payload{Action: "opened"}, CreatedAt: now.AddDate(0, 0, -2)},
+		{Type: "WatchEvent", CreatedAt: now.AddDate(0, 0, -20)},
+	}
+	// Act
+	deltas := compareWindows(events, now, 7)
+	// Assert
+	got := map[string]metricDelta{}
+	for _, d := range deltas {
+		got[d.name] = d
+	}
+	assertEqual(t, got["commits"], metricDelta{name: "commits", current: 6, previous: 4})
+	assertEqual(t, got["events"], metricDelta{name: "events", current: 2, previous: 1})
+	assertEqual(t, got["pull-requests"], metricDelta{name: "pull-requests", current: 1, previous: 0})
+	assertEqual(t, got["stars"], metricDelta{name: "stars", current: 0, previous: 0})
+}
+
+func TestUnitMetricDeltaChange(t *testing.T) {
+	testCases := []struct {
+		name  string
+		delta metricDelta
+		want  string
+	}{
+		{name: "increase", delta: metricDelta{current: 6, previous: 4}, want: "+50%"},
+		{name: "decrease", delta: metricDelta{current: 1, previous: 4}, want: "-75%"},
+		{name: "stable", delta: metricDelta{current: 3, previous: 3}, want: "+0%"},
+		{name: "nothing before", delta: metricDelta{current: 3}, want: "n/a"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			got := tc.delta.change()
+			// Assert
+			assertEqual(t, got, tc.want)
+		})
+	}
+}
+
+func TestUnitWriteComparison(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+	// Act
+	err := writeComparison(&buf, []metricDelta{{name: "commits", current: 37, previous: 20}})
+	// Assert
+	assertNoError(t, err)
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	assertEqual(t, len(lines), 2)
+	assertEqual(t, strings.Join(strings.Fields(lines[1]), " "), "commits 37 20 +17 +85%")
+}
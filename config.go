@@ -21,13 +21,22 @@ func (u *defaultUserHome) dir() (string, error) {
 	return os.UserHomeDir()
 }
 
+// appDir returns the directory holding the configuration and local data.
+func appDir(userHome userHome) (string, error) {
+	home, err := userHome.dir()
+	if err != nil {
+		return "", fmt.Errorf("get user home directory: %w", err)
+	}
+	return filepath.Join(home, ".go-github-activity"), nil
+}
+
 // initialize loads config file.
 func initialize(userHome userHome, fileName string) error {
-	home, err := userHome.dir()
+	dir, err := appDir(userHome)
 	if err != nil {
-		return fmt.Errorf("get user home directory: %w", err)
+		return err
 	}
-	cfgPath := filepath.Join(home, ".go-github-activity", fileName)
+	cfgPath := filepath.Join(dir, fileName)
 	byt, err := os.ReadFile(cfgPath)
 	if err != nil {
 		return fmt.Errorf("read the configuration file: %w ", err)
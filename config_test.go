@@ -76,3 +76,31 @@ func TestUnitInitialize(t *testing.T) {
 		})
 	}
 }
+
+func TestUnitLoadConfigLoaded(t *testing.T) {
+	testCases := []struct {
+		name    string
+		loaded  bool
+		wantErr bool
+	}{
+		{name: "not loaded", wantErr: true},
+		{name: "loaded", loaded: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			activeProfile = "missing"
+			t.Cleanup(func() { activeProfile = "" })
+			configLoaded.Store(tc.loaded)
+			t.Cleanup(func() { configLoaded.Store(false) })
+			// Act
+			err := loadConfig()
+			// Assert
+			if tc.wantErr {
+				assertNotNil(t, err)
+			} else {
+				assertNoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,361 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+type (
+	// cassette holds the recorded interactions of an API contract, played
+	// back in order by replayServer.
+	cassette struct {
+		Interactions []interaction `json:"interactions"`
+	}
+	// interaction is a request and the response recorded for it.
+	interaction struct {
+		Request struct {
+			Method string `json:"method"`
+			URI    string `json:"uri"`
+		} `json:"request"`
+		Response struct {
+			Status  int               `json:"status"`
+			Headers map[string]string `json:"headers"`
+			// Body is a JSON body, Text any other body.
+			Body json.RawMessage `json:"body"`
+			Text string          `json:"text"`
+		} `json:"response"`
+	}
+)
+
+// contractRepo and contractSHA are the repository and commit of the
+// cassettes.
+const (
+	contractRepo = "octocat/Hello-World"
+	contractSHA  = "6dcb09b5b57875f334f61aebed695e2e4193db5e"
+)
+
+// replayServer serves the interactions of testdata/cassettes/<name>.json in
+// order, without network access. A request that differs from the next
+// interaction, or that comes after the last one, e.g. an unexpected retry,
+// fails the test, as does an interaction left unplayed.
+func replayServer(t *testing.T, name string) *httptest.Server {
+	t.Helper()
+	byt, err := os.ReadFile(filepath.Join("testdata", "cassettes", name+".json"))
+	if err != nil {
+		t.Fatalf("read cassette: %v", err)
+	}
+	var c cassette
+	if err := json.Unmarshal(byt, &c); err != nil {
+		t.Fatalf("decode cassette %s: %v", name, err)
+	}
+	var (
+		mu     sync.Mutex
+		played int
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		got := r.Method + " " + normalizedURI(r.URL.RequestURI())
+		if played == len(c.Interactions) {
+			t.Errorf("%s: unexpected request %s after the last interaction", name, got)
+			http.Error(w, "cassette exhausted", http.StatusTeapot)
+			return
+		}
+		in := c.Interactions[played]
+		played++
+		if want := in.Request.Method + " " + normalizedURI(in.Request.URI); got != want {
+			t.Errorf("%s: got request %s, want %s", name, got, want)
+		}
+		if v := r.Header.Get("X-GitHub-Api-Version"); v != defaultAPIVersion {
+			t.Errorf("%s: got API version %q, want %q", name, v, defaultAPIVersion)
+		}
+		for k, v := range in.Response.Headers {
+			w.Header().Set(k, v)
+		}
+		w.WriteHeader(in.Response.Status)
+		if in.Response.Text != "" {
+			w.Write([]byte(in.Response.Text))
+			return
+		}
+		w.Write(in.Response.Body)
+	}))
+	t.Cleanup(func() {
+		srv.Close()
+		if played < len(c.Interactions) {
+			t.Errorf("%s: %d of %d interactions played", name, played, len(c.Interactions))
+		}
+	})
+	return srv
+}
+
+// normalizedURI sorts the query parameters of a request URI, so that their
+// order does not matter.
+func normalizedURI(uri string) string {
+	u, err := url.ParseRequestURI(uri)
+	if err != nil || u.RawQuery == "" {
+		return uri
+	}
+	return u.Path + "?" + u.Query().Encode()
+}
+
+func TestUnitContractEndpoints(t *testing.T) {
+	testCases := []struct {
+		cassette string
+		call     func(hc *client, base string) (any, error)
+		want     string
+	}{
+		{
+			cassette: "user_events",
+			call: func(hc *client, base string) (any, error) {
+				events, err := fetchGitHubResponse(hc, eventsURL(base, "octocat"))
+				return eventSummary(events), err
+			},
+			want: "[PushEvent octocat/Hello-World 1 WatchEvent octocat/Hello-World 0]",
+		},
+		{
+			cassette: "repo_events",
+			call: func(hc *client, base string) (any, error) {
+				events, err := contractFetcher(hc, base).repoEvents(contractRepo)
+				return eventSummary(events), err
+			},
+			want: "[PushEvent octocat/Hello-World 1]",
+		},
+		{
+			cassette: "repo_metadata",
+			call: func(hc *client, base string) (any, error) {
+				return contractFetcher(hc, base).metadata(contractRepo)
+			},
+			want: "{octocat/Hello-World My first repository on GitHub! 80 false Go false false}",
+		},
+		{
+			cassette: "repo_languages",
+			call: func(hc *client, base string) (any, error) {
+				return contractFetcher(hc, base).languages(contractRepo)
+			},
+			want: "map[Go:82140 Makefile:1240]",
+		},
+		{
+			cassette: "issue_labels",
+			call: func(hc *client, base string) (any, error) {
+				is, err := contractFetcher(hc, base).issueLabels(contractRepo, 1347)
+				if err != nil {
+					return nil, err
+				}
+				return fmt.Sprint(is.Labels, " ", is.Milestone.Title, " ", is.Milestone.ClosedIssues), nil
+			},
+			want: "[{bug}] v1.0 8",
+		},
+		{
+			cassette: "issue_thread",
+			call: func(hc *client, base string) (any, error) {
+				th, err := contractFetcher(hc, base).issueThread(contractRepo, 1347)
+				if err != nil {
+					return nil, err
+				}
+				return fmt.Sprint(th.Author, " ", th.State, " ", th.FirstResponse.Sub(th.CreatedAt)), nil
+			},
+			want: "octocat open 26h0m0s",
+		},
+		{
+			cassette: "pull_request",
+			call: func(hc *client, base string) (any, error) {
+				pr, err := contractFetcher(hc, base).pullRequest(contractRepo, 1347)
+				if err != nil {
+					return nil, err
+				}
+				return fmt.Sprint(pr.Additions, " ", pr.Deletions, " ", pr.ChangedFiles, " ", pr.MergedAt.Sub(pr.CreatedAt)), nil
+			},
+			want: "100 3 5 54h0m0s",
+		},
+		{
+			cassette: "commit_verification",
+			call: func(hc *client, base string) (any, error) {
+				return contractFetcher(hc, base).verification(contractRepo, contractSHA)
+			},
+			want: "{true valid}",
+		},
+		{
+			cassette: "commit_checks",
+			call: func(hc *client, base string) (any, error) {
+				return contractFetcher(hc, base).checksState(contractRepo, contractSHA)
+			},
+			want: "failure",
+		},
+		{
+			cassette: "deployments",
+			call: func(hc *client, base string) (any, error) {
+				ds, err := contractFetcher(hc, base).deployments(contractRepo)
+				if err != nil {
+					return nil, err
+				}
+				return fmt.Sprint(len(ds), " ", ds[0].Environment, " ", ds[0].CreatedAt.Format(time.RFC3339)), nil
+			},
+			want: "1 production 2025-03-09T12:00:00Z",
+		},
+		{
+			cassette: "traffic",
+			call: func(hc *client, base string) (any, error) {
+				views, clones, err := contractFetcher(hc, base).traffic(contractRepo)
+				if err != nil {
+					return nil, err
+				}
+				return fmt.Sprint(len(views), " ", views[0].Count, " ", len(clones), " ", clones[0].Uniques), nil
+			},
+			want: "2 10 1 1",
+		},
+		{
+			cassette: "author_commits",
+			call: func(hc *client, base string) (any, error) {
+				from := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+				commits, err := contractFetcher(hc, base).authorCommits(contractRepo, "octocat", from, from.AddDate(0, 0, 9))
+				if err != nil {
+					return nil, err
+				}
+				return fmt.Sprint(len(commits), " ", commits[0].SHA[:7], " ", commits[0].Commit.Message), nil
+			},
+			want: "1 6dcb09b Update README",
+		},
+		{
+			cassette: "user_profile",
+			call: func(hc *client, base string) (any, error) {
+				return (&userFetcher{hc: hc, base: base}).profile("octocat")
+			},
+			want: "{octocat The Octocat @github https://avatars.githubusercontent.com/u/1?v=4}",
+		},
+		{
+			cassette: "user_repos",
+			call: func(hc *client, base string) (any, error) {
+				return recentRepos(hc, base, "octocat", 2)
+			},
+			want: "[octocat/Hello-World octocat/boysenberry-repo-1]",
+		},
+		{
+			cassette: "org_repos",
+			call: func(hc *client, base string) (any, error) {
+				repos, err := fetchOrgRepos(hc, base, "github", 1)
+				return filterOrgRepos(repos, "", ""), err
+			},
+			want: "[github/docs]",
+		},
+		{
+			cassette: "search_issues",
+			call: func(hc *client, base string) (any, error) {
+				items, err := searchAll[searchIssue](hc, base, "issues", "author:octocat created:>=2025-03-01")
+				if err != nil {
+					return nil, err
+				}
+				return fmt.Sprint(len(items), " ", items[0].Number, " ", items[0].State), nil
+			},
+			want: "1 1347 open",
+		},
+		{
+			cassette: "search_commits",
+			call: func(hc *client, base string) (any, error) {
+				items, err := searchAll[apiCommit](hc, base, "commits", "author:octocat author-date:>=2025-03-01")
+				if err != nil {
+					return nil, err
+				}
+				return fmt.Sprint(len(items), " ", items[0].SHA[:7]), nil
+			},
+			want: "1 6dcb09b",
+		},
+		{
+			cassette: "rate_limit",
+			call: func(hc *client, base string) (any, error) {
+				left, reset, err := quotaLeft(hc, base)
+				return fmt.Sprint(left, " ", reset.Unix()), err
+			},
+			want: "4987 1741600800",
+		},
+		{
+			cassette: "security_advisories",
+			call: func(hc *client, base string) (any, error) {
+				events, _, err := fetchAdvisories(hc, base, []string{contractRepo})
+				if err != nil {
+					return nil, err
+				}
+				return fmt.Sprint(eventSummary(events), " ", notification(events[0])), nil
+			},
+			want: "[SecurityAdvisoryEvent octocat/Hello-World 0] " +
+				notification(apiAdvisory{
+					GHSAID: "GHSA-abcd-1234-efgh", CVEID: "CVE-2025-0001", Summary: "Path traversal in the file server",
+					Severity: "high", HTMLURL: "https://github.com/octocat/Hello-World/security/advisories/GHSA-abcd-1234-efgh",
+					PublishedAt: time.Date(2025, 3, 5, 8, 0, 0, 0, time.UTC),
+				}.event(contractRepo)),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.cassette, func(t *testing.T) {
+			// Arrange
+			srv := replayServer(t, tc.cassette)
+			// Act
+			got, err := tc.call(newClient("token"), srv.URL)
+			// Assert
+			assertNoError(t, err)
+			assertEqual(t, fmt.Sprint(got), tc.want)
+		})
+	}
+}
+
+func TestUnitContractErrors(t *testing.T) {
+	testCases := []struct {
+		cassette string
+		wantCode int
+		// wantReset is the least time before the rate limit resets, when
+		// the error comes from one.
+		wantReset time.Duration
+		wantErr   bool
+	}{
+		{cassette: "error_401", wantCode: exitAuth, wantErr: true},
+		{cassette: "error_403_secondary", wantCode: exitRateLimited, wantReset: 50 * time.Second, wantErr: true},
+		{cassette: "error_403_primary", wantCode: exitRateLimited, wantErr: true},
+		{cassette: "error_404", wantCode: exitNotFound, wantErr: true},
+		{cassette: "error_422", wantCode: exitFailure, wantErr: true},
+		{cassette: "error_502", wantCode: exitFailure, wantErr: true},
+		{cassette: "error_429_retried", wantCode: exitOK},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.cassette, func(t *testing.T) {
+			// Arrange
+			srv := replayServer(t, tc.cassette)
+			// Act
+			_, err := fetchGitHubResponse(newClient("token"), eventsURL(srv.URL, "octocat"))
+			// Assert
+			if tc.wantErr {
+				assertNotNil(t, err)
+			} else {
+				assertNoError(t, err)
+			}
+			assertEqual(t, exitCode(err), tc.wantCode)
+			reset, limited := rateLimitReset(err)
+			assertEqual(t, limited, tc.wantCode == exitRateLimited)
+			if tc.wantReset > 0 {
+				assertEqual(t, time.Until(reset) > tc.wantReset, true)
+			}
+		})
+	}
+}
+
+// contractFetcher returns a repository fetcher without cache, so that every
+// call reaches the replay server.
+func contractFetcher(hc *client, base string) *repoFetcher {
+	return &repoFetcher{hc: hc, base: base}
+}
+
+// eventSummary lists the type, repository and number of commits of the
+// events.
+func eventSummary(events []ghEvent) []string {
+	var summary []string
+	for _, ev := range events {
+		summary = append(summary, ev.Type, ev.Repo.Name, fmt.Sprint(len(ev.Payload.Commits)))
+	}
+	return summary
+}
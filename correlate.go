@@ -0,0 +1,157 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+type (
+	// gitOutput runs git in a directory and returns its standard output.
+	gitOutput func(dir string, args ...string) (string, error)
+	// clone is a local clone of a GitHub repository.
+	clone struct {
+		dir  string
+		repo string
+	}
+	// correlation compares the commits pushed to a repository with its
+	// local clone.
+	correlation struct {
+		repo           string
+		dir            string
+		pushed         int
+		missingLocally []commit
+		unpushed       []string
+	}
+)
+
+// githubRemote matches the owner/name of GitHub remote URLs over HTTPS or
+// SSH, e.g. "git@github.com:octo/api.git".
+var githubRemote = regexp.MustCompile(`github\.com[:/]([^/]+/[^/]+?)(?:\.git)?/?$`)
+
+// execGitOutput implements gitOutput with the git binary.
+func execGitOutput(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// runCorrelate compares the user's pushes with the clones of a workspace.
+func runCorrelate(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("correlate", flag.ContinueOnError)
+	workspace := flags.String("workspace", ".", "directory holding the local clones")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: go-github-activity correlate [flags] <username>")
+	}
+	events, err := fetchUserEvents(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	clones, err := findClones(*workspace, execGitOutput)
+	if err != nil {
+		return err
+	}
+	return writeCorrelations(stdout, correlate(events, clones, execGitOutput))
+}
+
+// findClones returns the GitHub clones directly in the workspace or one
+// level below it, e.g. workspace/api or workspace/octo/api.
+func findClones(workspace string, git gitOutput) ([]clone, error) {
+	var clones []clone
+	for _, pattern := range []string{"*/.git", "*/*/.git"} {
+		matches, err := filepath.Glob(filepath.Join(workspace, pattern))
+		if err != nil {
+			return nil, fmt.Errorf("scan workspace: %w", err)
+		}
+		for _, m := range matches {
+			dir := filepath.Dir(m)
+			remote, err := git(dir, "remote", "get-url", "origin")
+			if err != nil {
+				continue
+			}
+			if sub := githubRemote.FindStringSubmatch(remote); sub != nil {
+				clones = append(clones, clone{dir: dir, repo: strings.ToLower(sub[1])})
+			}
+		}
+	}
+	return clones, nil
+}
+
+// correlate checks every pushed commit against the clone of its
+// repository, and lists the local commits on no remote branch. Repositories
+// without a clone are reported with an empty directory.
+func correlate(events []ghEvent, clones []clone, git gitOutput) []correlation {
+	dirs := make(map[string]string, len(clones))
+	for _, c := range clones {
+		dirs[c.repo] = c.dir
+	}
+	byRepo := map[string]*correlation{}
+	var order []string
+	for _, ev := range events {
+		if ev.Type != "PushEvent" {
+			continue
+		}
+		key := strings.ToLower(ev.Repo.Name)
+		c, ok := byRepo[key]
+		if !ok {
+			c = &correlation{repo: ev.Repo.Name, dir: dirs[key]}
+			byRepo[key] = c
+			order = append(order, key)
+		}
+		for _, cm := range ev.Payload.Commits {
+			c.pushed++
+			if c.dir == "" {
+				continue
+			}
+			if _, err := git(c.dir, "cat-file", "-e", cm.SHA+"^{commit}"); err != nil {
+				c.missingLocally = append(c.missingLocally, cm)
+			}
+		}
+	}
+	out := make([]correlation, 0, len(order))
+	for _, key := range order {
+		c := byRepo[key]
+		if c.dir != "" {
+			log, err := git(c.dir, "log", "--branches", "--not", "--remotes", "--format=%h %s")
+			if err == nil && log != "" {
+				c.unpushed = strings.Split(log, "\n")
+			}
+		}
+		out = append(out, *c)
+	}
+	return out
+}
+
+// writeCorrelations prints one line per repository and the commits out of
+// sync.
+func writeCorrelations(w io.Writer, cs []correlation) error {
+	var b strings.Builder
+	for _, c := range cs {
+		if c.dir == "" {
+			fmt.Fprintf(&b, "%s: %d commit%s pushed, no local clone\n", c.repo, c.pushed, plural(c.pushed))
+			continue
+		}
+		fmt.Fprintf(&b, "%s (%s): %d commit%s pushed, %d missing locally, %d unpushed\n",
+			c.repo, c.dir, c.pushed, plural(c.pushed), len(c.missingLocally), len(c.unpushed))
+		for _, cm := range c.missingLocally {
+			subject, _, _ := strings.Cut(cm.Message, "\n")
+			fmt.Fprintf(&b, "  missing locally: %.7s %s\n", cm.SHA, subject)
+		}
+		for _, line := range c.unpushed {
+			fmt.Fprintf(&b, "  unpushed: %s\n", line)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
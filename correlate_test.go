@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeGit answers git commands from a table keyed by directory and
+// arguments; unknown commands fail.
+func fakeGit(answers map[string]string) gitOutput {
+	return func(dir string, args ...string) (string, error) {
+		out, ok := answers[dir+" "+strings.Join(args, " ")]
+		if !ok {
+			return "", errors.New("exit status 1")
+		}
+		return out, nil
+	}
+}
+
+func TestUnitGitHubRemote(t *testing.T) {
+	testCases := []struct {
+		name   string
+		remote string
+		want   string
+	}{
+		{name: "ssh", remote: "git@github.com:octo/api.git", want: "octo/api"},
+		{name: "https", remote: "https://github.com/octo/api", want: "octo/api"},
+		{name: "ssh url", remote: "ssh://git@github.com/octo/api.git", want: "octo/api"},
+		{name: "other host", remote: "git@gitlab.com:octo/api.git", want: ""},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			sub := githubRemote.FindStringSubmatch(tc.remote)
+			// Assert
+			got := ""
+			if sub != nil {
+				got = sub[1]
+			}
+			assertEqual(t, got, tc.want)
+		})
+	}
+}
+
+func TestUnitFindClones(t *testing.T) {
+	// Arrange
+	ws := t.TempDir()
+	for _, dir := range []string{"api/.git", "octo/web/.git", "notes"} {
+		assertNoError(t, os.MkdirAll(filepath.Join(ws, dir), 0o755))
+	}
+	api, web := filepath.Join(ws, "api"), filepath.Join(ws, "octo", "web")
+	git := fakeGit(map[string]string{
+		api + " remote get-url origin": "git@github.com:Octo/API.git",
+		web + " remote get-url origin": "https://github.com/octo/web",
+	})
+	// Act
+	clones, err := findClones(ws, git)
+	// Assert
+	assertNoError(t, err)
+	assertEqual(t, len(clones), 2)
+	assertEqual(t, clones[0], clone{dir: api, repo: "octo/api"})
+	assertEqual(t, clones[1], clone{dir: web, repo: "octo/web"})
+}
+
+func TestUnitCorrelate(t *testing.T) {
+	// Arrange
+	events := []ghEvent{
+		{Type: "PushEvent", Repo: repo{Name: "octo/api"}, Payload: payload{Commits: []commit{
+			{SHA: "aaaaaaaaaa", Message: "Fix login\n\nDetails"}, {SHA: "bbbbbbbbbb", Message: "Add tests"},
+		}}},
+		{Type: "WatchEvent", Repo: repo{Name: "octo/api"}},
+		{Type: "PushEvent", Repo: repo{Name: "octo/web"}, Payload: payload{Commits: []commit{{SHA: "cccccccccc"}}}},
+	}
+	clones := []clone{{dir: "/src/api", repo: "octo/api"}}
+	git := fakeGit(map[string]string{
+		"/src/api cat-file -e bbbbbbbbbb^{commit}":               "",
+		"/src/api log --branches --not --remotes --format=%h %s": "1234567 WIP",
+	})
+	var buf bytes.Buffer
+	// Act
+	cs := correlate(events, clones, git)
+	err := writeCorrelations(&buf, cs)
+	// Assert
+	assertNoError(t, err)
+	assertEqual(t, len(cs), 2)
+	assertEqual(t, buf.String(), "octo/api (/src/api): 2 commits pushed, 1 missing locally, 1 unpushed\n"+
+		"  missing locally: aaaaaaa Fix login\n"+
+		"  unpushed: 1234567 WIP\n"+
+		"octo/web: 1 commit pushed, no local clone\n")
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+type (
+	// credentialProvider reads the GitHub token from a secret store.
+	credentialProvider interface {
+		name() string
+		token() (string, error)
+	}
+	// outputRunner runs a program and returns its standard output.
+	outputRunner func(name string, args ...string) (string, error)
+	// commandCredential runs a shell command printing the token, e.g.
+	// "pass show github".
+	commandCredential struct {
+		command string
+		goos    string
+		run     outputRunner
+	}
+	// fileCredential reads the token from a file.
+	fileCredential struct {
+		path string
+	}
+	// keychainCredential reads the token from the keychain of the OS.
+	keychainCredential struct {
+		service string
+		account string
+		goos    string
+		run     outputRunner
+	}
+	// envCredential reads the token from an environment variable.
+	envCredential struct {
+		key    string
+		getenv func(string) string
+	}
+)
+
+// keychainAccount is the account of the token in the OS keychain.
+const keychainAccount = "github"
+
+// execOutput implements outputRunner with the binaries of the system.
+// With --no-input, the program runs without a controlling terminal, so that
+// a prompt fails instead of waiting for an answer.
+func execOutput(name string, args ...string) (string, error) {
+	cmd := exec.Command(name, args...)
+	if noInput {
+		detachTerminal(cmd)
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("run %s: %w", name, err)
+	}
+	return string(out), nil
+}
+
+func (c commandCredential) name() string { return "token_cmd" }
+
+func (c commandCredential) token() (string, error) {
+	if c.goos == "windows" {
+		return c.run("cmd", "/C", c.command)
+	}
+	return c.run("sh", "-c", c.command)
+}
+
+func (f fileCredential) name() string { return "token_file" }
+
+func (f fileCredential) token() (string, error) {
+	path, err := expandHome(f.path)
+	if err != nil {
+		return "", err
+	}
+	byt, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("read token file: %w", err)
+	}
+	return string(byt), nil
+}
+
+// expandHome replaces the leading ~/ of a configured path with the user's
+// home directory.
+func expandHome(path string) (string, error) {
+	rest, ok := strings.CutPrefix(path, "~/")
+	if !ok {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("get user home directory: %w", err)
+	}
+	return filepath.Join(home, rest), nil
+}
+
+func (k keychainCredential) name() string { return "token_keychain" }
+
+// token looks the token up with security on macOS, secret-tool (Secret
+// Service) on Linux and the BSDs, and the CredentialManager PowerShell
+// module on Windows.
+func (k keychainCredential) token() (string, error) {
+	switch k.goos {
+	case "darwin":
+		return k.run("security", "find-generic-password", "-s", k.service, "-a", k.account, "-w")
+	case "windows":
+		script := fmt.Sprintf("(Get-StoredCredential -Target '%s').GetNetworkCredential().Password", k.service)
+		return k.run("powershell", "-NoProfile", "-NonInteractive", "-Command", script)
+	default:
+		return k.run("secret-tool", "lookup", "service", k.service, "account", k.account)
+	}
+}
+
+func (e envCredential) name() string { return e.key }
+
+func (e envCredential) token() (string, error) { return e.getenv(e.key), nil }
+
+// credentialProviders lists the configured token sources, by priority.
+func credentialProviders() []credentialProvider {
+	var providers []credentialProvider
+	if cmd := viper.GetString("token_cmd"); cmd != "" {
+		providers = append(providers, commandCredential{command: cmd, goos: runtime.GOOS, run: execOutput})
+	}
+	if path := viper.GetString("token_file"); path != "" {
+		providers = append(providers, fileCredential{path: path})
+	}
+	if service := viper.GetString("token_keychain"); service != "" {
+		providers = append(providers, keychainCredential{
+			service: service, account: keychainAccount, goos: runtime.GOOS, run: execOutput,
+		})
+	}
+	return append(providers, envCredential{key: "GITHUB_TOKEN", getenv: os.Getenv})
+}
+
+// resolveToken returns the first token of the providers, or an empty token
+// when none has one.
+func resolveToken(providers []credentialProvider) (string, error) {
+	for _, p := range providers {
+		tok, err := p.token()
+		if err != nil {
+			return "", fmt.Errorf("read token from %s: %w", p.name(), err)
+		}
+		if tok = strings.TrimSpace(tok); tok != "" {
+			return tok, nil
+		}
+	}
+	return "", nil
+}
+
+// loadToken sets github_token from the credential providers when the
+// configuration has no token.
+func loadToken() error {
+	if viper.GetString("github_token") != "" {
+		return nil
+	}
+	tok, err := resolveToken(credentialProviders())
+	if err != nil || tok == "" {
+		return err
+	}
+	viper.Set("github_token", tok)
+	return nil
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// recordRunner returns an outputRunner recording the command line it runs
+// and answering with out or err.
+func recordRunner(cmdline *string, out string, err error) outputRunner {
+	return func(name string, args ...string) (string, error) {
+		*cmdline = strings.Join(append([]string{name}, args...), " ")
+		return out, err
+	}
+}
+
+func TestUnitKeychainCredential(t *testing.T) {
+	testCases := []struct {
+		goos string
+		want string
+	}{
+		{goos: "darwin", want: "security find-generic-password -s gha -a github -w"},
+		{goos: "linux", want: "secret-tool lookup service gha account github"},
+		{
+			goos: "windows",
+			want: "powershell -NoProfile -NonInteractive -Command " +
+				"(Get-StoredCredential -Target 'gha').GetNetworkCredential().Password",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.goos, func(t *testing.T) {
+			// Arrange
+			var cmdline string
+			k := keychainCredential{service: "gha", account: keychainAccount, goos: tc.goos,
+				run: recordRunner(&cmdline, "ghp_x\n", nil)}
+			// Act
+			tok, err := k.token()
+			// Assert
+			assertNoError(t, err)
+			assertEqual(t, tok, "ghp_x\n")
+			assertEqual(t, cmdline, tc.want)
+		})
+	}
+}
+
+func TestUnitCommandCredential(t *testing.T) {
+	// Arrange
+	var cmdline string
+	c := commandCredential{command: "pass show github", goos: "linux", run: recordRunner(&cmdline, "ghp_x", nil)}
+	// Act
+	_, err := c.token()
+	// Assert
+	assertNoError(t, err)
+	assertEqual(t, cmdline, "sh -c pass show github")
+}
+
+func TestUnitResolveToken(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "token")
+	assertNoError(t, os.WriteFile(path, []byte("ghp_file\n"), 0o600))
+	var cmdline string
+	env := func(string) string { return "ghp_env" }
+	testCases := []struct {
+		name      string
+		providers []credentialProvider
+		want      string
+		wantErr   bool
+	}{
+		{
+			name: "first token wins",
+			providers: []credentialProvider{
+				commandCredential{run: recordRunner(&cmdline, " \n", nil)},
+				fileCredential{path: path},
+				envCredential{key: "GITHUB_TOKEN", getenv: env},
+			},
+			want: "ghp_file",
+		},
+		{
+			name:      "environment",
+			providers: []credentialProvider{envCredential{key: "GITHUB_TOKEN", getenv: env}},
+			want:      "ghp_env",
+		},
+		{name: "none", providers: nil, want: ""},
+		{
+			name:      "failing provider",
+			providers: []credentialProvider{commandCredential{run: recordRunner(&cmdline, "", errors.New("exit status 1"))}},
+			wantErr:   true,
+		},
+		{name: "missing file", providers: []credentialProvider{fileCredential{path: path + ".missing"}}, wantErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			tok, err := resolveToken(tc.providers)
+			// Assert
+			if tc.wantErr {
+				assertNotNil(t, err)
+				return
+			}
+			assertNoError(t, err)
+			assertEqual(t, tok, tc.want)
+		})
+	}
+}
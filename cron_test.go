@@ -0,0 +1,58 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUnitParseCron(t *testing.T) {
+	testCases := []struct {
+		name    string
+		expr    string
+		at      time.Time
+		want    bool
+		wantErr bool
+	}{
+		{name: "every minute", expr: "* * * * *", at: time.Date(2025, 3, 3, 10, 17, 0, 0, time.UTC), want: true},
+		{name: "step match", expr: "*/15 * * * *", at: time.Date(2025, 3, 3, 10, 45, 0, 0, time.UTC), want: true},
+		{name: "step miss", expr: "*/15 * * * *", at: time.Date(2025, 3, 3, 10, 46, 0, 0, time.UTC), want: false},
+		{name: "weekday range", expr: "0 9-18 * * 1-5", at: time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC), want: true},
+		{name: "weekend excluded", expr: "0 9-18 * * 1-5", at: time.Date(2025, 3, 2, 9, 0, 0, 0, time.UTC), want: false},
+		{name: "sunday as 7", expr: "0 0 * * 7", at: time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC), want: true},
+		{name: "day of month or weekday", expr: "0 0 15 * 1", at: time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC), want: true},
+		{name: "list", expr: "5,35 6 * * *", at: time.Date(2025, 3, 3, 6, 35, 0, 0, time.UTC), want: true},
+		{name: "alias", expr: "@daily", at: time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC), want: true},
+		{name: "too few fields", expr: "0 0 * *", wantErr: true},
+		{name: "out of range", expr: "60 * * * *", wantErr: true},
+		{name: "bad step", expr: "*/0 * * * *", wantErr: true},
+		{name: "reversed range", expr: "0 18-9 * * *", wantErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			s, err := parseCron(tc.expr)
+			// Assert
+			if tc.wantErr {
+				assertNotNil(t, err)
+				return
+			}
+			assertNoError(t, err)
+			assertEqual(t, s.matches(tc.at), tc.want)
+		})
+	}
+}
+
+// FuzzParseCron checks that no schedule expression makes the parser panic,
+// and that a parsed schedule can be matched.
+func FuzzParseCron(f *testing.F) {
+	for _, seed := range []string{"0 9 * * 1-5", "*/15 * * * *", "@daily", "0 0 1 1 *", "5-1 * * * *", "*/0 * * * *", ""} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, expr string) {
+		s, err := parseCron(expr)
+		if err != nil {
+			return
+		}
+		s.matches(time.Date(2025, 3, 7, 9, 0, 0, 0, time.UTC))
+	})
+}
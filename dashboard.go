@@ -0,0 +1,21 @@
+package main
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+// webAssets holds the dashboard served at the root of serve mode.
+//
+//go:embed web
+var webAssets embed.FS
+
+// dashboard serves the embedded web UI.
+func dashboard() http.Handler {
+	sub, err := fs.Sub(webAssets, "web")
+	if err != nil {
+		panic(err) // the embedded tree always has web
+	}
+	return http.FileServerFS(sub)
+}
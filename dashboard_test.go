@@ -0,0 +1,240 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alnah/go-github-activity/activityclient"
+)
+
+// specSchema is the part of an OpenAPI schema compared with the Go types.
+type specSchema struct {
+	Ref        string                `json:"$ref"`
+	Type       string                `json:"type"`
+	Properties map[string]specSchema `json:"properties"`
+	Items      *specSchema           `json:"items"`
+}
+
+// specDocument is the part of web/openapi.json compared with the server.
+type specDocument struct {
+	Components struct {
+		Schemas map[string]specSchema `json:"schemas"`
+	} `json:"components"`
+	Paths map[string]map[string]json.RawMessage `json:"paths"`
+}
+
+// readSpec decodes the embedded OpenAPI document.
+func readSpec(t *testing.T) specDocument {
+	t.Helper()
+	byt, err := webAssets.ReadFile("web/openapi.json")
+	assertNoError(t, err)
+	var doc specDocument
+	assertNoError(t, json.Unmarshal(byt, &doc))
+	return doc
+}
+
+// schemaDrift lists the differences between the JSON encoding of typ and
+// the schema, at the given location.
+func schemaDrift(schemas map[string]specSchema, schema specSchema, typ reflect.Type, at string) []string {
+	if name, ok := strings.CutPrefix(schema.Ref, "#/components/schemas/"); ok {
+		schema = schemas[name]
+	}
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	want := ""
+	switch {
+	case typ == reflect.TypeOf(time.Time{}):
+		want = "string"
+	case typ == reflect.TypeOf(json.RawMessage{}):
+		return nil
+	case typ.Kind() == reflect.Struct, typ.Kind() == reflect.Map:
+		want = "object"
+	case typ.Kind() == reflect.Slice:
+		want = "array"
+	case typ.Kind() == reflect.String:
+		want = "string"
+	case typ.Kind() == reflect.Bool:
+		want = "boolean"
+	case typ.Kind() >= reflect.Int && typ.Kind() <= reflect.Uint64:
+		want = "integer"
+	case typ.Kind() == reflect.Float32 || typ.Kind() == reflect.Float64:
+		want = "number"
+	}
+	if schema.Type != want {
+		return []string{fmt.Sprintf("%s: type %q, want %q", at, schema.Type, want)}
+	}
+	switch {
+	case typ.Kind() == reflect.Slice && schema.Items != nil:
+		return schemaDrift(schemas, *schema.Items, typ.Elem(), at+"[]")
+	case typ.Kind() != reflect.Struct || typ == reflect.TypeOf(time.Time{}):
+		return nil
+	}
+	var drift []string
+	fields := jsonFields(typ)
+	for name, field := range fields {
+		prop, ok := schema.Properties[name]
+		if !ok {
+			drift = append(drift, fmt.Sprintf("%s.%s: not documented", at, name))
+			continue
+		}
+		drift = append(drift, schemaDrift(schemas, prop, field, at+"."+name)...)
+	}
+	for name := range schema.Properties {
+		if _, ok := fields[name]; !ok {
+			drift = append(drift, fmt.Sprintf("%s.%s: documented but missing", at, name))
+		}
+	}
+	sort.Strings(drift)
+	return drift
+}
+
+// jsonFields returns the types of the JSON fields of a struct, by name,
+// with the fields of its embedded structs.
+func jsonFields(typ reflect.Type) map[string]reflect.Type {
+	fields := map[string]reflect.Type{}
+	for i := range typ.NumField() {
+		f := typ.Field(i)
+		if f.Anonymous {
+			for name, ft := range jsonFields(f.Type) {
+				fields[name] = ft
+			}
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if !f.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+func TestUnitDashboard(t *testing.T) {
+	testCases := []struct {
+		name       string
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "index", path: "/", wantStatus: http.StatusOK, wantBody: "<title>GitHub activity</title>"},
+		{name: "script", path: "/app.js", wantStatus: http.StatusOK, wantBody: "users/${encodeURIComponent(user)}/activity"},
+		{name: "stylesheet", path: "/style.css", wantStatus: http.StatusOK, wantBody: "#heatmap"},
+		{name: "missing asset without key", path: "/nope.js", wantStatus: http.StatusUnauthorized},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			s := &server{tenants: &tenantSet{fallback: &tenant{}}, clock: systemClock{}, apiKeys: []string{"secret"}}
+			rec := httptest.NewRecorder()
+			// Act
+			s.routes().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.path, nil))
+			// Assert
+			assertEqual(t, rec.Code, tc.wantStatus)
+			assertEqual(t, strings.Contains(rec.Body.String(), tc.wantBody), true)
+		})
+	}
+}
+
+func TestUnitOpenAPIDocument(t *testing.T) {
+	// Arrange
+	s := &server{tenants: &tenantSet{fallback: &tenant{}}, clock: systemClock{}}
+	rec := httptest.NewRecorder()
+	// Act
+	s.routes().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	// Assert
+	assertEqual(t, rec.Code, http.StatusOK)
+	var doc struct {
+		OpenAPI string                     `json:"openapi"`
+		Paths   map[string]json.RawMessage `json:"paths"`
+	}
+	assertNoError(t, json.NewDecoder(rec.Body).Decode(&doc))
+	assertEqual(t, doc.OpenAPI, "3.0.3")
+	for _, path := range []string{"/healthz", "/users/{user}/activity", "/users/{user}/stream"} {
+		_, ok := doc.Paths[path]
+		assertEqual(t, ok, true)
+	}
+}
+
+func TestUnitOpenAPIRoutes(t *testing.T) {
+	// Arrange
+	doc := readSpec(t)
+	var documented []string
+	for path, ops := range doc.Paths {
+		for method := range ops {
+			documented = append(documented, strings.ToUpper(method)+" "+path)
+		}
+	}
+	var served []string
+	for pattern := range (&server{}).endpoints() {
+		served = append(served, pattern)
+	}
+	// Act
+	slices.Sort(documented)
+	slices.Sort(served)
+	// Assert
+	assertEqual(t, strings.Join(documented, ", "), strings.Join(served, ", "))
+}
+
+func TestUnitOpenAPISchemas(t *testing.T) {
+	testCases := []struct {
+		name string
+		typ  reflect.Type
+	}{
+		{name: "server", typ: reflect.TypeOf(jsonEvent{})},
+		{name: "client", typ: reflect.TypeOf(activityclient.Event{})},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			doc := readSpec(t)
+			schemas := doc.Components.Schemas
+			// Act
+			drift := schemaDrift(schemas, specSchema{Ref: "#/components/schemas/Event"}, tc.typ, "Event")
+			// Assert
+			assertEqual(t, strings.Join(drift, "\n"), "")
+		})
+	}
+}
+
+func TestUnitSwaggerUI(t *testing.T) {
+	testCases := []struct {
+		name       string
+		path       string
+		enabled    bool
+		wantStatus int
+	}{
+		{name: "enabled", path: "/docs.html", enabled: true, wantStatus: http.StatusOK},
+		{name: "vendored bundle", path: "/swagger-ui/swagger-ui-bundle.js", enabled: true, wantStatus: http.StatusOK},
+		{name: "disabled", path: "/docs.html", wantStatus: http.StatusNotFound},
+		{name: "disabled bundle", path: "/swagger-ui/swagger-ui-bundle.js", wantStatus: http.StatusNotFound},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			s := &server{
+				tenants:   &tenantSet{fallback: &tenant{}},
+				clock:     systemClock{},
+				apiKeys:   []string{"secret"},
+				swaggerUI: tc.enabled,
+			}
+			rec := httptest.NewRecorder()
+			// Act
+			s.routes().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.path, nil))
+			// Assert
+			assertEqual(t, rec.Code, tc.wantStatus)
+			assertEqual(t, strings.Contains(rec.Body.String(), "unpkg.com"), false)
+		})
+	}
+}
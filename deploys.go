@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+type (
+	// deployment is a GitHub deployment of a ref to an environment.
+	deployment struct {
+		ID          int64     `json:"id"`
+		Environment string    `json:"environment"`
+		Ref         string    `json:"ref"`
+		CreatedAt   time.Time `json:"created_at"`
+	}
+	// deployMetric is the deployment frequency of one repository.
+	deployMetric struct {
+		repo    string
+		deploys int
+		perWeek float64
+	}
+)
+
+// deployments returns the latest deployments of a repository.
+func (f *repoFetcher) deployments(name string) ([]deployment, error) {
+	return cached(f.cache, "deployments/"+name, func() ([]deployment, error) {
+		u, err := f.repoURL(name, "deployments?per_page=100")
+		if err != nil {
+			return nil, err
+		}
+		var ds []deployment
+		if err := fetchJSON(f.hc, u, &ds); err != nil {
+			return nil, fmt.Errorf("fetch deployments of %s: %w", name, err)
+		}
+		return ds, nil
+	})
+}
+
+// deploymentEvents fetches the deployments of the repositories of the
+// events and normalizes them as deployment events created since the given
+// time. Repositories without access to deployments are skipped.
+func deploymentEvents(
+	events []ghEvent, deploymentsOf func(repo string) ([]deployment, error), since time.Time,
+) ([]ghEvent, error) {
+	var out []ghEvent
+	seen := map[string]bool{}
+	for _, ev := range events {
+		if seen[ev.Repo.Name] {
+			continue
+		}
+		seen[ev.Repo.Name] = true
+		ds, err := deploymentsOf(ev.Repo.Name)
+		if err != nil {
+			if isStatus(err, http.StatusNotFound) || isStatus(err, http.StatusForbidden) {
+				continue
+			}
+			return nil, err
+		}
+		for _, d := range ds {
+			if d.CreatedAt.Before(since) {
+				continue
+			}
+			out = append(out, ghEvent{
+				ID:        fmt.Sprintf("deployment-%d", d.ID),
+				Type:      "DeploymentEvent",
+				Repo:      repo{Name: ev.Repo.Name},
+				Payload:   payload{Deployment: &d},
+				CreatedAt: d.CreatedAt,
+			})
+		}
+	}
+	return out, nil
+}
+
+// isDeploy reports whether an event ships code: a published release, or a
+// deployment to the environment when one is given.
+func isDeploy(ev ghEvent, environment string) bool {
+	switch ev.Type {
+	case "ReleaseEvent":
+		return ev.Payload.Action == "published" || ev.Payload.Action == ""
+	case "DeploymentEvent":
+		d := ev.Payload.Deployment
+		return d != nil && (environment == "" || d.Environment == environment)
+	}
+	return false
+}
+
+// deployFrequency counts the deployments per repository over a window of
+// days, by decreasing count then name. Releases and deployments of the same
+// repository both count, so a repository should publish one or the other.
+func deployFrequency(events []ghEvent, days int, environment string) []deployMetric {
+	var deploys []ghEvent
+	for _, ev := range events {
+		if isDeploy(ev, environment) {
+			deploys = append(deploys, ev)
+		}
+	}
+	counts := countBy(deploys, func(ev ghEvent) string { return ev.Repo.Name })
+	out := make([]deployMetric, 0, len(counts))
+	for _, c := range counts {
+		out = append(out, deployMetric{repo: c.name, deploys: c.count, perWeek: float64(c.count) * 7 / float64(days)})
+	}
+	return out
+}
+
+// doraLevel buckets a deployment frequency per week as in the DORA report:
+// elite for daily deploys, high for weekly, medium for monthly, else low.
+func doraLevel(perWeek float64) string {
+	switch {
+	case perWeek >= 7:
+		return "elite"
+	case perWeek >= 1:
+		return "high"
+	case perWeek >= 7.0/30:
+		return "medium"
+	}
+	return "low"
+}
+
+// deploysView prints the deployment frequency per repository, adding the
+// Deployments API data with --enrich.
+func deploysView(w io.Writer, events []ghEvent, opts statsOptions) error {
+	if opts.enrich {
+		fetcher, err := newRepoFetcher()
+		if err != nil {
+			return err
+		}
+		ds, err := deploymentEvents(events, fetcher.deployments, time.Now().AddDate(0, 0, -opts.days))
+		if err != nil {
+			return err
+		}
+		events = append(events, ds...)
+	}
+	return writeDeploys(w, deployFrequency(events, opts.days, opts.environment))
+}
+
+// writeDeploys prints one line per repository.
+func writeDeploys(w io.Writer, metrics []deployMetric) error {
+	if _, err := fmt.Fprintf(w, "%-30s %7s %8s  %s\n", "repository", "deploys", "per week", "level"); err != nil {
+		return err
+	}
+	for _, m := range metrics {
+		if _, err := fmt.Fprintf(w, "%-30s %7d %8.1f  %s\n", m.repo, m.deploys, m.perWeek, doraLevel(m.perWeek)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestUnitDeploymentEvents(t *testing.T) {
+	// Arrange
+	since := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	events := []ghEvent{
+		{Repo: repo{Name: "octo/a"}}, {Repo: repo{Name: "octo/a"}}, {Repo: repo{Name: "octo/private"}},
+	}
+	var calls int
+	deploymentsOf := func(name string) ([]deployment, error) {
+		calls++
+		if name == "octo/private" {
+			return nil, &apiError{StatusCode: http.StatusForbidden}
+		}
+		return []deployment{
+			{ID: 2, Environment: "production", CreatedAt: since.Add(time.Hour)},
+			{ID: 1, Environment: "production", CreatedAt: since.Add(-time.Hour)},
+		}, nil
+	}
+	// Act
+	got, err := deploymentEvents(events, deploymentsOf, since)
+	// Assert
+	assertNoError(t, err)
+	assertEqual(t, calls, 2)
+	assertEqual(t, len(got), 1)
+	assertEqual(t, got[0].ID, "deployment-2")
+	assertEqual(t, got[0].Type, "DeploymentEvent")
+	assertEqual(t, got[0].Payload.Deployment.Environment, "production")
+}
+
+func TestUnitIsDeploy(t *testing.T) {
+	testCases := []struct {
+		name        string
+		ev          ghEvent
+		environment string
+		want        bool
+	}{
+		{name: "published release", ev: ghEvent{Type: "ReleaseEvent", Payload: payload{Action: "published"}}, want: true},
+		{name: "edited release", ev: ghEvent{Type: "ReleaseEvent", Payload: payload{Action: "edited"}}, want: false},
+		{
+			name: "any environment", want: true,
+			ev: ghEvent{Type: "DeploymentEvent", Payload: payload{Deployment: &deployment{Environment: "staging"}}},
+		},
+		{
+			name: "other environment", environment: "production", want: false,
+			ev: ghEvent{Type: "DeploymentEvent", Payload: payload{Deployment: &deployment{Environment: "staging"}}},
+		},
+		{name: "push", ev: ghEvent{Type: "PushEvent"}, want: false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			got := isDeploy(tc.ev, tc.environment)
+			// Assert
+			assertEqual(t, got, tc.want)
+		})
+	}
+}
+
+func TestUnitDeployFrequency(t *testing.T) {
+	// Arrange
+	release := func(name string) ghEvent {
+		return ghEvent{Type: "ReleaseEvent", Repo: repo{Name: name}, Payload: payload{Action: "published"}}
+	}
+	var events []ghEvent
+	for range 10 {
+		events = append(events, release("octo/a"))
+	}
+	events = append(events, release("octo/b"), ghEvent{Type: "PushEvent", Repo: repo{Name: "octo/c"}})
+	var buf bytes.Buffer
+	// Act
+	metrics := deployFrequency(events, 14, "")
+	err := writeDeploys(&buf, metrics)
+	// Assert
+	assertNoError(t, err)
+	assertEqual(t, buf.String(), "repository                     deploys per week  level\n"+
+		"octo/a                              10      5.0  high\n"+
+		"octo/b                               1      0.5  medium\n")
+}
+
+func TestUnitDoraLevel(t *testing.T) {
+	testCases := []struct {
+		perWeek float64
+		want    string
+	}{
+		{perWeek: 14, want: "elite"},
+		{perWeek: 7, want: "elite"},
+		{perWeek: 1, want: "high"},
+		{perWeek: 0.25, want: "medium"},
+		{perWeek: 0.1, want: "low"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.want, func(t *testing.T) {
+			// Act
+			got := doraLevel(tc.perWeek)
+			// Assert
+			assertEqual(t, got, tc.want)
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+type (
+	// snapshotDiff is what changed from one activity snapshot to another.
+	snapshotDiff struct {
+		added   []ghEvent
+		removed []ghEvent
+		// changed holds the events of both snapshots whose content differs,
+		// as in the second one.
+		changed []ghEvent
+		deltas  []metricDelta
+	}
+	// jsonDiff is the JSON output of diff.
+	jsonDiff struct {
+		Added   []ghEvent    `json:"added"`
+		Removed []ghEvent    `json:"removed"`
+		Changed []ghEvent    `json:"changed"`
+		Metrics []jsonMetric `json:"metrics"`
+	}
+	// jsonMetric is a metric of both snapshots.
+	jsonMetric struct {
+		Name   string `json:"name"`
+		Before int    `json:"before"`
+		After  int    `json:"after"`
+	}
+)
+
+// runDiff reports what changed between two activity snapshots, each a JSON
+// file written with --output json, or a date or timestamp at which the
+// archive of --user is read, e.g. to check a sync or to report the activity
+// since last Friday.
+func runDiff(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("diff", flag.ContinueOnError)
+	user := flags.String("user", "", "user whose archive is read for the snapshots given as dates")
+	output := flags.String("output", "text", "output format: text or json")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 2 {
+		return errors.New("usage: go-github-activity diff [flags] <before.json|date> <after.json|date>")
+	}
+	if *output != "text" && *output != "json" {
+		return fmt.Errorf("unknown output format %q", *output)
+	}
+	var archived []ghEvent
+	loaded := false
+	archive := func() ([]ghEvent, error) {
+		if *user == "" {
+			return nil, errors.New("snapshots given as dates need --user")
+		}
+		if loaded {
+			return archived, nil
+		}
+		var err error
+		archived, err = archivedEvents(*user, time.Time{})
+		loaded = err == nil
+		return archived, err
+	}
+	before, err := loadSnapshot(flags.Arg(0), archive)
+	if err != nil {
+		return err
+	}
+	after, err := loadSnapshot(flags.Arg(1), archive)
+	if err != nil {
+		return err
+	}
+	d := diffSnapshots(before, after)
+	if *output == "json" {
+		return d.writeJSON(stdout)
+	}
+	return d.write(stdout)
+}
+
+// loadSnapshot reads the events of a JSON file, or of the archive up to a
+// timestamp, or up to the end of a day given as YYYY-MM-DD.
+func loadSnapshot(ref string, archive func() ([]ghEvent, error)) ([]ghEvent, error) {
+	if _, err := os.Stat(ref); err == nil {
+		byt, err := os.ReadFile(ref)
+		if err != nil {
+			return nil, fmt.Errorf("read snapshot: %w", err)
+		}
+		var events []ghEvent
+		if err := json.Unmarshal(byt, &events); err != nil {
+			return nil, fmt.Errorf("decode snapshot %s: %w", ref, err)
+		}
+		return events, nil
+	}
+	until, err := time.Parse(time.RFC3339, ref)
+	if err != nil {
+		day, errDay := time.ParseInLocation(time.DateOnly, ref, time.Local)
+		if errDay != nil {
+			return nil, fmt.Errorf("snapshot %q is neither a file nor a date", ref)
+		}
+		until = day.AddDate(0, 0, 1)
+	}
+	events, err := archive()
+	if err != nil {
+		return nil, err
+	}
+	return eventsBetween(events, time.Time{}, until), nil
+}
+
+// diffSnapshots compares the events of two snapshots by ID, and their
+// metrics.
+func diffSnapshots(before, after []ghEvent) snapshotDiff {
+	var d snapshotDiff
+	old := make(map[string]ghEvent, len(before))
+	for _, ev := range before {
+		old[ev.ID] = ev
+	}
+	kept := make(map[string]bool, len(after))
+	for _, ev := range after {
+		prev, ok := old[ev.ID]
+		kept[ev.ID] = true
+		switch {
+		case !ok:
+			d.added = append(d.added, ev)
+		case !sameEvent(prev, ev):
+			d.changed = append(d.changed, ev)
+		}
+	}
+	for _, ev := range before {
+		if !kept[ev.ID] {
+			d.removed = append(d.removed, ev)
+		}
+	}
+	for _, name := range sortedKeys(metrics) {
+		m := metricDelta{name: name}
+		for _, ev := range after {
+			m.current += metrics[name](ev)
+		}
+		for _, ev := range before {
+			m.previous += metrics[name](ev)
+		}
+		d.deltas = append(d.deltas, m)
+	}
+	return d
+}
+
+// sameEvent reports whether two events hold the same data, ignoring the raw
+// payload, kept by some archives only.
+func sameEvent(a, b ghEvent) bool {
+	a.Raw, b.Raw = nil, nil
+	x, errA := json.Marshal(a)
+	y, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(x, y)
+}
+
+// write prints the added, removed and changed events, marked +, - and ~,
+// then the metrics of both snapshots.
+func (d snapshotDiff) write(w io.Writer) error {
+	for _, part := range []struct {
+		mark   string
+		events []ghEvent
+	}{{"+", d.added}, {"-", d.removed}, {"~", d.changed}} {
+		for _, ev := range part.events {
+			_, err := fmt.Fprintf(w, "%s %s %s\n", part.mark, ev.CreatedAt.Format(time.DateOnly), notification(ev))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	_, err := fmt.Fprintf(w, "%d added, %d removed, %d changed\n\n", len(d.added), len(d.removed), len(d.changed))
+	if err != nil {
+		return err
+	}
+	return writeComparison(w, d.deltas)
+}
+
+// writeJSON encodes the diff, with empty arrays rather than nulls.
+func (d snapshotDiff) writeJSON(w io.Writer) error {
+	out := jsonDiff{
+		Added: append([]ghEvent{}, d.added...), Removed: append([]ghEvent{}, d.removed...),
+		Changed: append([]ghEvent{}, d.changed...),
+	}
+	for _, m := range d.deltas {
+		out.Metrics = append(out.Metrics, jsonMetric{Name: m.name, Before: m.previous, After: m.current})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(out); err != nil {
+		return fmt.Errorf("encode diff: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUnitDiffSnapshots(t *testing.T) {
+	// Arrange
+	at := time.Date(2025, 3, 7, 10, 0, 0, 0, time.UTC)
+	push := ghEvent{ID: "1", Type: "PushEvent", Repo: repo{Name: "octo/api"}, Payload: payload{Size: 2}, CreatedAt: at}
+	star := ghEvent{ID: "2", Type: "WatchEvent", Repo: repo{Name: "octo/web"}, CreatedAt: at}
+	amended := push
+	amended.Payload.Size = 3
+	issue := ghEvent{ID: "3", Type: "IssuesEvent", Payload: payload{Action: "opened"}, CreatedAt: at.Add(time.Hour)}
+	// Act
+	d := diffSnapshots([]ghEvent{push, star}, []ghEvent{issue, amended})
+	// Assert
+	ids := func(events []ghEvent) string {
+		var out []string
+		for _, ev := range events {
+			out = append(out, ev.ID)
+		}
+		return fmt.Sprint(out)
+	}
+	assertEqual(t, ids(d.added), "[3]")
+	assertEqual(t, ids(d.removed), "[2]")
+	assertEqual(t, ids(d.changed), "[1]")
+	assertEqual(t, fmt.Sprint(d.deltas), "[{commits 3 2} {discussions 0 0} {events 2 2} {failed-runs 0 0} "+
+		"{issues 1 0} {pull-requests 0 0} {stars 0 1}]")
+}
+
+func TestUnitDiffSnapshotsIdentical(t *testing.T) {
+	// Arrange
+	ev := ghEvent{ID: "1", Type: "PushEvent", Raw: []byte(`{"id":"1"}`)}
+	copied := ev
+	copied.Raw = nil
+	// Act
+	d := diffSnapshots([]ghEvent{ev}, []ghEvent{copied})
+	// Assert
+	assertEqual(t, len(d.added)+len(d.removed)+len(d.changed), 0)
+}
+
+func TestUnitLoadSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "run1.json")
+	if err := os.WriteFile(file, []byte(`[{"id":"9","type":"PushEvent","summary":"pushed"}]`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	archived := []ghEvent{
+		{ID: "3", CreatedAt: time.Date(2025, 3, 8, 9, 0, 0, 0, time.Local)},
+		{ID: "2", CreatedAt: time.Date(2025, 3, 7, 23, 0, 0, 0, time.Local)},
+		{ID: "1", CreatedAt: time.Date(2025, 3, 6, 9, 0, 0, 0, time.Local)},
+	}
+	archive := func() ([]ghEvent, error) { return archived, nil }
+	testCases := []struct {
+		name    string
+		ref     string
+		archive func() ([]ghEvent, error)
+		want    string
+		wantErr bool
+	}{
+		{name: "file", ref: file, want: "[9]"},
+		{name: "end of day", ref: "2025-03-07", archive: archive, want: "[2 1]"},
+		{
+			name: "timestamp", ref: time.Date(2025, 3, 7, 12, 0, 0, 0, time.Local).Format(time.RFC3339),
+			archive: archive, want: "[1]",
+		},
+		{name: "neither", ref: "last-friday", archive: archive, wantErr: true},
+		{
+			name: "archive fails", ref: "2025-03-07", wantErr: true,
+			archive: func() ([]ghEvent, error) { return nil, errors.New("snapshots given as dates need --user") },
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			got, err := loadSnapshot(tc.ref, tc.archive)
+			// Assert
+			if tc.wantErr {
+				assertNotNil(t, err)
+				return
+			}
+			assertNoError(t, err)
+			var ids []string
+			for _, ev := range got {
+				ids = append(ids, ev.ID)
+			}
+			assertEqual(t, fmt.Sprint(ids), tc.want)
+		})
+	}
+}
+
+func TestUnitSnapshotDiffWrite(t *testing.T) {
+	// Arrange
+	at := time.Date(2025, 3, 7, 10, 0, 0, 0, time.UTC)
+	star := ghEvent{
+		ID: "2", Type: "WatchEvent", Actor: actor{Login: "octocat"}, Repo: repo{Name: "octo/web"}, CreatedAt: at,
+	}
+	d := diffSnapshots(nil, []ghEvent{star})
+	var buf bytes.Buffer
+	// Act
+	err := d.write(&buf)
+	// Assert
+	assertNoError(t, err)
+	lines := strings.Split(buf.String(), "\n")
+	assertEqual(t, lines[0], "+ 2025-03-07 octocat: "+summarize(catalogs[defaultLang], star))
+	assertEqual(t, lines[1], "1 added, 0 removed, 0 changed")
+	assertEqual(t, strings.Contains(buf.String(), "stars"), true)
+}
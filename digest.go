@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+type (
+	// digest summarizes a user's activity over a window of days.
+	digest struct {
+		user   string
+		from   time.Time
+		to     time.Time
+		events []ghEvent
+		// sponsorships are kept out of the events, the totals and the
+		// summary, and only counted.
+		sponsorships []ghEvent
+		totals       map[string]int
+		topRepos     []namedCount
+		anomalies    []anomaly
+		summary      string
+	}
+	// digestOptions holds the digest settings.
+	digestOptions struct {
+		days     int
+		trailing int
+		sigma    float64
+	}
+)
+
+// digestTopRepos is the number of repositories listed in a digest.
+const digestTopRepos = 5
+
+// runDigest prints a Markdown digest of the archived activity of a user.
+func runDigest(args []string, stdout io.Writer) error {
+	return digestAt(systemClock{}, args, stdout)
+}
+
+// digestAt runs digest with the window ending on the day of the clock.
+func digestAt(c clock, args []string, stdout io.Writer) error {
+	var opts digestOptions
+	flags := flag.NewFlagSet("digest", flag.ContinueOnError)
+	flags.IntVar(&opts.days, "days", 7, "size of the digest window in days")
+	flags.IntVar(&opts.trailing, "trailing", 28, "days of history averaged to detect anomalies")
+	flags.Float64Var(&opts.sigma, "sigma", 2, "standard deviations from the trailing average flagged as anomalies")
+	output := flags.String("output", "markdown", "output format: markdown or pdf")
+	withSummary := flags.Bool("summarize", false,
+		"add a prose summary written by the configured language model, private repository names redacted")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: go-github-activity digest [flags] <username>")
+	}
+	if opts.days <= 0 || opts.trailing <= 0 {
+		return errors.New("the digest window and the trailing window must be positive")
+	}
+	if *output != "markdown" && *output != "pdf" {
+		return fmt.Errorf("unknown output format %q", *output)
+	}
+	if err := loadConfig(); err != nil {
+		return err
+	}
+	now := c.Now()
+	since := digestSince(now, opts)
+	events, err := archivedActivity(flags.Arg(0), since)
+	if err != nil {
+		return err
+	}
+	// The anomalies need to know whether the history goes past the events
+	// loaded; the archive is only scanned for it when they do not reach
+	// back to the first day of the trailing window.
+	start := since
+	if len(events) == 0 || startOfDay(events[len(events)-1].CreatedAt, now.Location()).After(since) {
+		if start, err = archiveStart(flags.Arg(0)); err != nil {
+			return err
+		}
+	}
+	if start.IsZero() {
+		return fmt.Errorf("no archived events for %s, run sync first", flags.Arg(0))
+	}
+	d := buildDigest(flags.Arg(0), events, start, now, opts)
+	if *withSummary && len(d.events) > 0 {
+		s, err := newLLMSummarizer()
+		if err != nil {
+			return err
+		}
+		if d.summary, err = s.summarize(context.Background(), d.events); err != nil {
+			return err
+		}
+	}
+	w, done := reportOutput(stdout, *output == "pdf")
+	if err := writeDigest(w, d); err != nil {
+		return err
+	}
+	return done()
+}
+
+// digestWindow returns the first and last days of the digest window ending
+// now.
+func digestWindow(now time.Time, opts digestOptions) (from, to time.Time) {
+	to = startOfDay(now, now.Location())
+	return to.AddDate(0, 0, 1-opts.days), to
+}
+
+// digestSince returns the start of the history a digest reads: its window
+// and the trailing window of its first day.
+func digestSince(now time.Time, opts digestOptions) time.Time {
+	from, _ := digestWindow(now, opts)
+	return from.AddDate(0, 0, -opts.trailing)
+}
+
+// buildDigest computes the totals, top repositories and anomalies of the
+// window ending now from archived events, newest first, start being the
+// time of the oldest event archived.
+func buildDigest(user string, events []ghEvent, start, now time.Time, opts digestOptions) digest {
+	from, to := digestWindow(now, opts)
+	d := digest{user: user, from: from, to: to, totals: map[string]int{}}
+	for _, ev := range eventsSince(events, from) {
+		if ev.Type == "SponsorshipEvent" {
+			d.sponsorships = append(d.sponsorships, ev)
+			continue
+		}
+		d.events = append(d.events, ev)
+	}
+	for _, ev := range d.events {
+		for name, m := range metrics {
+			d.totals[name] += m(ev)
+		}
+	}
+	d.topRepos = countBy(d.events, func(ev ghEvent) string { return ev.Repo.Name })
+	d.topRepos = d.topRepos[:min(digestTopRepos, len(d.topRepos))]
+	if len(events) > 0 {
+		first := startOfDay(start, now.Location())
+		counts := dailyCounts(events, now.Location())
+		d.anomalies = detectAnomalies(counts, first, from, to, opts.trailing, opts.sigma)
+	}
+	return d
+}
+
+// writeDigest renders the digest as Markdown.
+func writeDigest(w io.Writer, d digest) error {
+	const day = "2006-01-02"
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Activity digest for %s\n\n%s – %s\n\n", d.user, d.from.Format(day), d.to.Format(day))
+	if d.summary != "" {
+		fmt.Fprintf(&b, "## Summary\n\n%s\n\n", d.summary)
+	}
+	b.WriteString("## Totals\n\n")
+	for _, name := range sortedKeys(d.totals) {
+		fmt.Fprintf(&b, "- %s: %d\n", name, d.totals[name])
+	}
+	if len(d.topRepos) > 0 {
+		b.WriteString("\n## Top repositories\n\n")
+		for _, r := range d.topRepos {
+			fmt.Fprintf(&b, "- %s: %d events\n", r.name, r.count)
+		}
+	}
+	if created, cancelled, monthly := sponsorsSummary(d.sponsorships); created+cancelled > 0 {
+		fmt.Fprintf(&b, "\n## Sponsors\n\n- new: %d\n- cancelled: %d\n- monthly change: %+d USD\n",
+			created, cancelled, monthly)
+	}
+	if len(d.anomalies) > 0 {
+		b.WriteString("\n## Anomalies\n\n")
+		for _, a := range d.anomalies {
+			fmt.Fprintf(&b, "- %s: %s, %d events (trailing average %.1f ± %.1f)\n",
+				a.day.Format(day), a.kind(), a.count, a.mean, a.stddev)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
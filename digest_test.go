@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func TestUnitBuildDigest(t *testing.T) {
+	// Arrange
+	now := time.Date(2025, 3, 31, 12, 0, 0, 0, time.UTC)
+	var events []ghEvent
+	for i := 30; i >= 1; i-- {
+		day := now.AddDate(0, 0, -i)
+		events = append(events, ghEvent{
+			Type: "PushEvent", Repo: repo{Name: "octo/a"}, Payload: payload{Size: 1}, CreatedAt: day,
+		})
+		if i%2 == 0 {
+			events = append(events, ghEvent{Type: "WatchEvent", Repo: repo{Name: "octo/b"}, CreatedAt: day})
+		}
+	}
+	for i := 0; i < 20; i++ {
+		events = append(events, ghEvent{
+			Type: "PushEvent", Repo: repo{Name: "octo/c"}, Payload: payload{Size: 2}, CreatedAt: now,
+		})
+	}
+	slices.Reverse(events)
+	// Act
+	start := events[len(events)-1].CreatedAt
+	d := buildDigest("octocat", events, start, now, digestOptions{days: 7, trailing: 14, sigma: 2})
+	// Assert
+	assertEqual(t, d.from, time.Date(2025, 3, 25, 0, 0, 0, 0, time.UTC))
+	assertEqual(t, d.totals["commits"], 6+40)
+	assertEqual(t, d.topRepos[0], namedCount{name: "octo/c", count: 20})
+	assertEqual(t, len(d.anomalies), 1)
+	assertEqual(t, d.anomalies[0].kind(), "spike")
+}
+
+func TestUnitBuildDigestSince(t *testing.T) {
+	// Arrange
+	now := time.Date(2025, 3, 31, 12, 0, 0, 0, time.UTC)
+	opts := digestOptions{days: 7, trailing: 14, sigma: 2}
+	var events []ghEvent
+	for i := 60; i >= 1; i-- {
+		if i%5 != 0 {
+			events = append(events, ghEvent{Type: "PushEvent", CreatedAt: now.AddDate(0, 0, -i)})
+		}
+		if i == 6 {
+			for range 10 {
+				events = append(events, ghEvent{Type: "PushEvent", CreatedAt: now.AddDate(0, 0, -i)})
+			}
+		}
+	}
+	slices.Reverse(events)
+	since := digestSince(now, opts)
+	// Act
+	full := buildDigest("octocat", events, events[len(events)-1].CreatedAt, now, opts)
+	trimmed := buildDigest("octocat", eventsSince(events, since), events[len(events)-1].CreatedAt, now, opts)
+	// Assert
+	assertEqual(t, since, time.Date(2025, 3, 11, 0, 0, 0, 0, time.UTC))
+	assertEqual(t, fmt.Sprint(trimmed.anomalies), fmt.Sprint(full.anomalies))
+	assertEqual(t, len(full.anomalies), 1)
+	assertEqual(t, full.anomalies[0].day, time.Date(2025, 3, 25, 0, 0, 0, 0, time.UTC))
+}
+
+func TestUnitWriteDigest(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+	day := time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)
+	d := digest{
+		user: "octocat", from: day.AddDate(0, 0, -6), to: day,
+		totals:    map[string]int{"commits": 3},
+		topRepos:  []namedCount{{name: "octo/a", count: 2}},
+		anomalies: []anomaly{{day: day, count: 20, mean: 2, stddev: 1}},
+		summary:   "A busy week on octo/a.",
+	}
+	// Act
+	err := writeDigest(&buf, d)
+	// Assert
+	assertNoError(t, err)
+	got := buf.String()
+	assertEqual(t, strings.HasPrefix(got, "# Activity digest for octocat\n\n2025-03-25 – 2025-03-31\n"), true)
+	assertEqual(t, strings.Contains(got, "## Summary\n\nA busy week on octo/a.\n\n## Totals\n"), true)
+	assertEqual(t, strings.Contains(got, "- commits: 3\n"), true)
+	assertEqual(t, strings.Contains(got, "- octo/a: 2 events\n"), true)
+	assertEqual(t, strings.Contains(got, "- 2025-03-31: spike, 20 events (trailing average 2.0 ± 1.0)\n"), true)
+}
+
+func TestUnitDigestSponsorships(t *testing.T) {
+	// Arrange
+	now := time.Date(2025, 3, 31, 12, 0, 0, 0, time.UTC)
+	events := []ghEvent{
+		{Type: "SponsorshipEvent", Repo: repo{Name: "octocat"}, CreatedAt: now, Payload: payload{
+			Action: "created", Sponsorship: &sponsorship{Sponsor: "mona", MonthlyDollars: 5},
+		}},
+		{Type: "PushEvent", Repo: repo{Name: "octo/a"}, Payload: payload{Size: 1}, CreatedAt: now},
+	}
+	var buf bytes.Buffer
+	// Act
+	d := buildDigest("octocat", events, now, now, digestOptions{days: 7, trailing: 14, sigma: 2})
+	err := writeDigest(&buf, d)
+	// Assert
+	assertNoError(t, err)
+	assertEqual(t, d.totals["events"], 1)
+	assertEqual(t, len(d.events), 1)
+	want := "## Sponsors\n\n- new: 1\n- cancelled: 0\n- monthly change: +5 USD\n"
+	assertEqual(t, strings.Contains(buf.String(), want), true)
+	assertEqual(t, strings.Contains(buf.String(), "mona"), false)
+}
+
+func TestUnitDigestAt(t *testing.T) {
+	// Arrange
+	day := time.Date(2025, 3, 31, 12, 0, 0, 0, time.UTC)
+	t.Setenv("HOME", t.TempDir())
+	viper.Set("github_token", "ghp_test")
+	t.Cleanup(viper.Reset)
+	a, err := openArchive("octocat")
+	assertNoError(t, err)
+	_, err = a.add([]ghEvent{
+		{ID: "1", Type: "PushEvent", Repo: repo{Name: "octo/a"}, Payload: payload{Size: 2}, CreatedAt: day.Add(-time.Hour)},
+		{ID: "2", Type: "WatchEvent", Repo: repo{Name: "octo/b"}, CreatedAt: day.AddDate(0, 0, -40)},
+	})
+	assertNoError(t, err)
+	var buf bytes.Buffer
+	// Act
+	err = digestAt(&fakeClock{now: day}, []string{"octocat"}, &buf)
+	// Assert
+	assertNoError(t, err)
+	got := buf.String()
+	assertEqual(t, strings.HasPrefix(got, "# Activity digest for octocat\n\n2025-03-25 – 2025-03-31\n"), true)
+	assertEqual(t, strings.Contains(got, "- commits: 2\n"), true)
+}
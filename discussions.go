@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+type (
+	// discussionsProvider reads the GitHub Discussions a user started,
+	// commented on and answered.
+	discussionsProvider struct {
+		hc       *client
+		endpoint string
+	}
+	// discussion is the discussion of a DiscussionEvent or
+	// DiscussionCommentEvent.
+	discussion struct {
+		Number  int    `json:"number"`
+		Title   string `json:"title"`
+		HTMLURL string `json:"html_url"`
+	}
+	// ghDiscussion is a discussion as served by the GraphQL API.
+	ghDiscussion struct {
+		Number         int        `json:"number"`
+		Title          string     `json:"title"`
+		URL            string     `json:"url"`
+		CreatedAt      time.Time  `json:"createdAt"`
+		AnswerChosenAt *time.Time `json:"answerChosenAt"`
+		Repository     struct {
+			NameWithOwner string `json:"nameWithOwner"`
+			IsPrivate     bool   `json:"isPrivate"`
+		} `json:"repository"`
+	}
+	// ghDiscussionComment is a discussion comment as served by the GraphQL
+	// API.
+	ghDiscussionComment struct {
+		ID         string       `json:"id"`
+		URL        string       `json:"url"`
+		CreatedAt  time.Time    `json:"createdAt"`
+		IsAnswer   bool         `json:"isAnswer"`
+		Discussion ghDiscussion `json:"discussion"`
+	}
+)
+
+// discussionsQuery reads the 50 latest discussions started by an account
+// and its 50 latest discussion comments. The comments can't be ordered, and
+// come oldest first, so the latest are the last ones.
+const discussionsQuery = `query($login: String!) {
+  user(login: $login) {
+    repositoryDiscussions(first: 50, orderBy: {field: CREATED_AT, direction: DESC}) {
+      nodes { ...discussion }
+    }
+    repositoryDiscussionComments(last: 50) {
+      nodes {
+        id
+        url
+        createdAt
+        isAnswer
+        discussion { ...discussion }
+      }
+    }
+  }
+}
+
+fragment discussion on Discussion {
+  number
+  title
+  url
+  createdAt
+  answerChosenAt
+  repository { nameWithOwner isPrivate }
+}`
+
+func newDiscussionsProvider(hc *client, apiURL string) *discussionsProvider {
+	return &discussionsProvider{hc: hc, endpoint: graphqlURL(apiURL)}
+}
+
+func (p *discussionsProvider) name() string { return "github-discussions" }
+
+// events returns the discussions started by the user, their comments and,
+// for comments chosen as the answer, the answering of the discussion.
+func (p *discussionsProvider) events(login string) ([]ghEvent, error) {
+	var data struct {
+		User *struct {
+			RepositoryDiscussions struct {
+				Nodes []ghDiscussion `json:"nodes"`
+			} `json:"repositoryDiscussions"`
+			RepositoryDiscussionComments struct {
+				Nodes []ghDiscussionComment `json:"nodes"`
+			} `json:"repositoryDiscussionComments"`
+		} `json:"user"`
+	}
+	if err := fetchGraphQL(p.hc, p.endpoint, discussionsQuery, map[string]any{"login": login}, &data); err != nil {
+		return nil, fmt.Errorf("fetch discussions of %s: %w", login, err)
+	}
+	if data.User == nil {
+		return nil, nil
+	}
+	var events []ghEvent
+	for _, d := range data.User.RepositoryDiscussions.Nodes {
+		events = append(events, d.event(login, "created", d.CreatedAt))
+	}
+	for _, c := range data.User.RepositoryDiscussionComments.Nodes {
+		ev := c.Discussion.event(login, "created", c.CreatedAt)
+		ev.ID, ev.Type = "discussion-comment-"+c.ID, "DiscussionCommentEvent"
+		ev.Payload.Discussion.HTMLURL = c.URL
+		events = append(events, ev)
+		if c.IsAnswer && c.Discussion.AnswerChosenAt != nil {
+			events = append(events, c.Discussion.event(login, "answered", *c.Discussion.AnswerChosenAt))
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].CreatedAt.After(events[j].CreatedAt) })
+	return events, nil
+}
+
+// event returns a DiscussionEvent of the discussion.
+func (d ghDiscussion) event(login, action string, at time.Time) ghEvent {
+	name := d.Repository.NameWithOwner
+	return ghEvent{
+		ID:    fmt.Sprintf("discussion-%s-%d-%s", name, d.Number, action),
+		Type:  "DiscussionEvent",
+		Actor: actor{Login: login},
+		Repo:  repo{Name: name},
+		Payload: payload{Action: action, Discussion: &discussion{
+			Number: d.Number, Title: d.Title, HTMLURL: d.URL,
+		}},
+		Public:    !d.Repository.IsPrivate,
+		CreatedAt: at,
+	}
+}
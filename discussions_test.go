@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUnitDiscussionsProviderEvents(t *testing.T) {
+	// Arrange
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assertEqual(t, r.URL.Path, "/graphql")
+		body, err := io.ReadAll(r.Body)
+		assertNoError(t, err)
+		assertEqual(t, strings.Contains(string(body), "repositoryDiscussionComments(last: 50)"), true)
+		fmt.Fprint(w, `{"data":{"user":{
+			"repositoryDiscussions":{"nodes":[
+				{"number":7,"title":"Roadmap","url":"https://github.com/octo/api/discussions/7",
+				 "createdAt":"2025-03-01T00:00:00Z","repository":{"nameWithOwner":"octo/api","isPrivate":false}}
+			]},
+			"repositoryDiscussionComments":{"nodes":[
+				{"id":"DC1","url":"https://github.com/octo/web/discussions/2#c1","createdAt":"2025-03-02T00:00:00Z",
+				 "isAnswer":true,"discussion":{"number":2,"title":"How to build?",
+				 "url":"https://github.com/octo/web/discussions/2","createdAt":"2025-02-01T00:00:00Z",
+				 "answerChosenAt":"2025-03-03T00:00:00Z","repository":{"nameWithOwner":"octo/web","isPrivate":true}}}
+			]}
+		}}}`)
+	}))
+	defer srv.Close()
+	p := newDiscussionsProvider(newClient(""), srv.URL)
+	// Act
+	events, err := p.events("octocat")
+	// Assert
+	assertNoError(t, err)
+	assertEqual(t, len(events), 3)
+	answered, comment, started := events[0], events[1], events[2]
+	assertEqual(t, answered.Type, "DiscussionEvent")
+	assertEqual(t, answered.Payload.Action, "answered")
+	assertEqual(t, answered.CreatedAt, time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC))
+	assertEqual(t, answered.Public, false)
+	assertEqual(t, comment.ID, "discussion-comment-DC1")
+	assertEqual(t, comment.Type, "DiscussionCommentEvent")
+	assertEqual(t, comment.Payload.Discussion.HTMLURL, "https://github.com/octo/web/discussions/2#c1")
+	assertEqual(t, started.ID, "discussion-octo/api-7-created")
+	assertEqual(t, started.Public, true)
+	assertEqual(t, *started.Payload.Discussion, discussion{
+		Number: 7, Title: "Roadmap", HTMLURL: "https://github.com/octo/api/discussions/7",
+	})
+}
+
+func TestUnitSummarizeDiscussions(t *testing.T) {
+	testCases := []struct {
+		name string
+		ev   ghEvent
+		want string
+	}{
+		{
+			name: "started",
+			ev:   ghEvent{Type: "DiscussionEvent", Repo: repo{Name: "octo/api"}, Payload: payload{Action: "created"}},
+			want: "Started a discussion in octo/api",
+		},
+		{
+			name: "answered",
+			ev:   ghEvent{Type: "DiscussionEvent", Repo: repo{Name: "octo/api"}, Payload: payload{Action: "answered"}},
+			want: "Answered a discussion in octo/api",
+		},
+		{
+			name: "commented",
+			ev:   ghEvent{Type: "DiscussionCommentEvent", Repo: repo{Name: "octo/api"}},
+			want: "Commented on a discussion in octo/api",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			got := summarize(lookupCatalog("en"), tc.ev)
+			// Assert
+			assertEqual(t, got, tc.want)
+		})
+	}
+}
@@ -0,0 +1,363 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// diagnosis is the outcome of one check of config doctor, with the fix of
+// a warning or failure.
+type diagnosis struct {
+	check  string
+	status string
+	detail string
+	fix    string
+}
+
+// Statuses of a diagnosis.
+const (
+	doctorOK   = "ok"
+	doctorWarn = "warn"
+	doctorFail = "fail"
+)
+
+// configCommands lists the subcommands about the configuration.
+var configCommands = map[string]command{
+	"doctor": runDoctor,
+}
+
+// configSchema lists the top-level settings of the configuration file with
+// their kind: scalar, list or section.
+var configSchema = map[string]string{
+	"api_url": "scalar", "github_token": "scalar", "token_cmd": "scalar", "token_file": "scalar",
+	"token_keychain": "scalar", "user": "scalar", "output": "scalar", "lang": "scalar", "cache_ttl": "scalar",
+	"ignore_file": "scalar", "api_version": "scalar", "usage_log": "scalar",
+	"github_tokens": "list", "sources": "list", "notifiers": "list", "rules": "list", "people": "list",
+	"schedule": "list", "tenants": "list", "clients": "list",
+	"profiles": "section", "icons": "section", "archive": "section", "serve": "section", "sheets": "section",
+	"summarizer": "section", "tracker": "section", "notes": "section", "emails": "section", "redact": "section",
+	"gitlab": "section", "gitea": "section", "bitbucket": "section", "security": "section", "releases": "section",
+	"advisories": "section", "traffic": "section", "watch": "section",
+}
+
+// configDurations lists the settings holding a duration.
+var configDurations = []string{"cache_ttl", "serve.cache_ttl", "serve.stale_ttl", "serve.stream_interval",
+	"watch.interval"}
+
+// ruleKeys lists the routing rule lists of the configuration.
+var ruleKeys = []string{"rules", "security.rules", "releases.rules", "advisories.rules"}
+
+// runConfig dispatches a config subcommand.
+func runConfig(args []string, stdout io.Writer) error {
+	if len(args) > 0 {
+		if cmd, ok := configCommands[args[0]]; ok {
+			return cmd(args[1:], stdout)
+		}
+	}
+	return fmt.Errorf("usage: go-github-activity config %s [flags]", strings.Join(sortedKeys(configCommands), "|"))
+}
+
+// runDoctor checks the configuration file against its schema, the token,
+// the storage directory and the reachability of the notifiers, and prints
+// a fix for every problem. It fails when a check fails.
+func runDoctor(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("config doctor", flag.ContinueOnError)
+	offline := flags.Bool("offline", false, "skip the checks needing the network")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		return errors.New("usage: go-github-activity config doctor [flags]")
+	}
+	dir, err := appDir(&defaultUserHome{})
+	if err != nil {
+		return err
+	}
+	cfgPath := filepath.Join(dir, "config.yaml")
+	if err := loadConfig(); err != nil {
+		return writeDiagnoses(stdout, []diagnosis{{
+			check: "config", status: doctorFail, detail: err.Error(),
+			fix: "fix " + cfgPath + " or the credential settings it names",
+		}})
+	}
+	ds := checkConfigFile(cfgPath)
+	ds = append(ds, checkSchema(viper.AllSettings())...)
+	ds = append(ds, checkSettings()...)
+	ds = append(ds, checkStorage(dir)...)
+	if !*offline {
+		hc := configuredClient()
+		ds = append(ds, checkTokenHealth(hc, viper.GetString("api_url")))
+		var cfgs []notifierConfig
+		if err := viper.UnmarshalKey("notifiers", &cfgs); err == nil {
+			ds = append(ds, checkReachability(cfgs, net.DialTimeout)...)
+		}
+	}
+	return writeDiagnoses(stdout, ds)
+}
+
+// checkConfigFile reports a missing configuration file, and one readable
+// by other users while holding a token.
+func checkConfigFile(path string) []diagnosis {
+	info, err := os.Stat(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return []diagnosis{{
+			check: "config", status: doctorWarn, detail: "no configuration file, using the defaults",
+			fix: "create " + path + " to set a token and options",
+		}}
+	case err != nil:
+		return []diagnosis{{
+			check: "config", status: doctorFail, detail: err.Error(), fix: "check the permissions of " + path,
+		}}
+	}
+	d := diagnosis{check: "config", status: doctorOK, detail: path}
+	if info.Mode().Perm()&0o077 != 0 && viper.GetString("github_token") != "" {
+		d.status, d.detail = doctorWarn, fmt.Sprintf("%s holds a token and has mode %s", path, info.Mode().Perm())
+		d.fix = "run chmod 600 " + path
+	}
+	return []diagnosis{d}
+}
+
+// checkSchema reports the unknown top-level settings, suggesting the
+// closest known one, and the settings of the wrong kind.
+func checkSchema(settings map[string]any) []diagnosis {
+	var ds []diagnosis
+	for _, key := range sortedKeys(settings) {
+		kind, ok := configSchema[key]
+		if !ok {
+			d := diagnosis{check: "schema", status: doctorWarn, detail: fmt.Sprintf("unknown setting %q", key),
+				fix: "remove it"}
+			if near := closestKey(key); near != "" {
+				d.fix = fmt.Sprintf("did you mean %q?", near)
+			}
+			ds = append(ds, d)
+			continue
+		}
+		if got := settingKind(settings[key]); got != kind {
+			ds = append(ds, diagnosis{
+				check: "schema", status: doctorFail, detail: fmt.Sprintf("%s is a %s, not a %s", key, got, kind),
+				fix: fmt.Sprintf("write %s as a %s", key, kind),
+			})
+		}
+	}
+	if len(ds) == 0 {
+		ds = append(ds, diagnosis{check: "schema", status: doctorOK, detail: "every setting is known"})
+	}
+	return ds
+}
+
+// settingKind returns the schema kind of a decoded YAML value.
+func settingKind(v any) string {
+	switch v.(type) {
+	case []any:
+		return "list"
+	case map[string]any:
+		return "section"
+	}
+	return "scalar"
+}
+
+// closestKey returns the known setting within two edits of key, if any.
+func closestKey(key string) string {
+	best, bestDist := "", 3
+	for _, known := range sortedKeys(configSchema) {
+		if d := editDistance(key, known); d < bestDist {
+			best, bestDist = known, d
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between two strings.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+// checkSettings parses the durations, sources, notifiers, routing rules,
+// schedule, tenants and ignore files the way the commands do.
+func checkSettings() []diagnosis {
+	var ds []diagnosis
+	for _, key := range configDurations {
+		if s := viper.GetString(key); s != "" {
+			if _, err := time.ParseDuration(s); err != nil {
+				ds = append(ds, diagnosis{
+					check: "settings", status: doctorFail, detail: fmt.Sprintf("%s: invalid duration %q", key, s),
+					fix: "use a duration such as 30s, 10m or 24h",
+				})
+			}
+		}
+	}
+	fail := func(check string, err error, fix string) {
+		ds = append(ds, diagnosis{check: check, status: doctorFail, detail: err.Error(), fix: fix})
+	}
+	if err := checkAPIVersion(apiVersion()); err != nil {
+		fail("api_version", err, "set a REST API version date such as "+defaultAPIVersion+", or remove it")
+	}
+	if _, err := configuredSources(); err != nil {
+		fail("sources", err, "give each source a known type and a distinct name")
+	}
+	targets, err := loadNotifiers()
+	if err != nil {
+		fail("notifiers", err, "complete or remove the notifier")
+	} else {
+		for _, key := range ruleKeys {
+			if _, err := loadRules(key, targets); err != nil {
+				fail(key, err, "route the rule to a configured notifier")
+			}
+		}
+	}
+	if _, err := loadSchedule(); err != nil {
+		fail("schedule", err, "use a five-field cron expression such as \"0 9 * * 1-5\"")
+	}
+	if _, err := loadTenants(); err != nil {
+		fail("tenants", err, "give each tenant a token and its users")
+	}
+	if _, err := loadIgnoreRules(); err != nil {
+		fail("ignore", err, "write one repo, type, actor or message pattern per line")
+	}
+	if len(ds) == 0 {
+		ds = append(ds, diagnosis{check: "settings", status: doctorOK, detail: "every setting parses"})
+	}
+	return ds
+}
+
+// checkStorage checks that the application directory, holding the cache
+// and the archives, is writable.
+func checkStorage(dir string) []diagnosis {
+	if _, err := os.Stat(dir); errors.Is(err, fs.ErrNotExist) {
+		return []diagnosis{{
+			check: "storage", status: doctorWarn, detail: dir + " does not exist yet",
+			fix: "run mkdir -m 700 " + dir + ", or let the first sync create it",
+		}}
+	}
+	f, err := os.CreateTemp(dir, ".doctor-*")
+	if err != nil {
+		return []diagnosis{{
+			check: "storage", status: doctorFail, detail: fmt.Sprintf("%s is not writable: %v", dir, err),
+			fix: "run chmod u+rwx " + dir,
+		}}
+	}
+	f.Close()
+	os.Remove(f.Name())
+	return []diagnosis{{check: "storage", status: doctorOK, detail: dir + " is writable"}}
+}
+
+// checkTokenHealth checks the token against the API.
+func checkTokenHealth(hc *client, base string) diagnosis {
+	if hc.Token == "" {
+		return diagnosis{
+			check: "token", status: doctorWarn, detail: "no token: public activity only, 60 requests an hour",
+			fix: "set github_token, token_cmd, token_file or token_keychain, or $GITHUB_TOKEN",
+		}
+	}
+	st, err := checkToken(hc, base)
+	if err != nil {
+		return diagnosis{check: "token", status: doctorFail, detail: err.Error(),
+			fix: "create a new token at https://github.com/settings/tokens"}
+	}
+	return diagnosis{check: "token", status: doctorOK, detail: "logged in as " + st.login}
+}
+
+// checkReachability opens a connection to the endpoint of every notifier.
+func checkReachability(cfgs []notifierConfig, dial func(network, addr string, timeout time.Duration) (net.Conn, error),
+) []diagnosis {
+	var ds []diagnosis
+	for _, cfg := range cfgs {
+		name := orDefault(cfg.Name, cfg.Type)
+		check := "notifier " + name
+		addr, err := notifierAddr(cfg)
+		if err != nil {
+			ds = append(ds, diagnosis{check: check, status: doctorFail, detail: err.Error(),
+				fix: "set the URL of the notifier"})
+			continue
+		}
+		conn, err := dial("tcp", addr, 5*time.Second)
+		if err != nil {
+			ds = append(ds, diagnosis{check: check, status: doctorFail, detail: fmt.Sprintf("%s unreachable: %v", addr, err),
+				fix: "check the URL, the DNS and the proxy settings"})
+			continue
+		}
+		conn.Close()
+		ds = append(ds, diagnosis{check: check, status: doctorOK, detail: addr + " reachable"})
+	}
+	return ds
+}
+
+// notifierAddr returns the host:port a notifier sends to.
+func notifierAddr(cfg notifierConfig) (string, error) {
+	var endpoint string
+	switch cfg.Type {
+	case "matrix":
+		endpoint = cfg.Homeserver
+	case "ntfy":
+		endpoint = orDefault(cfg.Server, ntfyServer)
+	case "telegram":
+		endpoint = telegramAPIURL
+	case "pushover":
+		endpoint = pushoverAPIURL
+	default:
+		endpoint = cfg.URL
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return "", fmt.Errorf("invalid endpoint %q", endpoint)
+	}
+	port := u.Port()
+	if port == "" {
+		port = "443"
+		if u.Scheme == "http" {
+			port = "80"
+		}
+	}
+	return net.JoinHostPort(u.Hostname(), port), nil
+}
+
+// writeDiagnoses prints one line per check, followed by its fix, and fails
+// when a check failed.
+func writeDiagnoses(w io.Writer, ds []diagnosis) error {
+	failed := 0
+	for _, d := range ds {
+		if _, err := fmt.Fprintf(w, "[%s] %s: %s\n", d.status, d.check, d.detail); err != nil {
+			return err
+		}
+		if d.fix != "" && d.status != doctorOK {
+			if _, err := fmt.Fprintf(w, "       fix: %s\n", d.fix); err != nil {
+				return err
+			}
+		}
+		if d.status == doctorFail {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("config doctor: %d check%s failed", failed, plural(failed))
+	}
+	return nil
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func TestUnitCheckSchema(t *testing.T) {
+	testCases := []struct {
+		name     string
+		settings map[string]any
+		want     string
+	}{
+		{
+			name:     "valid",
+			settings: map[string]any{"github_token": "t", "notifiers": []any{}, "serve": map[string]any{}},
+			want:     "[ok schema every setting is known ]",
+		},
+		{
+			name:     "typo",
+			settings: map[string]any{"notifier": []any{}},
+			want:     `[warn schema unknown setting "notifier" did you mean "notifiers"?]`,
+		},
+		{
+			name:     "unknown",
+			settings: map[string]any{"colour_scheme": "dark"},
+			want:     `[warn schema unknown setting "colour_scheme" remove it]`,
+		},
+		{
+			name:     "wrong kind",
+			settings: map[string]any{"github_tokens": "a,b"},
+			want:     "[fail schema github_tokens is a scalar, not a list write github_tokens as a list]",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			got := checkSchema(tc.settings)
+			// Assert
+			assertEqual(t, len(got), 1)
+			assertEqual(t, fmt.Sprint([]string{got[0].status, got[0].check, got[0].detail, got[0].fix}), tc.want)
+		})
+	}
+}
+
+func TestUnitEditDistance(t *testing.T) {
+	testCases := []struct {
+		a, b string
+		want int
+	}{
+		{a: "", b: "abc", want: 3},
+		{a: "notifier", b: "notifiers", want: 1},
+		{a: "github_tokne", b: "github_token", want: 2},
+		{a: "lang", b: "lang", want: 0},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.a+"/"+tc.b, func(t *testing.T) {
+			// Act
+			got := editDistance(tc.a, tc.b)
+			// Assert
+			assertEqual(t, got, tc.want)
+		})
+	}
+}
+
+func TestUnitCheckSettings(t *testing.T) {
+	testCases := []struct {
+		name       string
+		settings   map[string]any
+		wantChecks string
+	}{
+		{name: "valid", wantChecks: "[settings]"},
+		{name: "duration", settings: map[string]any{"cache_ttl": "1 day"}, wantChecks: "[settings]"},
+		{
+			name:       "rule without notifier",
+			settings:   map[string]any{"releases.rules": []map[string]any{{"notify": []string{"slack"}}}},
+			wantChecks: "[releases.rules]",
+		},
+		{
+			name:       "source type",
+			settings:   map[string]any{"sources": []map[string]any{{"type": "svn"}}},
+			wantChecks: "[sources]",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			t.Setenv("HOME", t.TempDir())
+			for k, v := range tc.settings {
+				viper.Set(k, v)
+			}
+			t.Cleanup(viper.Reset)
+			// Act
+			got := checkSettings()
+			// Assert
+			var checks []string
+			for _, d := range got {
+				checks = append(checks, d.check)
+			}
+			assertEqual(t, fmt.Sprint(checks), tc.wantChecks)
+			assertEqual(t, got[0].status == doctorOK, len(tc.settings) == 0)
+		})
+	}
+}
+
+func TestUnitCheckStorage(t *testing.T) {
+	testCases := []struct {
+		name       string
+		dir        func(t *testing.T) string
+		wantStatus string
+	}{
+		{name: "writable", dir: func(t *testing.T) string { return t.TempDir() }, wantStatus: doctorOK},
+		{
+			name:       "missing",
+			dir:        func(t *testing.T) string { return filepath.Join(t.TempDir(), "missing") },
+			wantStatus: doctorWarn,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			got := checkStorage(tc.dir(t))
+			// Assert
+			assertEqual(t, got[0].status, tc.wantStatus)
+		})
+	}
+}
+
+func TestUnitCheckTokenHealth(t *testing.T) {
+	testCases := []struct {
+		name       string
+		token      string
+		status     int
+		wantStatus string
+		wantDetail string
+	}{
+		{name: "no token", wantStatus: doctorWarn, wantDetail: "no token"},
+		{name: "valid", token: "good", status: http.StatusOK, wantStatus: doctorOK, wantDetail: "logged in as octocat"},
+		{name: "revoked", token: "bad", status: http.StatusUnauthorized, wantStatus: doctorFail, wantDetail: "invalid"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.status)
+				w.Write([]byte(`{"login":"octocat"}`))
+			}))
+			t.Cleanup(srv.Close)
+			// Act
+			got := checkTokenHealth(newClient(tc.token), srv.URL)
+			// Assert
+			assertEqual(t, got.status, tc.wantStatus)
+			assertEqual(t, strings.Contains(got.detail, tc.wantDetail), true)
+		})
+	}
+}
+
+func TestUnitNotifierAddr(t *testing.T) {
+	testCases := []struct {
+		name    string
+		cfg     notifierConfig
+		want    string
+		wantErr bool
+	}{
+		{name: "webhook", cfg: notifierConfig{Type: "webhook", URL: "http://hooks.local/x"}, want: "hooks.local:80"},
+		{name: "matrix port", cfg: notifierConfig{Type: "matrix", Homeserver: "https://m.org:8448"}, want: "m.org:8448"},
+		{name: "default ntfy", cfg: notifierConfig{Type: "ntfy"}, want: "ntfy.sh:443"},
+		{name: "telegram", cfg: notifierConfig{Type: "telegram"}, want: "api.telegram.org:443"},
+		{name: "no URL", cfg: notifierConfig{Type: "webhook"}, wantErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			got, err := notifierAddr(tc.cfg)
+			// Assert
+			if tc.wantErr {
+				assertNotNil(t, err)
+				return
+			}
+			assertNoError(t, err)
+			assertEqual(t, got, tc.want)
+		})
+	}
+}
+
+func TestUnitCheckReachability(t *testing.T) {
+	// Arrange
+	cfgs := []notifierConfig{
+		{Name: "ops", Type: "webhook", URL: "https://up.example/hook"},
+		{Type: "webhook", URL: "https://down.example/hook"},
+	}
+	dial := func(network, addr string, _ time.Duration) (net.Conn, error) {
+		if addr == "down.example:443" {
+			return nil, errors.New("no such host")
+		}
+		client, server := net.Pipe()
+		server.Close()
+		return client, nil
+	}
+	// Act
+	got := checkReachability(cfgs, dial)
+	// Assert
+	assertEqual(t, len(got), 2)
+	assertEqual(t, got[0].check+" "+got[0].status, "notifier ops ok")
+	assertEqual(t, got[1].check+" "+got[1].status, "notifier webhook fail")
+}
+
+func TestUnitWriteDiagnoses(t *testing.T) {
+	// Arrange
+	ds := []diagnosis{
+		{check: "token", status: doctorOK, detail: "logged in as octocat", fix: "unused"},
+		{check: "storage", status: doctorFail, detail: "not writable", fix: "run chmod u+rwx dir"},
+	}
+	var b strings.Builder
+	// Act
+	err := writeDiagnoses(&b, ds)
+	// Assert
+	assertNotNil(t, err)
+	assertEqual(t, b.String(), "[ok] token: logged in as octocat\n"+
+		"[fail] storage: not writable\n       fix: run chmod u+rwx dir\n")
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+type (
+	// domainCount is the number of commits authored with an email domain.
+	domainCount struct {
+		domain  string
+		kind    string
+		commits int
+	}
+	// emailReport breaks the commits down by author email domain and lists
+	// the addresses used in the wrong kind of repository.
+	emailReport struct {
+		domains []domainCount
+		// personalInWork counts the commits pushed to work repositories
+		// with a non-work email, by address.
+		personalInWork map[string]int
+		// workInPersonal counts the commits pushed to personal repositories
+		// with a work email, by address.
+		workInPersonal map[string]int
+	}
+)
+
+// Email domain kinds.
+const (
+	workEmail     = "work"
+	personalEmail = "personal"
+	noreplyEmail  = "noreply"
+	otherEmail    = "other"
+)
+
+// personalDomains lists the common personal mail providers.
+var personalDomains = map[string]bool{
+	"gmail.com": true, "googlemail.com": true, "outlook.com": true, "hotmail.com": true, "live.com": true,
+	"yahoo.com": true, "icloud.com": true, "me.com": true, "protonmail.com": true, "proton.me": true,
+	"gmx.com": true, "gmx.de": true, "fastmail.com": true, "hey.com": true, "aol.com": true,
+}
+
+// emailDomain returns the lowercased domain of an address, empty if none.
+func emailDomain(email string) string {
+	_, domain, ok := strings.Cut(email, "@")
+	if !ok {
+		return ""
+	}
+	return strings.ToLower(domain)
+}
+
+// domainKind classifies a domain as work, personal, noreply or other.
+func domainKind(domain string, workDomains map[string]bool) string {
+	switch {
+	case workDomains[domain]:
+		return workEmail
+	case personalDomains[domain]:
+		return personalEmail
+	case domain == "users.noreply.github.com":
+		return noreplyEmail
+	default:
+		return otherEmail
+	}
+}
+
+// maskEmail keeps the first letter and the domain of an address, e.g.
+// "o***@example.com".
+func maskEmail(email string) string {
+	local, domain, ok := strings.Cut(email, "@")
+	if !ok || local == "" {
+		return "***"
+	}
+	return local[:1] + "***@" + domain
+}
+
+// lowerSet returns the lowercased values as a set.
+func lowerSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[strings.ToLower(strings.TrimSpace(v))] = true
+	}
+	return set
+}
+
+// emailDomains aggregates the pushed commits by author email domain. The
+// repositories of the work owners are work repositories; without any, no
+// attribution mistake is reported.
+func emailDomains(events []ghEvent, workDomains, workOwners map[string]bool) emailReport {
+	r := emailReport{personalInWork: map[string]int{}, workInPersonal: map[string]int{}}
+	counts := map[string]int{}
+	for _, ev := range events {
+		if ev.Type != "PushEvent" {
+			continue
+		}
+		workRepo := workOwners[strings.ToLower(repoOwner(ev.Repo.Name))]
+		for _, c := range ev.Payload.Commits {
+			domain := emailDomain(c.Author.Email)
+			if domain == "" {
+				continue
+			}
+			counts[domain]++
+			kind := domainKind(domain, workDomains)
+			switch {
+			case len(workOwners) == 0 || kind == noreplyEmail:
+			case workRepo && kind != workEmail:
+				r.personalInWork[c.Author.Email]++
+			case !workRepo && kind == workEmail:
+				r.workInPersonal[c.Author.Email]++
+			}
+		}
+	}
+	for domain, n := range counts {
+		r.domains = append(r.domains, domainCount{domain: domain, kind: domainKind(domain, workDomains), commits: n})
+	}
+	sort.Slice(r.domains, func(i, j int) bool {
+		if r.domains[i].commits != r.domains[j].commits {
+			return r.domains[i].commits > r.domains[j].commits
+		}
+		return r.domains[i].domain < r.domains[j].domain
+	})
+	return r
+}
+
+// emailsView prints the commits per author email domain and the commits
+// attributed with the wrong email, configured with emails.work_domains and
+// emails.work_owners.
+func emailsView(w io.Writer, events []ghEvent, opts statsOptions) error {
+	r := emailDomains(events, lowerSet(viper.GetStringSlice("emails.work_domains")),
+		lowerSet(viper.GetStringSlice("emails.work_owners")))
+	return writeEmailReport(w, r, opts.showEmails)
+}
+
+// writeEmailReport prints the domains, then the mistakes with the addresses
+// masked unless showEmails is set.
+func writeEmailReport(w io.Writer, r emailReport, showEmails bool) error {
+	if len(r.domains) == 0 {
+		_, err := fmt.Fprintln(w, "no commits")
+		return err
+	}
+	for _, d := range r.domains {
+		if _, err := fmt.Fprintf(w, "%-30s %-8s %5d\n", d.domain, d.kind, d.commits); err != nil {
+			return err
+		}
+	}
+	mistakes := []struct {
+		what   string
+		emails map[string]int
+	}{
+		{what: "with a non-work email to work repositories", emails: r.personalInWork},
+		{what: "with a work email to personal repositories", emails: r.workInPersonal},
+	}
+	for _, m := range mistakes {
+		total := 0
+		for _, n := range m.emails {
+			total += n
+		}
+		if total == 0 {
+			continue
+		}
+		if _, err := fmt.Fprintf(w, "\n%d commit%s pushed %s:\n", total, plural(total), m.what); err != nil {
+			return err
+		}
+		for _, email := range sortedKeys(m.emails) {
+			shown := email
+			if !showEmails {
+				shown = maskEmail(email)
+			}
+			if _, err := fmt.Fprintf(w, "  %-30s %5d\n", shown, m.emails[email]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnitDomainKind(t *testing.T) {
+	work := map[string]bool{"acme.com": true}
+	testCases := []struct {
+		name   string
+		domain string
+		want   string
+	}{
+		{name: "work", domain: "acme.com", want: workEmail},
+		{name: "personal", domain: "gmail.com", want: personalEmail},
+		{name: "noreply", domain: "users.noreply.github.com", want: noreplyEmail},
+		{name: "other", domain: "example.org", want: otherEmail},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			got := domainKind(tc.domain, work)
+			// Assert
+			assertEqual(t, got, tc.want)
+		})
+	}
+}
+
+func TestUnitMaskEmail(t *testing.T) {
+	testCases := []struct {
+		name  string
+		email string
+		want  string
+	}{
+		{name: "address", email: "octocat@gmail.com", want: "o***@gmail.com"},
+		{name: "no at sign", email: "octocat", want: "***"},
+		{name: "empty local part", email: "@gmail.com", want: "***"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			got := maskEmail(tc.email)
+			// Assert
+			assertEqual(t, got, tc.want)
+		})
+	}
+}
+
+func emailEvents() []ghEvent {
+	push := func(repoName string, emails ...string) ghEvent {
+		ev := ghEvent{Type: "PushEvent", Repo: repo{Name: repoName}}
+		for _, e := range emails {
+			ev.Payload.Commits = append(ev.Payload.Commits, commit{Author: author{Email: e}})
+		}
+		return ev
+	}
+	return []ghEvent{
+		push("acme/api", "octo@acme.com", "octo@acme.com", "octocat@gmail.com"),
+		push("Acme/web", "octocat@gmail.com", "1+octocat@users.noreply.github.com"),
+		push("octocat/dotfiles", "octo@ACME.com", "octocat@gmail.com", ""),
+		{Type: "WatchEvent", Repo: repo{Name: "acme/api"}},
+	}
+}
+
+func TestUnitEmailDomains(t *testing.T) {
+	// Arrange
+	events := emailEvents()
+	// Act
+	r := emailDomains(events, map[string]bool{"acme.com": true}, map[string]bool{"acme": true})
+	// Assert
+	assertEqual(t, len(r.domains), 3)
+	assertEqual(t, r.domains[0], domainCount{domain: "acme.com", kind: workEmail, commits: 3})
+	assertEqual(t, r.domains[1], domainCount{domain: "gmail.com", kind: personalEmail, commits: 3})
+	assertEqual(t, r.domains[2].kind, noreplyEmail)
+	assertEqual(t, r.personalInWork["octocat@gmail.com"], 2)
+	assertEqual(t, r.workInPersonal["octo@ACME.com"], 1)
+}
+
+func TestUnitEmailDomainsWithoutWorkOwners(t *testing.T) {
+	// Act
+	r := emailDomains(emailEvents(), map[string]bool{"acme.com": true}, nil)
+	// Assert
+	assertEqual(t, len(r.personalInWork), 0)
+	assertEqual(t, len(r.workInPersonal), 0)
+}
+
+func TestUnitWriteEmailReport(t *testing.T) {
+	r := emailDomains(emailEvents(), map[string]bool{"acme.com": true}, map[string]bool{"acme": true})
+	testCases := []struct {
+		name       string
+		showEmails bool
+		want       string
+		hidden     string
+	}{
+		{name: "masked", want: "o***@gmail.com", hidden: "octocat@gmail.com"},
+		{name: "shown", showEmails: true, want: "octocat@gmail.com", hidden: "o***@gmail.com"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			var b strings.Builder
+			// Act
+			err := writeEmailReport(&b, r, tc.showEmails)
+			// Assert
+			assertNoError(t, err)
+			out := b.String()
+			assertEqual(t, strings.Contains(out, "2 commits pushed with a non-work email to work repositories"), true)
+			assertEqual(t, strings.Contains(out, "1 commit pushed with a work email to personal repositories"), true)
+			assertEqual(t, strings.Contains(out, tc.want), true)
+			assertEqual(t, strings.Contains(out, tc.hidden), false)
+		})
+	}
+}
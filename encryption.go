@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	// archiveKeySize is the size of the AES-256 key of the archive.
+	archiveKeySize = 32
+	// archiveKeyAccount is the account of the archive key in the OS keychain.
+	archiveKeyAccount = "archive"
+)
+
+var (
+	// errArchiveLocked reports an encrypted archive read without its key.
+	errArchiveLocked = errors.New("the archive is encrypted: set archive.key_file or archive.key_keychain")
+	// errArchiveClear reports a line in clear in an archive with a key, which
+	// only archive compact encrypts.
+	errArchiveClear = errors.New("line in clear in an encrypted archive: run archive compact to encrypt it")
+	// errArchiveLegacy reports a line encrypted without associated data by an
+	// earlier version, which only archive compact encrypts again.
+	errArchiveLegacy = errors.New("line encrypted by an earlier version: run archive compact to encrypt it again")
+)
+
+// archiveKeyProvider returns the configured source of the archive key, or
+// nil when the archive is stored in clear.
+func archiveKeyProvider() credentialProvider {
+	if service := viper.GetString("archive.key_keychain"); service != "" {
+		return keychainCredential{service: service, account: archiveKeyAccount, goos: runtime.GOOS, run: execOutput}
+	}
+	if path := viper.GetString("archive.key_file"); path != "" {
+		return fileCredential{path: path}
+	}
+	return nil
+}
+
+// archiveCipher reads the configured archive key and returns its AES-GCM
+// cipher, or nil when no key is configured.
+func archiveCipher() (cipher.AEAD, error) {
+	p := archiveKeyProvider()
+	if p == nil {
+		return nil, nil
+	}
+	encoded, err := p.token()
+	if err != nil {
+		return nil, fmt.Errorf("read archive key: %w", err)
+	}
+	return newArchiveCipher(strings.TrimSpace(encoded))
+}
+
+// newArchiveCipher returns the AES-GCM cipher of a base64 encoded 256-bit
+// key.
+func newArchiveCipher(encoded string) (cipher.AEAD, error) {
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("decode archive key: %w", err)
+	}
+	if len(key) != archiveKeySize {
+		return nil, fmt.Errorf("invalid archive key: %d bytes, want %d", len(key), archiveKeySize)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("create archive cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// runKeygen prints a new archive key.
+func runKeygen(args []string, stdout io.Writer) error {
+	if len(args) != 0 {
+		return errors.New("usage: go-github-activity archive keygen")
+	}
+	key, err := newArchiveKey()
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(stdout, key)
+	return err
+}
+
+// newArchiveKey returns a random base64 encoded archive key.
+func newArchiveKey() (string, error) {
+	key := make([]byte, archiveKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return "", fmt.Errorf("generate archive key: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// encode returns the archive line of an event, encrypted when the archive
+// has a cipher.
+func (a *archive) encode(ev ghEvent) ([]byte, error) {
+	byt, err := json.Marshal(ev)
+	if err != nil {
+		return nil, fmt.Errorf("encode event %s: %w", ev.ID, err)
+	}
+	if a.aead == nil {
+		return append(byt, '\n'), nil
+	}
+	nonce := make([]byte, a.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("encrypt event %s: %w", ev.ID, err)
+	}
+	sealed := a.aead.Seal(nonce, nonce, byt, a.associatedData())
+	line := make([]byte, base64.StdEncoding.EncodedLen(len(sealed))+1)
+	base64.StdEncoding.Encode(line, sealed)
+	line[len(line)-1] = '\n'
+	return line, nil
+}
+
+// decode reads an event from an archive line. With a key, lines in clear
+// are refused, as anyone able to write the archive could add events, unless
+// the archive is being upgraded: lines in clear, written before encryption
+// was enabled, and lines encrypted without associated data by earlier
+// versions are then read for compaction to encrypt them again.
+func (a *archive) decode(line []byte, ev *ghEvent) error {
+	if bytes.HasPrefix(line, []byte("{")) {
+		if a.aead != nil && !a.upgrade {
+			return errArchiveClear
+		}
+		return json.Unmarshal(line, ev)
+	}
+	if a.aead == nil {
+		return errArchiveLocked
+	}
+	sealed := make([]byte, base64.StdEncoding.DecodedLen(len(line)))
+	n, err := base64.StdEncoding.Decode(sealed, line)
+	if err != nil {
+		return fmt.Errorf("decode encrypted line: %w", err)
+	}
+	size := a.aead.NonceSize()
+	if n < size {
+		return errors.New("decrypt line: too short")
+	}
+	nonce, ciphertext := sealed[:size], sealed[size:n]
+	plain, err := a.aead.Open(nil, nonce, ciphertext, a.associatedData())
+	if err != nil {
+		legacy, errLegacy := a.aead.Open(nil, nonce, ciphertext, nil)
+		switch {
+		case errLegacy != nil:
+			return fmt.Errorf("decrypt line: %w", err)
+		case !a.upgrade:
+			return errArchiveLegacy
+		}
+		plain = legacy
+	}
+	return json.Unmarshal(plain, ev)
+}
+
+// associatedData binds the encrypted lines to the archive, named by its
+// profile directory and file, so that they cannot be moved to the archive
+// of another user or profile.
+func (a *archive) associatedData() []byte {
+	return []byte(filepath.Base(filepath.Dir(a.path)) + "/" + filepath.Base(a.path))
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUnitNewArchiveCipher(t *testing.T) {
+	key, errKey := newArchiveKey()
+	assertNoError(t, errKey)
+	testCases := []struct {
+		name    string
+		key     string
+		wantErr bool
+	}{
+		{name: "generated key", key: key},
+		{name: "not base64", key: "not a key!", wantErr: true},
+		{name: "short key", key: "c2hvcnQ=", wantErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			_, err := newArchiveCipher(tc.key)
+			// Assert
+			if tc.wantErr {
+				assertNotNil(t, err)
+				return
+			}
+			assertNoError(t, err)
+		})
+	}
+}
+
+func TestUnitEncryptedArchive(t *testing.T) {
+	// Arrange
+	key, _ := newArchiveKey()
+	aead, _ := newArchiveCipher(key)
+	path := filepath.Join(t.TempDir(), "octocat.jsonl")
+	a := &archive{path: path, aead: aead}
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	events := []ghEvent{
+		{ID: "1", Type: "PushEvent", Repo: repo{Name: "octocat/secret"}, CreatedAt: now},
+		{ID: "2", Type: "WatchEvent", Repo: repo{Name: "octocat/secret"}, CreatedAt: now.Add(time.Hour)},
+	}
+	// Act
+	_, errAdd := a.add(events)
+	loaded, err := a.load()
+	raw, _ := os.ReadFile(path)
+	_, errLocked := (&archive{path: path}).load()
+	// Assert
+	assertNoError(t, errAdd)
+	assertNoError(t, err)
+	assertEqual(t, len(loaded), 2)
+	assertEqual(t, loaded[0].ID, "2")
+	assertEqual(t, loaded[1].Repo.Name, "octocat/secret")
+	assertEqual(t, bytes.Contains(raw, []byte("octocat/secret")), false)
+	assertEqual(t, errors.Is(errLocked, errArchiveLocked), true)
+}
+
+func TestUnitEncryptedArchiveWrongKey(t *testing.T) {
+	// Arrange
+	key, _ := newArchiveKey()
+	other, _ := newArchiveKey()
+	aead, _ := newArchiveCipher(key)
+	wrong, _ := newArchiveCipher(other)
+	path := filepath.Join(t.TempDir(), "octocat.jsonl")
+	(&archive{path: path, aead: aead}).add([]ghEvent{{ID: "1"}})
+	// Act
+	_, err := (&archive{path: path, aead: wrong}).load()
+	// Assert
+	assertNotNil(t, err)
+}
+
+func TestUnitEncryptArchiveOnCompaction(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "octocat.jsonl")
+	line := `{"id":"1","repo":{"name":"octocat/secret"},"created_at":"2025-03-01T00:00:00Z"}` + "\n"
+	os.WriteFile(path, []byte(line), 0o600)
+	key, _ := newArchiveKey()
+	aead, _ := newArchiveCipher(key)
+	a := &archive{path: path, aead: aead}
+	// Act
+	_, errClear := a.load()
+	_, errCompact := (&archive{path: path, aead: aead, upgrade: true}).compact(retention{})
+	after, errAfter := a.load()
+	raw, _ := os.ReadFile(path)
+	// Assert
+	assertEqual(t, errors.Is(errClear, errArchiveClear), true)
+	assertNoError(t, errCompact)
+	assertNoError(t, errAfter)
+	assertEqual(t, len(after), 1)
+	assertEqual(t, strings.Contains(string(raw), "octocat/secret"), false)
+}
+
+func TestUnitEncryptedArchiveBound(t *testing.T) {
+	// Arrange
+	key, _ := newArchiveKey()
+	aead, _ := newArchiveCipher(key)
+	dir := t.TempDir()
+	octocat := &archive{path: filepath.Join(dir, "default", "octocat.jsonl"), aead: aead}
+	other := &archive{path: filepath.Join(dir, "default", "other.jsonl"), aead: aead}
+	octocat.add([]ghEvent{{ID: "1"}})
+	stolen, _ := os.ReadFile(octocat.path)
+	os.WriteFile(other.path, stolen, 0o600)
+	// Act
+	_, errMoved := other.load()
+	kept, errKept := octocat.load()
+	// Assert
+	assertNotNil(t, errMoved)
+	assertNoError(t, errKept)
+	assertEqual(t, len(kept), 1)
+}
+
+func TestUnitEncryptedArchiveUpgrade(t *testing.T) {
+	// Arrange
+	key, _ := newArchiveKey()
+	aead, _ := newArchiveCipher(key)
+	path := filepath.Join(t.TempDir(), "octocat.jsonl")
+	nonce := make([]byte, aead.NonceSize())
+	sealed := aead.Seal(nonce, nonce, []byte(`{"id":"1"}`), nil) // as sealed by earlier versions
+	os.WriteFile(path, []byte(base64.StdEncoding.EncodeToString(sealed)+"\n"), 0o600)
+	// Act
+	_, errLegacy := (&archive{path: path, aead: aead}).load()
+	_, errCompact := (&archive{path: path, aead: aead, upgrade: true}).compact(retention{})
+	after, errAfter := (&archive{path: path, aead: aead}).load()
+	// Assert
+	assertEqual(t, errors.Is(errLegacy, errArchiveLegacy), true)
+	assertNoError(t, errCompact)
+	assertNoError(t, errAfter)
+	assertEqual(t, len(after), 1)
+	assertEqual(t, after[0].ID, "1")
+}
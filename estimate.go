@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+type (
+	// budgetPlan compares the requests a run needs with those left to its
+	// tokens before the reset of their rate limit.
+	budgetPlan struct {
+		need  int
+		left  int
+		reset time.Time
+	}
+	// budgetError refuses a run that would exhaust the rate limit, with
+	// --strict-budget.
+	budgetError struct {
+		plan budgetPlan
+	}
+)
+
+// errBudgetDeclined reports that the user declined a run exceeding the rate
+// limit.
+var errBudgetDeclined = errors.New("run cancelled to preserve the rate limit")
+
+func (e *budgetError) Error() string {
+	return fmt.Sprintf("the run needs about %d requests but only %d are left before %s",
+		e.plan.need, e.plan.left, e.plan.reset.Local().Format(time.TimeOnly))
+}
+
+// checkRunBudget estimates the cost of fetching need requests against the
+// quota left to the fetcher's tokens, keeping orgBudgetReserve aside, and
+// lets the run go on per confirmBudget.
+func checkRunBudget(f *repoFetcher, need int, strict bool) error {
+	left, reset, err := quotaLeft(f.hc, f.base)
+	if isStatus(err, http.StatusNotFound) {
+		return nil // rate limiting disabled, e.g. on GitHub Enterprise Server
+	}
+	if err != nil {
+		return err
+	}
+	p := budgetPlan{need: need, left: max(left-orgBudgetReserve, 0), reset: reset}
+	return confirmBudget(p, strict, terminalPrompt(os.Stdin, os.Stderr), os.Stderr)
+}
+
+// quotaLeft sums the core requests left to the tokens of the client, read
+// from the rate limit API, which costs no request, and returns the latest
+// of their reset times.
+func quotaLeft(hc *client, base string) (int, time.Time, error) {
+	tokens := []string{hc.Token}
+	if hc.pool != nil {
+		tokens = hc.pool.usable()
+	}
+	var (
+		left  int
+		reset time.Time
+	)
+	for _, tok := range tokens {
+		var limits rateLimits
+		if err := fetchJSON(newClient(tok), base+"/rate_limit", &limits); err != nil {
+			return 0, time.Time{}, fmt.Errorf("fetch rate limit: %w", err)
+		}
+		core := limits.Resources["core"]
+		left += core.Remaining
+		if t := time.Unix(core.Reset, 0); t.After(reset) {
+			reset = t
+		}
+	}
+	return left, reset, nil
+}
+
+// confirmBudget lets a run that fits the quota go on. Otherwise it fails
+// with --strict-budget, asks the user when ask is set, or else warns and
+// goes on, the fetch skipping what the quota does not cover.
+func confirmBudget(p budgetPlan, strict bool, ask func(question string) (bool, error), stderr io.Writer) error {
+	if p.need <= p.left {
+		return nil
+	}
+	msg := (&budgetError{plan: p}).Error()
+	switch {
+	case strict:
+		return &budgetError{plan: p}
+	case ask != nil:
+		ok, err := ask(msg + "; continue? [y/N] ")
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errBudgetDeclined
+		}
+		return nil
+	}
+	return infof(stderr, "%s: going on, the requests past the limit will be skipped\n", msg)
+}
+
+// terminalPrompt returns a function asking a yes or no question on the
+// terminal, or nil when in is not a terminal or --no-input is set.
+func terminalPrompt(in *os.File, out io.Writer) func(string) (bool, error) {
+	fi, err := in.Stat()
+	if noInput || err != nil || fi.Mode()&os.ModeCharDevice == 0 {
+		return nil
+	}
+	return func(question string) (bool, error) {
+		if _, err := fmt.Fprint(out, question); err != nil {
+			return false, err
+		}
+		answer, err := bufio.NewReader(in).ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return false, fmt.Errorf("read answer: %w", err)
+		}
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		return answer == "y" || answer == "yes", nil
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestUnitConfirmBudget(t *testing.T) {
+	reset := time.Date(2025, 3, 1, 12, 0, 0, 0, time.Local)
+	yes := func(string) (bool, error) { return true, nil }
+	no := func(string) (bool, error) { return false, nil }
+	testCases := []struct {
+		name     string
+		need     int
+		strict   bool
+		ask      func(string) (bool, error)
+		wantErr  error
+		wantWarn bool
+	}{
+		{name: "fits", need: 100, strict: true},
+		{name: "strict", need: 900, strict: true, wantErr: &budgetError{}},
+		{name: "accepted", need: 900, ask: yes},
+		{name: "declined", need: 900, ask: no, wantErr: errBudgetDeclined},
+		{name: "no terminal", need: 900, wantWarn: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			var stderr bytes.Buffer
+			p := budgetPlan{need: tc.need, left: 500, reset: reset}
+			// Act
+			err := confirmBudget(p, tc.strict, tc.ask, &stderr)
+			// Assert
+			var budgetErr *budgetError
+			switch {
+			case tc.wantErr == errBudgetDeclined:
+				assertEqual(t, errors.Is(err, errBudgetDeclined), true)
+			case tc.wantErr != nil:
+				assertEqual(t, errors.As(err, &budgetErr), true)
+				assertEqual(t, err.Error(), "the run needs about 900 requests but only 500 are left before 12:00:00")
+			default:
+				assertNoError(t, err)
+			}
+			assertEqual(t, stderr.Len() > 0, tc.wantWarn)
+		})
+	}
+}
+
+func TestUnitQuotaLeft(t *testing.T) {
+	testCases := []struct {
+		name     string
+		pool     *tokenPool
+		wantLeft int
+	}{
+		{name: "single token", wantLeft: 300},
+		{name: "pool", pool: newTokenPool([]string{"a", "b", "c"}), wantLeft: 600},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			remaining := map[string]int{"Bearer a": 300, "Bearer b": 200, "Bearer c": 100}
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := remaining[r.Header.Get("Authorization")]
+				fmt.Fprintf(w, `{"resources":{"core":{"limit":5000,"remaining":%d,"reset":%d}}}`, n, 1700000000+n)
+			}))
+			t.Cleanup(srv.Close)
+			hc := newClient("a")
+			hc.pool = tc.pool
+			// Act
+			left, reset, err := quotaLeft(hc, srv.URL)
+			// Assert
+			assertNoError(t, err)
+			assertEqual(t, left, tc.wantLeft)
+			assertEqual(t, reset.Unix(), int64(1700000300))
+		})
+	}
+}
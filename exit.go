@@ -0,0 +1,137 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Exit codes of the command, stable for scripts.
+const (
+	exitOK          = 0
+	exitFailure     = 1 // usage error or any other failure
+	exitNotFound    = 2
+	exitRateLimited = 3
+	exitAuth        = 4
+	exitNetwork     = 5
+	exitEmpty       = 6 // nothing to show with --fail-on-empty
+)
+
+// exitKinds names the exit codes in JSON errors.
+var exitKinds = map[int]string{
+	exitFailure:     "error",
+	exitNotFound:    "not_found",
+	exitRateLimited: "rate_limited",
+	exitAuth:        "auth_failed",
+	exitNetwork:     "network",
+	exitEmpty:       "empty",
+}
+
+// jsonError is the body of an error written as JSON.
+type jsonError struct {
+	Kind     string     `json:"kind"`
+	Message  string     `json:"message"`
+	ExitCode int        `json:"exit_code"`
+	ResetAt  *time.Time `json:"reset_at,omitempty"`
+}
+
+var (
+	// errEmpty reports an empty result with --fail-on-empty.
+	errEmpty = errors.New("no activity found")
+	// errTokensRevoked reports that no token of the pool is valid anymore.
+	errTokensRevoked = errors.New("every token of the pool is revoked")
+)
+
+// exitCode maps an error to the exit code of its kind.
+func exitCode(err error) int {
+	var apiErr *apiError
+	var netErr net.Error
+	var urlErr *url.Error
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.Is(err, errEmpty):
+		return exitEmpty
+	case isRateLimited(err):
+		return exitRateLimited
+	case errors.Is(err, errTokensRevoked):
+		return exitAuth
+	case errors.As(err, &apiErr):
+		switch apiErr.StatusCode {
+		case http.StatusNotFound, http.StatusGone:
+			return exitNotFound
+		case http.StatusUnauthorized, http.StatusForbidden:
+			return exitAuth
+		}
+	case errors.As(err, &netErr), errors.As(err, &urlErr):
+		return exitNetwork
+	}
+	return exitFailure
+}
+
+// isRateLimited reports whether err comes from an exhausted rate limit, of
+// a token or of every token of the pool.
+func isRateLimited(err error) bool {
+	var apiErr *apiError
+	var poolErr *poolLimitError
+	var budgetErr *budgetError
+	if errors.As(err, &poolErr) || errors.As(err, &budgetErr) {
+		return true
+	}
+	return errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusTooManyRequests || !apiErr.ResetAt.IsZero())
+}
+
+// rateLimitReset returns when the exhausted rate limit behind err resets.
+func rateLimitReset(err error) (time.Time, bool) {
+	var apiErr *apiError
+	var poolErr *poolLimitError
+	var budgetErr *budgetError
+	switch {
+	case errors.As(err, &poolErr):
+		return poolErr.reset, true
+	case errors.As(err, &budgetErr):
+		return budgetErr.plan.reset, true
+	case errors.As(err, &apiErr) && !apiErr.ResetAt.IsZero():
+		return apiErr.ResetAt, true
+	}
+	return time.Time{}, false
+}
+
+// jsonOutput reports whether the output flag of the command line, or else
+// the output setting of the loaded configuration, selects JSON output.
+func jsonOutput(args []string) bool {
+	for i, arg := range args {
+		name, value, hasValue := strings.Cut(arg, "=")
+		if name != "--output" && name != "-output" {
+			continue
+		}
+		if !hasValue {
+			return i+1 < len(args) && args[i+1] == "json"
+		}
+		return value == "json"
+	}
+	return viper.GetString("output") == "json"
+}
+
+// reportError writes the error for humans, or as a JSON object such as
+// {"error":{"kind":"rate_limited","reset_at":...}} for wrappers.
+func reportError(w io.Writer, err error, asJSON bool) {
+	if !asJSON {
+		fmt.Fprintf(w, "go-github-activity: %v\n", err)
+		return
+	}
+	code := exitCode(err)
+	body := jsonError{Kind: exitKinds[code], Message: err.Error(), ExitCode: code}
+	if reset, ok := rateLimitReset(err); ok {
+		body.ResetAt = &reset
+	}
+	json.NewEncoder(w).Encode(map[string]jsonError{"error": body})
+}
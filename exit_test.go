@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func TestUnitExitCode(t *testing.T) {
+	testCases := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "success", err: nil, want: exitOK},
+		{name: "usage", err: errors.New("usage: go-github-activity [flags] <username>"), want: exitFailure},
+		{name: "empty", err: fmt.Errorf("list: %w", errEmpty), want: exitEmpty},
+		{name: "not found", err: fmt.Errorf("fetch: %w", &apiError{StatusCode: http.StatusNotFound}), want: exitNotFound},
+		{name: "unauthorized", err: &apiError{StatusCode: http.StatusUnauthorized}, want: exitAuth},
+		{name: "forbidden", err: &apiError{StatusCode: http.StatusForbidden}, want: exitAuth},
+		{
+			name: "rate limited",
+			err:  &apiError{StatusCode: http.StatusForbidden, ResetAt: time.Now().Add(time.Hour)},
+			want: exitRateLimited,
+		},
+		{name: "too many requests", err: &apiError{StatusCode: http.StatusTooManyRequests}, want: exitRateLimited},
+		{name: "pool rate limited", err: &poolLimitError{reset: time.Now()}, want: exitRateLimited},
+		{name: "pool revoked", err: errTokensRevoked, want: exitAuth},
+		{name: "over budget", err: &budgetError{plan: budgetPlan{need: 900, left: 100}}, want: exitRateLimited},
+		{name: "server error", err: &apiError{StatusCode: http.StatusBadGateway}, want: exitFailure},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			got := exitCode(tc.err)
+			// Assert
+			assertEqual(t, got, tc.want)
+		})
+	}
+}
+
+func TestUnitExitCodeFromClient(t *testing.T) {
+	// Arrange
+	reset := time.Now().Add(time.Hour).Unix()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset, 10))
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	unreachable := httptest.NewServer(http.NotFoundHandler())
+	unreachable.Close()
+	t.Cleanup(srv.Close)
+	// Act
+	_, limited := fetchGitHubResponse(newClient(""), srv.URL)
+	_, offline := fetchGitHubResponse(newClient(""), unreachable.URL)
+	// Assert
+	assertEqual(t, exitCode(limited), exitRateLimited)
+	assertEqual(t, exitCode(offline), exitNetwork)
+}
+
+func TestUnitJSONOutput(t *testing.T) {
+	testCases := []struct {
+		name   string
+		args   []string
+		config string
+		want   bool
+	}{
+		{name: "flag and value", args: []string{"--output", "json", "octocat"}, want: true},
+		{name: "flag with value", args: []string{"stats", "-output=json", "octocat"}, want: true},
+		{name: "other format", args: []string{"--output", "table", "octocat"}, want: false},
+		{name: "username json", args: []string{"json"}, want: false},
+		{name: "configured json", args: []string{"octocat"}, config: "json", want: true},
+		{name: "flag over config", args: []string{"--output=text", "octocat"}, config: "json", want: false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			viper.Set("output", tc.config)
+			t.Cleanup(viper.Reset)
+			// Act
+			got := jsonOutput(tc.args)
+			// Assert
+			assertEqual(t, got, tc.want)
+		})
+	}
+}
+
+func TestUnitReportError(t *testing.T) {
+	// Arrange
+	reset := time.Date(2025, 3, 10, 10, 0, 0, 0, time.UTC)
+	limited := fmt.Errorf("fetch GitHub response: %w", &apiError{StatusCode: 403, Status: "403 Forbidden", ResetAt: reset})
+	testCases := []struct {
+		name   string
+		err    error
+		asJSON bool
+		want   string
+	}{
+		{name: "text", err: errEmpty, want: "go-github-activity: no activity found\n"},
+		{
+			name: "json", err: errEmpty, asJSON: true,
+			want: `{"error":{"kind":"empty","message":"no activity found","exit_code":6}}` + "\n",
+		},
+		{
+			name: "json rate limited", err: limited, asJSON: true,
+			want: `{"error":{"kind":"rate_limited","message":"fetch GitHub response: GitHub API client error: ` +
+				`\"403 Forbidden\"","exit_code":3,"reset_at":"2025-03-10T10:00:00Z"}}` + "\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			var buf bytes.Buffer
+			// Act
+			reportError(&buf, tc.err, tc.asJSON)
+			// Assert
+			assertEqual(t, buf.String(), tc.want)
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"flag"
+	"strings"
+)
+
+type (
+	// eventFilter reports whether an event should be kept.
+	eventFilter func(ev ghEvent) bool
+	// repoFilterFlags holds the repository filters shared by the commands.
+	repoFilterFlags struct {
+		forks    bool
+		archived bool
+		orgs     string
+		kinds    string
+	}
+)
+
+// register adds the repository filter flags to a command.
+func (f *repoFilterFlags) register(flags *flag.FlagSet) {
+	flags.BoolVar(&f.forks, "exclude-forks", false, "drop events in forked repositories")
+	flags.BoolVar(&f.archived, "exclude-archived", false, "drop events in archived repositories")
+	flags.StringVar(&f.orgs, "org-only", "", "keep events in repositories of these owners (comma-separated)")
+	flags.StringVar(&f.kinds, "commit-kind", "",
+		"keep pushes with commits of these conventional commit types, e.g. fix or feat,fix")
+}
+
+// filters returns the selected filters.
+func (f *repoFilterFlags) filters() []eventFilter {
+	var filters []eventFilter
+	if f.forks {
+		filters = append(filters, excludeForks)
+	}
+	if f.archived {
+		filters = append(filters, excludeArchived)
+	}
+	if f.orgs != "" {
+		filters = append(filters, onlyOwners(strings.Split(f.orgs, ",")))
+	}
+	if f.kinds != "" {
+		filters = append(filters, onlyCommitKinds(strings.Split(f.kinds, ",")))
+	}
+	return filters
+}
+
+// apply resolves repository metadata when a filter needs it and keeps the
+// events the filters accept.
+func (f *repoFilterFlags) apply(events []ghEvent) ([]ghEvent, error) {
+	if f.forks || f.archived {
+		if err := enrichEvents(events, false); err != nil {
+			return nil, err
+		}
+	}
+	return applyFilters(events, f.filters()...), nil
+}
+
+// applyFilters keeps the events accepted by every filter.
+func applyFilters(events []ghEvent, filters ...eventFilter) []ghEvent {
+	if len(filters) == 0 {
+		return events
+	}
+	kept := make([]ghEvent, 0, len(events))
+next:
+	for _, ev := range events {
+		for _, keep := range filters {
+			if !keep(ev) {
+				continue next
+			}
+		}
+		kept = append(kept, ev)
+	}
+	return kept
+}
+
+// excludeForks drops events in forked repositories; it needs enriched events.
+func excludeForks(ev ghEvent) bool {
+	return ev.Repo.Meta == nil || !ev.Repo.Meta.Fork
+}
+
+// excludeArchived drops events in archived repositories; it needs enriched events.
+func excludeArchived(ev ghEvent) bool {
+	return ev.Repo.Meta == nil || !ev.Repo.Meta.Archived
+}
+
+// onlyOwners keeps events in repositories owned by one of the given users
+// or organizations, compared case-insensitively.
+func onlyOwners(owners []string) eventFilter {
+	set := make(map[string]bool, len(owners))
+	for _, o := range owners {
+		set[strings.ToLower(strings.TrimSpace(o))] = true
+	}
+	return func(ev ghEvent) bool {
+		return set[strings.ToLower(repoOwner(ev.Repo.Name))]
+	}
+}
+
+// repoLabels flags events in forks or archived repositories.
+func repoLabels(meta *repoMeta) []string {
+	var labels []string
+	if meta != nil && meta.Fork {
+		labels = append(labels, "fork")
+	}
+	if meta != nil && meta.Archived {
+		labels = append(labels, "archived")
+	}
+	return labels
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"flag"
+	"strings"
+	"testing"
+)
+
+func TestUnitApplyFilters(t *testing.T) {
+	events := []ghEvent{
+		{ID: "1", Repo: repo{Name: "octo/own", Meta: &repoMeta{}}},
+		{ID: "2", Repo: repo{Name: "octo/fork", Meta: &repoMeta{Fork: true}}},
+		{ID: "3", Repo: repo{Name: "octo/gone"}},
+		{ID: "4", Repo: repo{Name: "octo/old", Meta: &repoMeta{Archived: true}}},
+	}
+	testCases := []struct {
+		name    string
+		filters []eventFilter
+		want    []string
+	}{
+		{name: "no filter", want: []string{"1", "2", "3", "4"}},
+		{name: "exclude forks", filters: []eventFilter{excludeForks}, want: []string{"1", "3", "4"}},
+		{name: "exclude archived", filters: []eventFilter{excludeArchived}, want: []string{"1", "2", "3"}},
+		{
+			name:    "org only",
+			filters: []eventFilter{onlyOwners([]string{"nobody", " OCTO "})},
+			want:    []string{"1", "2", "3", "4"},
+		},
+		{name: "other org", filters: []eventFilter{onlyOwners([]string{"acme"})}, want: []string{}},
+		{
+			name:    "all filters must accept",
+			filters: []eventFilter{excludeForks, func(ev ghEvent) bool { return ev.ID != "3" }},
+			want:    []string{"1", "4"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			got := applyFilters(events, tc.filters...)
+			// Assert
+			assertEqual(t, len(got), len(tc.want))
+			for i := range tc.want {
+				assertEqual(t, got[i].ID, tc.want[i])
+			}
+		})
+	}
+}
+
+func TestUnitRepoFilterFlags(t *testing.T) {
+	testCases := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{name: "none", args: nil, want: 0},
+		{name: "forks", args: []string{"--exclude-forks"}, want: 1},
+		{name: "both", args: []string{"--exclude-forks", "--exclude-archived"}, want: 2},
+		{name: "org", args: []string{"--org-only", "acme,octo"}, want: 1},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			var f repoFilterFlags
+			flags := flag.NewFlagSet("test", flag.ContinueOnError)
+			f.register(flags)
+			// Act
+			err := flags.Parse(tc.args)
+			// Assert
+			assertNoError(t, err)
+			assertEqual(t, len(f.filters()), tc.want)
+		})
+	}
+}
+
+func TestUnitRepoLabels(t *testing.T) {
+	testCases := []struct {
+		name string
+		meta *repoMeta
+		want string
+	}{
+		{name: "not enriched", want: ""},
+		{name: "regular", meta: &repoMeta{}, want: ""},
+		{name: "archived fork", meta: &repoMeta{Fork: true, Archived: true}, want: "fork,archived"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			got := strings.Join(repoLabels(tc.meta), ",")
+			// Assert
+			assertEqual(t, got, tc.want)
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+)
+
+// fixtureJSON holds the canonical events of the golden tests of the
+// renderers, one per common event type. They are shipped in the binary so
+// that the authors of sources, notifiers or tools reading --output json can
+// test against the same events.
+//
+//go:embed fixtures/events.json
+var fixtureJSON []byte
+
+// fixtureEvents decodes the canonical events, newest first.
+func fixtureEvents() ([]ghEvent, error) {
+	var events []ghEvent
+	if err := json.Unmarshal(fixtureJSON, &events); err != nil {
+		return nil, fmt.Errorf("decode fixtures: %w", err)
+	}
+	return events, nil
+}
+
+// runFixtures prints the canonical events as served by the API, or rendered
+// in an output format, in English.
+func runFixtures(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("fixtures", flag.ContinueOnError)
+	output := flags.String("output", "raw", "output format: raw, text, table, json or html")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		return errors.New("usage: go-github-activity fixtures [flags]")
+	}
+	if *output == "raw" {
+		_, err := stdout.Write(fixtureJSON)
+		return err
+	}
+	events, err := fixtureEvents()
+	if err != nil {
+		return err
+	}
+	cat := catalogs[defaultLang]
+	renderers := map[string]renderer{
+		"text":  textRenderer{cat: cat, icons: loadIcons(true, nil)},
+		"table": tableRenderer{cat: cat},
+		"json":  jsonRenderer{cat: cat},
+		"html":  htmlRenderer{cat: cat},
+	}
+	r, ok := renderers[*output]
+	if !ok {
+		return fmt.Errorf("unknown output format %q", *output)
+	}
+	return r.render(stdout, events)
+}
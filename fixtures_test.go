@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// update rewrites the golden files with the current outputs, to review
+// with git diff: go test -run Golden -update.
+var update = flag.Bool("update", false, "rewrite the golden files of testdata/golden")
+
+// assertGolden compares got with the golden file of testdata/golden.
+func assertGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", "golden", name)
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read golden file, create it with -update: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s differs from the output, rewrite it with -update if intended:\ngot:\n%s\nwant:\n%s", path, got, want)
+	}
+}
+
+func TestUnitRenderersGolden(t *testing.T) {
+	local := time.Local
+	time.Local = time.UTC
+	t.Cleanup(func() { time.Local = local })
+	cat := catalogs[defaultLang]
+	testCases := []struct {
+		golden string
+		render func(w io.Writer, events []ghEvent) error
+	}{
+		{golden: "text.golden", render: textRenderer{cat: cat, icons: loadIcons(true, nil)}.render},
+		{golden: "text_ascii.golden", render: textRenderer{cat: cat, icons: loadIcons(false, nil)}.render},
+		{golden: "text_fr.golden", render: textRenderer{cat: catalogs["fr"], icons: loadIcons(false, nil)}.render},
+		{golden: "table.golden", render: tableRenderer{cat: cat}.render},
+		{golden: "table_narrow.golden", render: tableRenderer{cat: cat, width: 80}.render},
+		{golden: "json.golden", render: jsonRenderer{cat: cat}.render},
+		{golden: "html.golden", render: htmlRenderer{cat: cat}.render},
+		{
+			golden: "markdown.golden",
+			render: func(w io.Writer, events []ghEvent) error {
+				_, err := io.WriteString(w, markdownSection(cat, loadIcons(true, nil), events, len(events)))
+				return err
+			},
+		},
+		{
+			golden: "timesheet.golden",
+			render: func(w io.Writer, events []ghEvent) error {
+				sessions := clusterSessions(events, 90*time.Minute)
+				return writeTimesheet(w, buildTimesheet(sessions, 30*time.Minute, time.UTC))
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.golden, func(t *testing.T) {
+			// Arrange
+			events, err := fixtureEvents()
+			assertNoError(t, err)
+			var buf bytes.Buffer
+			// Act
+			err = tc.render(&buf, events)
+			// Assert
+			assertNoError(t, err)
+			assertGolden(t, tc.golden, buf.Bytes())
+		})
+	}
+}
+
+func TestUnitRunFixtures(t *testing.T) {
+	testCases := []struct {
+		output  string
+		wantErr bool
+	}{
+		{output: "raw"},
+		{output: "text"},
+		{output: "csv", wantErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.output, func(t *testing.T) {
+			// Arrange
+			var buf bytes.Buffer
+			// Act
+			err := runFixtures([]string{"--output", tc.output}, &buf)
+			// Assert
+			if tc.wantErr {
+				assertNotNil(t, err)
+				return
+			}
+			assertNoError(t, err)
+			assertEqual(t, buf.Len() > 0, true)
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// followedLists maps the --from values to the API path of the list of
+// repositories of the authenticated user.
+var followedLists = map[string]string{
+	"watched": "/user/subscriptions",
+	"starred": "/user/starred",
+}
+
+// runFollowing aggregates the events of the repositories the authenticated
+// user watches or starred. With --watch it polls them and delivers new
+// events to the notifiers like watch does.
+func runFollowing(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("following", flag.ContinueOnError)
+	from := flags.String("from", "watched", "repositories to follow: watched or starred")
+	pages := flags.Int("pages", 10, "maximum number of pages of 100 repositories to read")
+	workers := flags.Int("concurrency", 4, "number of repositories fetched at once")
+	list := flags.Bool("list", false, "list the repositories without their events")
+	output := flags.String("output", "text", "output format: text or json")
+	strict := flags.Bool("strict-budget", false, "fail instead of asking when the run would exceed the rate limit")
+	watch := flags.Bool("watch", false, "poll the repositories and notify new events until interrupted")
+	interval := flags.Duration("interval", time.Minute, "delay between two polls with --watch")
+	refresh := flags.Duration("refresh", time.Hour, "delay between two listings of the repositories with --watch")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		return errors.New("usage: go-github-activity following [flags]")
+	}
+	if _, ok := followedLists[*from]; !ok {
+		return fmt.Errorf("unknown repository list %q", *from)
+	}
+	if *output != "text" && *output != "json" {
+		return fmt.Errorf("unknown output format %q", *output)
+	}
+	if *watch && *interval < time.Second {
+		return fmt.Errorf("invalid interval: %s", *interval)
+	}
+	if err := loadConfig(); err != nil {
+		return err
+	}
+	f, err := newRepoFetcher()
+	if err != nil {
+		return err
+	}
+	if viper.GetString("github_token") == "" {
+		return errors.New("no token configured: following lists the repositories of the authenticated user")
+	}
+	followed := func() ([]string, error) { return followedRepos(f.hc, f.base, *from, *pages) }
+	if *watch {
+		t := &repoTracker{
+			list: followed, eventsOf: f.repoEvents, workers: *workers, budget: f.hc.budget,
+			refresh: *refresh, clock: f.hc.clock,
+		}
+		return watchTracked(t, *interval, stdout)
+	}
+	names, err := followed()
+	if err != nil {
+		return err
+	}
+	if *list {
+		return writeRepoNames(stdout, names)
+	}
+	return renderTracked(stdout, *output, names, f, *workers, *strict)
+}
+
+// followedRepos returns the unarchived repositories of a list of the
+// authenticated user: watched or starred.
+func followedRepos(hc *client, base, from string, pages int) ([]string, error) {
+	repos, err := fetchRepoList(hc, base+followedLists[from], from+" repositories", pages)
+	if err != nil {
+		return nil, err
+	}
+	return filterOrgRepos(repos, "", ""), nil
+}
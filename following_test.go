@@ -0,0 +1,35 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestUnitFollowedRepos(t *testing.T) {
+	testCases := []struct {
+		from string
+		path string
+	}{
+		{from: "watched", path: "/user/subscriptions"},
+		{from: "starred", path: "/user/starred"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.from, func(t *testing.T) {
+			// Arrange
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assertEqual(t, r.URL.Path, tc.path)
+				assertEqual(t, r.URL.Query().Get("page"), "1")
+				w.Write([]byte(`[{"full_name":"octo/api"},{"full_name":"octo/old","archived":true},
+					{"full_name":"hub/web"}]`))
+			}))
+			t.Cleanup(srv.Close)
+			// Act
+			got, err := followedRepos(newClient("token"), srv.URL, tc.from, 10)
+			// Assert
+			assertNoError(t, err)
+			assertEqual(t, fmt.Sprint(got), "[octo/api hub/web]")
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type (
+	// giteaProvider fetches the activity feed of an account on a Gitea or
+	// Forgejo instance such as Codeberg.
+	giteaProvider struct {
+		hc   *client
+		base string
+	}
+	// giteaActivity is an entry of the Gitea activity feed.
+	giteaActivity struct {
+		ID        int64     `json:"id"`
+		OpType    string    `json:"op_type"`
+		ActUser   giteaUser `json:"act_user"`
+		Repo      giteaRepo `json:"repo"`
+		RefName   string    `json:"ref_name"`
+		Content   string    `json:"content"`
+		IsPrivate bool      `json:"is_private"`
+		Created   time.Time `json:"created"`
+	}
+	// giteaUser is the author of a Gitea activity.
+	giteaUser struct {
+		ID        int    `json:"id"`
+		Login     string `json:"login"`
+		AvatarURL string `json:"avatar_url"`
+		HTMLURL   string `json:"html_url"`
+	}
+	// giteaRepo is the repository of a Gitea activity.
+	giteaRepo struct {
+		ID       int    `json:"id"`
+		FullName string `json:"full_name"`
+		HTMLURL  string `json:"html_url"`
+	}
+	// giteaPush is the content of a commit_repo activity.
+	giteaPush struct {
+		Len     int `json:"Len"`
+		Commits []struct {
+			Sha1        string `json:"Sha1"`
+			Message     string `json:"Message"`
+			AuthorEmail string `json:"AuthorEmail"`
+			AuthorName  string `json:"AuthorName"`
+		} `json:"Commits"`
+	}
+)
+
+// defaultGiteaURL is the Gitea instance used when gitea.url is not set.
+const defaultGiteaURL = "https://codeberg.org"
+
+// giteaIssueActions maps the issue and pull request operations of Gitea to
+// the action of the GitHub event.
+var giteaIssueActions = map[string]string{
+	"create_issue": "opened", "close_issue": "closed", "reopen_issue": "reopened",
+	"create_pull_request": "opened", "close_pull_request": "closed", "reopen_pull_request": "reopened",
+	"merge_pull_request": "closed", "auto_merge_pull_request": "closed",
+}
+
+// newGiteaProvider returns the provider of a Gitea or Forgejo instance.
+func newGiteaProvider(instance, token string) *giteaProvider {
+	return &giteaProvider{hc: newClient(token), base: strings.TrimSuffix(instance, "/") + "/api/v1"}
+}
+
+func (p *giteaProvider) name() string { return "gitea" }
+
+func (p *giteaProvider) events(login string) ([]ghEvent, error) {
+	var feed []giteaActivity
+	u := fmt.Sprintf("%s/users/%s/activities/feeds?only-performed-by=true&limit=50", p.base, url.PathEscape(login))
+	if err := fetchJSON(p.hc, u, &feed); err != nil {
+		return nil, fmt.Errorf("fetch Gitea activities: %w", err)
+	}
+	events := make([]ghEvent, 0, len(feed))
+	for _, a := range feed {
+		if ev, ok := a.event(); ok {
+			events = append(events, ev)
+		}
+	}
+	return events, nil
+}
+
+// event normalizes a Gitea activity into the GitHub event of the same
+// meaning. Activities without an equivalent are skipped.
+func (a giteaActivity) event() (ghEvent, bool) {
+	ev := ghEvent{
+		ID: fmt.Sprintf("gitea-%d", a.ID),
+		Actor: actor{
+			ID: a.ActUser.ID, Login: a.ActUser.Login, DisplayLogin: a.ActUser.Login,
+			URL: a.ActUser.HTMLURL, AvatarURL: a.ActUser.AvatarURL,
+		},
+		Repo:      repo{ID: a.Repo.ID, Name: a.Repo.FullName, URL: a.Repo.HTMLURL},
+		Public:    !a.IsPrivate,
+		CreatedAt: a.Created,
+	}
+	number, text := giteaReference(a.Content)
+	switch a.OpType {
+	case "commit_repo", "mirror_sync_push":
+		var push giteaPush
+		if a.Content != "" {
+			if err := json.Unmarshal([]byte(a.Content), &push); err != nil {
+				return ghEvent{}, false
+			}
+		}
+		ev.Type = "PushEvent"
+		ev.Payload.Ref, ev.Payload.Size = a.RefName, max(push.Len, len(push.Commits))
+		for _, c := range push.Commits {
+			ev.Payload.Commits = append(ev.Payload.Commits, commit{
+				SHA: c.Sha1, Message: c.Message, Distinct: true,
+				Author: author{Name: c.AuthorName, Email: c.AuthorEmail},
+			})
+		}
+	case "create_repo":
+		ev.Type = "CreateEvent"
+		ev.Payload.RefType = "repository"
+	case "push_tag":
+		ev.Type = "CreateEvent"
+		ev.Payload.Ref, ev.Payload.RefType = strings.TrimPrefix(a.RefName, "refs/tags/"), "tag"
+	case "delete_tag", "delete_branch":
+		ev.Type = "DeleteEvent"
+		ev.Payload.Ref, ev.Payload.RefType = a.RefName, strings.TrimPrefix(a.OpType, "delete_")
+	case "create_issue", "close_issue", "reopen_issue":
+		ev.Type = "IssuesEvent"
+		ev.Payload.Action = giteaIssueActions[a.OpType]
+		ev.Payload.Issue = &issue{Number: number, Title: text}
+	case "create_pull_request", "close_pull_request", "reopen_pull_request", "merge_pull_request",
+		"auto_merge_pull_request":
+		ev.Type = "PullRequestEvent"
+		ev.Payload.Action = giteaIssueActions[a.OpType]
+		ev.Payload.PullRequest = &pullRequest{Number: number, Title: text, Merged: strings.Contains(a.OpType, "merge")}
+	case "comment_issue", "comment_pull":
+		ev.Type = "IssueCommentEvent"
+		ev.Payload.Action = "created"
+		ev.Payload.Issue = &issue{Number: number}
+		if a.OpType == "comment_pull" {
+			ev.Payload.Issue.PullRequest = &struct{}{}
+		}
+	case "star_repo":
+		ev.Type = "WatchEvent"
+		ev.Payload.Action = "started"
+	default:
+		return ghEvent{}, false
+	}
+	return ev, true
+}
+
+// giteaReference splits the "number|text" content of issue and pull request
+// activities.
+func giteaReference(content string) (int, string) {
+	num, text, ok := strings.Cut(content, "|")
+	if !ok {
+		return 0, ""
+	}
+	n, err := strconv.Atoi(num)
+	if err != nil {
+		return 0, ""
+	}
+	return n, text
+}
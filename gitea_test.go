@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestUnitGiteaActivity(t *testing.T) {
+	pushContent := `{"Len":3,"Commits":[{"Sha1":"abc","Message":"Fix crash","AuthorEmail":"o@example.com"}]}`
+	testCases := []struct {
+		name       string
+		activity   giteaActivity
+		wantType   string
+		wantAction string
+		wantNumber int
+		wantSkip   bool
+	}{
+		{
+			name: "push", wantType: "PushEvent",
+			activity: giteaActivity{OpType: "commit_repo", RefName: "refs/heads/main", Content: pushContent},
+		},
+		{name: "repository", wantType: "CreateEvent", activity: giteaActivity{OpType: "create_repo"}},
+		{name: "tag", wantType: "CreateEvent", activity: giteaActivity{OpType: "push_tag", RefName: "refs/tags/v1"}},
+		{name: "branch deletion", wantType: "DeleteEvent", activity: giteaActivity{OpType: "delete_branch"}},
+		{
+			name: "issue", wantType: "IssuesEvent", wantAction: "opened", wantNumber: 12,
+			activity: giteaActivity{OpType: "create_issue", Content: "12|Crash on start"},
+		},
+		{
+			name: "merge", wantType: "PullRequestEvent", wantAction: "closed", wantNumber: 5,
+			activity: giteaActivity{OpType: "merge_pull_request", Content: "5|Add feature"},
+		},
+		{
+			name: "comment", wantType: "IssueCommentEvent", wantAction: "created", wantNumber: 5,
+			activity: giteaActivity{OpType: "comment_pull", Content: "5|Looks good"},
+		},
+		{name: "star", wantType: "WatchEvent", wantAction: "started", activity: giteaActivity{OpType: "star_repo"}},
+		{name: "invalid push", activity: giteaActivity{OpType: "commit_repo", Content: "{"}, wantSkip: true},
+		{name: "unknown", activity: giteaActivity{OpType: "transfer_repo"}, wantSkip: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			tc.activity.ID = 9
+			tc.activity.Repo = giteaRepo{FullName: "octocat/hello"}
+			// Act
+			got, ok := tc.activity.event()
+			// Assert
+			assertEqual(t, ok, !tc.wantSkip)
+			if tc.wantSkip {
+				return
+			}
+			assertEqual(t, got.ID, "gitea-9")
+			assertEqual(t, got.Type, tc.wantType)
+			assertEqual(t, got.Payload.Action, tc.wantAction)
+			assertEqual(t, got.Repo.Name, "octocat/hello")
+			switch {
+			case got.Payload.Issue != nil:
+				assertEqual(t, got.Payload.Issue.Number, tc.wantNumber)
+			case got.Payload.PullRequest != nil:
+				assertEqual(t, got.Payload.PullRequest.Number, tc.wantNumber)
+				assertEqual(t, got.Payload.PullRequest.Merged, true)
+			}
+		})
+	}
+}
+
+func TestUnitGiteaPush(t *testing.T) {
+	// Arrange
+	a := giteaActivity{
+		OpType: "commit_repo", RefName: "refs/heads/main",
+		Content: `{"Len":3,"Commits":[{"Sha1":"abc","Message":"Fix crash","AuthorEmail":"o@example.com"}]}`,
+	}
+	// Act
+	got, ok := a.event()
+	// Assert
+	assertEqual(t, ok, true)
+	assertEqual(t, got.Payload.Ref, "refs/heads/main")
+	assertEqual(t, got.Payload.Size, 3)
+	assertEqual(t, len(got.Payload.Commits), 1)
+	assertEqual(t, got.Payload.Commits[0].Author.Email, "o@example.com")
+}
+
+func TestUnitGiteaProviderEvents(t *testing.T) {
+	// Arrange
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assertEqual(t, r.URL.Path, "/api/v1/users/octocat/activities/feeds")
+		assertEqual(t, r.URL.Query().Get("only-performed-by"), "true")
+		fmt.Fprint(w, `[
+			{"id":2,"op_type":"create_issue","content":"3|Bug","repo":{"full_name":"octocat/hello"},
+			 "act_user":{"login":"octocat"},"created":"2025-03-02T00:00:00Z"},
+			{"id":1,"op_type":"transfer_repo","created":"2025-03-01T00:00:00Z"}
+		]`)
+	}))
+	defer srv.Close()
+	p := newGiteaProvider(srv.URL, "")
+	// Act
+	events, err := p.events("octocat")
+	// Assert
+	assertNoError(t, err)
+	assertEqual(t, len(events), 1)
+	assertEqual(t, events[0].Actor.Login, "octocat")
+	assertEqual(t, events[0].Payload.Issue.Title, "Bug")
+}
@@ -1,12 +1,17 @@
 package main
 
 import (
+	"bytes"
+	"cmp"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"strconv"
+	"syscall"
 	"time"
 
 	"github.com/cenkalti/backoff/v5"
@@ -22,53 +27,124 @@ type (
 		Payload   payload   `json:"payload"`
 		Public    bool      `json:"public"`
 		CreatedAt time.Time `json:"created_at"`
+		// Raw is the event as served by the API, archived with
+		// archive.keep_raw so that it can be normalized again.
+		Raw json.RawMessage `json:"raw,omitempty"`
+		// Source names the configured source of the event when there are
+		// several.
+		Source string `json:"source,omitempty"`
+		// Local marks commits found in a local clone, which may never have
+		// been pushed.
+		Local bool `json:"local,omitempty"`
+		// redacted marks an event already redacted, see redactEvent.
+		redacted bool
 	}
 	// actor represents the user who triggered the event
 	actor struct {
-		ID           int    `json:"id"`
-		Login        string `json:"login"`
-		DisplayLogin string `json:"display_login"`
-		URL          string `json:"url"`
+		ID           int          `json:"id"`
+		Login        string       `json:"login"`
+		DisplayLogin string       `json:"display_login"`
+		URL          string       `json:"url"`
+		AvatarURL    string       `json:"avatar_url"`
+		Profile      *userProfile `json:"profile,omitempty"`
 	}
 	// repo represents the repository involved in the event
 	repo struct {
-		ID   int    `json:"id"`
-		Name string `json:"name"`
-		URL  string `json:"url"`
+		ID   int       `json:"id"`
+		Name string    `json:"name"`
+		URL  string    `json:"url"`
+		Meta *repoMeta `json:"meta,omitempty"`
 	}
 	// payload represents the specific data related to the event
 	payload struct {
-		Action       string   `json:"action,omitempty"`
-		PushID       int64    `json:"push_id,omitempty"`
-		Size         int      `json:"size,omitempty"`
-		DistinctSize int      `json:"distinct_size,omitempty"`
-		Ref          string   `json:"ref,omitempty"`
-		Head         string   `json:"head,omitempty"`
-		Before       string   `json:"before,omitempty"`
-		Commits      []commit `json:"commits,omitempty"`
+		Action       string         `json:"action,omitempty"`
+		PushID       int64          `json:"push_id,omitempty"`
+		Size         int            `json:"size,omitempty"`
+		DistinctSize int            `json:"distinct_size,omitempty"`
+		Ref          string         `json:"ref,omitempty"`
+		RefType      string         `json:"ref_type,omitempty"`
+		Head         string         `json:"head,omitempty"`
+		Before       string         `json:"before,omitempty"`
+		Commits      []commit       `json:"commits,omitempty"`
+		PullRequest  *pullRequest   `json:"pull_request,omitempty"`
+		Issue        *issue         `json:"issue,omitempty"`
+		Deployment   *deployment    `json:"deployment,omitempty"`
+		Project      *projectItem   `json:"project_item,omitempty"`
+		Discussion   *discussion    `json:"discussion,omitempty"`
+		Sponsorship  *sponsorship   `json:"sponsorship,omitempty"`
+		WorkflowRun  *workflowRun   `json:"workflow_run,omitempty"`
+		Alert        *securityAlert `json:"alert,omitempty"`
+		Release      *release       `json:"release,omitempty"`
+		// Labels and Milestone are those of the issue or pull request.
+		Labels    []label    `json:"labels,omitempty"`
+		Milestone *milestone `json:"milestone,omitempty"`
+		// Checks is the combined check state of the head of a push.
+		Checks string `json:"checks,omitempty"`
+	}
+	// pullRequest represents the pull request of a pull request event
+	pullRequest struct {
+		Number  int    `json:"number"`
+		Title   string `json:"title"`
+		HTMLURL string `json:"html_url"`
+		State   string `json:"state"`
+		Merged  bool   `json:"merged,omitempty"`
+	}
+	// issue represents the issue of an issue event
+	issue struct {
+		Number      int        `json:"number"`
+		Title       string     `json:"title"`
+		HTMLURL     string     `json:"html_url"`
+		State       string     `json:"state"`
+		User        *actor     `json:"user,omitempty"`
+		PullRequest *struct{}  `json:"pull_request,omitempty"`
+		CreatedAt   *time.Time `json:"created_at,omitempty"`
+		ClosedAt    *time.Time `json:"closed_at,omitempty"`
 	}
 	// commit represents a commit in a push event
 	commit struct {
-		SHA      string `json:"sha"`
-		Author   author `json:"author"`
-		Message  string `json:"message"`
-		Distinct bool   `json:"distinct"`
-		URL      string `json:"url"`
+		SHA          string        `json:"sha"`
+		Author       author        `json:"author"`
+		Message      string        `json:"message"`
+		Distinct     bool          `json:"distinct"`
+		URL          string        `json:"url"`
+		Verification *verification `json:"verification,omitempty"`
 	}
 	// author represents the author of a commit
 	author struct {
 		Email string `json:"email"`
 		Name  string `json:"name"`
 	}
+	// apiError reports a non-successful GitHub API response.
+	apiError struct {
+		StatusCode int
+		Status     string
+		// ResetAt is set when the rate limit is exhausted.
+		ResetAt time.Time
+	}
 	// client manages authenticated requests and error handling for GitHub API.
 	client struct {
 		url    string
 		Token  string
 		Method string
 		Client *http.Client
+		// body is sent with the requests, e.g. GraphQL queries.
+		body   []byte
+		budget *rateBudget
+		pool   *tokenPool
+		// apiVersion is sent as X-GitHub-Api-Version.
+		apiVersion string
+		// clock paces the retries and dates the rate limits.
+		clock clock
+		// ctx bounds the requests, e.g. to the run of a scheduled job; nil
+		// never ends.
+		ctx context.Context
 	}
 )
 
+// maxDrops is the number of dropped connections and truncated responses
+// retried per request.
+const maxDrops = 3
+
 // newClient configures secure defaults for GitHub API communication.
 func newClient(token string) *client {
 	return &client{
@@ -77,64 +153,161 @@ func newClient(token string) *client {
 		Client: &http.Client{
 			Timeout: 10 * time.Second,
 		},
+		apiVersion: apiVersion(),
+		clock:      systemClock{},
+	}
+}
+
+func (e *apiError) Error() string {
+	if e.StatusCode >= 500 {
+		return fmt.Sprintf("GitHub API server error: %q", e.Status)
 	}
+	return fmt.Sprintf("GitHub API client error: %q", e.Status)
+}
+
+// isStatus reports whether err wraps an API error with the given status code.
+func isStatus(err error, code int) bool {
+	var apiErr *apiError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == code
 }
 
 func (c *client) setURL(url string) {
 	c.url = url
 }
 
-// fetchGitHubResponse gets a single page of results from GitHub API.
-func fetchGitHubResponse(hc *client, url string) (ghEvent, error) {
+// fetchGitHubResponse gets a single page of events from GitHub API.
+func fetchGitHubResponse(hc *client, url string) ([]ghEvent, error) {
+	var events []ghEvent
+	if err := fetchJSON(hc, url, &events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// fetchJSON decodes the response of a GitHub API endpoint into v.
+func fetchJSON(hc *client, url string, v any) error {
 	hc.setURL(url)
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-	ghRes, err := hc.do(ctx)
-	if err != nil {
-		return ghEvent{}, err
+	parent := hc.ctx
+	if parent == nil {
+		parent = context.Background()
 	}
-	return ghRes, nil
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+	return hc.do(ctx, v)
 }
 
-// do retrieves movie data from GitHub with a retry mechanism based on exponential backoff.
-func (hc *client) do(ctx context.Context) (ghEvent, error) {
-	op := func() (*http.Response, error) {
-		req, err := http.NewRequestWithContext(ctx, hc.Method, hc.url, nil)
+// do retrieves data from GitHub with a retry mechanism based on exponential
+// backoff on the client's clock, and decodes it into v.
+func (hc *client) do(ctx context.Context, v any) error {
+	drops := 0
+	retryDrop := func(err error) error {
+		if dropped(err) && drops < maxDrops {
+			drops++
+			return err
+		}
+		return backoff.Permanent(err)
+	}
+	op := func() ([]byte, error) {
+		req, err := http.NewRequestWithContext(ctx, hc.Method, hc.url, bytes.NewReader(hc.body))
 		if err != nil {
 			return nil, backoff.Permanent(fmt.Errorf("request error: %w", err))
 		}
-		req.Header.Add("Authorization", "Bearer "+hc.APIKey)
+		token := hc.Token
+		var pooled *pooledToken
+		if hc.pool != nil {
+			if pooled, err = hc.pool.pick(hc.clock.Now()); err != nil {
+				return nil, backoff.Permanent(err)
+			}
+			token = pooled.token
+		}
+		if token != "" {
+			req.Header.Add("Authorization", "Bearer "+token)
+		}
 		req.Header.Add("Content-Type", "application/json")
-		cli := newClient(hc.APIKey)
-		res, err := cli.Client.Do(req)
+		req.Header.Set("X-GitHub-Api-Version", cmp.Or(hc.apiVersion, defaultAPIVersion))
+		res, err := hc.Client.Do(req)
 		if err != nil {
-			return nil, backoff.Permanent(fmt.Errorf("request error: %w", err))
+			return nil, retryDrop(fmt.Errorf("request error: %w", err))
+		}
+		if pooled == nil {
+			hc.budget.update(res.Header)
+		}
+		logUsage(requestUsage(req, res, hc.clock.Now()))
+		if pooled != nil && hc.pool.release(pooled, res, hc.clock.Now()) {
+			res.Body.Close()
+			return nil, backoff.RetryAfter(0)
+		}
+		if res.StatusCode < 400 {
+			byt, err := io.ReadAll(res.Body)
+			if errClose := res.Body.Close(); errClose != nil {
+				log.Printf("error closing response body: %v", errClose)
+			}
+			if err != nil {
+				return nil, retryDrop(fmt.Errorf("read response: %w", err))
+			}
+			return byt, nil
 		}
-		switch {
-		case res.StatusCode >= 500:
-			return nil, backoff.Permanent(fmt.Errorf("GitHub API server error: %q", res.Status))
-		case res.StatusCode == 429:
+		res.Body.Close()
+		if res.StatusCode == 429 {
 			sec, err := strconv.ParseInt(res.Header.Get("Retry-After"), 10, 64)
 			if err == nil {
 				return nil, backoff.RetryAfter(int(sec))
 			}
-		case res.StatusCode >= 400:
-			return nil, backoff.Permanent(fmt.Errorf("GitHub API client error: %q", res.Status))
 		}
-		return res, nil
+		apiErr := &apiError{StatusCode: res.StatusCode, Status: res.Status}
+		if res.Header.Get("X-RateLimit-Remaining") == "0" {
+			if reset, err := strconv.ParseInt(res.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+				apiErr.ResetAt = time.Unix(reset, 0)
+			}
+		}
+		// A secondary rate limit answers 403 with the seconds to wait before
+		// trying again, too long to wait for here.
+		if res.StatusCode == http.StatusForbidden && apiErr.ResetAt.IsZero() {
+			if sec, err := strconv.ParseInt(res.Header.Get("Retry-After"), 10, 64); err == nil {
+				apiErr.ResetAt = hc.clock.Now().Add(time.Duration(sec) * time.Second)
+			}
+		}
+		return nil, backoff.Permanent(apiErr)
 	}
-	res, err := backoff.Retry(ctx, op, backoff.WithBackOff(backoff.NewExponentialBackOff()))
+	byt, err := retry(ctx, hc.clock, backoff.NewExponentialBackOff(), op)
 	if err != nil {
-		return ghEvent{}, fmt.Errorf("fetch GitHub response: %w", err)
+		return fmt.Errorf("fetch GitHub response: %w", err)
+	}
+	if err = json.NewDecoder(bytes.NewReader(byt)).Decode(v); err != nil {
+		return fmt.Errorf("decode response: %w", err)
 	}
-	defer func() {
-		if err := res.Body.Close(); err != nil {
-			log.Printf("error closing response body: %v", err)
+	return nil
+}
+
+// retry runs op until it succeeds or fails with a permanent error, waiting
+// on the clock between two attempts for the delay of b or the one of a
+// backoff.RetryAfter error. It gives up after backoff.DefaultMaxElapsedTime.
+func retry[T any](ctx context.Context, c clock, b backoff.BackOff, op backoff.Operation[T]) (T, error) {
+	start := c.Now()
+	b.Reset()
+	for {
+		res, err := op()
+		var permanent *backoff.PermanentError
+		if err == nil || errors.As(err, &permanent) {
+			return res, err
+		}
+		next := b.NextBackOff()
+		var retryAfter *backoff.RetryAfterError
+		if errors.As(err, &retryAfter) {
+			next = retryAfter.Duration
+			b.Reset()
+		}
+		if next == backoff.Stop || c.Now().Sub(start)+next > backoff.DefaultMaxElapsedTime {
+			return res, err
+		}
+		if !sleepContext(ctx, c, next) {
+			return res, context.Cause(ctx)
 		}
-	}()
-	var results ghEvent
-	if err = json.NewDecoder(res.Body).Decode(&results); err != nil {
-		return ghEvent{}, fmt.Errorf("decode response: %w", err)
 	}
-	return results, nil
+}
+
+// dropped reports whether a request failed on a connection reset or closed
+// before the end of the response, which another attempt may get through.
+func dropped(err error) bool {
+	return errors.Is(err, syscall.ECONNRESET) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
 }
@@ -1 +1,164 @@
-package main
\ No newline at end of file
+package main
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/backoff/v5"
+)
+
+func TestUnitFetchJSON(t *testing.T) {
+	testCases := []struct {
+		name       string
+		status     int
+		body       string
+		wantStatus int
+		wantErr    bool
+	}{
+		{name: "success", status: http.StatusOK, body: `{"Go": 10}`},
+		{name: "not found", status: http.StatusNotFound, wantStatus: http.StatusNotFound, wantErr: true},
+		{name: "server error", status: http.StatusBadGateway, wantStatus: http.StatusBadGateway, wantErr: true},
+		{name: "invalid body", status: http.StatusOK, body: `{`, wantErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assertEqual(t, r.Header.Get("Authorization"), "Bearer token")
+				w.WriteHeader(tc.status)
+				w.Write([]byte(tc.body))
+			}))
+			t.Cleanup(srv.Close)
+			// Act
+			var got map[string]int
+			err := fetchJSON(newClient("token"), srv.URL, &got)
+			// Assert
+			if !tc.wantErr {
+				assertNoError(t, err)
+				assertEqual(t, got["Go"], 10)
+				return
+			}
+			assertNotNil(t, err)
+			if tc.wantStatus != 0 {
+				assertEqual(t, isStatus(err, tc.wantStatus), true)
+			}
+		})
+	}
+}
+
+func TestUnitFetchGitHubResponse(t *testing.T) {
+	// Arrange
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assertEqual(t, r.Header.Get("Authorization"), "")
+		w.Write([]byte(`[{"id": "1", "type": "PushEvent", "repo": {"name": "octo/repo"}}]`))
+	}))
+	t.Cleanup(srv.Close)
+	// Act
+	events, err := fetchGitHubResponse(newClient(""), srv.URL)
+	// Assert
+	assertNoError(t, err)
+	assertEqual(t, len(events), 1)
+	assertEqual(t, events[0].Repo.Name, "octo/repo")
+}
+
+func TestUnitRetry(t *testing.T) {
+	boom := errors.New("boom")
+	testCases := []struct {
+		name      string
+		errs      []error
+		interval  time.Duration
+		canceled  bool
+		wantWaits string
+		wantErr   bool
+	}{
+		{name: "success", errs: []error{nil}, wantWaits: "[]"},
+		{name: "backs off", errs: []error{boom, boom, nil}, wantWaits: "[1s 1s]"},
+		{name: "retry after", errs: []error{backoff.RetryAfter(30), nil}, wantWaits: "[30s]"},
+		{name: "permanent", errs: []error{backoff.Permanent(boom)}, wantWaits: "[]", wantErr: true},
+		{
+			name:      "max elapsed time",
+			errs:      []error{boom, boom, boom},
+			interval:  10 * time.Minute,
+			wantWaits: "[10m0s]",
+			wantErr:   true,
+		},
+		{name: "canceled", errs: []error{boom, nil}, canceled: true, wantWaits: "[1s]", wantErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			clk := &fakeClock{now: time.Date(2025, 3, 3, 10, 0, 0, 0, time.UTC)}
+			if tc.canceled {
+				clk.limit, clk.cancel = 1, cancel
+			}
+			calls := 0
+			op := func() (int, error) {
+				calls++
+				return calls, tc.errs[calls-1]
+			}
+			// Act
+			_, err := retry(ctx, clk, backoff.NewConstantBackOff(cmp.Or(tc.interval, time.Second)), op)
+			// Assert
+			if tc.wantErr {
+				assertNotNil(t, err)
+			} else {
+				assertNoError(t, err)
+			}
+			assertEqual(t, fmt.Sprint(clk.waits), tc.wantWaits)
+		})
+	}
+}
+
+// FuzzDecodeEvents checks that no API response, however malformed, makes
+// the decoding, summaries, renderers or redaction panic. Seeds are real API
+// captures and the fixture events; run with go test -fuzz FuzzDecodeEvents.
+func FuzzDecodeEvents(f *testing.F) {
+	capture, err := os.ReadFile(filepath.Join("testdata", "captures", "user_events.json"))
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Add(capture)
+	f.Add(fixtureJSON)
+	f.Add([]byte(`[{"type":"PullRequestEvent","payload":{"action":"closed"}}]`))
+	f.Add([]byte(`[{"type":"PushEvent","payload":{"size":-3,"commits":[{}]}}]`))
+	f.Add([]byte(`[{"type":"ReleaseEvent","payload":{"release":null}},{"type":"WorkflowRunEvent","payload":{}}]`))
+	f.Add([]byte(`[{"type":"","created_at":"0001-01-01T00:00:00Z"}]`))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var events []ghEvent
+		if json.Unmarshal(data, &events) != nil {
+			return
+		}
+		for _, cat := range catalogs {
+			for _, ev := range events {
+				summarize(cat, ev)
+			}
+		}
+		renderers := []renderer{
+			textRenderer{cat: catalogs[defaultLang], icons: loadIcons(true, nil)},
+			tableRenderer{cat: catalogs[defaultLang], width: 40},
+			jsonRenderer{cat: catalogs[defaultLang]},
+			htmlRenderer{cat: catalogs[defaultLang]},
+		}
+		for _, r := range renderers {
+			if err := r.render(io.Discard, events); err != nil {
+				t.Errorf("render %T: %v", r, err)
+			}
+		}
+		markBumps(events, nil)
+		for i := range events {
+			redactEvent(&events[i], "salt")
+		}
+	})
+}
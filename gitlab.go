@@ -0,0 +1,189 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type (
+	// gitlabProvider fetches the events of a GitLab account.
+	gitlabProvider struct {
+		hc       *client
+		base     string
+		projects map[int]gitlabProject
+	}
+	// gitlabEvent is an entry of the GitLab events API.
+	gitlabEvent struct {
+		ID          int         `json:"id"`
+		ProjectID   int         `json:"project_id"`
+		ActionName  string      `json:"action_name"`
+		TargetType  string      `json:"target_type"`
+		TargetIID   int         `json:"target_iid"`
+		TargetTitle string      `json:"target_title"`
+		Author      gitlabUser  `json:"author"`
+		PushData    *gitlabPush `json:"push_data"`
+		Note        *gitlabNote `json:"note"`
+		CreatedAt   time.Time   `json:"created_at"`
+	}
+	// gitlabUser is the author of a GitLab event.
+	gitlabUser struct {
+		ID        int    `json:"id"`
+		Username  string `json:"username"`
+		WebURL    string `json:"web_url"`
+		AvatarURL string `json:"avatar_url"`
+	}
+	// gitlabPush describes a push of a GitLab event.
+	gitlabPush struct {
+		CommitCount int    `json:"commit_count"`
+		Action      string `json:"action"`
+		RefType     string `json:"ref_type"`
+		CommitFrom  string `json:"commit_from"`
+		CommitTo    string `json:"commit_to"`
+		Ref         string `json:"ref"`
+		CommitTitle string `json:"commit_title"`
+	}
+	// gitlabNote is the comment of a GitLab note event.
+	gitlabNote struct {
+		NoteableType string `json:"noteable_type"`
+		NoteableIID  int    `json:"noteable_iid"`
+	}
+	// gitlabProject is the project of GitLab events.
+	gitlabProject struct {
+		ID                int    `json:"id"`
+		PathWithNamespace string `json:"path_with_namespace"`
+		WebURL            string `json:"web_url"`
+		Visibility        string `json:"visibility"`
+	}
+)
+
+// defaultGitLabURL is the GitLab instance used when gitlab.url is not set.
+const defaultGitLabURL = "https://gitlab.com"
+
+// newGitLabProvider returns the provider of a GitLab instance.
+func newGitLabProvider(instance, token string) *gitlabProvider {
+	return &gitlabProvider{
+		hc:       newClient(token),
+		base:     strings.TrimSuffix(instance, "/") + "/api/v4",
+		projects: map[int]gitlabProject{},
+	}
+}
+
+func (p *gitlabProvider) name() string { return "gitlab" }
+
+func (p *gitlabProvider) events(login string) ([]ghEvent, error) {
+	var raw []gitlabEvent
+	u := fmt.Sprintf("%s/users/%s/events?per_page=100", p.base, url.PathEscape(login))
+	if err := fetchJSON(p.hc, u, &raw); err != nil {
+		return nil, fmt.Errorf("fetch GitLab events: %w", err)
+	}
+	events := make([]ghEvent, 0, len(raw))
+	for _, e := range raw {
+		project, err := p.project(e.ProjectID)
+		if err != nil {
+			return nil, err
+		}
+		if ev, ok := e.event(project); ok {
+			events = append(events, ev)
+		}
+	}
+	return events, nil
+}
+
+// project returns a GitLab project, fetched once per provider. A project
+// that is gone or hidden is named after its ID.
+func (p *gitlabProvider) project(id int) (gitlabProject, error) {
+	if id == 0 {
+		return gitlabProject{}, nil
+	}
+	if project, ok := p.projects[id]; ok {
+		return project, nil
+	}
+	var project gitlabProject
+	err := fetchJSON(p.hc, fmt.Sprintf("%s/projects/%d", p.base, id), &project)
+	switch {
+	case isStatus(err, http.StatusNotFound) || isStatus(err, http.StatusForbidden):
+		project = gitlabProject{ID: id, PathWithNamespace: fmt.Sprintf("gitlab-project-%d", id)}
+	case err != nil:
+		return gitlabProject{}, fmt.Errorf("fetch GitLab project %d: %w", id, err)
+	}
+	p.projects[id] = project
+	return project, nil
+}
+
+// event normalizes a GitLab event into the GitHub event of the same
+// meaning. Events without an equivalent, such as joining a project, are
+// skipped.
+func (e gitlabEvent) event(project gitlabProject) (ghEvent, bool) {
+	ev := ghEvent{
+		ID: fmt.Sprintf("gitlab-%d", e.ID),
+		Actor: actor{
+			ID: e.Author.ID, Login: e.Author.Username, DisplayLogin: e.Author.Username,
+			URL: e.Author.WebURL, AvatarURL: e.Author.AvatarURL,
+		},
+		Repo:      repo{ID: project.ID, Name: project.PathWithNamespace, URL: project.WebURL},
+		Public:    project.Visibility == "public",
+		CreatedAt: e.CreatedAt,
+	}
+	action := strings.Fields(e.ActionName)
+	if len(action) == 0 {
+		return ghEvent{}, false
+	}
+	switch {
+	case e.PushData != nil:
+		return e.push(ev)
+	case e.TargetType == "MergeRequest":
+		ev.Type = "PullRequestEvent"
+		ev.Payload.PullRequest = &pullRequest{Number: e.TargetIID, Title: e.TargetTitle}
+		ev.Payload.Action = action[0]
+		if action[0] == "accepted" {
+			ev.Payload.Action, ev.Payload.PullRequest.Merged = "closed", true
+		}
+	case e.TargetType == "Issue":
+		ev.Type = "IssuesEvent"
+		ev.Payload.Action = action[0]
+		ev.Payload.Issue = &issue{Number: e.TargetIID, Title: e.TargetTitle}
+	case e.Note != nil:
+		ev.Type = "IssueCommentEvent"
+		ev.Payload.Action = "created"
+		ev.Payload.Issue = &issue{Number: e.Note.NoteableIID, Title: e.TargetTitle}
+		if e.Note.NoteableType == "MergeRequest" {
+			ev.Payload.Issue.PullRequest = &struct{}{}
+		}
+	case action[0] == "created" && e.TargetType == "":
+		ev.Type = "CreateEvent"
+		ev.Payload.RefType = "repository"
+	default:
+		return ghEvent{}, false
+	}
+	return ev, true
+}
+
+// push normalizes a push, branch or tag creation, or deletion.
+func (e gitlabEvent) push(ev ghEvent) (ghEvent, bool) {
+	pd := e.PushData
+	switch pd.Action {
+	case "pushed":
+		ev.Type = "PushEvent"
+		ref := "refs/heads/" + pd.Ref
+		if pd.RefType == "tag" {
+			ref = "refs/tags/" + pd.Ref
+		}
+		ev.Payload.Ref, ev.Payload.Head, ev.Payload.Before = ref, pd.CommitTo, pd.CommitFrom
+		ev.Payload.Size = pd.CommitCount
+		if pd.CommitTo != "" {
+			ev.Payload.Commits = []commit{{SHA: pd.CommitTo, Message: pd.CommitTitle, Distinct: true}}
+		}
+	case "created":
+		ev.Type = "CreateEvent"
+		ev.Payload.Ref, ev.Payload.RefType = pd.Ref, pd.RefType
+	case "removed":
+		ev.Type = "DeleteEvent"
+		ev.Payload.Ref, ev.Payload.RefType = pd.Ref, pd.RefType
+	default:
+		return ghEvent{}, false
+	}
+	return ev, true
+}
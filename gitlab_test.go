@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestUnitGitLabEvent(t *testing.T) {
+	project := gitlabProject{ID: 7, PathWithNamespace: "group/app", WebURL: "https://gitlab.com/group/app",
+		Visibility: "public"}
+	testCases := []struct {
+		name       string
+		event      gitlabEvent
+		wantType   string
+		wantAction string
+		wantSkip   bool
+	}{
+		{
+			name: "push", wantType: "PushEvent",
+			event: gitlabEvent{ActionName: "pushed to", PushData: &gitlabPush{
+				Action: "pushed", RefType: "branch", Ref: "main", CommitTo: "abc", CommitCount: 3, CommitTitle: "Fix",
+			}},
+		},
+		{
+			name: "branch creation", wantType: "CreateEvent",
+			event: gitlabEvent{ActionName: "pushed new", PushData: &gitlabPush{Action: "created", RefType: "branch"}},
+		},
+		{
+			name: "tag deletion", wantType: "DeleteEvent",
+			event: gitlabEvent{ActionName: "deleted", PushData: &gitlabPush{Action: "removed", RefType: "tag"}},
+		},
+		{
+			name: "merge request opened", wantType: "PullRequestEvent", wantAction: "opened",
+			event: gitlabEvent{ActionName: "opened", TargetType: "MergeRequest", TargetIID: 4},
+		},
+		{
+			name: "merge request merged", wantType: "PullRequestEvent", wantAction: "closed",
+			event: gitlabEvent{ActionName: "accepted", TargetType: "MergeRequest", TargetIID: 4},
+		},
+		{
+			name: "issue closed", wantType: "IssuesEvent", wantAction: "closed",
+			event: gitlabEvent{ActionName: "closed", TargetType: "Issue", TargetIID: 2},
+		},
+		{
+			name: "comment", wantType: "IssueCommentEvent", wantAction: "created",
+			event: gitlabEvent{ActionName: "commented on", TargetType: "Note", Note: &gitlabNote{NoteableIID: 2}},
+		},
+		{name: "project creation", wantType: "CreateEvent", event: gitlabEvent{ActionName: "created"}},
+		{name: "joined", event: gitlabEvent{ActionName: "joined"}, wantSkip: true},
+		{name: "no action", event: gitlabEvent{}, wantSkip: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			tc.event.ID = 42
+			// Act
+			got, ok := tc.event.event(project)
+			// Assert
+			assertEqual(t, ok, !tc.wantSkip)
+			if tc.wantSkip {
+				return
+			}
+			assertEqual(t, got.ID, "gitlab-42")
+			assertEqual(t, got.Type, tc.wantType)
+			assertEqual(t, got.Payload.Action, tc.wantAction)
+			assertEqual(t, got.Repo.Name, "group/app")
+			assertEqual(t, got.Public, true)
+		})
+	}
+}
+
+func TestUnitGitLabProviderEvents(t *testing.T) {
+	// Arrange
+	projectFetches := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assertEqual(t, r.Header.Get("Authorization"), "Bearer glpat")
+		switch r.URL.Path {
+		case "/api/v4/users/octocat/events":
+			fmt.Fprint(w, `[
+				{"id":2,"project_id":7,"action_name":"pushed to","created_at":"2025-03-02T00:00:00Z",
+				 "author":{"username":"octocat"},
+				 "push_data":{"action":"pushed","ref_type":"branch","ref":"main","commit_count":2,"commit_to":"abc"}},
+				{"id":1,"project_id":7,"action_name":"opened","target_type":"MergeRequest","target_iid":3,
+				 "target_title":"Add feature","created_at":"2025-03-01T00:00:00Z"},
+				{"id":0,"project_id":9,"action_name":"joined","created_at":"2025-02-28T00:00:00Z"}
+			]`)
+		case "/api/v4/projects/7":
+			projectFetches++
+			fmt.Fprint(w, `{"id":7,"path_with_namespace":"group/app","visibility":"private"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	p := newGitLabProvider(srv.URL+"/", "glpat")
+	// Act
+	events, err := p.events("octocat")
+	// Assert
+	assertNoError(t, err)
+	assertEqual(t, len(events), 2)
+	assertEqual(t, projectFetches, 1)
+	assertEqual(t, events[0].Payload.Size, 2)
+	assertEqual(t, events[0].Payload.Ref, "refs/heads/main")
+	assertEqual(t, events[1].Payload.PullRequest.Title, "Add feature")
+	assertEqual(t, events[1].Repo.Name, "group/app")
+	assertEqual(t, events[1].CreatedAt, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC))
+}
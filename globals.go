@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// globalOptions holds the flags given before the subcommand, which apply
+// to every command.
+type globalOptions struct {
+	profile string
+	quiet   bool
+	noInput bool
+	redact  bool
+}
+
+// profileEnv selects a profile when --profile is not given.
+const profileEnv = "GH_ACTIVITY_PROFILE"
+
+var (
+	// activeProfile is the profile selected for this run, empty for none.
+	activeProfile string
+	// quiet suppresses the non-essential output, such as progress and
+	// status messages.
+	quiet bool
+	// noInput guarantees that no command waits for an answer on the
+	// terminal, e.g. the passphrase prompt of a token_cmd.
+	noInput bool
+	// redact hides private repository names, commit messages and emails
+	// from every output, see redactEvents.
+	redact bool
+)
+
+// parseGlobalFlags removes the leading --profile, --quiet, --no-input and
+// --redact flags from the command line. The profile defaults to $GH_ACTIVITY_PROFILE.
+func parseGlobalFlags(args []string, getenv func(string) string) (globalOptions, []string) {
+	opts := globalOptions{profile: getenv(profileEnv)}
+	for len(args) > 0 {
+		if name, ok := strings.CutPrefix(args[0], "--profile="); ok {
+			opts.profile, args = name, args[1:]
+			continue
+		}
+		switch {
+		case args[0] == "--profile" && len(args) > 1:
+			opts.profile, args = args[1], args[2:]
+		case args[0] == "--quiet" || args[0] == "-q":
+			opts.quiet, args = true, args[1:]
+		case args[0] == "--no-input":
+			opts.noInput, args = true, args[1:]
+		case args[0] == "--redact":
+			opts.redact, args = true, args[1:]
+		default:
+			return opts, args
+		}
+	}
+	return opts, args
+}
+
+// infof writes a status message, unless --quiet is set.
+func infof(w io.Writer, format string, args ...any) error {
+	if quiet {
+		return nil
+	}
+	_, err := fmt.Fprintf(w, format, args...)
+	return err
+}
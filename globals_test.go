@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestUnitParseGlobalFlags(t *testing.T) {
+	testCases := []struct {
+		name        string
+		args        []string
+		env         string
+		wantProfile string
+		wantQuiet   bool
+		wantNoInput bool
+		wantRedact  bool
+		wantArgs    int
+	}{
+		{name: "flag", args: []string{"--profile", "work", "stats", "octocat"}, wantProfile: "work", wantArgs: 2},
+		{
+			name: "flag with value", args: []string{"--profile=work", "octocat"}, env: "personal",
+			wantProfile: "work", wantArgs: 1,
+		},
+		{name: "environment", args: []string{"octocat"}, env: "personal", wantProfile: "personal", wantArgs: 1},
+		{name: "none", args: []string{"octocat"}, wantArgs: 1},
+		{name: "flag without value", args: []string{"--profile"}, wantArgs: 1},
+		{
+			name: "quiet", args: []string{"-q", "--profile", "work", "--quiet", "octocat"},
+			wantProfile: "work", wantQuiet: true, wantArgs: 1,
+		},
+		{name: "no input", args: []string{"--no-input", "sync", "octocat"}, wantNoInput: true, wantArgs: 2},
+		{name: "redact", args: []string{"--redact", "stats", "octocat"}, wantRedact: true, wantArgs: 2},
+		{name: "subcommand flags", args: []string{"stats", "--quiet"}, wantArgs: 2},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			getenv := func(key string) string {
+				assertEqual(t, key, profileEnv)
+				return tc.env
+			}
+			// Act
+			opts, args := parseGlobalFlags(tc.args, getenv)
+			// Assert
+			assertEqual(t, opts.profile, tc.wantProfile)
+			assertEqual(t, opts.quiet, tc.wantQuiet)
+			assertEqual(t, opts.noInput, tc.wantNoInput)
+			assertEqual(t, opts.redact, tc.wantRedact)
+			assertEqual(t, len(args), tc.wantArgs)
+		})
+	}
+}
+
+func TestUnitInfof(t *testing.T) {
+	testCases := []struct {
+		name  string
+		quiet bool
+		want  string
+	}{
+		{name: "default", want: "archived 3 new events\n"},
+		{name: "quiet", quiet: true, want: ""},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			var buf bytes.Buffer
+			quiet = tc.quiet
+			t.Cleanup(func() { quiet = false })
+			// Act
+			err := infof(&buf, "archived %d new events\n", 3)
+			// Assert
+			assertNoError(t, err)
+			assertEqual(t, buf.String(), tc.want)
+		})
+	}
+}
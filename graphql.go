@@ -0,0 +1,55 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type (
+	// graphqlRequest is the body of a GitHub GraphQL API call.
+	graphqlRequest struct {
+		Query     string         `json:"query"`
+		Variables map[string]any `json:"variables,omitempty"`
+	}
+	// graphqlResponse is the envelope of a GitHub GraphQL API response.
+	graphqlResponse struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+)
+
+// graphqlURL returns the GraphQL endpoint of a REST API root: the
+// GraphQL API of GitHub Enterprise Server lives next to its /api/v3.
+func graphqlURL(apiURL string) string {
+	apiURL = strings.TrimSuffix(apiURL, "/")
+	if strings.HasSuffix(apiURL, "/api/v3") {
+		return strings.TrimSuffix(apiURL, "/v3") + "/graphql"
+	}
+	return apiURL + "/graphql"
+}
+
+// fetchGraphQL runs a GraphQL query and decodes its data into v. Errors
+// reported in the response fail the call even when some data came back.
+func fetchGraphQL(hc *client, endpoint, query string, variables map[string]any, v any) error {
+	body, err := json.Marshal(graphqlRequest{Query: query, Variables: variables})
+	if err != nil {
+		return fmt.Errorf("encode GraphQL query: %w", err)
+	}
+	post := *hc
+	post.Method, post.body = "POST", body
+	var res graphqlResponse
+	if err := fetchJSON(&post, endpoint, &res); err != nil {
+		return err
+	}
+	if len(res.Errors) > 0 {
+		return fmt.Errorf("GraphQL query: %w", errors.New(res.Errors[0].Message))
+	}
+	if err := json.Unmarshal(res.Data, v); err != nil {
+		return fmt.Errorf("decode GraphQL data: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestUnitGraphQLURL(t *testing.T) {
+	testCases := []struct {
+		name   string
+		apiURL string
+		want   string
+	}{
+		{name: "github.com", apiURL: "https://api.github.com", want: "https://api.github.com/graphql"},
+		{name: "enterprise server", apiURL: "https://ghe.example.com/api/v3/", want: "https://ghe.example.com/api/graphql"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			got := graphqlURL(tc.apiURL)
+			// Assert
+			assertEqual(t, got, tc.want)
+		})
+	}
+}
+
+func TestUnitFetchGraphQL(t *testing.T) {
+	testCases := []struct {
+		name     string
+		response string
+		want     string
+		wantErr  bool
+	}{
+		{name: "data", response: `{"data":{"viewer":{"login":"octocat"}}}`, want: "octocat"},
+		{
+			name:     "errors",
+			response: `{"data":null,"errors":[{"message":"Could not resolve to a User"}]}`,
+			wantErr:  true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assertEqual(t, r.Method, "POST")
+				assertEqual(t, r.Header.Get("Authorization"), "Bearer ghp")
+				var req graphqlRequest
+				assertNoError(t, json.NewDecoder(r.Body).Decode(&req))
+				assertEqual(t, req.Query, "query { viewer { login } }")
+				assertEqual(t, req.Variables["first"], any(float64(1)))
+				fmt.Fprint(w, tc.response)
+			}))
+			defer srv.Close()
+			hc := newClient("ghp")
+			var data struct {
+				Viewer struct {
+					Login string `json:"login"`
+				} `json:"viewer"`
+			}
+			// Act
+			err := fetchGraphQL(hc, srv.URL, "query { viewer { login } }", map[string]any{"first": 1}, &data)
+			// Assert
+			if tc.wantErr {
+				assertNotNil(t, err)
+				return
+			}
+			assertNoError(t, err)
+			assertEqual(t, data.Viewer.Login, tc.want)
+			assertEqual(t, hc.Method, "GET")
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	activityv1 "github.com/alnah/go-github-activity/proto/activityv1"
+)
+
+// grpcServer serves the activity over gRPC for the services preferring it
+// to the JSON API, with the tenants, API keys and stream hub of the server
+// last stored, so that a reload applies to both.
+type grpcServer struct {
+	activityv1.UnimplementedActivityServer
+	api *atomic.Pointer[server]
+	// done ends the open streams, e.g. on shutdown.
+	done <-chan struct{}
+}
+
+// defaultStatsDays is the window of GetStats when the request sets none.
+const defaultStatsDays = 7
+
+// newGRPCServer returns a gRPC server of the activity service, checking
+// the API keys of the requests.
+func newGRPCServer(g *grpcServer) *grpc.Server {
+	gs := grpc.NewServer(grpc.UnaryInterceptor(g.authorizeUnary), grpc.StreamInterceptor(g.authorizeStream))
+	activityv1.RegisterActivityServer(gs, g)
+	return gs
+}
+
+// ListActivity returns the latest events of a user, newest first.
+func (g *grpcServer) ListActivity(
+	_ context.Context, req *activityv1.ListActivityRequest,
+) (*activityv1.ListActivityResponse, error) {
+	events, err := g.events(req.GetUser())
+	if err != nil {
+		return nil, err
+	}
+	res := &activityv1.ListActivityResponse{Events: make([]*activityv1.Event, 0, len(events))}
+	for _, ev := range events {
+		res.Events = append(res.Events, protoEvent(ev))
+	}
+	return res, nil
+}
+
+// StreamActivity sends the user's new events until the client leaves or
+// the server stops.
+func (g *grpcServer) StreamActivity(
+	req *activityv1.StreamActivityRequest, stream grpc.ServerStreamingServer[activityv1.Event],
+) error {
+	if req.GetUser() == "" {
+		return status.Error(codes.InvalidArgument, "missing user")
+	}
+	events, unsubscribe := g.api.Load().hub.subscribe(req.GetUser())
+	defer unsubscribe()
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-g.done:
+			return nil
+		case ev := <-events:
+			if err := stream.Send(protoEvent(ev)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// GetStats returns the metric totals of the user's events over the last
+// days.
+func (g *grpcServer) GetStats(_ context.Context, req *activityv1.GetStatsRequest) (*activityv1.Stats, error) {
+	days := int(req.GetDays())
+	if days < 0 {
+		return nil, status.Error(codes.InvalidArgument, "days must be positive")
+	}
+	if days == 0 {
+		days = defaultStatsDays
+	}
+	events, err := g.events(req.GetUser())
+	if err != nil {
+		return nil, err
+	}
+	since := g.api.Load().clock.Now().AddDate(0, 0, -days)
+	stats := &activityv1.Stats{Totals: map[string]int64{}}
+	for name, total := range sumMetrics(eventsSince(events, since)) {
+		stats.Totals[name] = int64(total)
+	}
+	return stats, nil
+}
+
+// events fetches the events of a user with the credentials of their
+// tenant, refused while its budget is exhausted like on the JSON API.
+func (g *grpcServer) events(user string) ([]ghEvent, error) {
+	if user == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing user")
+	}
+	s := g.api.Load()
+	t := s.tenants.lookup(user)
+	if _, ok := t.budget.exhausted(s.clock.Now()); ok {
+		return nil, status.Errorf(codes.ResourceExhausted, "rate limit exhausted for %s", t.Name)
+	}
+	events, err := s.fetch(t, user)
+	switch {
+	case isStatus(err, http.StatusNotFound):
+		return nil, status.Error(codes.NotFound, err.Error())
+	case err != nil:
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	return hideSponsorships(events), nil
+}
+
+// authorize rejects the calls without one of the configured API keys,
+// given as a bearer token or in x-api-key. Without keys the API is open.
+func (g *grpcServer) authorize(ctx context.Context) error {
+	keys := g.api.Load().apiKeys
+	if len(keys) == 0 {
+		return nil
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	var key string
+	if v := md.Get("x-api-key"); len(v) > 0 {
+		key = v[0]
+	}
+	if v := md.Get("authorization"); len(v) > 0 {
+		if bearer, ok := strings.CutPrefix(v[0], "Bearer "); ok {
+			key = bearer
+		}
+	}
+	if !validKey(keys, key) {
+		return status.Error(codes.Unauthenticated, "missing or invalid API key")
+	}
+	return nil
+}
+
+func (g *grpcServer) authorizeUnary(
+	ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler,
+) (any, error) {
+	if err := g.authorize(ctx); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (g *grpcServer) authorizeStream(
+	srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler,
+) error {
+	if err := g.authorize(ss.Context()); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
+
+// protoEvent converts an event to its protobuf message, with its summary
+// and labels as served by the JSON API.
+func protoEvent(ev ghEvent) *activityv1.Event {
+	out := &activityv1.Event{
+		Id:   ev.ID,
+		Type: ev.Type,
+		Actor: &activityv1.Actor{
+			Id: int64(ev.Actor.ID), Login: ev.Actor.Login, DisplayLogin: ev.Actor.DisplayLogin,
+			Url: ev.Actor.URL, AvatarUrl: ev.Actor.AvatarURL,
+		},
+		Repo:      &activityv1.Repo{Id: int64(ev.Repo.ID), Name: ev.Repo.Name, Url: ev.Repo.URL},
+		Public:    ev.Public,
+		CreatedAt: timestamppb.New(ev.CreatedAt),
+		Summary:   summarize(catalogs[defaultLang], ev),
+		Labels:    repoLabels(ev.Repo.Meta),
+	}
+	if m := ev.Repo.Meta; m != nil {
+		out.Repo.Meta = &activityv1.RepoMeta{
+			FullName: m.FullName, Description: m.Description, Stars: int32(m.Stars),
+			Fork: m.Fork, Language: m.Language, Archived: m.Archived,
+		}
+	}
+	p := ev.Payload
+	out.Payload = &activityv1.Payload{
+		Action: p.Action, PushId: p.PushID, Size: int32(p.Size), DistinctSize: int32(p.DistinctSize),
+		Ref: p.Ref, RefType: p.RefType, Head: p.Head, Before: p.Before,
+	}
+	for _, c := range p.Commits {
+		out.Payload.Commits = append(out.Payload.Commits, &activityv1.Commit{
+			Sha: c.SHA, AuthorName: c.Author.Name, AuthorEmail: c.Author.Email,
+			Message: c.Message, Distinct: c.Distinct, Url: c.URL,
+		})
+	}
+	if pr := p.PullRequest; pr != nil {
+		out.Payload.PullRequest = &activityv1.PullRequest{
+			Number: int32(pr.Number), Title: pr.Title, HtmlUrl: pr.HTMLURL, State: pr.State,
+		}
+	}
+	return out
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	activityv1 "github.com/alnah/go-github-activity/proto/activityv1"
+)
+
+// dialGRPC serves s over gRPC in memory and returns a client of it.
+func dialGRPC(t *testing.T, s *server) activityv1.ActivityClient {
+	t.Helper()
+	var api atomic.Pointer[server]
+	api.Store(s)
+	done := make(chan struct{})
+	lis := bufconn.Listen(1 << 20)
+	gs := newGRPCServer(&grpcServer{api: &api, done: done})
+	go gs.Serve(lis)
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assertNoError(t, err)
+	t.Cleanup(func() {
+		close(done)
+		conn.Close()
+		gs.Stop()
+	})
+	return activityv1.NewActivityClient(conn)
+}
+
+func TestUnitGRPCListActivity(t *testing.T) {
+	exhausted := &tenant{Name: "work"}
+	exhausted.budget = rateBudget{known: true, remaining: 0, reset: time.Now().Add(time.Hour)}
+	testCases := []struct {
+		name     string
+		user     string
+		tenant   *tenant
+		key      string
+		err      error
+		wantCode codes.Code
+	}{
+		{name: "events", user: "octocat", tenant: &tenant{}, key: "k1", wantCode: codes.OK},
+		{name: "missing user", tenant: &tenant{}, key: "k1", wantCode: codes.InvalidArgument},
+		{name: "missing key", user: "octocat", tenant: &tenant{}, wantCode: codes.Unauthenticated},
+		{name: "exhausted budget", user: "octocat", tenant: exhausted, key: "k1", wantCode: codes.ResourceExhausted},
+		{
+			name:     "unknown user",
+			user:     "ghost",
+			tenant:   &tenant{},
+			key:      "k1",
+			err:      &apiError{StatusCode: 404, Status: "404 Not Found"},
+			wantCode: codes.NotFound,
+		},
+		{
+			name:     "upstream failure",
+			user:     "octocat",
+			tenant:   &tenant{},
+			key:      "k1",
+			err:      &apiError{StatusCode: 500, Status: "500 Internal Server Error"},
+			wantCode: codes.Unavailable,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			var gotUser string
+			client := dialGRPC(t, &server{
+				tenants: &tenantSet{fallback: tc.tenant},
+				fetch: func(_ *tenant, user string) ([]ghEvent, error) {
+					gotUser = user
+					return []ghEvent{{ID: "1", Type: "WatchEvent", Repo: repo{Name: "octo/repo"}}}, tc.err
+				},
+				clock:   systemClock{},
+				apiKeys: []string{"k1"},
+			})
+			ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+tc.key)
+			// Act
+			res, err := client.ListActivity(ctx, &activityv1.ListActivityRequest{User: tc.user})
+			// Assert
+			assertEqual(t, status.Code(err), tc.wantCode)
+			if tc.wantCode != codes.OK {
+				return
+			}
+			assertEqual(t, gotUser, "octocat")
+			assertEqual(t, len(res.Events), 1)
+			assertEqual(t, res.Events[0].Summary, "Starred octo/repo")
+			assertEqual(t, res.Events[0].Repo.Name, "octo/repo")
+		})
+	}
+}
+
+func TestUnitGRPCGetStats(t *testing.T) {
+	// Arrange
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	client := dialGRPC(t, &server{
+		tenants: &tenantSet{fallback: &tenant{}},
+		fetch: func(*tenant, string) ([]ghEvent, error) {
+			return []ghEvent{
+				{Type: "PushEvent", Payload: payload{Size: 3}, CreatedAt: now.AddDate(0, 0, -1)},
+				{Type: "WatchEvent", CreatedAt: now.AddDate(0, 0, -2)},
+				{Type: "WatchEvent", CreatedAt: now.AddDate(0, 0, -30)},
+			}, nil
+		},
+		clock: &fakeClock{now: now},
+	})
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", "unused")
+	// Act
+	stats, err := client.GetStats(ctx, &activityv1.GetStatsRequest{User: "octocat"})
+	// Assert
+	assertNoError(t, err)
+	assertEqual(t, stats.Totals["events"], int64(2))
+	assertEqual(t, stats.Totals["commits"], int64(3))
+	assertEqual(t, stats.Totals["stars"], int64(1))
+}
+
+func TestUnitGRPCStreamActivity(t *testing.T) {
+	// Arrange
+	var calls atomic.Int32
+	client := dialGRPC(t, &server{
+		tenants: &tenantSet{fallback: &tenant{}},
+		clock:   systemClock{},
+		hub: newStreamHub(5*time.Millisecond, func(string) ([]ghEvent, error) {
+			if calls.Add(1) == 1 {
+				return nil, nil
+			}
+			return []ghEvent{{ID: "42", Type: "WatchEvent", Repo: repo{Name: "octo/repo"}}}, nil
+		}),
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	// Act
+	stream, err := client.StreamActivity(ctx, &activityv1.StreamActivityRequest{User: "octocat"})
+	assertNoError(t, err)
+	ev, err := stream.Recv()
+	// Assert
+	assertNoError(t, err)
+	assertEqual(t, ev.Id, "42")
+	assertEqual(t, ev.Summary, "Starred octo/repo")
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"os"
+	"time"
+)
+
+const (
+	// heatmapCell is the side of a day square in pixels.
+	heatmapCell = 10
+	// heatmapGap is the space between day squares in pixels.
+	heatmapGap = 2
+)
+
+// heatmapPalette grades days from no activity to the busiest ones, using the
+// GitHub contribution graph colors.
+var heatmapPalette = []color.RGBA{
+	{0xeb, 0xed, 0xf0, 0xff},
+	{0x9b, 0xe9, 0xa8, 0xff},
+	{0x40, 0xc4, 0x63, 0xff},
+	{0x30, 0xa1, 0x4e, 0xff},
+	{0x21, 0x6e, 0x39, 0xff},
+}
+
+// runHeatmap writes a PNG contribution-style heatmap of the user's events.
+func runHeatmap(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("heatmap", flag.ContinueOnError)
+	weeks := flags.Int("weeks", 13, "number of weeks to draw, ending this week")
+	out := flags.String("out", "", "PNG file to write instead of stdout")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: go-github-activity heatmap [flags] <username>")
+	}
+	if *weeks <= 0 {
+		return fmt.Errorf("invalid number of weeks: %d", *weeks)
+	}
+	events, err := fetchUserEvents(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	img := drawHeatmap(dailyCounts(events, time.Local), time.Now(), *weeks)
+	w := stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return fmt.Errorf("create heatmap file: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := png.Encode(w, img); err != nil {
+		return fmt.Errorf("encode heatmap: %w", err)
+	}
+	return nil
+}
+
+// startOfDay truncates t to midnight in loc.
+func startOfDay(t time.Time, loc *time.Location) time.Time {
+	y, m, d := t.In(loc).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, loc)
+}
+
+// dailyCounts counts events per calendar day in loc.
+func dailyCounts(events []ghEvent, loc *time.Location) map[time.Time]int {
+	counts := make(map[time.Time]int)
+	for _, ev := range events {
+		counts[startOfDay(ev.CreatedAt, loc)]++
+	}
+	return counts
+}
+
+// drawHeatmap lays out one column per week (Sunday on top) up to the week
+// containing now, shading each day relative to the busiest one.
+func drawHeatmap(counts map[time.Time]int, now time.Time, weeks int) *image.RGBA {
+	step := heatmapCell + heatmapGap
+	img := image.NewRGBA(image.Rect(0, 0, weeks*step+heatmapGap, 7*step+heatmapGap))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	peak := 0
+	for _, n := range counts {
+		peak = max(peak, n)
+	}
+	today := startOfDay(now, now.Location())
+	first := today.AddDate(0, 0, -int(today.Weekday())-7*(weeks-1))
+	for day := first; !day.After(today); day = day.AddDate(0, 0, 1) {
+		col := int(day.Sub(first).Hours()/24+0.5) / 7
+		row := int(day.Weekday())
+		x, y := heatmapGap+col*step, heatmapGap+row*step
+		cell := image.Rect(x, y, x+heatmapCell, y+heatmapCell)
+		draw.Draw(img, cell, image.NewUniform(heatmapPalette[heatLevel(counts[day], peak)]), image.Point{}, draw.Src)
+	}
+	return img
+}
+
+// heatLevel maps a count to a palette index, 0 being reserved for idle days.
+func heatLevel(n, peak int) int {
+	if n <= 0 || peak <= 0 {
+		return 0
+	}
+	levels := len(heatmapPalette) - 1
+	return (n*levels + peak - 1) / peak
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUnitHeatLevel(t *testing.T) {
+	testCases := []struct {
+		name string
+		n    int
+		peak int
+		want int
+	}{
+		{name: "idle", n: 0, peak: 10, want: 0},
+		{name: "lightest", n: 1, peak: 10, want: 1},
+		{name: "middle", n: 5, peak: 10, want: 2},
+		{name: "busiest", n: 10, peak: 10, want: 4},
+		{name: "no peak", n: 0, peak: 0, want: 0},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			got := heatLevel(tc.n, tc.peak)
+			// Assert
+			assertEqual(t, got, tc.want)
+		})
+	}
+}
+
+func TestUnitDrawHeatmap(t *testing.T) {
+	// Arrange
+	now := time.Date(2025, 3, 12, 15, 0, 0, 0, time.UTC) // a Wednesday
+	events := []ghEvent{
+		{CreatedAt: now},
+		{CreatedAt: now.Add(-time.Hour)},
+		{CreatedAt: now.AddDate(0, 0, -10)},
+	}
+	// Act
+	img := drawHeatmap(dailyCounts(events, time.UTC), now, 2)
+	// Assert
+	step := heatmapCell + heatmapGap
+	assertEqual(t, img.Bounds().Dx(), 2*step+heatmapGap)
+	assertEqual(t, img.Bounds().Dy(), 7*step+heatmapGap)
+	today := img.RGBAAt(heatmapGap+step, heatmapGap+3*step)
+	assertEqual(t, today, heatmapPalette[4])
+	tenDaysAgo := img.RGBAAt(heatmapGap, heatmapGap+0*step) // Sunday of the first week
+	assertEqual(t, tenDaysAgo, heatmapPalette[2])
+	future := img.RGBAAt(heatmapGap+step, heatmapGap+4*step)
+	assertEqual(t, future.R, uint8(0xff))
+}
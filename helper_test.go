@@ -17,3 +17,10 @@ func assertNotNil(t testing.TB, err error) {
 		t.Error("expected an error, but got nil")
 	}
 }
+
+func assertEqual[T comparable](t testing.TB, got, want T) {
+	t.Helper()
+	if got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+)
+
+type (
+	// htmlRenderer writes events as a standalone HTML page.
+	htmlRenderer struct {
+		cat catalog
+	}
+	// htmlItem is the view of one event in the HTML page.
+	htmlItem struct {
+		Actor     string
+		AvatarURL string
+		Summary   string
+		Time      string
+		RepoURL   string
+	}
+)
+
+// htmlPage lists events with the actor avatar and display name.
+var htmlPage = template.Must(template.New("activity").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>GitHub activity</title></head>
+<body>
+<ul>
+{{- range .}}
+<li>{{if .AvatarURL}}<img src="{{.AvatarURL}}" alt="" width="20" height="20"> {{end -}}
+<strong>{{.Actor}}</strong> <a href="{{.RepoURL}}">{{.Summary}}</a> <time datetime="{{.Time}}">{{.Time}}</time></li>
+{{- end}}
+</ul>
+</body>
+</html>
+`))
+
+// render executes the page template over the events.
+func (r htmlRenderer) render(w io.Writer, events []ghEvent) error {
+	events = redactedCopy(events)
+	items := make([]htmlItem, 0, len(events))
+	for _, ev := range events {
+		items = append(items, htmlItem{
+			Actor:     ev.Actor.displayName(),
+			AvatarURL: ev.Actor.avatar(),
+			Summary:   summarize(r.cat, ev),
+			Time:      ev.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+			RepoURL:   githubURL + ev.Repo.Name,
+		})
+	}
+	if err := htmlPage.Execute(w, items); err != nil {
+		return fmt.Errorf("render HTML: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestUnitHTMLRenderer(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+	events := []ghEvent{{
+		Type: "WatchEvent",
+		Repo: repo{Name: "octo/<repo>"},
+		Actor: actor{
+			Login:     "octocat",
+			AvatarURL: "https://avatars.example/u/1",
+			Profile:   &userProfile{Name: "The Octocat"},
+		},
+	}}
+	// Act
+	err := htmlRenderer{cat: lookupCatalog("en")}.render(&buf, events)
+	// Assert
+	assertNoError(t, err)
+	got := buf.String()
+	assertEqual(t, strings.Contains(got, `<img src="https://avatars.example/u/1"`), true)
+	assertEqual(t, strings.Contains(got, "<strong>The Octocat</strong>"), true)
+	assertEqual(t, strings.Contains(got, "Starred octo/&lt;repo&gt;"), true)
+}
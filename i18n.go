@@ -0,0 +1,250 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// defaultLang is used when no requested language has a catalog.
+const defaultLang = "en"
+
+// catalog maps message keys to fmt format strings for a single language.
+// Formats may use explicit argument indexes so translations can reorder them.
+type catalog map[string]string
+
+// catalogs holds every supported language keyed by its ISO 639-1 code.
+var catalogs = map[string]catalog{
+	"en": {
+		"push.one":                      "Pushed %d commit to %s",
+		"push.other":                    "Pushed %d commits to %s",
+		"create.repository":             "Created repository %s",
+		"create.branch":                 "Created branch %s in %s",
+		"create.tag":                    "Created tag %s in %s",
+		"delete.branch":                 "Deleted branch %s in %s",
+		"delete.tag":                    "Deleted tag %s in %s",
+		"issues.opened":                 "Opened a new issue in %s",
+		"issues.closed":                 "Closed an issue in %s",
+		"issues.reopened":               "Reopened an issue in %s",
+		"issues":                        "Updated an issue in %s",
+		"issue_comment":                 "Commented on an issue in %s",
+		"pull_request.opened":           "Opened a pull request in %s",
+		"pull_request.closed":           "Closed a pull request in %s",
+		"pull_request":                  "Updated a pull request in %s",
+		"pull_request_review":           "Reviewed a pull request in %s",
+		"watch":                         "Starred %s",
+		"fork":                          "Forked %s",
+		"release":                       "Published a release in %s",
+		"release.tag":                   "Published %s in %s",
+		"release.major":                 "Published %s in %s, a major version bump",
+		"public":                        "Made %s public",
+		"member":                        "Added a collaborator to %s",
+		"audit":                         "%s by %s in %s",
+		"project_item.edited":           "Moved %s to %s in project %s",
+		"project_item.assigned":         "Was assigned %s in project %s",
+		"project_item":                  "Updated a project item in %s",
+		"discussion.created":            "Started a discussion in %s",
+		"discussion.answered":           "Answered a discussion in %s",
+		"discussion":                    "Updated a discussion in %s",
+		"discussion_comment":            "Commented on a discussion in %s",
+		"sponsorship.created":           "Gained %s as a sponsor",
+		"sponsorship.cancelled":         "Lost %s as a sponsor",
+		"sponsorship":                   "Sponsorships of %s changed",
+		"workflow_run.success":          "Workflow %s succeeded in %s",
+		"workflow_run.failure":          "Workflow %s failed in %s",
+		"workflow_run":                  "Workflow %s finished in %s",
+		"dependabot_alert.created":      "New Dependabot alert in %s",
+		"dependabot_alert.fixed":        "Fixed a Dependabot alert in %s",
+		"dependabot_alert.dismissed":    "Dismissed a Dependabot alert in %s",
+		"dependabot_alert":              "Updated a Dependabot alert in %s",
+		"code_scanning_alert.created":   "New code scanning alert in %s",
+		"code_scanning_alert.fixed":     "Fixed a code scanning alert in %s",
+		"code_scanning_alert.dismissed": "Dismissed a code scanning alert in %s",
+		"code_scanning_alert":           "Updated a code scanning alert in %s",
+		"security_advisory":             "Security advisory %s published for %s",
+		"security_advisory.severity":    "Security advisory %s (%s severity) published for %s",
+		"other":                         "%s in %s",
+	},
+	"fr": {
+		"push.one":                      "A poussé %d commit vers %s",
+		"push.other":                    "A poussé %d commits vers %s",
+		"create.repository":             "A créé le dépôt %s",
+		"create.branch":                 "A créé la branche %s dans %s",
+		"create.tag":                    "A créé le tag %s dans %s",
+		"delete.branch":                 "A supprimé la branche %s dans %s",
+		"delete.tag":                    "A supprimé le tag %s dans %s",
+		"issues.opened":                 "A ouvert un nouveau ticket dans %s",
+		"issues.closed":                 "A fermé un ticket dans %s",
+		"issues.reopened":               "A rouvert un ticket dans %s",
+		"issues":                        "A mis à jour un ticket dans %s",
+		"issue_comment":                 "A commenté un ticket dans %s",
+		"pull_request.opened":           "A ouvert une pull request dans %s",
+		"pull_request.closed":           "A fermé une pull request dans %s",
+		"pull_request":                  "A mis à jour une pull request dans %s",
+		"pull_request_review":           "A relu une pull request dans %s",
+		"watch":                         "A mis une étoile à %s",
+		"fork":                          "A forké %s",
+		"release":                       "A publié une version dans %s",
+		"release.tag":                   "A publié %s dans %s",
+		"release.major":                 "A publié %s dans %s, une nouvelle version majeure",
+		"public":                        "A rendu %s public",
+		"member":                        "A ajouté un collaborateur à %s",
+		"audit":                         "%s par %s dans %s",
+		"project_item.edited":           "A déplacé %s vers %s dans le projet %s",
+		"project_item.assigned":         "A été assigné à %s dans le projet %s",
+		"project_item":                  "A mis à jour un élément de projet dans %s",
+		"discussion.created":            "A lancé une discussion dans %s",
+		"discussion.answered":           "A répondu à une discussion dans %s",
+		"discussion":                    "A mis à jour une discussion dans %s",
+		"discussion_comment":            "A commenté une discussion dans %s",
+		"sponsorship.created":           "A gagné %s comme sponsor",
+		"sponsorship.cancelled":         "A perdu %s comme sponsor",
+		"sponsorship":                   "Les sponsors de %s ont changé",
+		"workflow_run.success":          "Le workflow %s a réussi dans %s",
+		"workflow_run.failure":          "Le workflow %s a échoué dans %s",
+		"workflow_run":                  "Le workflow %s s'est terminé dans %s",
+		"dependabot_alert.created":      "Nouvelle alerte Dependabot dans %s",
+		"dependabot_alert.fixed":        "A corrigé une alerte Dependabot dans %s",
+		"dependabot_alert.dismissed":    "A ignoré une alerte Dependabot dans %s",
+		"dependabot_alert":              "A mis à jour une alerte Dependabot dans %s",
+		"code_scanning_alert.created":   "Nouvelle alerte d'analyse de code dans %s",
+		"code_scanning_alert.fixed":     "A corrigé une alerte d'analyse de code dans %s",
+		"code_scanning_alert.dismissed": "A ignoré une alerte d'analyse de code dans %s",
+		"code_scanning_alert":           "A mis à jour une alerte d'analyse de code dans %s",
+		"security_advisory":             "Avis de sécurité %s publié pour %s",
+		"security_advisory.severity":    "Avis de sécurité %s (gravité %s) publié pour %s",
+		"other":                         "%s dans %s",
+	},
+	"es": {
+		"push.one":                      "Subió %d commit a %s",
+		"push.other":                    "Subió %d commits a %s",
+		"create.repository":             "Creó el repositorio %s",
+		"create.branch":                 "Creó la rama %s en %s",
+		"create.tag":                    "Creó la etiqueta %s en %s",
+		"delete.branch":                 "Eliminó la rama %s en %s",
+		"delete.tag":                    "Eliminó la etiqueta %s en %s",
+		"issues.opened":                 "Abrió una nueva incidencia en %s",
+		"issues.closed":                 "Cerró una incidencia en %s",
+		"issues.reopened":               "Reabrió una incidencia en %s",
+		"issues":                        "Actualizó una incidencia en %s",
+		"issue_comment":                 "Comentó una incidencia en %s",
+		"pull_request.opened":           "Abrió un pull request en %s",
+		"pull_request.closed":           "Cerró un pull request en %s",
+		"pull_request":                  "Actualizó un pull request en %s",
+		"pull_request_review":           "Revisó un pull request en %s",
+		"watch":                         "Marcó con estrella %s",
+		"fork":                          "Hizo fork de %s",
+		"release":                       "Publicó una versión en %s",
+		"release.tag":                   "Publicó %s en %s",
+		"release.major":                 "Publicó %s en %s, un salto de versión mayor",
+		"public":                        "Hizo público %s",
+		"member":                        "Añadió un colaborador a %s",
+		"audit":                         "%s por %s en %s",
+		"project_item.edited":           "Movió %s a %s en el proyecto %s",
+		"project_item.assigned":         "Fue asignado a %s en el proyecto %s",
+		"project_item":                  "Actualizó un elemento de proyecto en %s",
+		"discussion.created":            "Inició una discusión en %s",
+		"discussion.answered":           "Respondió una discusión en %s",
+		"discussion":                    "Actualizó una discusión en %s",
+		"discussion_comment":            "Comentó una discusión en %s",
+		"sponsorship.created":           "Ganó a %s como patrocinador",
+		"sponsorship.cancelled":         "Perdió a %s como patrocinador",
+		"sponsorship":                   "Los patrocinios de %s cambiaron",
+		"workflow_run.success":          "El workflow %s tuvo éxito en %s",
+		"workflow_run.failure":          "El workflow %s falló en %s",
+		"workflow_run":                  "El workflow %s terminó en %s",
+		"dependabot_alert.created":      "Nueva alerta de Dependabot en %s",
+		"dependabot_alert.fixed":        "Corrigió una alerta de Dependabot en %s",
+		"dependabot_alert.dismissed":    "Descartó una alerta de Dependabot en %s",
+		"dependabot_alert":              "Actualizó una alerta de Dependabot en %s",
+		"code_scanning_alert.created":   "Nueva alerta de análisis de código en %s",
+		"code_scanning_alert.fixed":     "Corrigió una alerta de análisis de código en %s",
+		"code_scanning_alert.dismissed": "Descartó una alerta de análisis de código en %s",
+		"code_scanning_alert":           "Actualizó una alerta de análisis de código en %s",
+		"security_advisory":             "Aviso de seguridad %s publicado para %s",
+		"security_advisory.severity":    "Aviso de seguridad %s (gravedad %s) publicado para %s",
+		"other":                         "%s en %s",
+	},
+	"ja": {
+		"push.one":                      "%[2]s に %[1]d 件のコミットをプッシュしました",
+		"push.other":                    "%[2]s に %[1]d 件のコミットをプッシュしました",
+		"create.repository":             "リポジトリ %s を作成しました",
+		"create.branch":                 "%[2]s にブランチ %[1]s を作成しました",
+		"create.tag":                    "%[2]s にタグ %[1]s を作成しました",
+		"delete.branch":                 "%[2]s のブランチ %[1]s を削除しました",
+		"delete.tag":                    "%[2]s のタグ %[1]s を削除しました",
+		"issues.opened":                 "%s で新しい issue を作成しました",
+		"issues.closed":                 "%s の issue をクローズしました",
+		"issues.reopened":               "%s の issue を再オープンしました",
+		"issues":                        "%s の issue を更新しました",
+		"issue_comment":                 "%s の issue にコメントしました",
+		"pull_request.opened":           "%s でプルリクエストを作成しました",
+		"pull_request.closed":           "%s のプルリクエストをクローズしました",
+		"pull_request":                  "%s のプルリクエストを更新しました",
+		"pull_request_review":           "%s のプルリクエストをレビューしました",
+		"watch":                         "%s にスターを付けました",
+		"fork":                          "%s をフォークしました",
+		"release":                       "%s でリリースを公開しました",
+		"release.tag":                   "%[2]s で %[1]s を公開しました",
+		"release.major":                 "%[2]s で %[1]s を公開しました（メジャーバージョンアップ）",
+		"public":                        "%s を公開しました",
+		"member":                        "%s にコラボレーターを追加しました",
+		"audit":                         "%[3]s で %[2]s が %[1]s",
+		"project_item.edited":           "プロジェクト %[3]s で %[1]s を %[2]s に移動しました",
+		"project_item.assigned":         "プロジェクト %[2]s で %[1]s に割り当てられました",
+		"project_item":                  "%s のプロジェクト項目を更新しました",
+		"discussion.created":            "%s でディスカッションを開始しました",
+		"discussion.answered":           "%s のディスカッションに回答しました",
+		"discussion":                    "%s のディスカッションを更新しました",
+		"discussion_comment":            "%s のディスカッションにコメントしました",
+		"sponsorship.created":           "%s がスポンサーになりました",
+		"sponsorship.cancelled":         "%s がスポンサーをやめました",
+		"sponsorship":                   "%s のスポンサーが変わりました",
+		"workflow_run.success":          "%[2]s のワークフロー %[1]s が成功しました",
+		"workflow_run.failure":          "%[2]s のワークフロー %[1]s が失敗しました",
+		"workflow_run":                  "%[2]s のワークフロー %[1]s が終了しました",
+		"dependabot_alert.created":      "%s に新しい Dependabot アラートがあります",
+		"dependabot_alert.fixed":        "%s の Dependabot アラートを修正しました",
+		"dependabot_alert.dismissed":    "%s の Dependabot アラートを却下しました",
+		"dependabot_alert":              "%s の Dependabot アラートを更新しました",
+		"code_scanning_alert.created":   "%s に新しいコードスキャンアラートがあります",
+		"code_scanning_alert.fixed":     "%s のコードスキャンアラートを修正しました",
+		"code_scanning_alert.dismissed": "%s のコードスキャンアラートを却下しました",
+		"code_scanning_alert":           "%s のコードスキャンアラートを更新しました",
+		"security_advisory":             "%[2]s のアドバイザリ %[1]s が公開されました",
+		"security_advisory.severity":    "%[3]s のアドバイザリ %[1]s（深刻度 %[2]s）が公開されました",
+		"other":                         "%[2]s で %[1]s",
+	},
+}
+
+// resolveLang picks the output language from the --lang flag, then the LANG
+// environment variable (e.g. "fr_FR.UTF-8"), falling back to English.
+func resolveLang(flagLang, envLang string) string {
+	for _, candidate := range []string{flagLang, envLang} {
+		code := strings.ToLower(candidate)
+		if i := strings.IndexAny(code, "_-.@"); i >= 0 {
+			code = code[:i]
+		}
+		if _, ok := catalogs[code]; ok {
+			return code
+		}
+	}
+	return defaultLang
+}
+
+// lookupCatalog returns the catalog for lang, or the default one.
+func lookupCatalog(lang string) catalog {
+	if c, ok := catalogs[lang]; ok {
+		return c
+	}
+	return catalogs[defaultLang]
+}
+
+// sprintf formats the message for key, falling back to English when the
+// catalog lacks a translation.
+func (c catalog) sprintf(key string, args ...any) string {
+	format, ok := c[key]
+	if !ok {
+		format = catalogs[defaultLang][key]
+	}
+	return fmt.Sprintf(format, args...)
+}
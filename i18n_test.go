@@ -0,0 +1,46 @@
+package main
+
+import "testing"
+
+func TestUnitResolveLang(t *testing.T) {
+	testCases := []struct {
+		name     string
+		flagLang string
+		envLang  string
+		want     string
+	}{
+		{name: "flag wins over env", flagLang: "ja", envLang: "fr_FR.UTF-8", want: "ja"},
+		{name: "env locale", envLang: "fr_FR.UTF-8", want: "fr"},
+		{name: "env with region dash", envLang: "es-ES", want: "es"},
+		{name: "unknown flag falls back to env", flagLang: "xx", envLang: "es", want: "es"},
+		{name: "posix locale", envLang: "C", want: "en"},
+		{name: "nothing set", want: "en"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			got := resolveLang(tc.flagLang, tc.envLang)
+			// Assert
+			assertEqual(t, got, tc.want)
+		})
+	}
+}
+
+func TestUnitCatalogsComplete(t *testing.T) {
+	for lang, cat := range catalogs {
+		for key := range catalogs[defaultLang] {
+			if _, ok := cat[key]; !ok {
+				t.Errorf("catalog %q is missing key %q", lang, key)
+			}
+		}
+	}
+}
+
+func TestUnitCatalogFallback(t *testing.T) {
+	// Arrange
+	cat := catalog{}
+	// Act
+	got := cat.sprintf("watch", "octo/repo")
+	// Assert
+	assertEqual(t, got, "Starred octo/repo")
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"strings"
+)
+
+// iconSet maps lower-cased event types, and "checks:" states, to the marker
+// printed with a summary.
+type iconSet map[string]string
+
+// defaultIcon prefixes events without a dedicated marker.
+const defaultIcon = "-"
+
+var (
+	// emojiIcons decorates lines on terminals that can display emoji.
+	emojiIcons = iconSet{
+		"pushevent":                     "🛠",
+		"issuesevent":                   "🐛",
+		"issuecommentevent":             "💬",
+		"pullrequestevent":              "🔀",
+		"pullrequestreviewevent":        "👀",
+		"pullrequestreviewcommentevent": "👀",
+		"watchevent":                    "⭐",
+		"forkevent":                     "🍴",
+		"createevent":                   "✨",
+		"deleteevent":                   "🗑",
+		"releaseevent":                  "🚀",
+		"publicevent":                   "📢",
+		"memberevent":                   "👥",
+		"projectsv2itemevent":           "📋",
+		"discussionevent":               "🗨",
+		"discussioncommentevent":        "🗨",
+		"sponsorshipevent":              "💖",
+		"workflowrunevent":              "⚙",
+		"dependabotalertevent":          "🛡",
+		"securityadvisoryevent":         "🚨",
+		"codescanningalertevent":        "🛡",
+		"checks:success":                "✅",
+		"checks:failure":                "❌",
+		"checks:pending":                "⏳",
+	}
+	// asciiIcons is the fallback when emoji are disabled.
+	asciiIcons = iconSet{
+		"pushevent":                     "[push]",
+		"issuesevent":                   "[issue]",
+		"issuecommentevent":             "[comment]",
+		"pullrequestevent":              "[pr]",
+		"pullrequestreviewevent":        "[review]",
+		"pullrequestreviewcommentevent": "[review]",
+		"watchevent":                    "[star]",
+		"forkevent":                     "[fork]",
+		"createevent":                   "[create]",
+		"deleteevent":                   "[delete]",
+		"releaseevent":                  "[release]",
+		"publicevent":                   "[public]",
+		"memberevent":                   "[member]",
+		"projectsv2itemevent":           "[project]",
+		"discussionevent":               "[discussion]",
+		"discussioncommentevent":        "[discussion]",
+		"sponsorshipevent":              "[sponsor]",
+		"workflowrunevent":              "[ci]",
+		"dependabotalertevent":          "[security]",
+		"securityadvisoryevent":         "[advisory]",
+		"codescanningalertevent":        "[security]",
+		"checks:success":                "[green]",
+		"checks:failure":                "[red]",
+		"checks:pending":                "[pending]",
+	}
+)
+
+// icon returns the marker for an event type.
+func (s iconSet) icon(eventType string) string {
+	if i, ok := s[strings.ToLower(eventType)]; ok {
+		return i
+	}
+	return defaultIcon
+}
+
+// loadIcons returns the emoji set with user overrides from the icons config
+// map, or the ASCII set when emoji are disabled.
+func loadIcons(emoji bool, overrides map[string]string) iconSet {
+	if !emoji {
+		return asciiIcons
+	}
+	set := make(iconSet, len(emojiIcons)+len(overrides))
+	for k, v := range emojiIcons {
+		set[k] = v
+	}
+	for k, v := range overrides {
+		set[strings.ToLower(k)] = v
+	}
+	return set
+}
+
+// emojiSupported reports whether the terminal is likely to render emoji:
+// dumb terminals and explicitly non-UTF-8 locales are not.
+func emojiSupported(getenv func(string) string) bool {
+	if getenv("TERM") == "dumb" {
+		return false
+	}
+	for _, key := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if v := getenv(key); v != "" {
+			v = strings.ToLower(v)
+			return strings.Contains(v, "utf-8") || strings.Contains(v, "utf8")
+		}
+	}
+	return true
+}
@@ -0,0 +1,55 @@
+package main
+
+import "testing"
+
+func TestUnitLoadIcons(t *testing.T) {
+	testCases := []struct {
+		name      string
+		emoji     bool
+		overrides map[string]string
+		eventType string
+		want      string
+	}{
+		{name: "emoji default", emoji: true, eventType: "PushEvent", want: "🛠"},
+		{name: "ascii fallback", emoji: false, eventType: "WatchEvent", want: "[star]"},
+		{
+			name: "override from config", emoji: true,
+			overrides: map[string]string{"pushevent": "🚢"}, eventType: "PushEvent", want: "🚢",
+		},
+		{
+			name: "override ignored without emoji", emoji: false,
+			overrides: map[string]string{"PushEvent": "🚢"}, eventType: "PushEvent", want: "[push]",
+		},
+		{name: "unknown type", emoji: true, eventType: "GollumEvent", want: defaultIcon},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			got := loadIcons(tc.emoji, tc.overrides).icon(tc.eventType)
+			// Assert
+			assertEqual(t, got, tc.want)
+		})
+	}
+}
+
+func TestUnitEmojiSupported(t *testing.T) {
+	testCases := []struct {
+		name string
+		env  map[string]string
+		want bool
+	}{
+		{name: "utf-8 locale", env: map[string]string{"LANG": "en_US.UTF-8"}, want: true},
+		{name: "posix locale", env: map[string]string{"LANG": "C"}, want: false},
+		{name: "lc_all wins", env: map[string]string{"LC_ALL": "C", "LANG": "en_US.UTF-8"}, want: false},
+		{name: "dumb terminal", env: map[string]string{"TERM": "dumb", "LANG": "en_US.UTF-8"}, want: false},
+		{name: "nothing set", env: map[string]string{}, want: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			got := emojiSupported(func(k string) string { return tc.env[k] })
+			// Assert
+			assertEqual(t, got, tc.want)
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// person is an entry of the people setting, grouping the accounts and
+// commit emails of one developer, e.g. a work and a personal account.
+type person struct {
+	Name   string   `mapstructure:"name"`
+	Logins []string `mapstructure:"logins"`
+	Emails []string `mapstructure:"emails"`
+}
+
+// lookupPerson returns the person of the people setting with this name,
+// ignoring case.
+func lookupPerson(name string) (person, bool, error) {
+	var people []person
+	if err := viper.UnmarshalKey("people", &people); err != nil {
+		return person{}, false, fmt.Errorf("parse people: %w", err)
+	}
+	for _, p := range people {
+		if strings.EqualFold(p.Name, name) && len(p.Logins) > 0 {
+			return p, true, nil
+		}
+	}
+	return person{}, false, nil
+}
+
+// identities returns the logins of a user: those of the person of that
+// name, or the user alone.
+func identities(user string) ([]string, error) {
+	p, ok, err := lookupPerson(user)
+	if err != nil || !ok {
+		return []string{user}, err
+	}
+	return p.Logins, nil
+}
+
+// ownEmails returns the lowercased commit emails of the person of that
+// name, nil for a plain user.
+func ownEmails(user string) (map[string]bool, error) {
+	p, ok, err := lookupPerson(user)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return lowerSet(p.Emails), nil
+}
+
+// mergeEvents merges the events of several accounts, newest first, keeping
+// one copy of the events seen by more than one.
+func mergeEvents(lists ...[]ghEvent) []ghEvent {
+	if len(lists) == 1 {
+		return lists[0]
+	}
+	var merged []ghEvent
+	seen := map[string]bool{}
+	for _, events := range lists {
+		for _, ev := range events {
+			if seen[ev.ID] {
+				continue
+			}
+			seen[ev.ID] = true
+			merged = append(merged, ev)
+		}
+	}
+	sort.SliceStable(merged, func(i, j int) bool { return merged[i].CreatedAt.After(merged[j].CreatedAt) })
+	return merged
+}
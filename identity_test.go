@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func setPeople(t *testing.T) {
+	t.Helper()
+	viper.Set("people", []map[string]any{
+		{"name": "Mona", "logins": []string{"mona", "mona-work"}, "emails": []string{"Mona@acme.com", "mona@gmail.com"}},
+		{"name": "nobody"},
+	})
+	t.Cleanup(viper.Reset)
+}
+
+func TestUnitIdentities(t *testing.T) {
+	testCases := []struct {
+		name string
+		user string
+		want []string
+	}{
+		{name: "person", user: "mona", want: []string{"mona", "mona-work"}},
+		{name: "plain user", user: "octocat", want: []string{"octocat"}},
+		{name: "person without logins", user: "nobody", want: []string{"nobody"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			setPeople(t)
+			// Act
+			got, err := identities(tc.user)
+			// Assert
+			assertNoError(t, err)
+			assertEqual(t, len(got), len(tc.want))
+			for i := range tc.want {
+				assertEqual(t, got[i], tc.want[i])
+			}
+		})
+	}
+}
+
+func TestUnitOwnEmails(t *testing.T) {
+	// Arrange
+	setPeople(t)
+	// Act
+	own, err := ownEmails("Mona")
+	none, errNone := ownEmails("octocat")
+	// Assert
+	assertNoError(t, err)
+	assertNoError(t, errNone)
+	assertEqual(t, own["mona@acme.com"], true)
+	assertEqual(t, own["mona@gmail.com"], true)
+	assertEqual(t, len(none), 0)
+}
+
+func TestUnitMergeEvents(t *testing.T) {
+	// Arrange
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	personal := []ghEvent{{ID: "3", CreatedAt: now.Add(3 * time.Hour)}, {ID: "1", CreatedAt: now}}
+	work := []ghEvent{{ID: "2", CreatedAt: now.Add(2 * time.Hour)}, {ID: "1", CreatedAt: now}}
+	// Act
+	got := mergeEvents(personal, work)
+	// Assert
+	assertEqual(t, len(got), 3)
+	assertEqual(t, got[0].ID, "3")
+	assertEqual(t, got[1].ID, "2")
+	assertEqual(t, got[2].ID, "1")
+}
+
+func TestUnitCollaboratorsViewOwnEmails(t *testing.T) {
+	// Arrange
+	setPeople(t)
+	events := []ghEvent{{Type: "PushEvent", Payload: payload{Commits: []commit{
+		{Message: "Fix\n\nCo-authored-by: Mona <mona@acme.com>"},
+		{Message: "Add\n\nCo-authored-by: Hubot <hubot@github.com>"},
+	}}}}
+	var buf bytes.Buffer
+	// Act
+	err := collaboratorsView(&buf, events, statsOptions{user: "mona"})
+	// Assert
+	assertNoError(t, err)
+	assertEqual(t, buf.String(), "paired commits: 1/2 (50.0%)\n"+
+		"Hubot                              1\n")
+}
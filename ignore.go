@@ -0,0 +1,162 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// ignoreRules holds the patterns of the ignore files. Repo, type and actor
+// patterns are case-insensitive globs; message patterns match commit
+// messages containing them, case-insensitively.
+type ignoreRules struct {
+	repos    []string
+	types    []string
+	actors   []string
+	messages []string
+}
+
+// ignoreFileName is the name of the ignore file, read from the application
+// directory and from the working directory, so that a team can share one
+// in a repository.
+const ignoreFileName = ".ghactivityignore"
+
+// parseIgnore reads an ignore file: one "kind:pattern" per line, where kind
+// is repo, type, actor or message, with blank lines and "#" comments.
+func parseIgnore(r io.Reader) (ignoreRules, error) {
+	var rules ignoreRules
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		kind, pattern, ok := strings.Cut(line, ":")
+		pattern = strings.TrimSpace(pattern)
+		if !ok || pattern == "" {
+			return ignoreRules{}, fmt.Errorf("line %d: expected kind:pattern, got %q", n, line)
+		}
+		switch kind {
+		case "repo":
+			rules.repos = append(rules.repos, pattern)
+		case "type":
+			rules.types = append(rules.types, pattern)
+		case "actor":
+			rules.actors = append(rules.actors, pattern)
+		case "message":
+			rules.messages = append(rules.messages, strings.ToLower(pattern))
+			continue
+		default:
+			return ignoreRules{}, fmt.Errorf("line %d: unknown kind %q: use repo, type, actor or message", n, kind)
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return ignoreRules{}, fmt.Errorf("line %d: invalid pattern %q: %w", n, pattern, err)
+		}
+	}
+	return rules, sc.Err()
+}
+
+// loadIgnoreRules merges the ignore file of the application directory with
+// the one set by ignore_file, or else the one of the working directory.
+// Missing files are skipped.
+func loadIgnoreRules() (ignoreRules, error) {
+	var paths []string
+	if dir, err := appDir(&defaultUserHome{}); err == nil {
+		paths = append(paths, filepath.Join(dir, ignoreFileName))
+	}
+	paths = append(paths, orDefault(viper.GetString("ignore_file"), ignoreFileName))
+	var all ignoreRules
+	for _, name := range paths {
+		f, err := os.Open(name)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return ignoreRules{}, fmt.Errorf("open ignore file: %w", err)
+		}
+		rules, err := parseIgnore(f)
+		f.Close()
+		if err != nil {
+			return ignoreRules{}, fmt.Errorf("parse %s: %w", name, err)
+		}
+		all.repos = append(all.repos, rules.repos...)
+		all.types = append(all.types, rules.types...)
+		all.actors = append(all.actors, rules.actors...)
+		all.messages = append(all.messages, rules.messages...)
+	}
+	return all, nil
+}
+
+// apply drops the events of ignored repositories, types and actors, and
+// the commits with an ignored message. Pushes left without commits are
+// dropped too.
+func (r ignoreRules) apply(events []ghEvent) []ghEvent {
+	var kept []ghEvent
+	for _, ev := range events {
+		if anyGlob(r.repos, ev.Repo.Name) || anyGlob(r.types, ev.Type) || anyGlob(r.actors, ev.Actor.Login) {
+			continue
+		}
+		if len(r.messages) > 0 && len(ev.Payload.Commits) > 0 {
+			var commits []commit
+			removed, distinct := 0, 0
+			for _, c := range ev.Payload.Commits {
+				if !r.ignoresMessage(c.Message) {
+					commits = append(commits, c)
+					continue
+				}
+				removed++
+				if c.Distinct {
+					distinct++
+				}
+			}
+			p := &ev.Payload
+			p.Size, p.DistinctSize = max(p.Size-removed, 0), max(p.DistinctSize-distinct, 0)
+			if len(commits) == 0 && p.Size == 0 {
+				continue
+			}
+			p.Commits = commits
+		}
+		kept = append(kept, ev)
+	}
+	return kept
+}
+
+// ignoresMessage reports whether a commit message contains an ignored
+// pattern.
+func (r ignoreRules) ignoresMessage(msg string) bool {
+	msg = strings.ToLower(msg)
+	for _, m := range r.messages {
+		if strings.Contains(msg, m) {
+			return true
+		}
+	}
+	return false
+}
+
+// anyGlob reports whether s equals or matches one of the patterns, so that
+// "dependabot[bot]" ignores that actor despite the brackets.
+func anyGlob(patterns []string, s string) bool {
+	for _, p := range patterns {
+		if strings.EqualFold(p, s) || globMatch(p, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// ignoreEvents applies the ignore files to the events.
+func ignoreEvents(events []ghEvent) ([]ghEvent, error) {
+	rules, err := loadIgnoreRules()
+	if err != nil {
+		return nil, err
+	}
+	return rules.apply(events), nil
+}
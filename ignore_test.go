@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestUnitParseIgnore(t *testing.T) {
+	testCases := []struct {
+		name    string
+		file    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "every kind",
+			file: "# bots\nactor: dependabot[bot]\n\nrepo:octo/sandbox-*\ntype:WatchEvent\nmessage:Chore(deps):\n",
+			want: "{[octo/sandbox-*] [WatchEvent] [dependabot[bot]] [chore(deps):]}",
+		},
+		{name: "unknown kind", file: "branch:main\n", wantErr: true},
+		{name: "missing pattern", file: "repo:\n", wantErr: true},
+		{name: "no kind", file: "octo/api\n", wantErr: true},
+		{name: "invalid pattern", file: "repo:octo/[\n", wantErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			got, err := parseIgnore(strings.NewReader(tc.file))
+			// Assert
+			if tc.wantErr {
+				assertNotNil(t, err)
+				return
+			}
+			assertNoError(t, err)
+			assertEqual(t, fmt.Sprint(got), tc.want)
+		})
+	}
+}
+
+func TestUnitIgnoreRulesApply(t *testing.T) {
+	// Arrange
+	rules := ignoreRules{
+		repos:    []string{"octo/sandbox-*"},
+		types:    []string{"watchevent"},
+		actors:   []string{"dependabot[bot]"},
+		messages: []string{"chore(deps):"},
+	}
+	push := func(id string, size int, messages ...string) ghEvent {
+		ev := ghEvent{ID: id, Type: "PushEvent", Repo: repo{Name: "octo/api"}, Payload: payload{Size: size}}
+		for _, m := range messages {
+			ev.Payload.Commits = append(ev.Payload.Commits, commit{Message: m, Distinct: true})
+		}
+		ev.Payload.DistinctSize = len(messages)
+		return ev
+	}
+	events := []ghEvent{
+		{ID: "sandbox", Type: "IssuesEvent", Repo: repo{Name: "Octo/Sandbox-1"}},
+		{ID: "star", Type: "WatchEvent", Repo: repo{Name: "octo/api"}},
+		{ID: "bot", Type: "PullRequestEvent", Repo: repo{Name: "octo/api"}, Actor: actor{Login: "dependabot[bot]"}},
+		push("deps", 1, "chore(deps): bump x"),
+		push("mixed", 2, "fix: crash", "CHORE(deps): bump y"),
+		push("truncated", 30, "chore(deps): bump z"),
+		{ID: "issue", Type: "IssuesEvent", Repo: repo{Name: "octo/api"}},
+	}
+	// Act
+	got := rules.apply(events)
+	// Assert
+	var ids []string
+	for _, ev := range got {
+		ids = append(ids, ev.ID)
+	}
+	assertEqual(t, fmt.Sprint(ids), "[mixed truncated issue]")
+	assertEqual(t, len(got[0].Payload.Commits), 1)
+	assertEqual(t, got[0].Payload.Size, 1)
+	assertEqual(t, got[0].Payload.DistinctSize, 1)
+	assertEqual(t, got[1].Payload.Size, 29)
+	assertEqual(t, len(events[4].Payload.Commits), 2)
+}
+
+func TestUnitLoadIgnoreRules(t *testing.T) {
+	// Arrange
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	dir := filepath.Join(home, ".go-github-activity")
+	assertNoError(t, os.MkdirAll(dir, 0o700))
+	assertNoError(t, os.WriteFile(filepath.Join(dir, ignoreFileName), []byte("type:WatchEvent\n"), 0o600))
+	team := filepath.Join(t.TempDir(), "team-ignore")
+	assertNoError(t, os.WriteFile(team, []byte("repo:octo/sandbox\n"), 0o600))
+	viper.Set("ignore_file", team)
+	t.Cleanup(viper.Reset)
+	// Act
+	got, err := loadIgnoreRules()
+	// Assert
+	assertNoError(t, err)
+	assertEqual(t, fmt.Sprint(got.types, got.repos), "[WatchEvent] [octo/sandbox]")
+}
+
+// FuzzParseIgnore checks that no ignore file makes the parser or the rules
+// panic.
+func FuzzParseIgnore(f *testing.F) {
+	f.Add("# bots\nactor:dependabot[bot]\nrepo:octo/*\ntype:WatchEvent\nmessage:wip\n")
+	f.Add("repo:[\n")
+	f.Add("message:\n")
+	f.Fuzz(func(t *testing.T, content string) {
+		rules, err := parseIgnore(strings.NewReader(content))
+		if err != nil {
+			return
+		}
+		rules.apply([]ghEvent{{
+			Type: "PushEvent", Actor: actor{Login: "dependabot[bot]"}, Repo: repo{Name: "octo/api"},
+			Payload: payload{Size: 1, Commits: []commit{{Message: "wip", Distinct: true}}},
+		}})
+	})
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+type (
+	// jsonRenderer writes events as an indented JSON array, with enriched
+	// repository and actor data when present.
+	jsonRenderer struct {
+		cat     catalog
+		tickets *ticketMatcher
+	}
+	// jsonEvent is an event with its human-readable summary, labels and
+	// referenced tickets.
+	jsonEvent struct {
+		ghEvent
+		Summary string   `json:"summary"`
+		Labels  []string `json:"labels,omitempty"`
+		Tickets []ticket `json:"tickets,omitempty"`
+	}
+)
+
+// render encodes the events, always as an array.
+func (r jsonRenderer) render(w io.Writer, events []ghEvent) error {
+	events = redactedCopy(events)
+	out := make([]jsonEvent, 0, len(events))
+	for _, ev := range events {
+		je := jsonEvent{ghEvent: ev, Summary: summarize(r.cat, ev), Labels: repoLabels(ev.Repo.Meta)}
+		if r.tickets != nil {
+			je.Tickets = r.tickets.tickets(ev)
+		}
+		out = append(out, je)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(out); err != nil {
+		return fmt.Errorf("encode events: %w", err)
+	}
+	return nil
+}
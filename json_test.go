@@ -0,0 +1,45 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestUnitJSONRenderer(t *testing.T) {
+	testCases := []struct {
+		name       string
+		events     []ghEvent
+		wantLen    int
+		wantAvatar string
+	}{
+		{name: "no events", events: nil, wantLen: 0},
+		{
+			name: "enriched actor",
+			events: []ghEvent{{
+				Type:  "WatchEvent",
+				Repo:  repo{Name: "octo/repo"},
+				Actor: actor{Login: "octocat", Profile: &userProfile{AvatarURL: "https://a/1"}},
+			}},
+			wantLen:    1,
+			wantAvatar: "https://a/1",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			var buf bytes.Buffer
+			// Act
+			err := jsonRenderer{cat: lookupCatalog("en")}.render(&buf, tc.events)
+			// Assert
+			assertNoError(t, err)
+			var got []jsonEvent
+			assertNoError(t, json.Unmarshal(buf.Bytes(), &got))
+			assertEqual(t, len(got), tc.wantLen)
+			if tc.wantLen > 0 {
+				assertEqual(t, got[0].Summary, "Starred octo/repo")
+				assertEqual(t, got[0].Actor.Profile.AvatarURL, tc.wantAvatar)
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+)
+
+type (
+	// label is a label of an issue or pull request.
+	label struct {
+		Name string `json:"name"`
+	}
+	// milestone is the milestone of an issue or pull request, with its
+	// progress.
+	milestone struct {
+		Title        string     `json:"title"`
+		State        string     `json:"state"`
+		OpenIssues   int        `json:"open_issues"`
+		ClosedIssues int        `json:"closed_issues"`
+		DueOn        *time.Time `json:"due_on,omitempty"`
+	}
+	// issueLabels are the labels and milestone of an issue or pull request
+	// according to the Issues API.
+	issueLabels struct {
+		Labels    []label    `json:"labels"`
+		Milestone *milestone `json:"milestone"`
+	}
+	// milestoneProgress is the latest progress of a milestone and the
+	// number of events on its issues and pull requests.
+	milestoneProgress struct {
+		repo         string
+		milestone    milestone
+		interactions int
+	}
+)
+
+// issueLabels returns the labels and milestone of an issue or pull
+// request.
+func (f *repoFetcher) issueLabels(name string, number int) (issueLabels, error) {
+	return cached(f.cache, "labels/"+name+"/"+strconv.Itoa(number), func() (issueLabels, error) {
+		u, err := f.repoURL(name, "issues", strconv.Itoa(number))
+		if err != nil {
+			return issueLabels{}, err
+		}
+		var is issueLabels
+		if err := fetchJSON(f.hc, u, &is); err != nil {
+			return issueLabels{}, fmt.Errorf("fetch labels of %s#%d: %w", name, number, err)
+		}
+		return is, nil
+	})
+}
+
+// issueRefOf returns the issue or pull request an event is about.
+func issueRefOf(ev ghEvent) (issueRef, bool) {
+	switch {
+	case ev.Payload.Issue != nil && ev.Payload.Issue.Number > 0:
+		return issueRef{repo: ev.Repo.Name, number: ev.Payload.Issue.Number}, true
+	case ev.Payload.PullRequest != nil && ev.Payload.PullRequest.Number > 0:
+		return issueRef{repo: ev.Repo.Name, number: ev.Payload.PullRequest.Number}, true
+	}
+	return issueRef{}, false
+}
+
+// enrichLabels attaches their labels and milestone to the events about an
+// issue or pull request, fetching each one once. Deleted ones are left
+// without.
+func enrichLabels(events []ghEvent, labelsOf func(repo string, number int) (issueLabels, error)) error {
+	seen := map[issueRef]issueLabels{}
+	for i := range events {
+		ref, ok := issueRefOf(events[i])
+		if !ok {
+			continue
+		}
+		is, ok := seen[ref]
+		if !ok {
+			var err error
+			is, err = labelsOf(ref.repo, ref.number)
+			switch {
+			case isStatus(err, http.StatusNotFound) || isStatus(err, http.StatusGone):
+				is = issueLabels{}
+			case err != nil:
+				return err
+			}
+			seen[ref] = is
+		}
+		events[i].Payload.Labels, events[i].Payload.Milestone = is.Labels, is.Milestone
+	}
+	return nil
+}
+
+// labelCounts counts the events per label of their issue or pull request,
+// by decreasing count then name.
+func labelCounts(events []ghEvent) []namedCount {
+	counts := map[string]int{}
+	for _, ev := range events {
+		for _, l := range ev.Payload.Labels {
+			counts[l.Name]++
+		}
+	}
+	return rankCounts(counts)
+}
+
+// milestonesProgress returns the milestones of the events, by repository
+// then title, with the progress of the newest event.
+func milestonesProgress(events []ghEvent) []milestoneProgress {
+	byKey := map[string]*milestoneProgress{}
+	for _, ev := range events {
+		m := ev.Payload.Milestone
+		if m == nil {
+			continue
+		}
+		key := ev.Repo.Name + "\x00" + m.Title
+		p, ok := byKey[key]
+		if !ok {
+			p = &milestoneProgress{repo: ev.Repo.Name, milestone: *m}
+			byKey[key] = p
+		}
+		p.interactions++
+	}
+	out := make([]milestoneProgress, 0, len(byKey))
+	for _, p := range byKey {
+		out = append(out, *p)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].repo != out[j].repo {
+			return out[i].repo < out[j].repo
+		}
+		return out[i].milestone.Title < out[j].milestone.Title
+	})
+	return out
+}
+
+// labelsView prints the number of interactions per label.
+func labelsView(w io.Writer, events []ghEvent, _ statsOptions) error {
+	fetcher, err := newRepoFetcher()
+	if err != nil {
+		return err
+	}
+	if err := enrichLabels(events, fetcher.issueLabels); err != nil {
+		return err
+	}
+	return writeLabels(w, labelCounts(events))
+}
+
+// writeLabels prints one line per label, e.g. "bug: 12 interactions".
+func writeLabels(w io.Writer, counts []namedCount) error {
+	if len(counts) == 0 {
+		_, err := fmt.Fprintln(w, "no labelled issues or pull requests")
+		return err
+	}
+	for _, c := range counts {
+		if _, err := fmt.Fprintf(w, "%s: %d interactions\n", c.name, c.count); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// milestonesView prints the progress of the milestones worked on.
+func milestonesView(w io.Writer, events []ghEvent, _ statsOptions) error {
+	fetcher, err := newRepoFetcher()
+	if err != nil {
+		return err
+	}
+	if err := enrichLabels(events, fetcher.issueLabels); err != nil {
+		return err
+	}
+	return writeMilestones(w, milestonesProgress(events))
+}
+
+// writeMilestones prints one line per milestone, e.g. "octo/api v1.2:
+// 8/10 closed (80%), 5 interactions, due 2025-04-01".
+func writeMilestones(w io.Writer, progress []milestoneProgress) error {
+	if len(progress) == 0 {
+		_, err := fmt.Fprintln(w, "no issues or pull requests in a milestone")
+		return err
+	}
+	for _, p := range progress {
+		m := p.milestone
+		total := m.OpenIssues + m.ClosedIssues
+		share := 0.0
+		if total > 0 {
+			share = float64(m.ClosedIssues) * 100 / float64(total)
+		}
+		line := fmt.Sprintf("%s %s: %d/%d closed (%.0f%%), %d interactions",
+			p.repo, m.Title, m.ClosedIssues, total, share, p.interactions)
+		if m.DueOn != nil {
+			line += ", due " + m.DueOn.Format(time.DateOnly)
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUnitEnrichLabels(t *testing.T) {
+	due := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	v12 := &milestone{Title: "v1.2", OpenIssues: 2, ClosedIssues: 8, DueOn: &due}
+	testCases := []struct {
+		name      string
+		err       error
+		wantCalls int
+		wantLabel string
+		wantErr   bool
+	}{
+		{name: "fetched once per issue", wantCalls: 2, wantLabel: "bug"},
+		{name: "deleted issue", err: &apiError{StatusCode: http.StatusNotFound}, wantCalls: 2},
+		{name: "failure", err: errors.New("boom"), wantErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			events := []ghEvent{
+				{Type: "IssueCommentEvent", Repo: repo{Name: "octo/api"}, Payload: payload{Issue: &issue{Number: 3}}},
+				{Type: "IssuesEvent", Repo: repo{Name: "octo/api"}, Payload: payload{Issue: &issue{Number: 3}}},
+				{Type: "PullRequestEvent", Repo: repo{Name: "octo/api"}, Payload: payload{PullRequest: &pullRequest{Number: 4}}},
+				{Type: "WatchEvent", Repo: repo{Name: "octo/api"}},
+			}
+			calls := 0
+			labelsOf := func(_ string, number int) (issueLabels, error) {
+				calls++
+				return issueLabels{Labels: []label{{Name: "bug"}}, Milestone: v12}, tc.err
+			}
+			// Act
+			err := enrichLabels(events, labelsOf)
+			// Assert
+			if tc.wantErr {
+				assertNotNil(t, err)
+				return
+			}
+			assertNoError(t, err)
+			assertEqual(t, calls, tc.wantCalls)
+			if tc.wantLabel == "" {
+				assertEqual(t, len(events[0].Payload.Labels), 0)
+				return
+			}
+			assertEqual(t, events[1].Payload.Labels[0].Name, tc.wantLabel)
+			assertEqual(t, events[2].Payload.Milestone.Title, "v1.2")
+			assertEqual(t, len(events[3].Payload.Labels), 0)
+		})
+	}
+}
+
+func TestUnitRepoFetcherIssueLabels(t *testing.T) {
+	// Arrange
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assertEqual(t, r.URL.Path, "/repos/octo/api/issues/3")
+		w.Write([]byte(`{"number":3,"labels":[{"name":"bug"},{"name":"ui"}],
+			"milestone":{"title":"v1.2","state":"open","open_issues":2,"closed_issues":8}}`))
+	}))
+	t.Cleanup(srv.Close)
+	f := &repoFetcher{hc: newClient(""), base: srv.URL}
+	// Act
+	got, err := f.issueLabels("octo/api", 3)
+	// Assert
+	assertNoError(t, err)
+	assertEqual(t, len(got.Labels), 2)
+	assertEqual(t, got.Labels[1].Name, "ui")
+	assertEqual(t, *got.Milestone, milestone{Title: "v1.2", State: "open", OpenIssues: 2, ClosedIssues: 8})
+}
+
+func TestUnitWriteLabels(t *testing.T) {
+	// Arrange
+	events := []ghEvent{
+		{Payload: payload{Labels: []label{{Name: "bug"}, {Name: "ui"}}}},
+		{Payload: payload{Labels: []label{{Name: "bug"}}}},
+		{Payload: payload{Labels: []label{{Name: "feature"}}}},
+		{},
+	}
+	var b strings.Builder
+	// Act
+	err := writeLabels(&b, labelCounts(events))
+	// Assert
+	assertNoError(t, err)
+	assertEqual(t, b.String(), "bug: 2 interactions\nfeature: 1 interactions\nui: 1 interactions\n")
+}
+
+func TestUnitWriteMilestones(t *testing.T) {
+	// Arrange
+	due := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	events := []ghEvent{
+		{Repo: repo{Name: "octo/web"}, Payload: payload{Milestone: &milestone{Title: "beta"}}},
+		{Repo: repo{Name: "octo/api"}, Payload: payload{Milestone: &milestone{
+			Title: "v1.2", OpenIssues: 2, ClosedIssues: 8, DueOn: &due,
+		}}},
+		{Repo: repo{Name: "octo/api"}, Payload: payload{Milestone: &milestone{
+			Title: "v1.2", OpenIssues: 3, ClosedIssues: 7,
+		}}},
+		{Repo: repo{Name: "octo/api"}},
+	}
+	var b strings.Builder
+	// Act
+	err := writeMilestones(&b, milestonesProgress(events))
+	// Assert
+	assertNoError(t, err)
+	assertEqual(t, b.String(), "octo/api v1.2: 8/10 closed (80%), 2 interactions, due 2025-04-01\n"+
+		"octo/web beta: 0/0 closed (0%), 1 interactions\n")
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type (
+	// localProvider scans local git repositories for the commits of a
+	// user on any branch, including those never pushed.
+	localProvider struct {
+		git    gitOutput
+		paths  []string
+		emails []string
+		days   int
+	}
+)
+
+// defaultLocalDays is the window of local scans, the retention of the
+// GitHub events API.
+const defaultLocalDays = 90
+
+// localLogFormat prints one commit per record: its SHA, author date, author
+// name, author email and message, separated by unit separators.
+const localLogFormat = "--format=%H%x1f%aI%x1f%an%x1f%ae%x1f%B%x1e"
+
+func newLocalProvider(git gitOutput, paths, emails []string, days int) *localProvider {
+	if days <= 0 {
+		days = defaultLocalDays
+	}
+	return &localProvider{git: git, paths: paths, emails: emails, days: days}
+}
+
+func (p *localProvider) name() string { return "local" }
+
+// events returns one push event per commit authored with the emails of the
+// source or of the user's person. Without any, the user.email of each
+// repository is used.
+func (p *localProvider) events(login string) ([]ghEvent, error) {
+	emails, err := ownEmails(login)
+	if err != nil {
+		return nil, err
+	}
+	if emails == nil {
+		emails = map[string]bool{}
+	}
+	for e := range lowerSet(p.emails) {
+		emails[e] = true
+	}
+	var events []ghEvent
+	for _, path := range p.paths {
+		path, err := expandHome(path)
+		if err != nil {
+			return nil, err
+		}
+		scanned, err := p.scan(path, login, emails)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, scanned...)
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].CreatedAt.After(events[j].CreatedAt) })
+	return events, nil
+}
+
+// scan returns the commits of a repository authored with one of the emails.
+func (p *localProvider) scan(path, login string, emails map[string]bool) ([]ghEvent, error) {
+	if len(emails) == 0 {
+		email, err := p.git(path, "config", "user.email")
+		if err != nil {
+			return nil, fmt.Errorf("scan %s: %w", path, err)
+		}
+		emails = map[string]bool{strings.ToLower(email): true}
+	}
+	name := filepath.Base(filepath.Clean(path))
+	if remote, err := p.git(path, "remote", "get-url", "origin"); err == nil {
+		if sub := githubRemote.FindStringSubmatch(remote); sub != nil {
+			name = sub[1]
+		}
+	}
+	out, err := p.git(path, "log", "--all", "--no-merges", "--since="+strconv.Itoa(p.days)+".days", localLogFormat)
+	if err != nil {
+		return nil, fmt.Errorf("scan %s: %w", path, err)
+	}
+	var events []ghEvent
+	for _, record := range strings.Split(out, "\x1e") {
+		fields := strings.SplitN(strings.TrimSpace(record), "\x1f", 5)
+		if len(fields) != 5 || !emails[strings.ToLower(fields[3])] {
+			continue
+		}
+		created, err := time.Parse(time.RFC3339, fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("scan %s: parse date of %s: %w", path, fields[0], err)
+		}
+		events = append(events, ghEvent{
+			ID: "commit-" + fields[0], Type: "PushEvent", Actor: actor{Login: login}, Repo: repo{Name: name},
+			Payload: payload{Size: 1, Commits: []commit{{
+				SHA: fields[0], Message: strings.TrimSpace(fields[4]), Distinct: true,
+				Author: author{Name: fields[2], Email: fields[3]},
+			}}},
+			CreatedAt: created.UTC(),
+			Local:     true,
+		})
+	}
+	return events, nil
+}
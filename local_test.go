@@ -0,0 +1,85 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func TestUnitLocalProvider(t *testing.T) {
+	record := func(sha, date, email, message string) string {
+		return sha + "\x1f" + date + "\x1f" + "Octo Cat\x1f" + email + "\x1f" + message + "\n\x1e"
+	}
+	log := "--all --no-merges --since=30.days " + localLogFormat
+	git := fakeGit(map[string]string{
+		"/src/api remote get-url origin": "git@github.com:octo/api.git",
+		"/src/api log " + log: record("a1", "2025-03-01T10:00:00+01:00", "me@work.example", "Fix login\n\nDetails") +
+			record("b2", "2025-03-02T09:00:00Z", "someone@else.example", "Not mine") +
+			record("c3", "2025-02-28T09:00:00Z", "ME@personal.example", "Draft"),
+		"/src/notes config user.email": "me@personal.example",
+		"/src/notes log " + log:        record("d4", "2025-03-03T09:00:00Z", "me@personal.example", "Notes"),
+	})
+	testCases := []struct {
+		name    string
+		paths   []string
+		emails  []string
+		wantIDs []string
+		wantErr bool
+	}{
+		{
+			name: "configured emails", paths: []string{"/src/api"},
+			emails: []string{"me@work.example", "me@personal.example"}, wantIDs: []string{"commit-a1", "commit-c3"},
+		},
+		{name: "repository email", paths: []string{"/src/notes"}, wantIDs: []string{"commit-d4"}},
+		{name: "home path", paths: []string{"~/notes"}, wantIDs: []string{"commit-d4"}},
+		{
+			name: "several repositories", paths: []string{"/src/api", "/src/notes"},
+			emails: []string{"me@work.example", "me@personal.example"}, wantIDs: []string{"commit-d4", "commit-a1", "commit-c3"},
+		},
+		{name: "not a repository", paths: []string{"/src/missing"}, emails: []string{"me@work.example"}, wantErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			t.Setenv("HOME", "/src")
+			t.Cleanup(viper.Reset)
+			p := newLocalProvider(git, tc.paths, tc.emails, 30)
+			// Act
+			got, err := p.events("octocat")
+			// Assert
+			if tc.wantErr {
+				assertNotNil(t, err)
+				return
+			}
+			assertNoError(t, err)
+			assertEqual(t, len(got), len(tc.wantIDs))
+			for i, id := range tc.wantIDs {
+				assertEqual(t, got[i].ID, id)
+				assertEqual(t, got[i].Local, true)
+			}
+		})
+	}
+}
+
+func TestUnitLocalProviderEvent(t *testing.T) {
+	// Arrange
+	git := fakeGit(map[string]string{
+		"/src/api remote get-url origin": "https://github.com/octo/api",
+		"/src/api log --all --no-merges --since=90.days " + localLogFormat: "a1\x1f2025-03-01T10:00:00+01:00\x1f" +
+			"Octo Cat\x1fme@work.example\x1fFix login\n\nDetails\n\x1e",
+	})
+	p := newLocalProvider(git, []string{"/src/api"}, []string{"me@work.example"}, 0)
+	// Act
+	events, err := p.events("octocat")
+	// Assert
+	assertNoError(t, err)
+	assertEqual(t, len(events), 1)
+	ev := events[0]
+	assertEqual(t, ev.Type, "PushEvent")
+	assertEqual(t, ev.Repo.Name, "octo/api")
+	assertEqual(t, ev.Actor.Login, "octocat")
+	assertEqual(t, ev.CreatedAt, time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC))
+	assertEqual(t, ev.Payload.Commits[0].Message, "Fix login\n\nDetails")
+	assertEqual(t, ev.Payload.Commits[0].Author, author{Name: "Octo Cat", Email: "me@work.example"})
+}
@@ -0,0 +1,299 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// configLoaded is set while serve runs, once the configuration is loaded:
+// loadConfig then leaves it untouched, so that the scheduled jobs calling it
+// only read it, concurrently with the requests.
+var configLoaded atomic.Bool
+
+// defaultAPIURL is the GitHub REST API root, overridable with api_url.
+const defaultAPIURL = "https://api.github.com"
+
+// version is set at build time by goreleaser.
+var version = "dev"
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		reportError(os.Stderr, err, jsonOutput(os.Args[1:]))
+		os.Exit(exitCode(err))
+	}
+}
+
+// command runs a subcommand with its own arguments.
+type command func(args []string, stdout io.Writer) error
+
+// commands lists the subcommands; anything else is a username for the
+// default activity listing.
+var commands = map[string]command{
+	"readme-section": runReadmeSection,
+	"action":         runAction,
+	"badge":          runBadge,
+	"stats":          runStats,
+	"sync":           runSync,
+	"digest":         runDigest,
+	"watch":          runWatch,
+	"heatmap":        runHeatmap,
+	"service":        runService,
+	"serve":          runServe,
+	"mcp":            runMCP,
+	"standup":        runStandup,
+	"timesheet":      runTimesheet,
+	"report":         runReport,
+	"notes":          runNotes,
+	"correlate":      runCorrelate,
+	"backfill":       runBackfill,
+	"audit":          runAudit,
+	"ratelimit":      runRateLimit,
+	"auth":           runAuth,
+	"archive":        runArchive,
+	"security":       runSecurity,
+	"traffic":        runTraffic,
+	"org":            runOrg,
+	"topic":          runTopic,
+	"following":      runFollowing,
+	"releases":       runReleases,
+	"advisories":     runAdvisories,
+	"config":         runConfig,
+	"version":        runVersion,
+	"usage":          runUsage,
+	"diff":           runDiff,
+	"fixtures":       runFixtures,
+}
+
+// run dispatches the command line to a subcommand or the activity listing,
+// after reading the global flags.
+func run(args []string, stdout io.Writer) error {
+	var globals globalOptions
+	globals, args = parseGlobalFlags(args, os.Getenv)
+	activeProfile, quiet, noInput, redact = globals.profile, globals.quiet, globals.noInput, globals.redact
+	if len(args) > 0 {
+		if cmd, ok := commands[args[0]]; ok {
+			return cmd(args[1:], stdout)
+		}
+	}
+	return runActivity(args, stdout)
+}
+
+// runActivity fetches the user's events and renders them.
+func runActivity(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("go-github-activity", flag.ContinueOnError)
+	lang := flags.String("lang", "", "output language (en, fr, es, ja), defaults to the lang setting, then $LANG")
+	noEmoji := flags.Bool("no-emoji", false, "use ASCII markers instead of emoji icons")
+	output := flags.String("output", "", "output format: text, table, json or html, defaults to text")
+	noTruncate := flags.Bool("no-truncate", false, "do not truncate table cells to the terminal width")
+	enrich := flags.Bool("enrich", false, "resolve repository metadata and actor profiles")
+	verify := flags.Bool("verify", false, "check the signature status of pushed commits")
+	checks := flags.Bool("checks", false, "mark pushes with the status and check runs of their head commit")
+	deep := flags.Bool("deep", false, "supplement the events with the Search API beyond the events window")
+	failOnEmpty := flags.Bool("fail-on-empty", false, "exit with code 6 when there is no activity to show")
+	since := flags.String("since", "", "with --deep, first day searched as YYYY-MM-DD, defaults to a year ago")
+	var repoFilters repoFilterFlags
+	repoFilters.register(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if err := loadConfig(); err != nil {
+		return err
+	}
+	user := flags.Arg(0)
+	if flags.NArg() == 0 {
+		user = viper.GetString("user")
+	}
+	if flags.NArg() > 1 || user == "" {
+		return errors.New("usage: go-github-activity [flags] <username>")
+	}
+	from := time.Now().AddDate(-1, 0, 0)
+	if *since != "" {
+		t, err := time.Parse(time.DateOnly, *since)
+		if err != nil {
+			return fmt.Errorf("parse since: %w", err)
+		}
+		from = t
+	}
+	events, err := fetchDeepEvents(user, *deep, from)
+	if err != nil {
+		return err
+	}
+	if *enrich {
+		if err := enrichEvents(events, true); err != nil {
+			return err
+		}
+	}
+	if events, err = repoFilters.apply(events); err != nil {
+		return err
+	}
+	if *verify {
+		fetcher, err := newRepoFetcher()
+		if err != nil {
+			return err
+		}
+		if err := verifyCommits(events, fetcher.verification); err != nil {
+			return err
+		}
+	}
+	if *checks {
+		fetcher, err := newRepoFetcher()
+		if err != nil {
+			return err
+		}
+		if err := resolveChecks(events, fetcher.checksState); err != nil {
+			return err
+		}
+	}
+	if *failOnEmpty && len(events) == 0 {
+		return errEmpty
+	}
+	if *lang == "" {
+		*lang = viper.GetString("lang")
+	}
+	if *output == "" {
+		*output = cmp.Or(viper.GetString("output"), "text")
+	}
+	cat := lookupCatalog(resolveLang(*lang, os.Getenv("LANG")))
+	var r renderer
+	switch *output {
+	case "text":
+		r = textRenderer{
+			cat:     cat,
+			icons:   loadIcons(!*noEmoji && emojiSupported(os.Getenv), viper.GetStringMapString("icons")),
+			tickets: configuredTickets(),
+		}
+	case "table":
+		width := 0
+		if !*noTruncate {
+			width = terminalWidth(os.Getenv)
+		}
+		r = tableRenderer{cat: cat, width: width}
+	case "json":
+		r = jsonRenderer{cat: cat, tickets: configuredTickets()}
+	case "html":
+		r = htmlRenderer{cat: cat}
+	default:
+		return fmt.Errorf("unknown output format %q", *output)
+	}
+	return r.render(stdout, events)
+}
+
+// loadConfig reads the optional configuration file and sets defaults.
+func loadConfig() error {
+	if configLoaded.Load() {
+		return nil
+	}
+	viper.SetDefault("api_url", defaultAPIURL)
+	viper.SetDefault("cache_ttl", "24h")
+	viper.SetDefault("sheets.range", "Sheet1!A1")
+	viper.SetDefault("serve.cache_ttl", "1m")
+	viper.SetDefault("serve.stale_ttl", "10m")
+	viper.SetDefault("serve.stream_interval", "1m")
+	viper.SetDefault("watch.interval", "1m")
+	viper.SetDefault("archive.batch_size", defaultBatchSize)
+	viper.SetDefault("gitlab.url", defaultGitLabURL)
+	viper.SetDefault("gitea.url", defaultGiteaURL)
+	viper.SetDefault("bitbucket.url", defaultBitbucketURL)
+	err := initialize(&defaultUserHome{}, "config.yaml")
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if err := applyProfile(); err != nil {
+		return err
+	}
+	if redact {
+		salt, err := redactSalt(&defaultUserHome{})
+		if err != nil {
+			return err
+		}
+		viper.Set("redact.salt", salt)
+	}
+	return loadToken()
+}
+
+// fetchUserEvents loads the configuration and fetches the user's events.
+func fetchUserEvents(user string) ([]ghEvent, error) {
+	if err := loadConfig(); err != nil {
+		return nil, err
+	}
+	sources, err := configuredSources()
+	if err != nil {
+		return nil, err
+	}
+	events, err := fetchSourceEvents(sources, user)
+	if err != nil {
+		return nil, err
+	}
+	if events, err = ignoreEvents(events); err != nil {
+		return nil, err
+	}
+	return redactEvents(hideSponsorships(events)), nil
+}
+
+// fetchLoginEvents fetches the latest events of one account.
+func fetchLoginEvents(hc *client, login string) ([]ghEvent, error) {
+	return fetchGitHubResponse(hc, eventsURL(viper.GetString("api_url"), login))
+}
+
+// newRepoFetcher returns a cached repository fetcher for the configured API.
+func newRepoFetcher() (*repoFetcher, error) {
+	cache, err := newFileCache(viper.GetDuration("cache_ttl"))
+	if err != nil {
+		return nil, err
+	}
+	hc := configuredClient()
+	return &repoFetcher{hc: hc, base: viper.GetString("api_url"), cache: cache}, nil
+}
+
+// enrichEvents attaches repository metadata to the events, and actor
+// profiles too when withActors is set.
+func enrichEvents(events []ghEvent, withActors bool) error {
+	repos, err := newRepoFetcher()
+	if err != nil {
+		return err
+	}
+	if err := enrichRepos(events, repos.metadata); err != nil {
+		return err
+	}
+	if !withActors {
+		return nil
+	}
+	users := &userFetcher{hc: repos.hc, base: repos.base, cache: repos.cache}
+	return enrichActors(events, users.profile)
+}
+
+// configuredTickets returns the issue-tracker matcher of the tracker config
+// section, or nil when no tracker is configured.
+func configuredTickets() *ticketMatcher {
+	base, projects := viper.GetString("tracker.url"), viper.GetStringSlice("tracker.projects")
+	if base == "" && len(projects) == 0 {
+		return nil
+	}
+	return newTicketMatcher(base, projects)
+}
+
+// eventsURL builds the public events endpoint for a user.
+func eventsURL(base, user string) string {
+	return fmt.Sprintf("%s/users/%s/events?per_page=100", base, url.PathEscape(user))
+}
+
+// sortedKeys returns the keys of m in lexical order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+type (
+	// mcpServer answers Model Context Protocol requests, one JSON-RPC
+	// message per line, exposing the activity queries as tools.
+	mcpServer struct {
+		fetch func(user string) ([]ghEvent, error)
+		clock clock
+	}
+	// mcpTool is a tool callable by the assistant.
+	mcpTool struct {
+		description string
+		schema      string
+		call        func(s *mcpServer, args mcpArgs) (string, error)
+	}
+	// mcpArgs holds the arguments of every tool.
+	mcpArgs struct {
+		User  string `json:"user"`
+		Limit int    `json:"limit"`
+		Days  int    `json:"days"`
+		Query string `json:"query"`
+	}
+	// rpcRequest is a JSON-RPC 2.0 request or notification.
+	rpcRequest struct {
+		JSONRPC string          `json:"jsonrpc"`
+		ID      json.RawMessage `json:"id,omitempty"`
+		Method  string          `json:"method"`
+		Params  json.RawMessage `json:"params,omitempty"`
+	}
+	// rpcResponse is a JSON-RPC 2.0 response.
+	rpcResponse struct {
+		JSONRPC string          `json:"jsonrpc"`
+		ID      json.RawMessage `json:"id"`
+		Result  any             `json:"result,omitempty"`
+		Error   *rpcError       `json:"error,omitempty"`
+	}
+	// rpcError is a JSON-RPC 2.0 error object.
+	rpcError struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+)
+
+// mcpProtocolVersion is the MCP revision implemented.
+const mcpProtocolVersion = "2024-11-05"
+
+// JSON-RPC error codes.
+const (
+	rpcParseError     = -32700
+	rpcMethodNotFound = -32601
+	rpcInvalidParams  = -32602
+)
+
+// mcpTools lists the tools by name.
+var mcpTools = map[string]mcpTool{
+	"get_user_activity": {
+		description: "List the latest public GitHub events of a user, newest first, one summary per line.",
+		schema: `{"type":"object","properties":{"user":{"type":"string","description":"GitHub login"},` +
+			`"limit":{"type":"integer","description":"maximum number of events, 30 by default"}},"required":["user"]}`,
+		call: (*mcpServer).userActivity,
+	},
+	"get_activity_stats": {
+		description: "Count the events, commits, pull requests, issues and stars of a user over recent days.",
+		schema: `{"type":"object","properties":{"user":{"type":"string","description":"GitHub login"},` +
+			`"days":{"type":"integer","description":"size of the window in days, 7 by default"}},"required":["user"]}`,
+		call: (*mcpServer).activityStats,
+	},
+	"search_activity": {
+		description: "Find the events of a user whose summary, repository, commit messages or pull request titles " +
+			"contain the query, case-insensitively.",
+		schema: `{"type":"object","properties":{"user":{"type":"string","description":"GitHub login"},` +
+			`"query":{"type":"string","description":"text to search for"}},"required":["user","query"]}`,
+		call: (*mcpServer).searchActivity,
+	},
+}
+
+// runMCP serves MCP over stdin and stdout until stdin closes.
+func runMCP(args []string, stdout io.Writer) error {
+	if len(args) != 0 {
+		return errors.New("usage: go-github-activity mcp")
+	}
+	if err := loadConfig(); err != nil {
+		return err
+	}
+	s := &mcpServer{fetch: fetchUserEvents, clock: systemClock{}}
+	return s.serve(os.Stdin, stdout)
+}
+
+// serve reads requests line by line and writes a response to each request;
+// notifications get none.
+func (s *mcpServer) serve(r io.Reader, w io.Writer) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	enc := json.NewEncoder(w)
+	for sc.Scan() {
+		if strings.TrimSpace(sc.Text()) == "" {
+			continue
+		}
+		var req rpcRequest
+		if err := json.Unmarshal(sc.Bytes(), &req); err != nil {
+			res := rpcResponse{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &rpcError{rpcParseError, err.Error()}}
+			if err := enc.Encode(res); err != nil {
+				return fmt.Errorf("write response: %w", err)
+			}
+			continue
+		}
+		if req.ID == nil {
+			continue
+		}
+		res := rpcResponse{JSONRPC: "2.0", ID: req.ID}
+		res.Result, res.Error = s.handle(req)
+		if err := enc.Encode(res); err != nil {
+			return fmt.Errorf("write response: %w", err)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return fmt.Errorf("read request: %w", err)
+	}
+	return nil
+}
+
+// handle answers one request.
+func (s *mcpServer) handle(req rpcRequest) (any, *rpcError) {
+	switch req.Method {
+	case "initialize":
+		return map[string]any{
+			"protocolVersion": mcpProtocolVersion,
+			"capabilities":    map[string]any{"tools": map[string]any{}},
+			"serverInfo":      map[string]string{"name": "go-github-activity", "version": version},
+		}, nil
+	case "ping":
+		return map[string]any{}, nil
+	case "tools/list":
+		tools := make([]map[string]any, 0, len(mcpTools))
+		for _, name := range sortedKeys(mcpTools) {
+			tools = append(tools, map[string]any{
+				"name":        name,
+				"description": mcpTools[name].description,
+				"inputSchema": json.RawMessage(mcpTools[name].schema),
+			})
+		}
+		return map[string]any{"tools": tools}, nil
+	case "tools/call":
+		var params struct {
+			Name      string  `json:"name"`
+			Arguments mcpArgs `json:"arguments"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &rpcError{rpcInvalidParams, err.Error()}
+		}
+		tool, ok := mcpTools[params.Name]
+		if !ok {
+			return nil, &rpcError{rpcInvalidParams, fmt.Sprintf("unknown tool %q", params.Name)}
+		}
+		if params.Arguments.User == "" {
+			return nil, &rpcError{rpcInvalidParams, "missing user"}
+		}
+		// Tool failures are results, so the assistant can read and react to them.
+		text, err := tool.call(s, params.Arguments)
+		if err != nil {
+			return mcpText(err.Error(), true), nil
+		}
+		return mcpText(text, false), nil
+	default:
+		return nil, &rpcError{rpcMethodNotFound, fmt.Sprintf("unknown method %q", req.Method)}
+	}
+}
+
+// mcpText is a tool result holding one text block.
+func mcpText(text string, isError bool) map[string]any {
+	return map[string]any{"content": []map[string]string{{"type": "text", "text": text}}, "isError": isError}
+}
+
+// userActivity lists event summaries with their dates.
+func (s *mcpServer) userActivity(args mcpArgs) (string, error) {
+	events, err := s.fetch(args.User)
+	if err != nil {
+		return "", err
+	}
+	limit := args.Limit
+	if limit <= 0 {
+		limit = 30
+	}
+	return describeEvents(events[:min(limit, len(events))]), nil
+}
+
+// activityStats prints the metric totals of the window.
+func (s *mcpServer) activityStats(args mcpArgs) (string, error) {
+	events, err := s.fetch(args.User)
+	if err != nil {
+		return "", err
+	}
+	days := args.Days
+	if days <= 0 {
+		days = 7
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Last %d days:\n", days)
+	if err := writeTotals(&b, sumMetrics(eventsSince(events, s.clock.Now().AddDate(0, 0, -days)))); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// searchActivity lists the events mentioning the query.
+func (s *mcpServer) searchActivity(args mcpArgs) (string, error) {
+	events, err := s.fetch(args.User)
+	if err != nil {
+		return "", err
+	}
+	query := strings.ToLower(args.Query)
+	var found []ghEvent
+	for _, ev := range events {
+		texts := append(eventTexts(ev), summarize(catalogs[defaultLang], ev), ev.Repo.Name)
+		for _, text := range texts {
+			if strings.Contains(strings.ToLower(text), query) {
+				found = append(found, ev)
+				break
+			}
+		}
+	}
+	if len(found) == 0 {
+		return fmt.Sprintf("No events match %q.", args.Query), nil
+	}
+	return describeEvents(found), nil
+}
+
+// describeEvents writes one dated summary per event.
+func describeEvents(events []ghEvent) string {
+	if len(events) == 0 {
+		return "No recent events."
+	}
+	var b strings.Builder
+	for _, ev := range events {
+		fmt.Fprintf(&b, "%s %s\n", ev.CreatedAt.Format(time.RFC3339), summarize(catalogs[defaultLang], ev))
+	}
+	return b.String()
+}
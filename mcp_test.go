@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUnitMCPServer(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	events := []ghEvent{
+		{
+			Type:      "PushEvent",
+			Repo:      repo{Name: "octo/api"},
+			Payload:   payload{Size: 2, Commits: []commit{{Message: "Fix login redirect"}}},
+			CreatedAt: now.Add(-time.Hour),
+		},
+		{Type: "WatchEvent", Repo: repo{Name: "octo/web"}, CreatedAt: now.AddDate(0, 0, -30)},
+	}
+	testCases := []struct {
+		name      string
+		request   string
+		fetchErr  error
+		wantError int
+		want      string
+	}{
+		{
+			name:    "initialize",
+			request: `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`,
+			want:    `"protocolVersion":"2024-11-05"`,
+		},
+		{
+			name:    "list tools",
+			request: `{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+			want:    `"name":"get_activity_stats"`,
+		},
+		{
+			name:    "user activity",
+			request: toolCall(3, "get_user_activity", `{"user":"octocat","limit":1}`),
+			want:    `Pushed 2 commits to octo/api`,
+		},
+		{
+			name:    "stats",
+			request: toolCall(4, "get_activity_stats", `{"user":"octocat"}`),
+			want:    `commits:       2`,
+		},
+		{
+			name:    "search",
+			request: toolCall(5, "search_activity", `{"user":"octocat","query":"LOGIN"}`),
+			want:    `octo/api`,
+		},
+		{
+			name:     "tool failure",
+			request:  toolCall(6, "get_user_activity", `{"user":"ghost"}`),
+			fetchErr: errors.New("user not found"),
+			want:     `"isError":true`,
+		},
+		{
+			name:      "unknown tool",
+			request:   toolCall(7, "delete_repo", `{"user":"octocat"}`),
+			wantError: rpcInvalidParams,
+		},
+		{name: "unknown method", request: `{"jsonrpc":"2.0","id":8,"method":"resources/list"}`, wantError: rpcMethodNotFound},
+		{name: "malformed", request: `{"jsonrpc":`, wantError: rpcParseError},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			s := &mcpServer{
+				fetch: func(string) ([]ghEvent, error) { return events, tc.fetchErr },
+				clock: &fakeClock{now: now},
+			}
+			in := `{"jsonrpc":"2.0","method":"notifications/initialized"}` + "\n" + tc.request + "\n"
+			var out bytes.Buffer
+			// Act
+			err := s.serve(strings.NewReader(in), &out)
+			// Assert
+			assertNoError(t, err)
+			assertEqual(t, strings.Count(out.String(), "\n"), 1)
+			var res struct {
+				Error *rpcError `json:"error"`
+			}
+			assertNoError(t, json.Unmarshal(out.Bytes(), &res))
+			if tc.wantError != 0 {
+				assertEqual(t, res.Error != nil, true)
+				assertEqual(t, res.Error.Code, tc.wantError)
+				return
+			}
+			assertEqual(t, res.Error == nil, true)
+			assertEqual(t, strings.Contains(out.String(), tc.want), true)
+		})
+	}
+}
+
+func toolCall(id int, name, args string) string {
+	const format = `{"jsonrpc":"2.0","id":%d,"method":"tools/call","params":{"name":%q,"arguments":%s}}`
+	return fmt.Sprintf(format, id, name, args)
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// runNotes writes each day's activity into the daily note of that day in
+// a Markdown vault, e.g. an Obsidian one.
+func runNotes(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("notes", flag.ContinueOnError)
+	days := flags.Int("days", 1, "number of days to write, ending today")
+	vault := flags.String("vault", "", "vault directory (default: notes.vault from the config)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: go-github-activity notes [flags] <username>")
+	}
+	if *days <= 0 {
+		return fmt.Errorf("invalid number of days: %d", *days)
+	}
+	events, err := fetchUserEvents(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	if *vault == "" {
+		*vault = viper.GetString("notes.vault")
+	}
+	if *vault == "" {
+		return errors.New("no vault: set --vault or notes.vault")
+	}
+	dir := filepath.Join(*vault, viper.GetString("notes.folder"))
+	today := startOfDay(time.Now(), time.Local)
+	for day := today.AddDate(0, 0, 1-*days); !day.After(today); day = day.AddDate(0, 0, 1) {
+		dayEvents := eventsBetween(events, day, day.AddDate(0, 0, 1))
+		if len(dayEvents) == 0 {
+			continue
+		}
+		path := filepath.Join(dir, day.Format(time.DateOnly)+".md")
+		changed, err := updateDailyNote(path, day, dailySection(dayEvents))
+		if err != nil {
+			return err
+		}
+		if changed {
+			if err := infof(stdout, "updated %s\n", path); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// dailySection lists the events of a day oldest first, with their local
+// time, between the activity markers.
+func dailySection(events []ghEvent) string {
+	var b strings.Builder
+	b.WriteString(readmeStartMarker + "\n")
+	for i := len(events) - 1; i >= 0; i-- {
+		ev := events[i]
+		line := linkRepo(summarize(catalogs[defaultLang], ev), ev.Repo.Name)
+		fmt.Fprintf(&b, "- %s %s\n", ev.CreatedAt.In(time.Local).Format("15:04"), line)
+	}
+	b.WriteString(readmeEndMarker + "\n")
+	return b.String()
+}
+
+// updateDailyNote creates the note with frontmatter, replaces its activity
+// section, or appends one under a heading, and reports whether it changed.
+func updateDailyNote(path string, day time.Time, section string) (bool, error) {
+	byt, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return false, fmt.Errorf("read the daily note: %w", err)
+	}
+	var doc string
+	switch {
+	case err != nil:
+		doc = fmt.Sprintf("---\ndate: %s\ntags: [github-activity]\n---\n\n## GitHub activity\n\n%s",
+			day.Format(time.DateOnly), section)
+	case strings.Contains(string(byt), readmeStartMarker):
+		if doc, err = replaceSection(string(byt), section); err != nil {
+			return false, fmt.Errorf("update %s: %w", path, err)
+		}
+	default:
+		doc = strings.TrimRight(string(byt), "\n") + "\n\n## GitHub activity\n\n" + section
+	}
+	if doc == string(byt) {
+		return false, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return false, fmt.Errorf("create notes directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(doc), 0o644); err != nil { //nolint:gosec // notes are the user's documents
+		return false, fmt.Errorf("write the daily note: %w", err)
+	}
+	return true, nil
+}
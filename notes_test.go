@@ -0,0 +1,74 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUnitDailySection(t *testing.T) {
+	// Arrange
+	day := time.Date(2025, 3, 4, 0, 0, 0, 0, time.Local)
+	events := []ghEvent{
+		{Type: "WatchEvent", Repo: repo{Name: "octo/b"}, CreatedAt: day.Add(15 * time.Hour)},
+		{Type: "ForkEvent", Repo: repo{Name: "octo/a"}, CreatedAt: day.Add(9*time.Hour + 5*time.Minute)},
+	}
+	// Act
+	got := dailySection(events)
+	// Assert
+	want := readmeStartMarker + "\n" +
+		"- 09:05 Forked [octo/a](https://github.com/octo/a)\n" +
+		"- 15:00 Starred [octo/b](https://github.com/octo/b)\n" +
+		readmeEndMarker + "\n"
+	assertEqual(t, got, want)
+}
+
+func TestUnitUpdateDailyNote(t *testing.T) {
+	day := time.Date(2025, 3, 4, 0, 0, 0, 0, time.UTC)
+	section := readmeStartMarker + "\n- 09:05 new\n" + readmeEndMarker + "\n"
+	testCases := []struct {
+		name        string
+		existing    string
+		want        string
+		wantChanged bool
+	}{
+		{
+			name:        "new note",
+			want:        "---\ndate: 2025-03-04\ntags: [github-activity]\n---\n\n## GitHub activity\n\n" + section,
+			wantChanged: true,
+		},
+		{
+			name:        "note without section",
+			existing:    "# Tuesday\n\nMeeting notes.\n",
+			want:        "# Tuesday\n\nMeeting notes.\n\n## GitHub activity\n\n" + section,
+			wantChanged: true,
+		},
+		{
+			name:        "note with section",
+			existing:    "Intro\n" + readmeStartMarker + "\n- old\n" + readmeEndMarker + "\nOutro\n",
+			want:        "Intro\n" + section + "Outro\n",
+			wantChanged: true,
+		},
+		{name: "up to date", existing: "Intro\n" + section, want: "Intro\n" + section},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			path := filepath.Join(t.TempDir(), "Daily", "2025-03-04.md")
+			if tc.existing != "" {
+				assertNoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+				assertNoError(t, os.WriteFile(path, []byte(tc.existing), 0o644))
+			}
+			// Act
+			changed, err := updateDailyNote(path, day, section)
+			// Assert
+			assertNoError(t, err)
+			assertEqual(t, changed, tc.wantChanged)
+			got, _ := os.ReadFile(path)
+			assertEqual(t, string(got), tc.want)
+			assertEqual(t, strings.HasSuffix(string(got), "\n"), true)
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+type (
+	// notifier delivers a notice to a chat, push or webhook service.
+	notifier interface {
+		notify(ctx context.Context, n notice) error
+	}
+	// notice is a text message and the events it reports.
+	notice struct {
+		Message string
+		Events  []ghEvent
+	}
+	// notifierConfig is one entry of the notifiers config list; each backend
+	// reads the fields it needs.
+	notifierConfig struct {
+		Name        string            `mapstructure:"name"`
+		Type        string            `mapstructure:"type"`
+		Homeserver  string            `mapstructure:"homeserver"`
+		AccessToken string            `mapstructure:"access_token"`
+		RoomID      string            `mapstructure:"room_id"`
+		BotToken    string            `mapstructure:"bot_token"`
+		ChatID      string            `mapstructure:"chat_id"`
+		URL         string            `mapstructure:"url"`
+		Method      string            `mapstructure:"method"`
+		Headers     map[string]string `mapstructure:"headers"`
+		Template    string            `mapstructure:"template"`
+		Secret      string            `mapstructure:"secret"`
+		Server      string            `mapstructure:"server"`
+		Topic       string            `mapstructure:"topic"`
+		AppToken    string            `mapstructure:"app_token"`
+		UserKey     string            `mapstructure:"user_key"`
+		Events      []string          `mapstructure:"events"`
+		Batch       time.Duration     `mapstructure:"batch"`
+		QuietHours  string            `mapstructure:"quiet_hours"`
+		Urgent      []string          `mapstructure:"urgent"`
+	}
+	// eventTypeFilter forwards only the notices about selected event types.
+	eventTypeFilter struct {
+		next  notifier
+		types map[string]bool
+	}
+	// notifierFactory builds a backend from its configuration.
+	notifierFactory func(cfg notifierConfig, hc *http.Client) (notifier, error)
+)
+
+// notifierFactories lists the supported backends by config type.
+var notifierFactories = map[string]notifierFactory{
+	"matrix":   newMatrixNotifier,
+	"ntfy":     newNtfyNotifier,
+	"pushover": newPushoverNotifier,
+	"telegram": newTelegramNotifier,
+	"webhook":  newWebhookNotifier,
+}
+
+// loadNotifiers builds the targets of the notifiers config list, keyed by
+// name (or type when unnamed). Targets with an events list only receive
+// notices about those event types; targets with a batch window or quiet
+// hours hold notices back until flushed.
+func loadNotifiers() (map[string]notifier, error) {
+	var cfgs []notifierConfig
+	if err := viper.UnmarshalKey("notifiers", &cfgs); err != nil {
+		return nil, fmt.Errorf("parse notifiers: %w", err)
+	}
+	hc := &http.Client{Timeout: 10 * time.Second}
+	targets := make(map[string]notifier, len(cfgs))
+	for _, cfg := range cfgs {
+		factory, ok := notifierFactories[cfg.Type]
+		if !ok {
+			return nil, fmt.Errorf("unknown notifier type %q", cfg.Type)
+		}
+		if cfg.Name == "" {
+			cfg.Name = cfg.Type
+		}
+		if _, dup := targets[cfg.Name]; dup {
+			return nil, fmt.Errorf("duplicate notifier %q", cfg.Name)
+		}
+		n, err := factory(cfg, hc)
+		if err != nil {
+			return nil, fmt.Errorf("notifier %q: %w", cfg.Name, err)
+		}
+		if cfg.Batch != 0 || cfg.QuietHours != "" {
+			if n, err = newBatchingNotifier(n, cfg); err != nil {
+				return nil, fmt.Errorf("notifier %q: %w", cfg.Name, err)
+			}
+		}
+		if len(cfg.Events) > 0 {
+			n = newEventTypeFilter(n, cfg.Events)
+		}
+		targets[cfg.Name] = n
+	}
+	return targets, nil
+}
+
+// newEventTypeFilter wraps next so it only receives the given event types.
+func newEventTypeFilter(next notifier, types []string) *eventTypeFilter {
+	f := &eventTypeFilter{next: next, types: make(map[string]bool, len(types))}
+	for _, t := range types {
+		f.types[t] = true
+	}
+	return f
+}
+
+// notify forwards the notice restricted to the selected events, and drops it
+// when none is left.
+func (f *eventTypeFilter) notify(ctx context.Context, n notice) error {
+	var kept []ghEvent
+	for _, ev := range n.Events {
+		if f.types[ev.Type] {
+			kept = append(kept, ev)
+		}
+	}
+	if len(kept) == 0 {
+		return nil
+	}
+	n.Events = kept
+	return f.next.notify(ctx, n)
+}
+
+// flush flushes the wrapped notifier when it holds notices back.
+func (f *eventTypeFilter) flush(ctx context.Context) error {
+	if fl, ok := f.next.(flusher); ok {
+		return fl.flush(ctx)
+	}
+	return nil
+}
+
+// sendJSON sends body encoded as JSON and fails on non-2xx responses.
+func sendJSON(ctx context.Context, hc *http.Client, method, url string, headers map[string]string, body any) error {
+	byt, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("encode notification: %w", err)
+	}
+	return send(ctx, hc, method, url, headers, byt)
+}
+
+// send sends a JSON payload and fails on non-2xx responses.
+func send(ctx context.Context, hc *http.Client, method, url string, headers map[string]string, byt []byte) error {
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(byt))
+	if err != nil {
+		return fmt.Errorf("request error: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	res, err := hc.Do(req)
+	if err != nil {
+		return fmt.Errorf("send notification: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("send notification: %q", res.Status)
+	}
+	return nil
+}
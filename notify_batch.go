@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type (
+	// flusher is a notifier holding notices back until a later flush.
+	flusher interface {
+		flush(ctx context.Context) error
+	}
+	// quietHours is a range of local hours, possibly wrapping past midnight.
+	quietHours struct {
+		start int
+		end   int
+	}
+	// batchingNotifier coalesces notices into one message per window and
+	// holds them during quiet hours; urgent event types skip both.
+	batchingNotifier struct {
+		next    notifier
+		window  time.Duration
+		quiet   *quietHours
+		urgent  map[string]bool
+		clock   clock
+		pending []notice
+		since   time.Time
+	}
+)
+
+// parseQuietHours parses a "start-end" range of hours such as "22-7".
+func parseQuietHours(s string) (*quietHours, error) {
+	from, to, ok := strings.Cut(s, "-")
+	start, errStart := strconv.Atoi(strings.TrimSpace(from))
+	end, errEnd := strconv.Atoi(strings.TrimSpace(to))
+	if !ok || errStart != nil || errEnd != nil || start < 0 || start > 23 || end < 0 || end > 24 || start == end {
+		return nil, fmt.Errorf("invalid quiet hours %q, want start-end such as 22-7", s)
+	}
+	return &quietHours{start: start, end: end}, nil
+}
+
+// contains reports whether t falls within the quiet hours.
+func (q *quietHours) contains(t time.Time) bool {
+	if q == nil {
+		return false
+	}
+	h := t.Hour()
+	if q.start < q.end {
+		return h >= q.start && h < q.end
+	}
+	return h >= q.start || h < q.end
+}
+
+// highPriorityTypes are event types always delivered at once, as if listed
+// in urgent.
+var highPriorityTypes = []string{"SecurityAdvisoryEvent"}
+
+// newBatchingNotifier wraps next with the batching settings of cfg.
+func newBatchingNotifier(next notifier, cfg notifierConfig) (*batchingNotifier, error) {
+	if cfg.Batch < 0 {
+		return nil, fmt.Errorf("invalid batch window: %s", cfg.Batch)
+	}
+	b := &batchingNotifier{next: next, window: cfg.Batch, urgent: map[string]bool{}, clock: systemClock{}}
+	if cfg.QuietHours != "" {
+		q, err := parseQuietHours(cfg.QuietHours)
+		if err != nil {
+			return nil, err
+		}
+		b.quiet = q
+	}
+	for _, t := range slices.Concat(highPriorityTypes, cfg.Urgent) {
+		b.urgent[t] = true
+	}
+	return b, nil
+}
+
+// notify delivers urgent notices at once and queues the others.
+func (b *batchingNotifier) notify(ctx context.Context, n notice) error {
+	for _, ev := range n.Events {
+		if b.urgent[ev.Type] {
+			return b.next.notify(ctx, n)
+		}
+	}
+	if len(b.pending) == 0 {
+		b.since = b.clock.Now()
+	}
+	b.pending = append(b.pending, n)
+	return b.flush(ctx)
+}
+
+// flush delivers the queued notices as one message once the window has
+// elapsed outside quiet hours; notices held overnight thus arrive as a
+// morning summary.
+func (b *batchingNotifier) flush(ctx context.Context) error {
+	now := b.clock.Now()
+	if len(b.pending) == 0 || b.quiet.contains(now) || now.Sub(b.since) < b.window {
+		return nil
+	}
+	return b.next.notify(ctx, b.take())
+}
+
+// take empties the queue and returns its notices as one.
+func (b *batchingNotifier) take() notice {
+	n := b.pending[0]
+	if len(b.pending) > 1 {
+		lines := []string{fmt.Sprintf("%d notifications since %s:", len(b.pending), b.since.Format("15:04"))}
+		n = notice{}
+		for _, p := range b.pending {
+			lines = append(lines, p.Message)
+			n.Events = append(n.Events, p.Events...)
+		}
+		n.Message = strings.Join(lines, "\n")
+	}
+	b.pending = nil
+	return n
+}
+
+// handOver moves the queued notices to the notifier replacing b on a
+// reload: a batching one queues them since the same time, as if they had
+// been its own, any other one delivers them at once. Without a replacement,
+// b's backend delivers them at once.
+func (b *batchingNotifier) handOver(ctx context.Context, next notifier) error {
+	if len(b.pending) == 0 {
+		return nil
+	}
+	if nb := batchingOf(next); nb != nil {
+		if len(nb.pending) == 0 || b.since.Before(nb.since) {
+			nb.since = b.since
+		}
+		nb.pending = append(b.pending, nb.pending...)
+		b.pending = nil
+		return nil
+	}
+	if next == nil {
+		next = b.next
+	}
+	return next.notify(ctx, b.take())
+}
+
+// batchingOf returns the batching notifier of n, within its event type
+// filter, or nil.
+func batchingOf(n notifier) *batchingNotifier {
+	if f, ok := n.(*eventTypeFilter); ok {
+		n = f.next
+	}
+	b, _ := n.(*batchingNotifier)
+	return b
+}
+
+// handOverPending hands the notices queued by the notifiers of a replaced
+// configuration over to those of the same name replacing them, so that a
+// reload loses none.
+func handOverPending(ctx context.Context, prev, next map[string]notifier) {
+	for _, name := range sortedKeys(prev) {
+		b := batchingOf(prev[name])
+		if b == nil {
+			continue
+		}
+		if err := b.handOver(ctx, next[name]); err != nil {
+			log.Printf("notify %s: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestUnitParseQuietHours(t *testing.T) {
+	testCases := []struct {
+		name    string
+		in      string
+		at      int
+		want    bool
+		wantErr bool
+	}{
+		{name: "overnight inside", in: "22-7", at: 23, want: true},
+		{name: "overnight early morning", in: "22-7", at: 6, want: true},
+		{name: "overnight outside", in: "22-7", at: 7, want: false},
+		{name: "same day", in: "12-14", at: 13, want: true},
+		{name: "empty range", in: "8-8", wantErr: true},
+		{name: "malformed", in: "night", wantErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			q, err := parseQuietHours(tc.in)
+			// Assert
+			if tc.wantErr {
+				assertNotNil(t, err)
+				return
+			}
+			assertNoError(t, err)
+			assertEqual(t, q.contains(time.Date(2025, 3, 3, tc.at, 30, 0, 0, time.Local)), tc.want)
+		})
+	}
+}
+
+func TestUnitBatchingNotifier(t *testing.T) {
+	start := time.Date(2025, 3, 3, 21, 50, 0, 0, time.Local)
+	push := notice{Message: "octo: pushed", Events: []ghEvent{{Type: "PushEvent"}}}
+	release := notice{Message: "octo: released", Events: []ghEvent{{Type: "ReleaseEvent"}}}
+	advisory := notice{Message: "octo: advisory", Events: []ghEvent{{Type: "SecurityAdvisoryEvent"}}}
+	testCases := []struct {
+		name     string
+		cfg      notifierConfig
+		notices  []notice
+		flushAt  time.Duration
+		wantSent []string
+	}{
+		{
+			name:     "batched within the window",
+			cfg:      notifierConfig{Batch: 15 * time.Minute},
+			notices:  []notice{push, push},
+			flushAt:  5 * time.Minute,
+			wantSent: nil,
+		},
+		{
+			name:     "batched after the window",
+			cfg:      notifierConfig{Batch: 15 * time.Minute},
+			notices:  []notice{push, push},
+			flushAt:  15 * time.Minute,
+			wantSent: []string{"2 notifications since 21:50:\nocto: pushed\nocto: pushed"},
+		},
+		{
+			name:     "held during quiet hours",
+			cfg:      notifierConfig{QuietHours: "21-7"},
+			notices:  []notice{push},
+			flushAt:  8 * time.Hour,
+			wantSent: nil,
+		},
+		{
+			name:     "morning summary",
+			cfg:      notifierConfig{QuietHours: "21-7"},
+			notices:  []notice{push, push},
+			flushAt:  10 * time.Hour,
+			wantSent: []string{"2 notifications since 21:50:\nocto: pushed\nocto: pushed"},
+		},
+		{
+			name:     "held past midnight",
+			cfg:      notifierConfig{Batch: 15 * time.Minute, QuietHours: "23-7"},
+			notices:  []notice{push, push},
+			flushAt:  3 * time.Hour,
+			wantSent: nil,
+		},
+		{
+			name:     "sent when quiet hours end",
+			cfg:      notifierConfig{Batch: 15 * time.Minute, QuietHours: "23-7"},
+			notices:  []notice{push},
+			flushAt:  9*time.Hour + 10*time.Minute,
+			wantSent: []string{"octo: pushed"},
+		},
+		{
+			name:     "urgent skips quiet hours",
+			cfg:      notifierConfig{QuietHours: "21-7", Urgent: []string{"ReleaseEvent"}},
+			notices:  []notice{push, release},
+			flushAt:  time.Hour,
+			wantSent: []string{"octo: released"},
+		},
+		{
+			name:     "advisories are always urgent",
+			cfg:      notifierConfig{Batch: 15 * time.Minute, QuietHours: "21-7"},
+			notices:  []notice{push, advisory},
+			flushAt:  time.Minute,
+			wantSent: []string{"octo: advisory"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			next := &recordingNotifier{}
+			b, err := newBatchingNotifier(next, tc.cfg)
+			assertNoError(t, err)
+			clk := &fakeClock{now: start}
+			b.clock = clk
+			for _, n := range tc.notices {
+				assertNoError(t, b.notify(context.Background(), n))
+			}
+			// Act
+			clk.advance(tc.flushAt)
+			err = b.flush(context.Background())
+			// Assert
+			assertNoError(t, err)
+			assertEqual(t, len(next.got), len(tc.wantSent))
+			for i, want := range tc.wantSent {
+				assertEqual(t, next.got[i].Message, want)
+			}
+		})
+	}
+}
+
+// FuzzParseQuietHours checks that no quiet hours setting makes the parser
+// panic.
+func FuzzParseQuietHours(f *testing.F) {
+	for _, seed := range []string{"22:00-07:00", "00:00-00:00", "25:00-07:00", "22-7", ""} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		parseQuietHours(s)
+	})
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// matrixNotifier posts text messages to a Matrix room through the
+// client-server API of a homeserver.
+type matrixNotifier struct {
+	homeserver string
+	token      string
+	roomID     string
+	hc         *http.Client
+	txn        atomic.Int64
+}
+
+func newMatrixNotifier(cfg notifierConfig, hc *http.Client) (notifier, error) {
+	if cfg.Homeserver == "" || cfg.AccessToken == "" || cfg.RoomID == "" {
+		return nil, errors.New("matrix needs homeserver, access_token and room_id")
+	}
+	return &matrixNotifier{
+		homeserver: strings.TrimRight(cfg.Homeserver, "/"),
+		token:      cfg.AccessToken,
+		roomID:     cfg.RoomID,
+		hc:         hc,
+	}, nil
+}
+
+// notify sends an m.text message; the transaction id makes retries idempotent.
+func (m *matrixNotifier) notify(ctx context.Context, n notice) error {
+	txnID := fmt.Sprintf("gha-%d-%d", time.Now().UnixNano(), m.txn.Add(1))
+	endpoint := fmt.Sprintf("%s/_matrix/client/v3/rooms/%s/send/m.room.message/%s",
+		m.homeserver, url.PathEscape(m.roomID), txnID)
+	body := map[string]string{"msgtype": "m.text", "body": n.Message}
+	return sendJSON(ctx, m.hc, http.MethodPut, endpoint, map[string]string{"Authorization": "Bearer " + m.token}, body)
+}
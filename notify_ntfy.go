@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// ntfyServer is the public ntfy instance used when no server is configured.
+const ntfyServer = "https://ntfy.sh"
+
+// ntfyNotifier publishes messages to an ntfy topic.
+type ntfyNotifier struct {
+	server string
+	topic  string
+	token  string
+	hc     *http.Client
+}
+
+func newNtfyNotifier(cfg notifierConfig, hc *http.Client) (notifier, error) {
+	if cfg.Topic == "" {
+		return nil, errors.New("ntfy needs topic")
+	}
+	server := cfg.Server
+	if server == "" {
+		server = ntfyServer
+	}
+	return &ntfyNotifier{server: strings.TrimRight(server, "/"), topic: cfg.Topic, token: cfg.AccessToken, hc: hc}, nil
+}
+
+// notify publishes with the JSON API; clicking the notification opens the
+// repository of the first event.
+func (n *ntfyNotifier) notify(ctx context.Context, nt notice) error {
+	body := map[string]any{"topic": n.topic, "title": "GitHub activity", "message": nt.Message}
+	if len(nt.Events) > 0 {
+		body["click"] = githubURL + nt.Events[0].Repo.Name
+	}
+	var headers map[string]string
+	if n.token != "" {
+		headers = map[string]string{"Authorization": "Bearer " + n.token}
+	}
+	return sendJSON(ctx, n.hc, http.MethodPost, n.server, headers, body)
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// pushoverAPIURL is the Pushover message endpoint.
+const pushoverAPIURL = "https://api.pushover.net/1/messages.json"
+
+// pushoverNotifier sends messages to a user's devices through a Pushover
+// application.
+type pushoverNotifier struct {
+	apiURL  string
+	token   string
+	userKey string
+	hc      *http.Client
+}
+
+func newPushoverNotifier(cfg notifierConfig, hc *http.Client) (notifier, error) {
+	if cfg.AppToken == "" || cfg.UserKey == "" {
+		return nil, errors.New("pushover needs app_token and user_key")
+	}
+	return &pushoverNotifier{apiURL: pushoverAPIURL, token: cfg.AppToken, userKey: cfg.UserKey, hc: hc}, nil
+}
+
+// notify sends the message with a link to the repository of the first event.
+func (p *pushoverNotifier) notify(ctx context.Context, n notice) error {
+	body := map[string]any{"token": p.token, "user": p.userKey, "title": "GitHub activity", "message": n.Message}
+	if len(n.Events) > 0 {
+		body["url"] = githubURL + n.Events[0].Repo.Name
+	}
+	return sendJSON(ctx, p.hc, http.MethodPost, p.apiURL, nil, body)
+}
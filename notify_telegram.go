@@ -0,0 +1,32 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// telegramAPIURL is the Telegram Bot API root.
+const telegramAPIURL = "https://api.telegram.org"
+
+// telegramNotifier sends messages to a chat through a Telegram bot.
+type telegramNotifier struct {
+	apiURL string
+	token  string
+	chatID string
+	hc     *http.Client
+}
+
+func newTelegramNotifier(cfg notifierConfig, hc *http.Client) (notifier, error) {
+	if cfg.BotToken == "" || cfg.ChatID == "" {
+		return nil, errors.New("telegram needs bot_token and chat_id")
+	}
+	return &telegramNotifier{apiURL: telegramAPIURL, token: cfg.BotToken, chatID: cfg.ChatID, hc: hc}, nil
+}
+
+// notify calls sendMessage with plain text.
+func (t *telegramNotifier) notify(ctx context.Context, n notice) error {
+	endpoint := t.apiURL + "/bot" + t.token + "/sendMessage"
+	body := map[string]any{"chat_id": t.chatID, "text": n.Message, "disable_web_page_preview": true}
+	return sendJSON(ctx, t.hc, http.MethodPost, endpoint, nil, body)
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestUnitMatrixNotifier(t *testing.T) {
+	// Arrange
+	var gotPath, gotAuth string
+	var gotBody map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotAuth = r.URL.EscapedPath(), r.Header.Get("Authorization")
+		assertEqual(t, r.Method, http.MethodPut)
+		json.NewDecoder(r.Body).Decode(&gotBody)
+	}))
+	t.Cleanup(srv.Close)
+	cfg := notifierConfig{Homeserver: srv.URL + "/", AccessToken: "syt_token", RoomID: "!room:example.org"}
+	n, _ := newMatrixNotifier(cfg, srv.Client())
+	// Act
+	err := n.notify(context.Background(), notice{Message: "hello"})
+	// Assert
+	assertNoError(t, err)
+	wantPrefix := "/_matrix/client/v3/rooms/%21room:example.org/send/m.room.message/gha-"
+	assertEqual(t, strings.HasPrefix(gotPath, wantPrefix), true)
+	assertEqual(t, gotAuth, "Bearer syt_token")
+	assertEqual(t, gotBody["msgtype"], "m.text")
+	assertEqual(t, gotBody["body"], "hello")
+}
+
+func TestUnitTelegramNotifier(t *testing.T) {
+	testCases := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{name: "delivered", status: http.StatusOK},
+		{name: "rejected", status: http.StatusBadRequest, wantErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			var gotBody map[string]any
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assertEqual(t, r.URL.Path, "/bot123:abc/sendMessage")
+				json.NewDecoder(r.Body).Decode(&gotBody)
+				w.WriteHeader(tc.status)
+			}))
+			t.Cleanup(srv.Close)
+			n := &telegramNotifier{apiURL: srv.URL, token: "123:abc", chatID: "-100", hc: srv.Client()}
+			// Act
+			err := n.notify(context.Background(), notice{Message: "hello"})
+			// Assert
+			if tc.wantErr {
+				assertNotNil(t, err)
+				return
+			}
+			assertNoError(t, err)
+			assertEqual(t, gotBody["chat_id"], any("-100"))
+			assertEqual(t, gotBody["text"], any("hello"))
+		})
+	}
+}
+
+func TestUnitLoadNotifiers(t *testing.T) {
+	testCases := []struct {
+		name      string
+		notifiers []map[string]any
+		wantNames []string
+		wantErr   bool
+	}{
+		{
+			name: "valid targets",
+			notifiers: []map[string]any{
+				{"name": "phone", "type": "telegram", "bot_token": "t", "chat_id": "1"},
+				{"type": "matrix", "homeserver": "https://m", "access_token": "a", "room_id": "!r"},
+			},
+			wantNames: []string{"matrix", "phone"},
+		},
+		{name: "unknown type", notifiers: []map[string]any{{"type": "pager"}}, wantErr: true},
+		{
+			name: "push targets",
+			notifiers: []map[string]any{
+				{"type": "ntfy", "topic": "octo", "events": []string{"ReleaseEvent"}},
+				{"type": "pushover", "app_token": "a", "user_key": "u"},
+			},
+			wantNames: []string{"ntfy", "pushover"},
+		},
+		{name: "missing settings", notifiers: []map[string]any{{"type": "telegram"}}, wantErr: true},
+		{name: "missing topic", notifiers: []map[string]any{{"type": "ntfy"}}, wantErr: true},
+		{
+			name:      "batched target",
+			notifiers: []map[string]any{{"type": "ntfy", "topic": "octo", "batch": "15m", "quiet_hours": "22-7"}},
+			wantNames: []string{"ntfy"},
+		},
+		{
+			name:      "invalid quiet hours",
+			notifiers: []map[string]any{{"type": "ntfy", "topic": "octo", "quiet_hours": "late"}},
+			wantErr:   true,
+		},
+		{
+			name: "duplicate names",
+			notifiers: []map[string]any{
+				{"type": "telegram", "bot_token": "t", "chat_id": "1"},
+				{"type": "telegram", "bot_token": "t", "chat_id": "2"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			viper.Set("notifiers", tc.notifiers)
+			t.Cleanup(func() { viper.Set("notifiers", nil) })
+			// Act
+			got, err := loadNotifiers()
+			// Assert
+			if tc.wantErr {
+				assertNotNil(t, err)
+				return
+			}
+			assertNoError(t, err)
+			assertEqual(t, strings.Join(sortedKeys(got), ","), strings.Join(tc.wantNames, ","))
+		})
+	}
+}
+
+func TestUnitPushNotifiers(t *testing.T) {
+	testCases := []struct {
+		name     string
+		build    func(url string, hc *http.Client) notifier
+		wantPath string
+		wantAuth string
+		want     map[string]any
+	}{
+		{
+			name: "ntfy",
+			build: func(url string, hc *http.Client) notifier {
+				return &ntfyNotifier{server: url, topic: "octo-alerts", token: "tk_1", hc: hc}
+			},
+			wantPath: "/",
+			wantAuth: "Bearer tk_1",
+			want:     map[string]any{"topic": "octo-alerts", "message": "hello", "click": "https://github.com/octo/repo"},
+		},
+		{
+			name: "pushover",
+			build: func(url string, hc *http.Client) notifier {
+				return &pushoverNotifier{apiURL: url + "/1/messages.json", token: "app", userKey: "usr", hc: hc}
+			},
+			wantPath: "/1/messages.json",
+			want:     map[string]any{"token": "app", "user": "usr", "message": "hello", "url": "https://github.com/octo/repo"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			var gotPath, gotAuth string
+			var gotBody map[string]any
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotPath, gotAuth = r.URL.Path, r.Header.Get("Authorization")
+				json.NewDecoder(r.Body).Decode(&gotBody)
+			}))
+			t.Cleanup(srv.Close)
+			n := tc.build(srv.URL, srv.Client())
+			// Act
+			err := n.notify(context.Background(), notice{Message: "hello", Events: []ghEvent{{Repo: repo{Name: "octo/repo"}}}})
+			// Assert
+			assertNoError(t, err)
+			assertEqual(t, gotPath, tc.wantPath)
+			assertEqual(t, gotAuth, tc.wantAuth)
+			for k, v := range tc.want {
+				assertEqual(t, gotBody[k], v)
+			}
+		})
+	}
+}
+
+type recordingNotifier struct{ got []notice }
+
+func (r *recordingNotifier) notify(_ context.Context, n notice) error {
+	r.got = append(r.got, n)
+	return nil
+}
+
+func TestUnitEventTypeFilter(t *testing.T) {
+	testCases := []struct {
+		name      string
+		events    []ghEvent
+		wantCount int
+	}{
+		{name: "selected type", events: []ghEvent{{Type: "ReleaseEvent"}}, wantCount: 1},
+		{name: "other type", events: []ghEvent{{Type: "WatchEvent"}}, wantCount: 0},
+		{name: "no events", wantCount: 0},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			next := &recordingNotifier{}
+			f := newEventTypeFilter(next, []string{"ReleaseEvent", "PushEvent"})
+			// Act
+			err := f.notify(context.Background(), notice{Message: "m", Events: tc.events})
+			// Assert
+			assertNoError(t, err)
+			assertEqual(t, len(next.got), tc.wantCount)
+		})
+	}
+}
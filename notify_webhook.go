@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"text/template"
+)
+
+const (
+	// defaultWebhookTemplate posts the message and the raw events.
+	defaultWebhookTemplate = `{"message": {{json .Message}}, "events": {{json .Events}}}`
+	// webhookSignatureHeader carries the HMAC-SHA256 of the body, in the
+	// format of GitHub's own webhooks.
+	webhookSignatureHeader = "X-Signature-256"
+)
+
+// webhookFuncs are available in payload templates; json encodes any value,
+// so strings are escaped properly.
+var webhookFuncs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		byt, err := json.Marshal(v)
+		return string(byt), err
+	},
+}
+
+// webhookNotifier sends a user-templated JSON payload to an arbitrary URL.
+type webhookNotifier struct {
+	url     string
+	method  string
+	headers map[string]string
+	tmpl    *template.Template
+	secret  string
+	hc      *http.Client
+}
+
+func newWebhookNotifier(cfg notifierConfig, hc *http.Client) (notifier, error) {
+	if cfg.URL == "" {
+		return nil, errors.New("webhook needs url")
+	}
+	text := cfg.Template
+	if text == "" {
+		text = defaultWebhookTemplate
+	}
+	tmpl, err := template.New("webhook").Funcs(webhookFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parse template: %w", err)
+	}
+	method := strings.ToUpper(cfg.Method)
+	if method == "" {
+		method = http.MethodPost
+	}
+	return &webhookNotifier{
+		url:     cfg.URL,
+		method:  method,
+		headers: cfg.Headers,
+		tmpl:    tmpl,
+		secret:  cfg.Secret,
+		hc:      hc,
+	}, nil
+}
+
+// notify renders the payload, checks it is valid JSON, signs it when a
+// secret is configured and sends it.
+func (w *webhookNotifier) notify(ctx context.Context, n notice) error {
+	var body bytes.Buffer
+	if err := w.tmpl.Execute(&body, n); err != nil {
+		return fmt.Errorf("render payload: %w", err)
+	}
+	if !json.Valid(body.Bytes()) {
+		return errors.New("render payload: the template did not produce valid JSON")
+	}
+	headers := make(map[string]string, len(w.headers)+1)
+	for k, v := range w.headers {
+		headers[k] = v
+	}
+	if w.secret != "" {
+		headers[webhookSignatureHeader] = signPayload(w.secret, body.Bytes())
+	}
+	return send(ctx, w.hc, w.method, w.url, headers, body.Bytes())
+}
+
+// signPayload returns "sha256=<hex HMAC-SHA256 of body>".
+func signPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
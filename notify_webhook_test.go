@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestUnitWebhookNotifier(t *testing.T) {
+	n := notice{
+		Message: `octocat: Starred "octo/repo"`,
+		Events:  []ghEvent{{ID: "1", Type: "WatchEvent", Repo: repo{Name: "octo/repo"}}},
+	}
+	testCases := []struct {
+		name      string
+		cfg       notifierConfig
+		wantBody  string
+		wantSig   bool
+		wantErr   bool
+		wantBuild bool
+	}{
+		{
+			name: "custom template with headers and signature",
+			cfg: notifierConfig{
+				Template: `{"text": {{json .Message}}, "repo": {{json (index .Events 0).Repo.Name}}}`,
+				Secret:   "s3cret",
+				Headers:  map[string]string{"X-Team": "core"},
+			},
+			wantBody: `{"text": "octocat: Starred \"octo/repo\"", "repo": "octo/repo"}`,
+			wantSig:  true,
+		},
+		{
+			name:    "template producing invalid JSON",
+			cfg:     notifierConfig{Template: `{"text": {{.Message}}}`},
+			wantErr: true,
+		},
+		{
+			name:      "invalid template",
+			cfg:       notifierConfig{Template: `{{.Message`},
+			wantBuild: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			var gotBody, gotSig, gotTeam string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				byt, _ := io.ReadAll(r.Body)
+				gotBody, gotSig, gotTeam = string(byt), r.Header.Get(webhookSignatureHeader), r.Header.Get("X-Team")
+			}))
+			t.Cleanup(srv.Close)
+			tc.cfg.URL = srv.URL
+			wh, err := newWebhookNotifier(tc.cfg, srv.Client())
+			if tc.wantBuild {
+				assertNotNil(t, err)
+				return
+			}
+			// Act
+			err = wh.notify(context.Background(), n)
+			// Assert
+			if tc.wantErr {
+				assertNotNil(t, err)
+				return
+			}
+			assertNoError(t, err)
+			assertEqual(t, gotBody, tc.wantBody)
+			assertEqual(t, gotTeam, "core")
+			assertEqual(t, gotSig, signPayload("s3cret", []byte(tc.wantBody)))
+		})
+	}
+}
+
+func TestUnitWebhookDefaultTemplate(t *testing.T) {
+	// Arrange
+	var gotBody string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		byt, _ := io.ReadAll(r.Body)
+		gotBody = string(byt)
+		assertEqual(t, r.Header.Get(webhookSignatureHeader), "")
+	}))
+	t.Cleanup(srv.Close)
+	wh, _ := newWebhookNotifier(notifierConfig{URL: srv.URL}, srv.Client())
+	// Act
+	err := wh.notify(context.Background(), notice{Message: "hi"})
+	// Assert
+	assertNoError(t, err)
+	assertEqual(t, gotBody, `{"message": "hi", "events": null}`)
+}
+
+func TestUnitSignPayload(t *testing.T) {
+	// Act
+	got := signPayload("It's a Secret to Everybody", []byte("Hello, World!"))
+	// Assert
+	assertEqual(t, got, "sha256=757107ea0eb2509fc211221cce984b8a37570b6d7586c22c46f4379c8b043e17")
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"sync"
+	"time"
+)
+
+type (
+	// orgRepo is a repository listed by the organization repositories API.
+	orgRepo struct {
+		FullName   string   `json:"full_name"`
+		Visibility string   `json:"visibility"`
+		Topics     []string `json:"topics"`
+		Archived   bool     `json:"archived"`
+	}
+	// repoTracker tracks a changing set of repositories, listing them again
+	// every refresh so that the repositories added later are picked up.
+	repoTracker struct {
+		list     func() ([]string, error)
+		eventsOf func(name string) ([]ghEvent, error)
+		workers  int
+		budget   *rateBudget
+		refresh  time.Duration
+		clock    clock
+		names    []string
+		listed   time.Time
+	}
+)
+
+const (
+	// orgPerPage is the page size of the organization repositories API.
+	orgPerPage = 100
+	// orgBudgetReserve is the number of API requests left untouched when
+	// tracking the repositories of an organization.
+	orgBudgetReserve = 50
+)
+
+// runOrg lists the repositories of an organization and, unless --list is
+// set, their recent events fetched concurrently.
+func runOrg(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("org", flag.ContinueOnError)
+	topic := flags.String("topic", "", "only the repositories with this topic")
+	visibility := flags.String("visibility", "all", "only the repositories of this visibility: all, public, "+
+		"private or internal")
+	pages := flags.Int("pages", 10, "maximum number of pages of 100 repositories to read")
+	workers := flags.Int("concurrency", 4, "number of repositories fetched at once")
+	list := flags.Bool("list", false, "list the repositories without their events")
+	output := flags.String("output", "text", "output format: text or json")
+	strict := flags.Bool("strict-budget", false, "fail instead of asking when the run would exceed the rate limit")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: go-github-activity org [flags] <org>")
+	}
+	if *output != "text" && *output != "json" {
+		return fmt.Errorf("unknown output format %q", *output)
+	}
+	if err := loadConfig(); err != nil {
+		return err
+	}
+	org := flags.Arg(0)
+	f, err := newRepoFetcher()
+	if err != nil {
+		return err
+	}
+	all, err := fetchOrgRepos(f.hc, f.base, org, *pages)
+	if err != nil {
+		return err
+	}
+	names := filterOrgRepos(all, *topic, *visibility)
+	if *list {
+		return writeRepoNames(stdout, names)
+	}
+	return renderTracked(stdout, *output, names, f, *workers, *strict)
+}
+
+// writeRepoNames prints one repository name per line.
+func writeRepoNames(w io.Writer, names []string) error {
+	for _, name := range names {
+		if _, err := fmt.Fprintln(w, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renderTracked fetches and renders the events of the tracked
+// repositories once their cost fits the rate limit, see checkRunBudget,
+// warning when some were skipped for the rate limit.
+func renderTracked(stdout io.Writer, output string, names []string, f *repoFetcher, workers int, strict bool) error {
+	if err := checkRunBudget(f, len(names), strict); err != nil {
+		return err
+	}
+	events, skipped, err := trackRepos(names, f.repoEvents, workers, f.hc.budget, f.hc.clock)
+	if err != nil {
+		return err
+	}
+	if skipped > 0 {
+		err := infof(os.Stderr, "partial: %d of %d repositories skipped to preserve the rate limit\n", skipped, len(names))
+		if err != nil {
+			return err
+		}
+	}
+	cat := lookupCatalog(resolveLang("", os.Getenv("LANG")))
+	if output == "json" {
+		return jsonRenderer{cat: cat}.render(stdout, events)
+	}
+	return textRenderer{cat: cat, icons: loadIcons(false, nil)}.render(stdout, events)
+}
+
+// fetchOrgRepos lists the repositories of an organization, up to the given
+// number of pages.
+func fetchOrgRepos(hc *client, base, org string, pages int) ([]orgRepo, error) {
+	return fetchRepoList(hc, fmt.Sprintf("%s/orgs/%s/repos", base, url.PathEscape(org)), "repositories of "+org, pages)
+}
+
+// fetchRepoList reads a paginated list of repositories, up to the given
+// number of pages; what describes the list in progress and errors.
+func fetchRepoList(hc *client, u, what string, pages int) ([]orgRepo, error) {
+	var all []orgRepo
+	p := newProgress(what, hc.budget)
+	defer p.done()
+	for page := 1; page <= pages; page++ {
+		var repos []orgRepo
+		if err := fetchJSON(hc, fmt.Sprintf("%s?per_page=%d&page=%d", u, orgPerPage, page), &repos); err != nil {
+			return nil, fmt.Errorf("fetch %s: %w", what, err)
+		}
+		all = append(all, repos...)
+		p.add(len(repos))
+		if len(repos) < orgPerPage {
+			break
+		}
+	}
+	return all, nil
+}
+
+// filterOrgRepos returns the names of the repositories that are not
+// archived and match the topic and visibility; empty or "all" match any.
+func filterOrgRepos(repos []orgRepo, topic, visibility string) []string {
+	var names []string
+	for _, r := range repos {
+		switch {
+		case r.Archived:
+		case topic != "" && !slices.Contains(r.Topics, topic):
+		case visibility != "" && visibility != "all" && r.Visibility != visibility:
+		default:
+			names = append(names, r.FullName)
+		}
+	}
+	return names
+}
+
+// repoEvents returns the recent events of a repository. The client is
+// copied since fetchJSON sets its URL and repositories are fetched
+// concurrently; the copies share the rate-limit budget.
+func (f *repoFetcher) repoEvents(name string) ([]ghEvent, error) {
+	u, err := f.repoURL(name, "events")
+	if err != nil {
+		return nil, err
+	}
+	hc := *f.hc
+	events, err := fetchGitHubResponse(&hc, u+"?per_page=100")
+	if err != nil {
+		return nil, fmt.Errorf("fetch events of %s: %w", name, err)
+	}
+	return events, nil
+}
+
+// trackRepos fetches the events of the repositories on up to workers
+// goroutines and merges them, newest first. It stops fetching when the
+// shared budget falls to the reserve and reports the number of
+// repositories skipped. Repositories that no longer exist are ignored.
+func trackRepos(
+	names []string, eventsOf func(name string) ([]ghEvent, error), workers int, budget *rateBudget, c clock,
+) ([]ghEvent, int, error) {
+	lists := make([][]ghEvent, len(names))
+	errs := make([]error, len(names))
+	var (
+		mu      sync.Mutex
+		skipped int
+	)
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range max(1, min(workers, len(names))) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if budget.below(orgBudgetReserve, c.Now()) {
+					mu.Lock()
+					skipped++
+					mu.Unlock()
+					continue
+				}
+				lists[i], errs[i] = eventsOf(names[i])
+			}
+		}()
+	}
+	for i := range names {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil && !isStatus(err, http.StatusNotFound) {
+			return nil, 0, err
+		}
+	}
+	return mergeEvents(lists...), skipped, nil
+}
+
+// events lists the repositories again when the refresh delay has passed,
+// and returns their events. A failed listing keeps the previous one.
+func (t *repoTracker) events() ([]ghEvent, error) {
+	if t.listed.IsZero() || t.clock.Now().Sub(t.listed) >= t.refresh {
+		names, err := t.list()
+		switch {
+		case err == nil:
+			t.names, t.listed = names, t.clock.Now()
+		case t.listed.IsZero():
+			return nil, err
+		default:
+			log.Printf("refresh repositories: %v", err)
+		}
+	}
+	events, skipped, err := trackRepos(t.names, t.eventsOf, t.workers, t.budget, t.clock)
+	if skipped > 0 {
+		log.Printf("%d of %d repositories skipped to preserve the rate limit", skipped, len(t.names))
+	}
+	return events, err
+}
+
+// watchTracked polls the tracked repositories every interval and delivers
+// new events to the notifiers selected by the routing rules, like watch.
+func watchTracked(t *repoTracker, interval time.Duration, stdout io.Writer) error {
+	return watchLive(&poller{fetch: t.events}, func() (watchSettings, error) {
+		return loadWatchSettings("rules", interval)
+	}, stdout)
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestUnitFilterOrgRepos(t *testing.T) {
+	repos := []orgRepo{
+		{FullName: "octo/api", Visibility: "public", Topics: []string{"go", "backend"}},
+		{FullName: "octo/web", Visibility: "private", Topics: []string{"frontend"}},
+		{FullName: "octo/old", Visibility: "public", Topics: []string{"go"}, Archived: true},
+		{FullName: "octo/ops", Visibility: "internal", Topics: []string{"go"}},
+	}
+	testCases := []struct {
+		name       string
+		topic      string
+		visibility string
+		want       string
+	}{
+		{name: "all", visibility: "all", want: "[octo/api octo/web octo/ops]"},
+		{name: "topic", topic: "go", want: "[octo/api octo/ops]"},
+		{name: "visibility", visibility: "private", want: "[octo/web]"},
+		{name: "both", topic: "go", visibility: "internal", want: "[octo/ops]"},
+		{name: "none", topic: "rust", want: "[]"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			got := filterOrgRepos(repos, tc.topic, tc.visibility)
+			// Assert
+			assertEqual(t, fmt.Sprint(got), tc.want)
+		})
+	}
+}
+
+func TestUnitFetchOrgRepos(t *testing.T) {
+	// Arrange
+	var pages []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assertEqual(t, r.URL.Path, "/orgs/octo/repos")
+		page := r.URL.Query().Get("page")
+		pages = append(pages, page)
+		n := orgPerPage
+		if page == "2" {
+			n = 1
+		}
+		repos := make([]orgRepo, n)
+		for i := range repos {
+			repos[i].FullName = fmt.Sprintf("octo/r%s-%d", page, i)
+		}
+		json.NewEncoder(w).Encode(repos)
+	}))
+	t.Cleanup(srv.Close)
+	// Act
+	got, err := fetchOrgRepos(newClient(""), srv.URL, "octo", 10)
+	// Assert
+	assertNoError(t, err)
+	assertEqual(t, len(got), orgPerPage+1)
+	assertEqual(t, got[orgPerPage].FullName, "octo/r2-0")
+	assertEqual(t, fmt.Sprint(pages), "[1 2]")
+}
+
+func TestUnitTrackRepos(t *testing.T) {
+	now := time.Now()
+	events := map[string][]ghEvent{
+		"octo/api": {{ID: "3", CreatedAt: now}, {ID: "1", CreatedAt: now.Add(-2 * time.Hour)}},
+		"octo/web": {{ID: "2", CreatedAt: now.Add(-time.Hour)}},
+	}
+	testCases := []struct {
+		name        string
+		names       []string
+		budget      *rateBudget
+		want        string
+		wantSkipped int
+		wantErr     bool
+	}{
+		{name: "merged newest first", names: []string{"octo/api", "octo/web"}, want: "[3 2 1]"},
+		{name: "deleted repository", names: []string{"octo/gone", "octo/web"}, want: "[2]"},
+		{name: "failure", names: []string{"octo/api", "octo/fail"}, wantErr: true},
+		{
+			name:        "reserve reached",
+			names:       []string{"octo/api", "octo/web"},
+			budget:      &rateBudget{known: true, remaining: orgBudgetReserve, reset: now.Add(time.Hour)},
+			want:        "[]",
+			wantSkipped: 2,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			eventsOf := func(name string) ([]ghEvent, error) {
+				switch name {
+				case "octo/gone":
+					return nil, &apiError{StatusCode: http.StatusNotFound}
+				case "octo/fail":
+					return nil, errors.New("boom")
+				}
+				return events[name], nil
+			}
+			// Act
+			got, skipped, err := trackRepos(tc.names, eventsOf, 2, tc.budget, systemClock{})
+			// Assert
+			if tc.wantErr {
+				assertNotNil(t, err)
+				return
+			}
+			assertNoError(t, err)
+			ids := []string{}
+			for _, ev := range got {
+				ids = append(ids, ev.ID)
+			}
+			assertEqual(t, fmt.Sprint(ids), tc.want)
+			assertEqual(t, skipped, tc.wantSkipped)
+		})
+	}
+}
+
+func TestUnitRepoTrackerEvents(t *testing.T) {
+	// Arrange
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	clk := &fakeClock{now: now}
+	listings := [][]string{{"octo/api"}, {"octo/api", "octo/web"}}
+	var calls int
+	var listErr error
+	tr := &repoTracker{
+		list: func() ([]string, error) {
+			if listErr != nil {
+				return nil, listErr
+			}
+			calls++
+			return listings[min(calls, len(listings))-1], nil
+		},
+		eventsOf: func(name string) ([]ghEvent, error) {
+			return []ghEvent{{ID: name, CreatedAt: now}}, nil
+		},
+		workers: 2,
+		refresh: time.Hour,
+		clock:   clk,
+	}
+	ids := func(events []ghEvent) string {
+		var out []string
+		for _, ev := range events {
+			out = append(out, ev.ID)
+		}
+		return fmt.Sprint(out)
+	}
+	// Act
+	first, err := tr.events()
+	assertNoError(t, err)
+	clk.advance(30 * time.Minute)
+	cached, err := tr.events()
+	assertNoError(t, err)
+	clk.advance(30 * time.Minute)
+	refreshed, err := tr.events()
+	assertNoError(t, err)
+	clk.advance(time.Hour)
+	listErr = errors.New("boom")
+	stale, err := tr.events()
+	// Assert
+	assertNoError(t, err)
+	assertEqual(t, ids(first), "[octo/api]")
+	assertEqual(t, ids(cached), "[octo/api]")
+	assertEqual(t, ids(refreshed), "[octo/api octo/web]")
+	assertEqual(t, ids(stale), "[octo/api octo/web]")
+	assertEqual(t, calls, 2)
+}
+
+func TestUnitRepoTrackerFirstListingFails(t *testing.T) {
+	// Arrange
+	tr := &repoTracker{
+		list:    func() ([]string, error) { return nil, errors.New("boom") },
+		refresh: time.Hour,
+		clock:   systemClock{},
+	}
+	// Act
+	_, err := tr.events()
+	// Assert
+	assertNotNil(t, err)
+}
+
+func TestUnitTrackReposPooled(t *testing.T) {
+	testCases := []struct {
+		name        string
+		remaining   int
+		wantSkipped int
+	}{
+		{name: "pool above the reserve", remaining: orgBudgetReserve, wantSkipped: 0},
+		{name: "pool at the reserve", remaining: orgBudgetReserve / 2, wantSkipped: 2},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			reset := strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(tc.remaining))
+				w.Header().Set("X-RateLimit-Reset", reset)
+				w.Write([]byte("[]"))
+			}))
+			t.Cleanup(srv.Close)
+			hc := newClient("")
+			hc.pool = newTokenPool([]string{"a", "b"})
+			hc.budget = &hc.pool.total
+			for range hc.pool.tokens {
+				_, err := fetchGitHubResponse(hc, srv.URL)
+				assertNoError(t, err)
+			}
+			eventsOf := func(string) ([]ghEvent, error) { return fetchGitHubResponse(hc, srv.URL) }
+			// Act
+			_, skipped, err := trackRepos([]string{"octo/api", "octo/web"}, eventsOf, 1, hc.budget, hc.clock)
+			// Assert
+			assertNoError(t, err)
+			assertEqual(t, skipped, tc.wantSkipped)
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// pdfLine is a line of text laid out with one of the standard fonts.
+type pdfLine struct {
+	font string
+	size float64
+	text string
+}
+
+// A4 page layout in points.
+const (
+	pdfWidth   = 595.0
+	pdfHeight  = 842.0
+	pdfMargin  = 56.0
+	pdfLeading = 1.4
+)
+
+// pdfWinAnsi maps the non-Latin-1 characters of the reports to their
+// WinAnsiEncoding code; other characters outside Latin-1 become "?".
+var pdfWinAnsi = map[rune]byte{'–': 0x96, '—': 0x97, '•': 0x95, '…': 0x85, '’': 0x92, '█': '#', '★': '*'}
+
+// layoutPDF styles Markdown-like text: "#" headings in bold, everything
+// else in a monospace font so tables stay aligned.
+func layoutPDF(text string) []pdfLine {
+	var lines []pdfLine
+	for _, l := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		switch {
+		case strings.HasPrefix(l, "# "):
+			lines = append(lines, pdfLine{"F2", 16, strings.TrimPrefix(l, "# ")})
+		case strings.HasPrefix(l, "## "):
+			lines = append(lines, pdfLine{"F2", 12, strings.TrimPrefix(l, "## ")})
+		default:
+			lines = append(lines, pdfLine{"F1", 9, l})
+		}
+	}
+	return lines
+}
+
+// pdfString encodes s as a PDF literal string in WinAnsiEncoding.
+func pdfString(s string) string {
+	var b strings.Builder
+	b.WriteByte('(')
+	for _, r := range s {
+		c, ok := pdfWinAnsi[r]
+		switch {
+		case ok:
+		case r < 0x100:
+			c = byte(r)
+		default:
+			c = '?'
+		}
+		if c == '(' || c == ')' || c == '\\' {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(c)
+	}
+	b.WriteByte(')')
+	return b.String()
+}
+
+// writePDF renders text as a paginated A4 document using the standard
+// Courier and Helvetica-Bold fonts, so nothing needs embedding.
+func writePDF(w io.Writer, text string) error {
+	var pages []string
+	var page strings.Builder
+	y := pdfHeight - pdfMargin
+	for _, l := range layoutPDF(text) {
+		if y-l.size*pdfLeading < pdfMargin {
+			pages, y = append(pages, page.String()), pdfHeight-pdfMargin
+			page.Reset()
+		}
+		y -= l.size * pdfLeading
+		if l.text != "" {
+			fmt.Fprintf(&page, "BT /%s %g Tf %g %g Td %s Tj ET\n", l.font, l.size, pdfMargin, y, pdfString(l.text))
+		}
+	}
+	pages = append(pages, page.String())
+
+	// Objects: 1 catalog, 2 page tree, 3-4 fonts, then a page and its
+	// content stream per page.
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>",
+	}
+	kids := make([]string, 0, len(pages))
+	for _, content := range pages {
+		pageID := len(objects) + 1
+		kids = append(kids, fmt.Sprintf("%d 0 R", pageID))
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %g %g] "+
+				"/Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>", pdfWidth, pdfHeight, pageID+1),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", len(content), content),
+		)
+	}
+	objects[1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages))
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("write PDF: %w", err)
+	}
+	return nil
+}
+
+// reportOutput returns the writer a text report is printed to, and a
+// function to call once done: with pdf it renders the buffered report as a
+// PDF document to stdout.
+func reportOutput(stdout io.Writer, pdf bool) (io.Writer, func() error) {
+	if !pdf {
+		return stdout, func() error { return nil }
+	}
+	var buf bytes.Buffer
+	return &buf, func() error { return writePDF(stdout, buf.String()) }
+}
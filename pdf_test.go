@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestUnitPDFString(t *testing.T) {
+	testCases := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "ascii", in: "commits: 3", want: "(commits: 3)"},
+		{name: "escaped", in: `a (b) \c`, want: `(a \(b\) \\c)`},
+		{name: "latin-1", in: "créé ± 1", want: "(cr\xe9\xe9 \xb1 1)"},
+		{name: "win-ansi", in: "2025-03-25 – 2025-03-31", want: "(2025-03-25 \x96 2025-03-31)"},
+		{name: "unsupported", in: "日本", want: "(??)"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			got := pdfString(tc.in)
+			// Assert
+			assertEqual(t, got, tc.want)
+		})
+	}
+}
+
+func TestUnitWritePDF(t *testing.T) {
+	testCases := []struct {
+		name      string
+		text      string
+		wantPages int
+	}{
+		{name: "one page", text: "# Digest\n\n## Totals\n\n- commits: 3\n", wantPages: 1},
+		{name: "several pages", text: strings.Repeat("line\n", 150), wantPages: 3},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			var buf bytes.Buffer
+			// Act
+			err := writePDF(&buf, tc.text)
+			// Assert
+			assertNoError(t, err)
+			out := buf.String()
+			assertEqual(t, strings.HasPrefix(out, "%PDF-1.4\n"), true)
+			assertEqual(t, strings.HasSuffix(out, "%%EOF\n"), true)
+			assertEqual(t, strings.Contains(out, "/Count "+strconv.Itoa(tc.wantPages)+" >>"), true)
+			// Every xref offset points at its object.
+			offsets := regexp.MustCompile(`(\d{10}) 00000 n`).FindAllStringSubmatch(out, -1)
+			for i, m := range offsets {
+				off, _ := strconv.Atoi(m[1])
+				assertEqual(t, strings.HasPrefix(out[off:], strconv.Itoa(i+1)+" 0 obj"), true)
+			}
+		})
+	}
+}
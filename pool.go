@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+type (
+	// tokenPool rotates the requests across several tokens, skipping the
+	// revoked ones and those out of rate limit.
+	tokenPool struct {
+		mu     sync.Mutex
+		tokens []*pooledToken
+		next   int
+		// total is the budget of the usable tokens together, the budget of
+		// the clients of the pool.
+		total rateBudget
+	}
+	// poolLimitError reports that every token of a pool is rate limited.
+	poolLimitError struct {
+		reset time.Time
+	}
+	// pooledToken is a token of a pool with its own rate-limit budget.
+	pooledToken struct {
+		token   string
+		budget  rateBudget
+		revoked bool
+	}
+)
+
+var (
+	// sharedPoolOnce builds sharedPool from the configuration on first use,
+	// so that every client of the process tracks the same budgets.
+	sharedPoolOnce sync.Once
+	sharedPool     *tokenPool
+)
+
+// newTokenPool returns a pool of the non-empty, distinct tokens, or nil when
+// there are fewer than two.
+func newTokenPool(tokens []string) *tokenPool {
+	p := &tokenPool{}
+	seen := map[string]bool{}
+	for _, tok := range tokens {
+		if tok != "" && !seen[tok] {
+			seen[tok] = true
+			p.tokens = append(p.tokens, &pooledToken{token: tok})
+		}
+	}
+	if len(p.tokens) < 2 {
+		return nil
+	}
+	return p
+}
+
+// configuredClient returns a client for the configured token, rotating
+// across github_tokens when several are configured.
+func configuredClient() *client {
+	hc := newClient(viper.GetString("github_token"))
+	sharedPoolOnce.Do(func() {
+		tokens := append([]string{viper.GetString("github_token")}, viper.GetStringSlice("github_tokens")...)
+		sharedPool = newTokenPool(tokens)
+	})
+	hc.pool = sharedPool
+	hc.budget = &rateBudget{}
+	if hc.pool != nil {
+		hc.budget = &hc.pool.total
+	}
+	return hc
+}
+
+// usable returns the tokens of the pool that are not revoked.
+func (p *tokenPool) usable() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var tokens []string
+	for _, t := range p.tokens {
+		if !t.revoked {
+			tokens = append(tokens, t.token)
+		}
+	}
+	return tokens
+}
+
+// pick returns the next usable token in turn. When every token is revoked
+// or out of rate limit, it fails with the earliest reset time.
+func (p *tokenPool) pick(now time.Time) (*pooledToken, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var reset time.Time
+	for range p.tokens {
+		t := p.tokens[p.next]
+		p.next = (p.next + 1) % len(p.tokens)
+		if t.revoked {
+			continue
+		}
+		at, exhausted := t.budget.exhausted(now)
+		if !exhausted {
+			return t, nil
+		}
+		if reset.IsZero() || at.Before(reset) {
+			reset = at
+		}
+	}
+	if reset.IsZero() {
+		return nil, errTokensRevoked
+	}
+	return nil, &poolLimitError{reset: reset}
+}
+
+func (e *poolLimitError) Error() string {
+	return fmt.Sprintf("every token of the pool is rate limited until %s", e.reset.Format(time.TimeOnly))
+}
+
+// release records the outcome of a request made with a token, and reports
+// whether the request should be retried at once with another token: after a 401
+// the token is revoked, after a 403 or 429 it is out of rate limit.
+func (p *tokenPool) release(t *pooledToken, res *http.Response, now time.Time) bool {
+	t.budget.update(res.Header)
+	defer p.tally()
+	switch res.StatusCode {
+	case http.StatusUnauthorized:
+		p.mu.Lock()
+		t.revoked = true
+		p.mu.Unlock()
+		return true
+	case http.StatusForbidden, http.StatusTooManyRequests:
+		_, exhausted := t.budget.exhausted(now)
+		return exhausted
+	}
+	return false
+}
+
+// tally sets the total budget to the requests left with the usable tokens,
+// known once every one is, until the earliest reset.
+func (p *tokenPool) tally() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	known, remaining, reset := true, 0, time.Time{}
+	for _, t := range p.tokens {
+		if t.revoked {
+			continue
+		}
+		t.budget.mu.Lock()
+		known = known && t.budget.known
+		remaining += t.budget.remaining
+		if reset.IsZero() || t.budget.reset.Before(reset) {
+			reset = t.budget.reset
+		}
+		t.budget.mu.Unlock()
+	}
+	p.total.mu.Lock()
+	defer p.total.mu.Unlock()
+	p.total.known, p.total.remaining, p.total.reset = known, remaining, reset
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestUnitNewTokenPool(t *testing.T) {
+	testCases := []struct {
+		name   string
+		tokens []string
+		want   int
+	}{
+		{name: "several", tokens: []string{"a", "b", "", "a", "c"}, want: 3},
+		{name: "single", tokens: []string{"a", "", "a"}, want: 0},
+		{name: "none", tokens: nil, want: 0},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			p := newTokenPool(tc.tokens)
+			// Assert
+			got := 0
+			if p != nil {
+				got = len(p.tokens)
+			}
+			assertEqual(t, got, tc.want)
+		})
+	}
+}
+
+func TestUnitTokenPoolPick(t *testing.T) {
+	// Arrange
+	now := time.Now()
+	p := newTokenPool([]string{"a", "b", "c"})
+	p.tokens[1].revoked = true
+	h := http.Header{}
+	h.Set("X-RateLimit-Remaining", "0")
+	h.Set("X-RateLimit-Reset", strconv.FormatInt(now.Add(time.Hour).Unix(), 10))
+	// Act
+	first, _ := p.pick(now)
+	second, _ := p.pick(now)
+	p.tokens[0].budget.update(h)
+	p.tokens[2].budget.update(h)
+	_, err := p.pick(now)
+	// Assert
+	assertEqual(t, first.token, "a")
+	assertEqual(t, second.token, "c")
+	assertNotNil(t, err)
+}
+
+func TestUnitPooledClientFailover(t *testing.T) {
+	// Arrange
+	reset := strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+	var auths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		auths = append(auths, auth)
+		switch auth {
+		case "Bearer revoked":
+			w.WriteHeader(http.StatusUnauthorized)
+		case "Bearer limited":
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", reset)
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			w.Write([]byte("[]"))
+		}
+	}))
+	t.Cleanup(srv.Close)
+	hc := newClient("")
+	hc.pool = newTokenPool([]string{"revoked", "limited", "valid"})
+	// Act
+	_, err := fetchGitHubResponse(hc, srv.URL)
+	_, again := fetchGitHubResponse(hc, srv.URL)
+	// Assert
+	assertNoError(t, err)
+	assertNoError(t, again)
+	assertEqual(t, len(auths), 4)
+	assertEqual(t, auths[2], "Bearer valid")
+	assertEqual(t, auths[3], "Bearer valid")
+}
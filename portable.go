@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+// gzipMagic starts every gzip stream.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// runExport writes the archive of a user as portable JSON Lines, gzipped by
+// default, to a file or the standard output. An encrypted archive is
+// exported in clear.
+func runExport(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("archive export", flag.ContinueOnError)
+	format := flags.String("format", "jsonl.gz", "export format: jsonl or jsonl.gz")
+	file := flags.String("file", "-", "file to write, - for the standard output")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: go-github-activity archive export [flags] <username>")
+	}
+	if *format != "jsonl" && *format != "jsonl.gz" {
+		return fmt.Errorf("unknown export format %q", *format)
+	}
+	if err := loadConfig(); err != nil {
+		return err
+	}
+	a, err := openArchive(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	w := stdout
+	if *file != "-" {
+		f, err := os.OpenFile(*file, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+		if err != nil {
+			return fmt.Errorf("create export file: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+	n, err := exportArchive(w, a, *format == "jsonl.gz")
+	if err != nil {
+		return err
+	}
+	if *file == "-" {
+		return nil
+	}
+	return infof(stdout, "exported %d events to %s\n", n, *file)
+}
+
+// exportArchive streams the archived events to w as JSON Lines and returns
+// their number.
+func exportArchive(w io.Writer, a *archive, compress bool) (int, error) {
+	var zw *gzip.Writer
+	if compress {
+		zw = gzip.NewWriter(w)
+		w = zw
+	}
+	bw := bufio.NewWriter(w)
+	n := 0
+	err := a.scan(archiveBatchSize(), func(batch []ghEvent) error {
+		for _, ev := range batch {
+			byt, err := json.Marshal(ev)
+			if err != nil {
+				return fmt.Errorf("encode event %s: %w", ev.ID, err)
+			}
+			bw.Write(append(byt, '\n'))
+			n++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	if err := bw.Flush(); err != nil {
+		return 0, fmt.Errorf("write the export: %w", err)
+	}
+	if zw != nil {
+		if err := zw.Close(); err != nil {
+			return 0, fmt.Errorf("write the export: %w", err)
+		}
+	}
+	return n, nil
+}
+
+// runImport adds the events of an export, gzipped or not, to the archive of
+// a user, skipping those already archived.
+func runImport(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("archive import", flag.ContinueOnError)
+	file := flags.String("file", "-", "export to read, - for the standard input")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: go-github-activity archive import [flags] <username>")
+	}
+	if err := loadConfig(); err != nil {
+		return err
+	}
+	a, err := openArchive(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	var r io.Reader = os.Stdin
+	if *file != "-" {
+		f, err := os.Open(*file)
+		if err != nil {
+			return fmt.Errorf("open export file: %w", err)
+		}
+		defer f.Close()
+		r = f
+	}
+	imported, err := importArchive(r, a)
+	if err != nil {
+		return err
+	}
+	return infof(stdout, "imported %d new events\n", imported)
+}
+
+// importArchive appends the events of an export read from r to the archive
+// and returns the number of new events.
+func importArchive(r io.Reader, a *archive) (int, error) {
+	br := bufio.NewReader(r)
+	if magic, _ := br.Peek(len(gzipMagic)); bytes.Equal(magic, gzipMagic) {
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return 0, fmt.Errorf("read the export: %w", err)
+		}
+		defer zr.Close()
+		r = zr
+	} else {
+		r = br
+	}
+	seen, err := a.ids()
+	if err != nil {
+		return 0, err
+	}
+	imported := 0
+	err = (&archive{}).scanEvents(r, archiveBatchSize(), func(batch []ghEvent) error {
+		added, err := a.appendNew(seen, batch)
+		imported += len(added)
+		return err
+	})
+	return imported, err
+}
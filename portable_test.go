@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUnitExportImportArchive(t *testing.T) {
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	events := []ghEvent{
+		{ID: "1", Type: "PushEvent", Repo: repo{Name: "octocat/hello"}, CreatedAt: now},
+		{ID: "2", Type: "WatchEvent", Repo: repo{Name: "octocat/hello"}, CreatedAt: now.Add(time.Hour)},
+	}
+	testCases := []struct {
+		name     string
+		compress bool
+		gzipped  bool
+	}{
+		{name: "jsonl", compress: false},
+		{name: "jsonl.gz", compress: true, gzipped: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			dir := t.TempDir()
+			src := &archive{path: filepath.Join(dir, "src.jsonl")}
+			dst := &archive{path: filepath.Join(dir, "dst.jsonl")}
+			dst.add(events[:1])
+			src.add(events)
+			var b bytes.Buffer
+			// Act
+			exported, errExport := exportArchive(&b, src, tc.compress)
+			export := b.Bytes()
+			imported, errImport := importArchive(bytes.NewReader(export), dst)
+			again, errAgain := importArchive(bytes.NewReader(export), dst)
+			got, errLoad := dst.load()
+			// Assert
+			assertNoError(t, errExport)
+			assertNoError(t, errImport)
+			assertNoError(t, errAgain)
+			assertNoError(t, errLoad)
+			assertEqual(t, exported, 2)
+			assertEqual(t, bytes.HasPrefix(export, gzipMagic), tc.gzipped)
+			assertEqual(t, imported, 1)
+			assertEqual(t, again, 0)
+			assertEqual(t, len(got), 2)
+			assertEqual(t, got[0].ID, "2")
+		})
+	}
+}
+
+func TestUnitImportArchiveInvalid(t *testing.T) {
+	// Arrange
+	a := &archive{path: filepath.Join(t.TempDir(), "octocat.jsonl")}
+	// Act
+	_, err := importArchive(strings.NewReader("{\"id\":\"1\"}\nnot json\n"), a)
+	// Assert
+	assertNotNil(t, err)
+}
+
+func TestUnitRunArchiveUnknown(t *testing.T) {
+	// Act
+	err := runArchive([]string{"vacuum"}, &bytes.Buffer{})
+	// Assert
+	assertNotNil(t, err)
+	assertEqual(t, strings.Contains(err.Error(), "compact|export|import|keygen|prune"), true)
+}
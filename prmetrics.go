@@ -0,0 +1,160 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+type (
+	// prDetails holds the pull request fields used by the size and merge
+	// time metrics.
+	prDetails struct {
+		Additions    int        `json:"additions"`
+		Deletions    int        `json:"deletions"`
+		ChangedFiles int        `json:"changed_files"`
+		CreatedAt    time.Time  `json:"created_at"`
+		MergedAt     *time.Time `json:"merged_at"`
+	}
+	// prMetric summarizes the pull requests of one repository.
+	prMetric struct {
+		repo        string
+		prs         int
+		merged      int
+		medianSize  int
+		medianFiles int
+		medianMerge time.Duration
+	}
+	// issueRef identifies an issue or a pull request across its events.
+	issueRef struct {
+		repo   string
+		number int
+	}
+)
+
+// prBudgetReserve is the number of API requests left untouched by the pull
+// request metrics, so that they never exhaust the rate limit of the token.
+const prBudgetReserve = 50
+
+// pullRequest returns the size and merge date of a pull request.
+func (f *repoFetcher) pullRequest(name string, number int) (prDetails, error) {
+	return cached(f.cache, "pulls/"+name+"/"+strconv.Itoa(number), func() (prDetails, error) {
+		u, err := f.repoURL(name, "pulls", strconv.Itoa(number))
+		if err != nil {
+			return prDetails{}, err
+		}
+		var pr prDetails
+		if err := fetchJSON(f.hc, u, &pr); err != nil {
+			return prDetails{}, fmt.Errorf("fetch pull request %s#%d: %w", name, number, err)
+		}
+		return pr, nil
+	})
+}
+
+// prMetrics fetches the details of the pull requests of the events and
+// summarizes them per repository. It stops fetching when the budget falls
+// to the reserve and reports the number of pull requests skipped.
+func prMetrics(
+	events []ghEvent, detailsOf func(repo string, number int) (prDetails, error), budget *rateBudget, now time.Time,
+) ([]prMetric, int, error) {
+	var keys []issueRef
+	seen := map[issueRef]bool{}
+	for _, ev := range events {
+		if ev.Type != "PullRequestEvent" || ev.Payload.PullRequest == nil {
+			continue
+		}
+		k := issueRef{repo: ev.Repo.Name, number: ev.Payload.PullRequest.Number}
+		if !seen[k] {
+			seen[k] = true
+			keys = append(keys, k)
+		}
+	}
+	byRepo := map[string][]prDetails{}
+	for i, k := range keys {
+		if budget.below(prBudgetReserve, now) {
+			return summarizePRs(byRepo), len(keys) - i, nil
+		}
+		pr, err := detailsOf(k.repo, k.number)
+		switch {
+		case err == nil:
+			byRepo[k.repo] = append(byRepo[k.repo], pr)
+		case !isStatus(err, http.StatusNotFound):
+			return nil, 0, err
+		}
+	}
+	return summarizePRs(byRepo), 0, nil
+}
+
+// summarizePRs computes the median size, changed files and merge time of
+// the pull requests of each repository, by repository name.
+func summarizePRs(byRepo map[string][]prDetails) []prMetric {
+	out := make([]prMetric, 0, len(byRepo))
+	for _, name := range sortedKeys(byRepo) {
+		prs := byRepo[name]
+		m := prMetric{repo: name, prs: len(prs)}
+		var sizes, files []int
+		var merges []time.Duration
+		for _, pr := range prs {
+			sizes = append(sizes, pr.Additions+pr.Deletions)
+			files = append(files, pr.ChangedFiles)
+			if pr.MergedAt != nil {
+				merges = append(merges, pr.MergedAt.Sub(pr.CreatedAt))
+			}
+		}
+		m.merged = len(merges)
+		m.medianSize, m.medianFiles, m.medianMerge = median(sizes), median(files), median(merges)
+		out = append(out, m)
+	}
+	return out
+}
+
+// median returns the middle value of xs, the lower one for an even count,
+// or zero when xs is empty.
+func median[T int | time.Duration](xs []T) T {
+	if len(xs) == 0 {
+		return 0
+	}
+	sorted := slices.Clone(xs)
+	slices.Sort(sorted)
+	return sorted[(len(sorted)-1)/2]
+}
+
+// prsView prints the pull request metrics of the window.
+func prsView(w io.Writer, events []ghEvent, _ statsOptions) error {
+	fetcher, err := newRepoFetcher()
+	if err != nil {
+		return err
+	}
+	metrics, skipped, err := prMetrics(events, fetcher.pullRequest, fetcher.hc.budget, time.Now())
+	if err != nil {
+		return err
+	}
+	return writePRMetrics(w, metrics, skipped)
+}
+
+// writePRMetrics prints one line per repository, and a note when the
+// metrics are partial.
+func writePRMetrics(w io.Writer, metrics []prMetric, skipped int) error {
+	if _, err := fmt.Fprintf(w, "%-30s %5s %7s %11s %6s %13s\n",
+		"repository", "prs", "merged", "median size", "files", "time to merge"); err != nil {
+		return err
+	}
+	for _, m := range metrics {
+		merge := "-"
+		if m.merged > 0 {
+			merge = m.medianMerge.Round(time.Minute).String()
+		}
+		if _, err := fmt.Fprintf(w, "%-30s %5d %7d %11d %6d %13s\n",
+			m.repo, m.prs, m.merged, m.medianSize, m.medianFiles, merge); err != nil {
+			return err
+		}
+	}
+	if skipped > 0 {
+		_, err := fmt.Fprintf(w, "partial: %d pull request%s skipped to preserve the rate limit\n", skipped, plural(skipped))
+		return err
+	}
+	return nil
+}
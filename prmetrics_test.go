@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestUnitPRMetrics(t *testing.T) {
+	// Arrange
+	created := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	merged := func(d time.Duration) *time.Time { m := created.Add(d); return &m }
+	details := map[issueRef]prDetails{
+		{repo: "octo/a", number: 1}: {
+			Additions: 10, Deletions: 5, ChangedFiles: 2, CreatedAt: created, MergedAt: merged(2 * time.Hour),
+		},
+		{repo: "octo/a", number: 2}: {Additions: 100, ChangedFiles: 8, CreatedAt: created, MergedAt: merged(26 * time.Hour)},
+		{repo: "octo/a", number: 3}: {Additions: 40, ChangedFiles: 3, CreatedAt: created},
+		{repo: "octo/b", number: 7}: {Additions: 1, ChangedFiles: 1, CreatedAt: created},
+	}
+	pr := func(name string, number int, action string) ghEvent {
+		return ghEvent{Type: "PullRequestEvent", Repo: repo{Name: name},
+			Payload: payload{Action: action, PullRequest: &pullRequest{Number: number}}}
+	}
+	events := []ghEvent{
+		pr("octo/a", 1, "closed"), pr("octo/a", 1, "opened"), pr("octo/a", 2, "closed"),
+		pr("octo/a", 3, "opened"), pr("octo/b", 7, "opened"), pr("octo/b", 8, "opened"),
+		{Type: "PushEvent", Repo: repo{Name: "octo/a"}},
+	}
+	var calls int
+	detailsOf := func(name string, number int) (prDetails, error) {
+		calls++
+		d, ok := details[issueRef{repo: name, number: number}]
+		if !ok {
+			return prDetails{}, &apiError{StatusCode: http.StatusNotFound}
+		}
+		return d, nil
+	}
+	// Act
+	metrics, skipped, err := prMetrics(events, detailsOf, nil, created)
+	// Assert
+	assertNoError(t, err)
+	assertEqual(t, calls, 5)
+	assertEqual(t, skipped, 0)
+	assertEqual(t, len(metrics), 2)
+	assertEqual(t, metrics[0], prMetric{
+		repo: "octo/a", prs: 3, merged: 2, medianSize: 40, medianFiles: 3, medianMerge: 2 * time.Hour,
+	})
+	assertEqual(t, metrics[1], prMetric{repo: "octo/b", prs: 1, medianSize: 1, medianFiles: 1})
+}
+
+func TestUnitPRMetricsBudget(t *testing.T) {
+	// Arrange
+	now := time.Now()
+	var budget rateBudget
+	h := http.Header{}
+	h.Set("X-RateLimit-Remaining", strconv.Itoa(prBudgetReserve+1))
+	h.Set("X-RateLimit-Reset", strconv.FormatInt(now.Add(time.Hour).Unix(), 10))
+	budget.update(h)
+	events := []ghEvent{
+		{Type: "PullRequestEvent", Repo: repo{Name: "octo/a"}, Payload: payload{PullRequest: &pullRequest{Number: 1}}},
+		{Type: "PullRequestEvent", Repo: repo{Name: "octo/a"}, Payload: payload{PullRequest: &pullRequest{Number: 2}}},
+		{Type: "PullRequestEvent", Repo: repo{Name: "octo/a"}, Payload: payload{PullRequest: &pullRequest{Number: 3}}},
+	}
+	detailsOf := func(string, int) (prDetails, error) {
+		h.Set("X-RateLimit-Remaining", strconv.Itoa(prBudgetReserve))
+		budget.update(h)
+		return prDetails{Additions: 1}, nil
+	}
+	// Act
+	metrics, skipped, err := prMetrics(events, detailsOf, &budget, now)
+	// Assert
+	assertNoError(t, err)
+	assertEqual(t, skipped, 2)
+	assertEqual(t, metrics[0].prs, 1)
+}
+
+func TestUnitMedian(t *testing.T) {
+	testCases := []struct {
+		name string
+		xs   []int
+		want int
+	}{
+		{name: "empty", xs: nil, want: 0},
+		{name: "odd", xs: []int{9, 1, 5}, want: 5},
+		{name: "even", xs: []int{4, 1, 3, 2}, want: 2},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			got := median(tc.xs)
+			// Assert
+			assertEqual(t, got, tc.want)
+		})
+	}
+}
+
+func TestUnitWritePRMetrics(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+	metrics := []prMetric{
+		{repo: "octo/a", prs: 3, merged: 2, medianSize: 40, medianFiles: 3, medianMerge: 2 * time.Hour},
+		{repo: "octo/b", prs: 1, medianSize: 1, medianFiles: 1},
+	}
+	// Act
+	err := writePRMetrics(&buf, metrics, 2)
+	// Assert
+	assertNoError(t, err)
+	assertEqual(t, buf.String(),
+		"repository                       prs  merged median size  files time to merge\n"+
+			"octo/a                             3       2          40      3        2h0m0s\n"+
+			"octo/b                             1       0           1      1             -\n"+
+			"partial: 2 pull requests skipped to preserve the rate limit\n")
+}
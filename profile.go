@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/viper"
+)
+
+// profileSettings returns the settings of a profile of the profiles config
+// section, e.g. its github_token, api_url, user, lang and output.
+func profileSettings(name string) (map[string]any, error) {
+	key := "profiles." + name
+	if !viper.IsSet(key) {
+		return nil, fmt.Errorf("unknown profile %q", name)
+	}
+	return viper.GetStringMap(key), nil
+}
+
+// applyProfile overrides the configuration with the settings of the active
+// profile.
+func applyProfile() error {
+	if activeProfile == "" {
+		return nil
+	}
+	settings, err := profileSettings(activeProfile)
+	if err != nil {
+		return err
+	}
+	if err := viper.MergeConfigMap(settings); err != nil {
+		return fmt.Errorf("apply profile %s: %w", activeProfile, err)
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestUnitProfileSettings(t *testing.T) {
+	// Arrange
+	viper.Set("profiles", map[string]any{
+		"work": map[string]any{"github_token": "ghp_work", "api_url": "https://ghe.example.com/api/v3", "user": "octo"},
+	})
+	t.Cleanup(func() { viper.Set("profiles", nil) })
+	// Act
+	settings, err := profileSettings("work")
+	_, errUnknown := profileSettings("personal")
+	// Assert
+	assertNoError(t, err)
+	assertEqual(t, settings["api_url"], any("https://ghe.example.com/api/v3"))
+	assertEqual(t, settings["user"], any("octo"))
+	assertNotNil(t, errUnknown)
+}
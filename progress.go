@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// progress shows a spinner with the pages fetched, the events collected and
+// the requests left during a multi-page fetch. A nil progress shows
+// nothing.
+type progress struct {
+	mu     sync.Mutex
+	w      io.Writer
+	label  string
+	budget *rateBudget
+	clock  clock
+	pages  int
+	events int
+	frame  int
+}
+
+// spinnerFrames are the frames of the spinner, one per page.
+var spinnerFrames = []rune("⠋⠙⠹⠸⠼⠴⠦⠧⠇⠏")
+
+// newProgress returns a progress on stderr when it is a terminal and
+// --quiet is not set, else nil.
+func newProgress(label string, budget *rateBudget) *progress {
+	if quiet || ttyWidth(os.Stderr) == 0 {
+		return nil
+	}
+	return &progress{w: os.Stderr, label: label, budget: budget, clock: systemClock{}}
+}
+
+// add records a page of n events and redraws the line.
+func (p *progress) add(n int) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.pages++
+	p.events += n
+	p.frame = (p.frame + 1) % len(spinnerFrames)
+	line := fmt.Sprintf("%c %s: %d page%s, %d events", spinnerFrames[p.frame], p.label, p.pages, plural(p.pages), p.events)
+	if left, ok := p.budget.left(p.clock.Now()); ok {
+		line += fmt.Sprintf(", %d requests left", left)
+	}
+	fmt.Fprint(p.w, "\r\033[K"+line)
+}
+
+// done clears the line.
+func (p *progress) done() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.pages > 0 {
+		fmt.Fprint(p.w, "\r\033[K")
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestUnitProgress(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+	budget := &rateBudget{}
+	h := http.Header{}
+	h.Set("X-RateLimit-Remaining", "4820")
+	h.Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+	p := &progress{w: &buf, label: "search commits", budget: budget, clock: systemClock{}}
+	// Act
+	p.add(100)
+	budget.update(h)
+	p.add(20)
+	p.done()
+	// Assert
+	assertEqual(t, buf.String(), "\r\033[K⠙ search commits: 1 page, 100 events"+
+		"\r\033[K⠹ search commits: 2 pages, 120 events, 4820 requests left"+
+		"\r\033[K")
+}
+
+func TestUnitProgressNil(t *testing.T) {
+	// Arrange
+	var p *progress
+	// Act & Assert
+	p.add(1)
+	p.done()
+}
+
+func TestUnitNewProgressQuiet(t *testing.T) {
+	// Arrange
+	quiet = true
+	t.Cleanup(func() { quiet = false })
+	// Act
+	p := newProgress("search", nil)
+	// Assert
+	assertEqual(t, p == nil, true)
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+type (
+	// projectsProvider reads the GitHub Projects (v2) of a user and of some
+	// organizations, for the kanban moves and assignments of the user that
+	// the events API does not report.
+	projectsProvider struct {
+		hc       *client
+		endpoint string
+		orgs     []string
+	}
+	// projectItem is the project item changed by a ProjectsV2ItemEvent.
+	projectItem struct {
+		Project string `json:"project"`
+		URL     string `json:"url"`
+		Title   string `json:"title"`
+		Field   string `json:"field,omitempty"`
+		Value   string `json:"value,omitempty"`
+	}
+	// projectV2 is a project with its latest items.
+	projectV2 struct {
+		Title string `json:"title"`
+		URL   string `json:"url"`
+		Items struct {
+			Nodes []projectV2Item `json:"nodes"`
+		} `json:"items"`
+	}
+	// projectV2Item is a project item with its field values.
+	projectV2Item struct {
+		ID        string    `json:"id"`
+		UpdatedAt time.Time `json:"updatedAt"`
+		Content   struct {
+			Title      string `json:"title"`
+			URL        string `json:"url"`
+			Repository struct {
+				NameWithOwner string `json:"nameWithOwner"`
+			} `json:"repository"`
+		} `json:"content"`
+		FieldValues struct {
+			Nodes []projectV2FieldValue `json:"nodes"`
+		} `json:"fieldValues"`
+	}
+	// projectV2FieldValue is a single-select value, e.g. a status, or the
+	// users of a user field.
+	projectV2FieldValue struct {
+		Name      string         `json:"name"`
+		UpdatedAt time.Time      `json:"updatedAt"`
+		Creator   *projectV2User `json:"creator"`
+		Field     struct {
+			Name string `json:"name"`
+		} `json:"field"`
+		Users *struct {
+			Nodes []projectV2User `json:"nodes"`
+		} `json:"users"`
+	}
+	// projectV2User is the account that set a field value or is assigned.
+	projectV2User struct {
+		Login string `json:"login"`
+	}
+)
+
+// projectsQuery reads the 100 latest items of the 20 latest projects of an
+// account, with the single-select and user values of their fields.
+const projectsQuery = `query($owner: String!) {
+  repositoryOwner(login: $owner) {
+    ... on ProjectV2Owner {
+      projectsV2(first: 20, orderBy: {field: UPDATED_AT, direction: DESC}) {
+        nodes {
+          title
+          url
+          items(last: 100) {
+            nodes {
+              id
+              updatedAt
+              content {
+                ... on DraftIssue { title }
+                ... on Issue { title url repository { nameWithOwner } }
+                ... on PullRequest { title url repository { nameWithOwner } }
+              }
+              fieldValues(first: 20) {
+                nodes {
+                  ... on ProjectV2ItemFieldSingleSelectValue {
+                    name
+                    updatedAt
+                    creator { login }
+                    field { ... on ProjectV2SingleSelectField { name } }
+                  }
+                  ... on ProjectV2ItemFieldUserValue {
+                    field { ... on ProjectV2Field { name } }
+                    users(first: 10) { nodes { login } }
+                  }
+                }
+              }
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+func newProjectsProvider(hc *client, apiURL string, orgs []string) *projectsProvider {
+	return &projectsProvider{hc: hc, endpoint: graphqlURL(apiURL), orgs: orgs}
+}
+
+func (p *projectsProvider) name() string { return "github-projects" }
+
+// events returns the status moves made by the user and the items assigned
+// to them, in the projects of the user and of the organizations.
+func (p *projectsProvider) events(login string) ([]ghEvent, error) {
+	var events []ghEvent
+	for _, owner := range append([]string{login}, p.orgs...) {
+		var data struct {
+			RepositoryOwner *struct {
+				ProjectsV2 struct {
+					Nodes []projectV2 `json:"nodes"`
+				} `json:"projectsV2"`
+			} `json:"repositoryOwner"`
+		}
+		if err := fetchGraphQL(p.hc, p.endpoint, projectsQuery, map[string]any{"owner": owner}, &data); err != nil {
+			return nil, fmt.Errorf("fetch projects of %s: %w", owner, err)
+		}
+		if data.RepositoryOwner == nil {
+			continue
+		}
+		for _, project := range data.RepositoryOwner.ProjectsV2.Nodes {
+			for _, item := range project.Items.Nodes {
+				events = append(events, item.events(project, owner, login)...)
+			}
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].CreatedAt.After(events[j].CreatedAt) })
+	return events, nil
+}
+
+// events returns the ProjectsV2ItemEvent of each field value of the item
+// set by the user, or assigning them. Draft issues belong to the owner of
+// the project.
+func (item projectV2Item) events(project projectV2, owner, login string) []ghEvent {
+	name := item.Content.Repository.NameWithOwner
+	if name == "" {
+		name = owner
+	}
+	base := ghEvent{Type: "ProjectsV2ItemEvent", Actor: actor{Login: login}, Repo: repo{Name: name}}
+	var events []ghEvent
+	for _, v := range item.FieldValues.Nodes {
+		ref := &projectItem{
+			Project: project.Title, URL: orDefault(item.Content.URL, project.URL), Title: item.Content.Title,
+			Field: v.Field.Name, Value: v.Name,
+		}
+		switch {
+		case v.Creator != nil && strings.EqualFold(v.Creator.Login, login):
+			ev := base
+			ev.ID = fmt.Sprintf("project-%s-%s-%d", item.ID, v.Field.Name, v.UpdatedAt.Unix())
+			ev.Payload = payload{Action: "edited", Project: ref}
+			ev.CreatedAt = v.UpdatedAt
+			events = append(events, ev)
+		case v.Users != nil && assigns(v.Users.Nodes, login):
+			ev := base
+			ev.ID = fmt.Sprintf("project-%s-%s-%s", item.ID, v.Field.Name, strings.ToLower(login))
+			ev.Payload = payload{Action: "assigned", Project: ref}
+			ev.CreatedAt = item.UpdatedAt
+			events = append(events, ev)
+		}
+	}
+	return events
+}
+
+// assigns reports whether login is one of the users of a user field.
+func assigns(users []projectV2User, login string) bool {
+	for _, u := range users {
+		if strings.EqualFold(u.Login, login) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestUnitProjectsProviderEvents(t *testing.T) {
+	// Arrange
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assertEqual(t, r.URL.Path, "/graphql")
+		var req graphqlRequest
+		assertNoError(t, json.NewDecoder(r.Body).Decode(&req))
+		if req.Variables["owner"] == "octo-org" {
+			fmt.Fprint(w, `{"data":{"repositoryOwner":null}}`)
+			return
+		}
+		fmt.Fprint(w, `{"data":{"repositoryOwner":{"projectsV2":{"nodes":[{
+			"title":"Roadmap","url":"https://github.com/users/octocat/projects/1",
+			"items":{"nodes":[
+				{"id":"I1","updatedAt":"2025-03-02T00:00:00Z",
+				 "content":{"title":"Fix login","url":"https://github.com/octo/api/issues/3",
+				            "repository":{"nameWithOwner":"octo/api"}},
+				 "fieldValues":{"nodes":[
+					{"name":"Done","updatedAt":"2025-03-01T12:00:00Z","creator":{"login":"octocat"},
+					 "field":{"name":"Status"}},
+					{"field":{"name":"Assignees"},"users":{"nodes":[{"login":"OctoCat"}]}}
+				 ]}},
+				{"id":"I2","updatedAt":"2025-03-03T00:00:00Z","content":{"title":"Draft idea"},
+				 "fieldValues":{"nodes":[
+					{"name":"Todo","updatedAt":"2025-03-03T00:00:00Z","creator":{"login":"mona"},
+					 "field":{"name":"Status"}},
+					{}
+				 ]}}
+			]}
+		}]}}}}`)
+	}))
+	defer srv.Close()
+	p := newProjectsProvider(newClient(""), srv.URL, []string{"octo-org"})
+	// Act
+	events, err := p.events("octocat")
+	// Assert
+	assertNoError(t, err)
+	assertEqual(t, len(events), 2)
+	assigned, moved := events[0], events[1]
+	assertEqual(t, assigned.Payload.Action, "assigned")
+	assertEqual(t, assigned.ID, "project-I1-Assignees-octocat")
+	assertEqual(t, assigned.CreatedAt, time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC))
+	assertEqual(t, moved.Type, "ProjectsV2ItemEvent")
+	assertEqual(t, moved.Repo.Name, "octo/api")
+	assertEqual(t, moved.Payload.Action, "edited")
+	assertEqual(t, *moved.Payload.Project, projectItem{
+		Project: "Roadmap", URL: "https://github.com/octo/api/issues/3", Title: "Fix login", Field: "Status", Value: "Done",
+	})
+	assertEqual(t, summarize(lookupCatalog("en"), moved), "Moved Fix login to Done in project Roadmap")
+}
+
+func TestUnitProjectItemEventsDraft(t *testing.T) {
+	// Arrange
+	item := projectV2Item{ID: "I3"}
+	item.Content.Title = "Draft idea"
+	item.FieldValues.Nodes = []projectV2FieldValue{{Name: "Todo", Creator: &projectV2User{Login: "octocat"}}}
+	project := projectV2{Title: "Roadmap", URL: "https://github.com/orgs/octo-org/projects/2"}
+	// Act
+	events := item.events(project, "octo-org", "octocat")
+	// Assert
+	assertEqual(t, len(events), 1)
+	assertEqual(t, events[0].Repo.Name, "octo-org")
+	assertEqual(t, events[0].Payload.Project.URL, "https://github.com/orgs/octo-org/projects/2")
+}
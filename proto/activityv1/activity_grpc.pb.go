@@ -0,0 +1,214 @@
+// Schema of the normalized activity model served by go-github-activity,
+// mirroring the JSON API of the serve subcommand.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: proto/activity.proto
+
+package activityv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Activity_ListActivity_FullMethodName   = "/activity.v1.Activity/ListActivity"
+	Activity_StreamActivity_FullMethodName = "/activity.v1.Activity/StreamActivity"
+	Activity_GetStats_FullMethodName       = "/activity.v1.Activity/GetStats"
+)
+
+// ActivityClient is the client API for Activity service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Activity reads the events and statistics of GitHub users.
+type ActivityClient interface {
+	// ListActivity returns the latest events of a user, newest first.
+	ListActivity(ctx context.Context, in *ListActivityRequest, opts ...grpc.CallOption) (*ListActivityResponse, error)
+	// StreamActivity sends the user's events as they are observed.
+	StreamActivity(ctx context.Context, in *StreamActivityRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Event], error)
+	// GetStats returns the activity totals over a window of days.
+	GetStats(ctx context.Context, in *GetStatsRequest, opts ...grpc.CallOption) (*Stats, error)
+}
+
+type activityClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewActivityClient(cc grpc.ClientConnInterface) ActivityClient {
+	return &activityClient{cc}
+}
+
+func (c *activityClient) ListActivity(ctx context.Context, in *ListActivityRequest, opts ...grpc.CallOption) (*ListActivityResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListActivityResponse)
+	err := c.cc.Invoke(ctx, Activity_ListActivity_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *activityClient) StreamActivity(ctx context.Context, in *StreamActivityRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Event], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Activity_ServiceDesc.Streams[0], Activity_StreamActivity_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamActivityRequest, Event]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Activity_StreamActivityClient = grpc.ServerStreamingClient[Event]
+
+func (c *activityClient) GetStats(ctx context.Context, in *GetStatsRequest, opts ...grpc.CallOption) (*Stats, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Stats)
+	err := c.cc.Invoke(ctx, Activity_GetStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ActivityServer is the server API for Activity service.
+// All implementations must embed UnimplementedActivityServer
+// for forward compatibility.
+//
+// Activity reads the events and statistics of GitHub users.
+type ActivityServer interface {
+	// ListActivity returns the latest events of a user, newest first.
+	ListActivity(context.Context, *ListActivityRequest) (*ListActivityResponse, error)
+	// StreamActivity sends the user's events as they are observed.
+	StreamActivity(*StreamActivityRequest, grpc.ServerStreamingServer[Event]) error
+	// GetStats returns the activity totals over a window of days.
+	GetStats(context.Context, *GetStatsRequest) (*Stats, error)
+	mustEmbedUnimplementedActivityServer()
+}
+
+// UnimplementedActivityServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedActivityServer struct{}
+
+func (UnimplementedActivityServer) ListActivity(context.Context, *ListActivityRequest) (*ListActivityResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListActivity not implemented")
+}
+func (UnimplementedActivityServer) StreamActivity(*StreamActivityRequest, grpc.ServerStreamingServer[Event]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamActivity not implemented")
+}
+func (UnimplementedActivityServer) GetStats(context.Context, *GetStatsRequest) (*Stats, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStats not implemented")
+}
+func (UnimplementedActivityServer) mustEmbedUnimplementedActivityServer() {}
+func (UnimplementedActivityServer) testEmbeddedByValue()                  {}
+
+// UnsafeActivityServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ActivityServer will
+// result in compilation errors.
+type UnsafeActivityServer interface {
+	mustEmbedUnimplementedActivityServer()
+}
+
+func RegisterActivityServer(s grpc.ServiceRegistrar, srv ActivityServer) {
+	// If the following call pancis, it indicates UnimplementedActivityServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Activity_ServiceDesc, srv)
+}
+
+func _Activity_ListActivity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListActivityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ActivityServer).ListActivity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Activity_ListActivity_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ActivityServer).ListActivity(ctx, req.(*ListActivityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Activity_StreamActivity_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamActivityRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ActivityServer).StreamActivity(m, &grpc.GenericServerStream[StreamActivityRequest, Event]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Activity_StreamActivityServer = grpc.ServerStreamingServer[Event]
+
+func _Activity_GetStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ActivityServer).GetStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Activity_GetStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ActivityServer).GetStats(ctx, req.(*GetStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Activity_ServiceDesc is the grpc.ServiceDesc for Activity service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Activity_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "activity.v1.Activity",
+	HandlerType: (*ActivityServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListActivity",
+			Handler:    _Activity_ListActivity_Handler,
+		},
+		{
+			MethodName: "GetStats",
+			Handler:    _Activity_GetStats_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamActivity",
+			Handler:       _Activity_StreamActivity_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "proto/activity.proto",
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/viper"
+)
+
+type (
+	// provider fetches the latest activity of an account on a forge,
+	// normalized into GitHub events so that every view and renderer
+	// handles it.
+	provider interface {
+		name() string
+		events(login string) ([]ghEvent, error)
+	}
+	// githubProvider fetches the events of every GitHub account of a user.
+	githubProvider struct {
+		hc   *client
+		base string
+	}
+	// sourceConfig is an entry of the sources setting.
+	sourceConfig struct {
+		Name     string   `mapstructure:"name"`
+		Type     string   `mapstructure:"type"`
+		URL      string   `mapstructure:"url"`
+		Token    string   `mapstructure:"token"`
+		TokenEnv string   `mapstructure:"token_env"`
+		Users    []string `mapstructure:"users"`
+		Repos    []string `mapstructure:"repos"`
+		Paths    []string `mapstructure:"paths"`
+		Emails   []string `mapstructure:"emails"`
+		Days     int      `mapstructure:"days"`
+		Orgs     []string `mapstructure:"orgs"`
+	}
+	// source is a named provider with the accounts it tracks; without any,
+	// it tracks the requested user.
+	source struct {
+		name     string
+		provider provider
+		users    []string
+	}
+)
+
+// providerFactories builds the provider of a source, by source type.
+var providerFactories = map[string]func(cfg sourceConfig) provider{
+	"github": func(cfg sourceConfig) provider {
+		return githubProvider{hc: cfg.githubClient(), base: orDefault(cfg.URL, viper.GetString("api_url"))}
+	},
+	"gitlab": func(cfg sourceConfig) provider {
+		return newGitLabProvider(orDefault(cfg.URL, defaultGitLabURL), cfg.token())
+	},
+	"gitea": func(cfg sourceConfig) provider {
+		return newGiteaProvider(orDefault(cfg.URL, defaultGiteaURL), cfg.token())
+	},
+	"bitbucket": func(cfg sourceConfig) provider {
+		return newBitbucketProvider(orDefault(cfg.URL, defaultBitbucketURL), cfg.token(), cfg.Repos)
+	},
+	"github-projects": func(cfg sourceConfig) provider {
+		return newProjectsProvider(cfg.githubClient(), orDefault(cfg.URL, viper.GetString("api_url")), cfg.Orgs)
+	},
+	"github-discussions": func(cfg sourceConfig) provider {
+		return newDiscussionsProvider(cfg.githubClient(), orDefault(cfg.URL, viper.GetString("api_url")))
+	},
+	"github-sponsors": func(cfg sourceConfig) provider {
+		return newSponsorsProvider(cfg.githubClient(), orDefault(cfg.URL, viper.GetString("api_url")))
+	},
+	"github-actions": func(cfg sourceConfig) provider {
+		return newActionsProvider(cfg.githubClient(), orDefault(cfg.URL, viper.GetString("api_url")), cfg.Repos)
+	},
+	"github-advisories": func(cfg sourceConfig) provider {
+		return newAdvisoriesProvider(cfg.githubClient(), orDefault(cfg.URL, viper.GetString("api_url")), cfg.Repos)
+	},
+	"local": func(cfg sourceConfig) provider {
+		return newLocalProvider(execGitOutput, cfg.Paths, cfg.Emails, cfg.Days)
+	},
+}
+
+func (p githubProvider) name() string { return "github" }
+
+func (p githubProvider) events(user string) ([]ghEvent, error) {
+	logins, err := identities(user)
+	if err != nil {
+		return nil, err
+	}
+	lists := make([][]ghEvent, 0, len(logins))
+	for _, login := range logins {
+		events, err := fetchGitHubResponse(p.hc, eventsURL(p.base, login))
+		if err != nil {
+			return nil, err
+		}
+		lists = append(lists, events)
+	}
+	return mergeEvents(lists...), nil
+}
+
+// token returns the token of the source, read from its environment
+// variable when token_env is set.
+func (cfg sourceConfig) token() string {
+	if cfg.TokenEnv != "" {
+		return os.Getenv(cfg.TokenEnv)
+	}
+	return cfg.Token
+}
+
+// githubClient returns a GitHub client with the token of the source, or
+// the configured GitHub tokens without one.
+func (cfg sourceConfig) githubClient() *client {
+	if cfg.token() == "" {
+		return configuredClient()
+	}
+	hc := newClient(cfg.token())
+	hc.budget = &rateBudget{}
+	return hc
+}
+
+// orDefault returns s, or fallback when s is empty.
+func orDefault(s, fallback string) string {
+	if s == "" {
+		return fallback
+	}
+	return s
+}
+
+// configuredSources returns the sources of the sources setting. Without
+// it, GitHub is the only source, joined by the forges configured in the
+// gitlab, gitea and bitbucket sections.
+func configuredSources() ([]source, error) {
+	var configs []sourceConfig
+	if err := viper.UnmarshalKey("sources", &configs); err != nil {
+		return nil, fmt.Errorf("parse sources: %w", err)
+	}
+	if len(configs) == 0 {
+		configs = sectionSources()
+	}
+	sources := make([]source, 0, len(configs))
+	seen := map[string]bool{}
+	for _, cfg := range configs {
+		factory, ok := providerFactories[cfg.Type]
+		if !ok {
+			return nil, fmt.Errorf("unknown type %q of source %q", cfg.Type, cfg.Name)
+		}
+		name := orDefault(cfg.Name, cfg.Type)
+		if seen[name] {
+			return nil, fmt.Errorf("duplicate source %q", name)
+		}
+		seen[name] = true
+		sources = append(sources, source{name: name, provider: factory(cfg), users: cfg.Users})
+	}
+	return sources, nil
+}
+
+// sectionSources returns GitHub and the forges of the gitlab, gitea and
+// bitbucket sections with a token or user.
+func sectionSources() []sourceConfig {
+	configs := []sourceConfig{{Type: "github"}}
+	for _, kind := range []string{"gitlab", "gitea", "bitbucket"} {
+		if !viper.IsSet(kind+".token") && !viper.IsSet(kind+".user") {
+			continue
+		}
+		cfg := sourceConfig{
+			Type: kind, URL: viper.GetString(kind + ".url"), Token: viper.GetString(kind + ".token"),
+			Repos: viper.GetStringSlice(kind + ".repos"),
+		}
+		if user := viper.GetString(kind + ".user"); user != "" {
+			cfg.Users = []string{user}
+		}
+		configs = append(configs, cfg)
+	}
+	return configs
+}
+
+// fetchSourceEvents merges the events of every source, newest first. With
+// more than one source, each event is tagged with the name of its source,
+// its ID is prefixed with that name so that two sources of the same type
+// don't collide, and commits seen by several sources are kept once.
+func fetchSourceEvents(sources []source, user string) ([]ghEvent, error) {
+	var lists [][]ghEvent
+	for _, src := range sources {
+		users := src.users
+		if len(users) == 0 {
+			users = []string{user}
+		}
+		for _, login := range users {
+			events, err := src.provider.events(login)
+			if err != nil {
+				return nil, fmt.Errorf("source %s: %w", src.name, err)
+			}
+			if len(sources) > 1 {
+				for i := range events {
+					events[i].Source = src.name
+					events[i].ID = src.name + ":" + events[i].ID
+				}
+			}
+			lists = append(lists, events)
+		}
+	}
+	if len(sources) > 1 {
+		return unifyEvents(mergeEvents(lists...)), nil
+	}
+	return mergeEvents(lists...), nil
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// stubProvider returns fixed events acted by the requested login, or an
+// error.
+type stubProvider struct {
+	fixed []ghEvent
+	err   error
+}
+
+func (s stubProvider) name() string { return "stub" }
+
+func (s stubProvider) events(login string) ([]ghEvent, error) {
+	events := make([]ghEvent, len(s.fixed))
+	for i, ev := range s.fixed {
+		ev.Actor.Login = login
+		events[i] = ev
+	}
+	return events, s.err
+}
+
+func TestUnitFetchSourceEvents(t *testing.T) {
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	github := source{name: "github", provider: stubProvider{
+		fixed: []ghEvent{{ID: "1", CreatedAt: now.Add(time.Hour)}, {ID: "2", CreatedAt: now}},
+	}}
+	gitlab := source{name: "work-gitlab", users: []string{"octo-gl"}, provider: stubProvider{
+		fixed: []ghEvent{{ID: "gitlab-1", CreatedAt: now.Add(30 * time.Minute)}},
+	}}
+	personal := source{name: "personal-gitlab", provider: stubProvider{
+		fixed: []ghEvent{{ID: "gitlab-1", CreatedAt: now.Add(45 * time.Minute)}},
+	}}
+	failing := source{name: "down", provider: stubProvider{err: errors.New("down")}}
+	testCases := []struct {
+		name        string
+		sources     []source
+		wantIDs     []string
+		wantSources []string
+		wantLogins  []string
+		wantErr     bool
+	}{
+		{
+			name: "github only", sources: []source{github}, wantIDs: []string{"1", "2"},
+			wantSources: []string{"", ""}, wantLogins: []string{"octocat", "octocat"},
+		},
+		{
+			name: "merged and tagged", sources: []source{github, gitlab},
+			wantIDs:     []string{"github:1", "work-gitlab:gitlab-1", "github:2"},
+			wantSources: []string{"github", "work-gitlab", "github"},
+			wantLogins:  []string{"octocat", "octo-gl", "octocat"},
+		},
+		{
+			name: "same type", sources: []source{gitlab, personal},
+			wantIDs:     []string{"personal-gitlab:gitlab-1", "work-gitlab:gitlab-1"},
+			wantSources: []string{"personal-gitlab", "work-gitlab"},
+			wantLogins:  []string{"octocat", "octo-gl"},
+		},
+		{name: "failing source", sources: []source{github, failing}, wantErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			got, err := fetchSourceEvents(tc.sources, "octocat")
+			// Assert
+			if tc.wantErr {
+				assertNotNil(t, err)
+				return
+			}
+			assertNoError(t, err)
+			assertEqual(t, len(got), len(tc.wantIDs))
+			for i, id := range tc.wantIDs {
+				assertEqual(t, got[i].ID, id)
+				assertEqual(t, got[i].Source, tc.wantSources[i])
+				assertEqual(t, got[i].Actor.Login, tc.wantLogins[i])
+			}
+		})
+	}
+}
+
+func TestUnitConfiguredSources(t *testing.T) {
+	testCases := []struct {
+		name      string
+		settings  map[string]any
+		wantNames []string
+		wantErr   bool
+	}{
+		{name: "github by default", wantNames: []string{"github"}},
+		{
+			name:      "forge sections",
+			settings:  map[string]any{"gitlab.token": "glpat", "bitbucket.user": "octo-bb"},
+			wantNames: []string{"github", "gitlab", "bitbucket"},
+		},
+		{
+			name: "registry",
+			settings: map[string]any{"gitlab.token": "ignored", "sources": []map[string]any{
+				{"name": "work", "type": "gitlab", "url": "https://gitlab.example.com", "users": []string{"octo"}},
+				{"type": "gitea"},
+				{"name": "laptop", "type": "local", "paths": []string{"~/src/api"}},
+			}},
+			wantNames: []string{"work", "gitea", "laptop"},
+		},
+		{
+			name:     "unknown type",
+			settings: map[string]any{"sources": []map[string]any{{"name": "svn", "type": "subversion"}}},
+			wantErr:  true,
+		},
+		{
+			name: "duplicate name",
+			settings: map[string]any{"sources": []map[string]any{
+				{"type": "gitea"}, {"type": "gitea", "url": "https://codeberg.org"},
+			}},
+			wantErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			t.Cleanup(viper.Reset)
+			for key, value := range tc.settings {
+				viper.Set(key, value)
+			}
+			// Act
+			got, err := configuredSources()
+			// Assert
+			if tc.wantErr {
+				assertNotNil(t, err)
+				return
+			}
+			assertNoError(t, err)
+			assertEqual(t, len(got), len(tc.wantNames))
+			for i, name := range tc.wantNames {
+				assertEqual(t, got[i].name, name)
+			}
+		})
+	}
+}
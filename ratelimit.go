@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+type (
+	// rateBucket is the quota of one resource of the rate limit API.
+	rateBucket struct {
+		Limit     int   `json:"limit"`
+		Used      int   `json:"used"`
+		Remaining int   `json:"remaining"`
+		Reset     int64 `json:"reset"`
+	}
+	// rateLimits is the response of the rate limit API.
+	rateLimits struct {
+		Resources map[string]rateBucket `json:"resources"`
+	}
+)
+
+// runRateLimit prints the quota of every rate limit resource of the
+// configured token; checking it costs no request.
+func runRateLimit(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("ratelimit", flag.ContinueOnError)
+	output := flags.String("output", "text", "output format: text or json")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		return errors.New("usage: go-github-activity ratelimit [flags]")
+	}
+	if *output != "text" && *output != "json" {
+		return fmt.Errorf("unknown output format %q", *output)
+	}
+	if err := loadConfig(); err != nil {
+		return err
+	}
+	var limits rateLimits
+	hc := configuredClient()
+	if err := fetchJSON(hc, viper.GetString("api_url")+"/rate_limit", &limits); err != nil {
+		return fmt.Errorf("fetch rate limit: %w", err)
+	}
+	if *output == "json" {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(limits.Resources)
+	}
+	return writeRateLimits(stdout, limits, time.Now())
+}
+
+// writeRateLimits prints one line per resource, by name, with the time
+// left before its reset.
+func writeRateLimits(w io.Writer, limits rateLimits, now time.Time) error {
+	if _, err := fmt.Fprintf(w, "%-28s %6s %6s %9s  %s\n", "resource", "limit", "used", "remaining", "reset"); err != nil {
+		return err
+	}
+	for _, name := range sortedKeys(limits.Resources) {
+		b := limits.Resources[name]
+		reset := time.Unix(b.Reset, 0)
+		in := max(reset.Sub(now), 0).Round(time.Second)
+		if _, err := fmt.Fprintf(w, "%-28s %6d %6d %9d  %s (in %s)\n",
+			name, b.Limit, b.Used, b.Remaining, reset.Local().Format(time.TimeOnly), in); err != nil {
+			return err
+		}
+	}
+	return nil
+}
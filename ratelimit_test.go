@@ -0,0 +1,28 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestUnitWriteRateLimits(t *testing.T) {
+	// Arrange
+	now := time.Date(2025, 3, 10, 10, 0, 0, 0, time.Local)
+	var limits rateLimits
+	err := json.Unmarshal([]byte(fmt.Sprintf(`{"resources": {
+		"search": {"limit": 30, "used": 2, "remaining": 28, "reset": %d},
+		"core": {"limit": 5000, "used": 120, "remaining": 4880, "reset": %d}
+	}}`, now.Add(time.Minute).Unix(), now.Add(-time.Minute).Unix())), &limits)
+	assertNoError(t, err)
+	var buf bytes.Buffer
+	// Act
+	err = writeRateLimits(&buf, limits, now)
+	// Assert
+	assertNoError(t, err)
+	assertEqual(t, buf.String(), "resource                      limit   used remaining  reset\n"+
+		"core                           5000    120      4880  09:59:00 (in 0s)\n"+
+		"search                           30      2        28  10:01:00 (in 1m0s)\n")
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/spf13/viper"
+)
+
+// fetchRawLoginEvents fetches the events of one account like
+// fetchLoginEvents, keeping the JSON of each event as served by the API.
+func fetchRawLoginEvents(hc *client, login string) ([]ghEvent, error) {
+	var raw []json.RawMessage
+	if err := fetchJSON(hc, eventsURL(viper.GetString("api_url"), login), &raw); err != nil {
+		return nil, err
+	}
+	return decodeRawEvents(raw)
+}
+
+// decodeRawEvents normalizes the raw events, attaching each to its event.
+func decodeRawEvents(raw []json.RawMessage) ([]ghEvent, error) {
+	events := make([]ghEvent, 0, len(raw))
+	for i, r := range raw {
+		var ev ghEvent
+		if err := json.Unmarshal(r, &ev); err != nil {
+			return nil, fmt.Errorf("decode event %d: %w", i, err)
+		}
+		ev.Raw = r
+		events = append(events, ev)
+	}
+	return events, nil
+}
+
+// runReprocess normalizes the archived raw payloads again, so that events
+// archived with archive.keep_raw benefit from model upgrades.
+func runReprocess(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("archive reprocess", flag.ContinueOnError)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: go-github-activity archive reprocess <username>")
+	}
+	if err := loadConfig(); err != nil {
+		return err
+	}
+	a, err := openArchive(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	reprocessed, skipped, err := a.reprocess()
+	if err != nil {
+		return err
+	}
+	return infof(stdout, "reprocessed %d events, %d without raw payload\n", reprocessed, skipped)
+}
+
+// reprocess rewrites the archive, replacing each event that has a raw
+// payload with its normalization. It returns the number of reprocessed
+// events and of events left as is.
+func (a *archive) reprocess() (reprocessed, skipped int, err error) {
+	var decodeErr error
+	err = a.rewrite(func(ev *ghEvent) bool {
+		if len(ev.Raw) == 0 || decodeErr != nil {
+			skipped++
+			return true
+		}
+		var fresh ghEvent
+		if err := json.Unmarshal(ev.Raw, &fresh); err != nil {
+			decodeErr = fmt.Errorf("reprocess event %s: %w", ev.ID, err)
+			return true
+		}
+		fresh.Raw = ev.Raw
+		*ev = fresh
+		reprocessed++
+		return true
+	})
+	if err == nil {
+		err = decodeErr
+	}
+	return reprocessed, skipped, err
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestUnitDecodeRawEvents(t *testing.T) {
+	testCases := []struct {
+		name    string
+		raw     []json.RawMessage
+		want    int
+		wantErr bool
+	}{
+		{name: "empty", raw: nil, want: 0},
+		{
+			name: "events",
+			raw:  []json.RawMessage{json.RawMessage(`{"id":"1","type":"PushEvent"}`), json.RawMessage(`{"id":"2"}`)},
+			want: 2,
+		},
+		{name: "invalid", raw: []json.RawMessage{json.RawMessage(`{"id":1}`)}, wantErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			got, err := decodeRawEvents(tc.raw)
+			// Assert
+			if tc.wantErr {
+				assertNotNil(t, err)
+				return
+			}
+			assertNoError(t, err)
+			assertEqual(t, len(got), tc.want)
+			for i, ev := range got {
+				assertEqual(t, string(ev.Raw), string(tc.raw[i]))
+			}
+		})
+	}
+}
+
+func TestUnitArchiveReprocess(t *testing.T) {
+	// Arrange
+	a := &archive{path: filepath.Join(t.TempDir(), "octocat.jsonl")}
+	raw := `{"id":"1","type":"PushEvent","repo":{"name":"octocat/hello"},"payload":{"size":3},` +
+		`"created_at":"2025-03-01T00:00:00Z"}`
+	lines := `{"id":"1","type":"PushEvent","created_at":"2025-03-01T00:00:00Z","raw":` + raw + "}\n" +
+		`{"id":"2","type":"WatchEvent","created_at":"2025-03-02T00:00:00Z"}` + "\n"
+	os.WriteFile(a.path, []byte(lines), 0o600)
+	// Act
+	reprocessed, skipped, err := a.reprocess()
+	events, errLoad := a.load()
+	// Assert
+	assertNoError(t, err)
+	assertNoError(t, errLoad)
+	assertEqual(t, reprocessed, 1)
+	assertEqual(t, skipped, 1)
+	assertEqual(t, events[1].Repo.Name, "octocat/hello")
+	assertEqual(t, events[1].Payload.Size, 3)
+	assertEqual(t, len(events[1].Raw) > 0, true)
+}
+
+func TestUnitCompactRawPayloads(t *testing.T) {
+	testCases := []struct {
+		name    string
+		keepRaw bool
+	}{
+		{name: "dropped by default", keepRaw: false},
+		{name: "kept with keep_raw", keepRaw: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			viper.Set("archive.keep_raw", tc.keepRaw)
+			t.Cleanup(viper.Reset)
+			a := &archive{path: filepath.Join(t.TempDir(), "octocat.jsonl")}
+			a.add([]ghEvent{{ID: "1", Raw: json.RawMessage(`{"id":"1"}`)}})
+			// Act
+			_, err := a.compact(retention{})
+			events, _ := a.load()
+			// Assert
+			assertNoError(t, err)
+			assertEqual(t, len(events[0].Raw) > 0, tc.keepRaw)
+		})
+	}
+}
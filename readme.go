@@ -0,0 +1,110 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	// readmeStartMarker opens the generated section in a README.
+	readmeStartMarker = "<!--ACTIVITY:START-->"
+	// readmeEndMarker closes the generated section in a README.
+	readmeEndMarker = "<!--ACTIVITY:END-->"
+	// githubURL prefixes repository links in Markdown output.
+	githubURL = "https://github.com/"
+)
+
+// errMissingMarkers reports a README without a well-formed activity section.
+var errMissingMarkers = errors.New("activity markers not found")
+
+// runReadmeSection renders the last events as a Markdown snippet, printed or
+// written in place between the activity markers of a README.
+func runReadmeSection(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("readme-section", flag.ContinueOnError)
+	count := flags.Int("count", 5, "number of events to include")
+	file := flags.String("file", "", "README to update in place instead of printing the snippet")
+	lang := flags.String("lang", "", "output language (en, fr, es, ja), defaults to $LANG")
+	noEmoji := flags.Bool("no-emoji", false, "use ASCII markers instead of emoji icons")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: go-github-activity readme-section [flags] <username>")
+	}
+	events, err := fetchUserEvents(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	cat := lookupCatalog(resolveLang(*lang, os.Getenv("LANG")))
+	section := markdownSection(cat, loadIcons(!*noEmoji, viper.GetStringMapString("icons")), events, *count)
+	if *file == "" {
+		_, err = io.WriteString(stdout, section)
+		return err
+	}
+	_, err = updateReadme(*file, section)
+	return err
+}
+
+// markdownSection renders up to n events as a numbered Markdown list wrapped
+// in the activity markers, linking each repository.
+func markdownSection(cat catalog, icons iconSet, events []ghEvent, n int) string {
+	var b strings.Builder
+	b.WriteString(readmeStartMarker + "\n")
+	for i, ev := range events {
+		if i == n {
+			break
+		}
+		fmt.Fprintf(&b, "%d. %s %s\n", i+1, icons.icon(ev.Type), linkRepo(summarize(cat, ev), ev.Repo.Name))
+	}
+	b.WriteString(readmeEndMarker + "\n")
+	return b.String()
+}
+
+// linkRepo turns the first mention of a repository in line into a Markdown
+// link.
+func linkRepo(line, name string) string {
+	if name == "" {
+		return line
+	}
+	return strings.Replace(line, name, fmt.Sprintf("[%s](%s%s)", name, githubURL, name), 1)
+}
+
+// replaceSection swaps the marked section of doc for section.
+func replaceSection(doc, section string) (string, error) {
+	start := strings.Index(doc, readmeStartMarker)
+	end := strings.Index(doc, readmeEndMarker)
+	if start < 0 || end < start {
+		return "", errMissingMarkers
+	}
+	end += len(readmeEndMarker)
+	if strings.HasPrefix(doc[end:], "\n") {
+		end++
+	}
+	return doc[:start] + section + doc[end:], nil
+}
+
+// updateReadme rewrites the marked section of the README at path and
+// reports whether its content changed.
+func updateReadme(path, section string) (bool, error) {
+	byt, err := os.ReadFile(path)
+	if err != nil {
+		return false, fmt.Errorf("read the README: %w", err)
+	}
+	doc, err := replaceSection(string(byt), section)
+	if err != nil {
+		return false, fmt.Errorf("update %s: %w", path, err)
+	}
+	if doc == string(byt) {
+		return false, nil
+	}
+	if err := os.WriteFile(path, []byte(doc), 0o644); err != nil { //nolint:gosec // READMEs are world-readable
+		return false, fmt.Errorf("write the README: %w", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestUnitMarkdownSection(t *testing.T) {
+	// Arrange
+	events := []ghEvent{
+		{Type: "WatchEvent", Repo: repo{Name: "octo/a"}},
+		{Type: "ForkEvent", Repo: repo{Name: "octo/b"}},
+		{Type: "PublicEvent", Repo: repo{Name: "octo/c"}},
+	}
+	// Act
+	got := markdownSection(lookupCatalog("en"), asciiIcons, events, 2)
+	// Assert
+	want := readmeStartMarker + "\n" +
+		"1. [star] Starred [octo/a](https://github.com/octo/a)\n" +
+		"2. [fork] Forked [octo/b](https://github.com/octo/b)\n" +
+		readmeEndMarker + "\n"
+	assertEqual(t, got, want)
+}
+
+func TestUnitReplaceSection(t *testing.T) {
+	section := readmeStartMarker + "\nnew\n" + readmeEndMarker + "\n"
+	testCases := []struct {
+		name    string
+		doc     string
+		want    string
+		wantErr error
+	}{
+		{
+			name: "replaces existing section",
+			doc:  "# Hi\n" + readmeStartMarker + "\nold\n" + readmeEndMarker + "\nBye\n",
+			want: "# Hi\n" + section + "Bye\n",
+		},
+		{
+			name: "empty section",
+			doc:  readmeStartMarker + readmeEndMarker,
+			want: section,
+		},
+		{
+			name:    "missing markers",
+			doc:     "# Hi\n",
+			wantErr: errMissingMarkers,
+		},
+		{
+			name:    "markers out of order",
+			doc:     readmeEndMarker + readmeStartMarker,
+			wantErr: errMissingMarkers,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			got, err := replaceSection(tc.doc, section)
+			// Assert
+			if tc.wantErr != nil {
+				assertEqual(t, errors.Is(err, tc.wantErr), true)
+				return
+			}
+			assertNoError(t, err)
+			assertEqual(t, got, tc.want)
+		})
+	}
+}
+
+func TestUnitUpdateReadme(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "README.md")
+	os.WriteFile(path, []byte("intro\n"+readmeStartMarker+"\n"+readmeEndMarker+"\n"), 0o644)
+	section := readmeStartMarker + "\n1. item\n" + readmeEndMarker + "\n"
+	// Act
+	changed, err := updateReadme(path, section)
+	unchanged, _ := updateReadme(path, section)
+	// Assert
+	assertNoError(t, err)
+	assertEqual(t, changed, true)
+	assertEqual(t, unchanged, false)
+	byt, _ := os.ReadFile(path)
+	assertEqual(t, string(byt), "intro\n"+section)
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	// redactedOwner replaces the owner of redacted repository names.
+	redactedOwner = "redacted"
+	// redactedText replaces redacted titles and commit messages.
+	redactedText = "[redacted]"
+	// redactSaltFile keeps, in the app directory, the salt generated when
+	// redact.salt is not set.
+	redactSaltFile = "redact.salt"
+)
+
+// redactEvents hides, with --redact, the commit authors of every event and,
+// for private repositories, the repository name, the titles, the commit
+// messages and the links. Names and emails are replaced by a hash salted
+// with redact.salt, so the activity of a repository still adds up.
+func redactEvents(events []ghEvent) []ghEvent {
+	if !redact {
+		return events
+	}
+	salt := viper.GetString("redact.salt")
+	for i := range events {
+		redactEvent(&events[i], salt)
+	}
+	return events
+}
+
+// redactedCopy returns, with --redact, redacted copies of the events,
+// leaving the originals untouched. The renderers write it, so that every
+// listing is redacted whatever fetched its events.
+func redactedCopy(events []ghEvent) []ghEvent {
+	if !redact {
+		return events
+	}
+	return redactEvents(slices.Clone(events))
+}
+
+// redactEvent redacts one event in place, once: the hashes of an event
+// already redacted are left as they are. The commits, labels and nested
+// payloads are copied before being redacted, so that a copy of an event
+// can be redacted leaving the original untouched.
+func redactEvent(ev *ghEvent, salt string) {
+	if ev.redacted {
+		return
+	}
+	ev.redacted = true
+	ev.Raw = nil
+	p := &ev.Payload
+	p.Commits = slices.Clone(p.Commits)
+	for i := range p.Commits {
+		a := &p.Commits[i].Author
+		if a.Email != "" {
+			a.Email = redactedHash(a.Email, salt) + "@" + redactedOwner
+		}
+		if a.Name != "" {
+			a.Name = redactedHash(a.Name, salt)
+		}
+	}
+	if ev.Public && (ev.Repo.Meta == nil || !ev.Repo.Meta.Private) {
+		return
+	}
+	ev.Repo.Name = redactedOwner + "/" + redactedHash(ev.Repo.Name, salt)
+	ev.Repo.URL = ""
+	if ev.Repo.Meta != nil {
+		meta := *ev.Repo.Meta
+		meta.FullName, meta.Description = ev.Repo.Name, ""
+		ev.Repo.Meta = &meta
+	}
+	for i := range p.Commits {
+		p.Commits[i].Message, p.Commits[i].URL = redactedText, ""
+	}
+	if p.PullRequest != nil {
+		pr := *p.PullRequest
+		pr.Title, pr.HTMLURL = redactedText, ""
+		p.PullRequest = &pr
+	}
+	if p.Issue != nil {
+		is := *p.Issue
+		is.Title, is.HTMLURL = redactedText, ""
+		p.Issue = &is
+	}
+	if p.Alert != nil {
+		alert := *p.Alert
+		alert.Advisory, alert.Summary, alert.Package, alert.HTMLURL = "", redactedText, "", ""
+		p.Alert = &alert
+	}
+	if p.WorkflowRun != nil {
+		run := *p.WorkflowRun
+		run.Name, run.HeadBranch, run.HTMLURL, run.Repository = redactedText, redactedText, "", nil
+		p.WorkflowRun = &run
+	}
+	if p.Deployment != nil {
+		d := *p.Deployment
+		d.Environment, d.Ref = redactedText, redactedText
+		p.Deployment = &d
+	}
+	if p.Labels != nil {
+		p.Labels = make([]label, len(p.Labels))
+		for i := range p.Labels {
+			p.Labels[i].Name = redactedText
+		}
+	}
+	if p.Milestone != nil {
+		m := *p.Milestone
+		m.Title = redactedText
+		p.Milestone = &m
+	}
+	if p.Sponsorship != nil {
+		s := *p.Sponsorship
+		s.Sponsor = redactedHash(s.Sponsor, salt)
+		p.Sponsorship = &s
+	}
+	if p.Discussion != nil {
+		d := *p.Discussion
+		d.Title, d.HTMLURL = redactedText, ""
+		p.Discussion = &d
+	}
+	if p.Project != nil {
+		item := *p.Project
+		item.Project, item.Title, item.URL = redactedText, redactedText, ""
+		p.Project = &item
+	}
+	if p.Release != nil {
+		r := *p.Release
+		r.TagName, r.Name, r.HTMLURL = redactedText, redactedText, ""
+		p.Release = &r
+	}
+	if p.Ref != "" {
+		p.Ref = redactedText
+	}
+}
+
+// redactedHash returns a 64-bit salted hash of s.
+func redactedHash(s, salt string) string {
+	sum := sha256.Sum256([]byte(salt + s))
+	return hex.EncodeToString(sum[:8])
+}
+
+// redactSalt returns the redact.salt setting or, without one, a random salt
+// generated once and kept in the app directory. Without a secret salt, the
+// hashes of known names, emails and repositories could be looked up.
+func redactSalt(userHome userHome) (string, error) {
+	if salt := viper.GetString("redact.salt"); salt != "" {
+		return salt, nil
+	}
+	dir, err := appDir(userHome)
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, redactSaltFile)
+	byt, err := os.ReadFile(path)
+	switch {
+	case err == nil && len(strings.TrimSpace(string(byt))) > 0:
+		return strings.TrimSpace(string(byt)), nil
+	case err != nil && !errors.Is(err, fs.ErrNotExist):
+		return "", fmt.Errorf("read the redaction salt: %w", err)
+	}
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", fmt.Errorf("generate the redaction salt: %w", err)
+	}
+	salt := hex.EncodeToString(key)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("create the app directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(salt+"\n"), 0o600); err != nil {
+		return "", fmt.Errorf("write the redaction salt: %w", err)
+	}
+	return salt, nil
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestUnitRedactEvents(t *testing.T) {
+	testCases := []struct {
+		name        string
+		ev          ghEvent
+		wantPrivate bool
+	}{
+		{name: "public event", ev: ghEvent{Public: true, Repo: repo{Name: "octocat/hello"}}},
+		{name: "private event", ev: ghEvent{Public: false, Repo: repo{Name: "octocat/hello"}}, wantPrivate: true},
+		{
+			name:        "public event of a private repository",
+			ev:          ghEvent{Public: true, Repo: repo{Name: "octocat/hello", Meta: &repoMeta{Private: true}}},
+			wantPrivate: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			redact = true
+			t.Cleanup(func() { redact = false })
+			tc.ev.Payload = payload{
+				Ref:         "feature/secret",
+				Commits:     []commit{{Message: "Add the secret", Author: author{Email: "octo@example.com", Name: "Octo Cat"}}},
+				PullRequest: &pullRequest{Title: "Secret feature", HTMLURL: "https://github.com/octocat/hello/pull/1"},
+				Project:     &projectItem{Project: "Roadmap", Title: "Secret feature"},
+			}
+			// Act
+			got := redactEvents([]ghEvent{tc.ev})[0]
+			// Assert
+			c := got.Payload.Commits[0]
+			assertEqual(t, strings.HasSuffix(c.Author.Email, "@redacted"), true)
+			assertEqual(t, strings.Contains(c.Author.Email, "octo"), false)
+			assertEqual(t, len(c.Author.Name), 16)
+			assertEqual(t, strings.HasPrefix(got.Repo.Name, "redacted/"), tc.wantPrivate)
+			assertEqual(t, c.Message == redactedText, tc.wantPrivate)
+			assertEqual(t, got.Payload.PullRequest.Title == redactedText, tc.wantPrivate)
+			assertEqual(t, got.Payload.Ref == redactedText, tc.wantPrivate)
+			assertEqual(t, got.Payload.Project.Title == redactedText, tc.wantPrivate)
+		})
+	}
+}
+
+func TestUnitRedactEventsStable(t *testing.T) {
+	// Arrange
+	redact = true
+	t.Cleanup(func() { redact = false })
+	events := []ghEvent{
+		{Repo: repo{Name: "octocat/hello"}},
+		{Repo: repo{Name: "octocat/hello"}},
+		{Repo: repo{Name: "octocat/world"}},
+	}
+	// Act
+	got := redactEvents(events)
+	// Assert
+	assertEqual(t, got[0].Repo.Name, got[1].Repo.Name)
+	assertEqual(t, got[0].Repo.Name == got[2].Repo.Name, false)
+	assertEqual(t, repoOwner(got[0].Repo.Name), redactedOwner)
+}
+
+func TestUnitRedactEventsOnce(t *testing.T) {
+	// Arrange
+	redact = true
+	t.Cleanup(func() { redact = false })
+	events := []ghEvent{{Repo: repo{Name: "octocat/hello"}}}
+	// Act
+	once := redactEvents(events)[0].Repo.Name
+	twice := redactEvents(events)[0].Repo.Name
+	// Assert
+	assertEqual(t, twice, once)
+}
+
+func TestUnitRenderersRedact(t *testing.T) {
+	testCases := []struct {
+		name string
+		r    renderer
+	}{
+		{name: "text", r: textRenderer{cat: catalogs[defaultLang]}},
+		{name: "json", r: jsonRenderer{cat: catalogs[defaultLang]}},
+		{name: "table", r: tableRenderer{cat: catalogs[defaultLang]}},
+		{name: "html", r: htmlRenderer{cat: catalogs[defaultLang]}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			redact = true
+			t.Cleanup(func() { redact = false })
+			events := []ghEvent{{
+				Type: "PushEvent",
+				Repo: repo{Name: "octocat/secret"},
+				Payload: payload{
+					Size:    1,
+					Commits: []commit{{Message: "Add the secret", Author: author{Name: "Octo Cat", Email: "octo@example.com"}}},
+				},
+			}}
+			var buf bytes.Buffer
+			// Act
+			err := tc.r.render(&buf, events)
+			// Assert
+			assertNoError(t, err)
+			for _, secret := range []string{"octocat/secret", "Add the secret", "Octo Cat", "octo@example.com"} {
+				assertEqual(t, strings.Contains(buf.String(), secret), false)
+			}
+			assertEqual(t, events[0].Repo.Name, "octocat/secret")
+		})
+	}
+}
+
+func TestUnitRedactSalt(t *testing.T) {
+	testCases := []struct {
+		name       string
+		configured string
+	}{
+		{name: "configured", configured: "pepper"},
+		{name: "generated"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			t.Setenv("HOME", t.TempDir())
+			viper.Set("redact.salt", tc.configured)
+			t.Cleanup(viper.Reset)
+			// Act
+			first, errFirst := redactSalt(&defaultUserHome{})
+			second, errSecond := redactSalt(&defaultUserHome{})
+			// Assert
+			assertNoError(t, errFirst)
+			assertNoError(t, errSecond)
+			assertEqual(t, second, first)
+			if tc.configured != "" {
+				assertEqual(t, first, tc.configured)
+				return
+			}
+			assertEqual(t, len(first), 64)
+		})
+	}
+}
+
+func TestUnitRedactEventsDisabled(t *testing.T) {
+	// Arrange
+	events := []ghEvent{{Repo: repo{Name: "octocat/hello"}}}
+	// Act
+	got := redactEvents(events)
+	// Assert
+	assertEqual(t, got[0].Repo.Name, "octocat/hello")
+}
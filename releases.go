@@ -0,0 +1,288 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+type (
+	// release is the release of a ReleaseEvent.
+	release struct {
+		TagName    string `json:"tag_name"`
+		Name       string `json:"name"`
+		HTMLURL    string `json:"html_url"`
+		Prerelease bool   `json:"prerelease"`
+		// Bump is set by markBumps: major, minor or patch when the tag is
+		// a semantic version above every earlier one of the repository.
+		Bump string `json:"bump,omitempty"`
+	}
+	// publishedRelease is a release as listed by the releases API.
+	publishedRelease struct {
+		TagName     string    `json:"tag_name"`
+		Draft       bool      `json:"draft"`
+		PublishedAt time.Time `json:"published_at"`
+	}
+	// semver is a parsed semantic version; pre holds the pre-release part.
+	semver struct {
+		major, minor, patch int
+		pre                 string
+	}
+)
+
+// runReleases lists the releases published in a set of repositories,
+// flagging major version bumps, or watches them and notifies new releases
+// through releases.rules.
+func runReleases(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("releases", flag.ContinueOnError)
+	majorOnly := flags.Bool("major", false, "only the major version bumps")
+	workers := flags.Int("concurrency", 4, "number of repositories fetched at once")
+	output := flags.String("output", "text", "output format: text or json")
+	strict := flags.Bool("strict-budget", false, "fail instead of asking when the run would exceed the rate limit")
+	watch := flags.Bool("watch", false, "poll the repositories and notify new releases through releases.rules")
+	interval := flags.Duration("interval", 10*time.Minute, "with --watch, delay between two polls")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *output != "text" && *output != "json" {
+		return fmt.Errorf("unknown output format %q", *output)
+	}
+	if *watch && *interval < time.Second {
+		return fmt.Errorf("invalid interval: %s", *interval)
+	}
+	if err := loadConfig(); err != nil {
+		return err
+	}
+	repos := flags.Args()
+	if len(repos) == 0 {
+		repos = viper.GetStringSlice("releases.repos")
+	}
+	if len(repos) == 0 {
+		return errors.New("usage: go-github-activity releases [flags] <owner/repo>..., or set releases.repos")
+	}
+	f, err := newRepoFetcher()
+	if err != nil {
+		return err
+	}
+	fetch := func() ([]ghEvent, error) {
+		events, _, err := trackRepos(repos, f.repoEvents, *workers, f.hc.budget, f.hc.clock)
+		if err != nil {
+			return nil, err
+		}
+		seeds, err := releaseSeeds(events, f.releaseHistory)
+		if err != nil {
+			return nil, err
+		}
+		releases := markBumps(events, seeds)
+		if *majorOnly {
+			releases = majorBumps(releases)
+		}
+		return releases, nil
+	}
+	if *watch {
+		return watchLive(&poller{fetch: fetch}, func() (watchSettings, error) {
+			return loadWatchSettings("releases.rules", *interval)
+		}, stdout)
+	}
+	// Each repository costs its events and, with releases, its release
+	// history.
+	if err := checkRunBudget(f, 2*len(repos), *strict); err != nil {
+		return err
+	}
+	events, err := fetch()
+	if err != nil {
+		return err
+	}
+	cat := lookupCatalog(resolveLang(viper.GetString("lang"), os.Getenv("LANG")))
+	if *output == "json" {
+		return jsonRenderer{cat: cat}.render(stdout, events)
+	}
+	return textRenderer{cat: cat, icons: loadIcons(false, nil)}.render(stdout, events)
+}
+
+// parseSemver parses a tag such as "v1.2.3", "1.2" or "v2.0.0-rc.1";
+// missing minor and patch numbers are zero and build metadata is ignored.
+func parseSemver(tag string) (semver, bool) {
+	s := strings.TrimPrefix(strings.TrimPrefix(tag, "v"), "V")
+	s, _, _ = strings.Cut(s, "+")
+	s, pre, _ := strings.Cut(s, "-")
+	parts := strings.Split(s, ".")
+	if len(parts) > 3 {
+		return semver{}, false
+	}
+	var nums [3]int
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return semver{}, false
+		}
+		nums[i] = n
+	}
+	return semver{major: nums[0], minor: nums[1], patch: nums[2], pre: pre}, true
+}
+
+// less reports whether v precedes w; a pre-release precedes its release.
+func (v semver) less(w semver) bool {
+	switch {
+	case v.major != w.major:
+		return v.major < w.major
+	case v.minor != w.minor:
+		return v.minor < w.minor
+	case v.patch != w.patch:
+		return v.patch < w.patch
+	case v.pre == "" || w.pre == "":
+		return v.pre != "" && w.pre == ""
+	}
+	return comparePre(v.pre, w.pre) < 0
+}
+
+// comparePre compares two pre-release parts identifier by identifier, as
+// semver orders them: numeric identifiers numerically and before the others,
+// which compare as text, and a shorter part first when its identifiers all
+// match, so that rc.9 precedes rc.10 and rc precedes rc.1.
+func comparePre(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := range min(len(as), len(bs)) {
+		x, y := as[i], bs[i]
+		xNum, yNum := isNumeric(x), isNumeric(y)
+		c := strings.Compare(x, y)
+		switch {
+		case xNum && yNum:
+			c = cmp.Or(cmp.Compare(len(x), len(y)), c)
+		case xNum:
+			c = -1
+		case yNum:
+			c = 1
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(len(as), len(bs))
+}
+
+// isNumeric reports whether s is a non-empty run of digits.
+func isNumeric(s string) bool {
+	return s != "" && strings.Trim(s, "0123456789") == ""
+}
+
+// bumpOf names the part of the version raised from prev to next: major,
+// minor or patch, or nothing when next is not above prev.
+func bumpOf(prev, next semver) string {
+	switch {
+	case !prev.less(next):
+		return ""
+	case next.major > prev.major:
+		return "major"
+	case next.major == prev.major && next.minor > prev.minor:
+		return "minor"
+	}
+	return "patch"
+}
+
+// releaseHistory fetches the latest 100 releases of a repository.
+func (f *repoFetcher) releaseHistory(name string) ([]publishedRelease, error) {
+	return cached(f.cache, "releases/"+name, func() ([]publishedRelease, error) {
+		u, err := f.repoURL(name, "releases?per_page=100")
+		if err != nil {
+			return nil, err
+		}
+		var rs []publishedRelease
+		if err := fetchJSON(f.hc, u, &rs); err != nil {
+			return nil, fmt.Errorf("fetch releases of %s: %w", name, err)
+		}
+		return rs, nil
+	})
+}
+
+// releaseSeeds returns, for each repository publishing releases among the
+// events, the highest version it published before the first of them, so
+// that the oldest release of the events page gets a bump too. Repositories
+// that no longer exist are ignored.
+func releaseSeeds(
+	events []ghEvent, historyOf func(name string) ([]publishedRelease, error),
+) (map[string]semver, error) {
+	first := map[string]time.Time{}
+	for _, ev := range events {
+		if !isDeploy(ev, "") || ev.Type != "ReleaseEvent" || ev.Payload.Release == nil {
+			continue
+		}
+		if at, ok := first[ev.Repo.Name]; !ok || ev.CreatedAt.Before(at) {
+			first[ev.Repo.Name] = ev.CreatedAt
+		}
+	}
+	seeds := map[string]semver{}
+	for _, name := range sortedKeys(first) {
+		history, err := historyOf(name)
+		switch {
+		case isStatus(err, http.StatusNotFound):
+			continue
+		case err != nil:
+			return nil, err
+		}
+		for _, r := range history {
+			v, ok := parseSemver(r.TagName)
+			if !ok || r.Draft || !r.PublishedAt.Before(first[name]) {
+				continue
+			}
+			if prev, seen := seeds[name]; !seen || prev.less(v) {
+				seeds[name] = v
+			}
+		}
+	}
+	return seeds, nil
+}
+
+// markBumps keeps the published releases, newest first, and sets the bump
+// of each against the highest earlier version of its repository, starting
+// from its seed. The first release of a repository without a seed, and tags
+// that are not semantic versions, have no bump.
+func markBumps(events []ghEvent, seeds map[string]semver) []ghEvent {
+	var releases []ghEvent
+	for _, ev := range events {
+		if isDeploy(ev, "") && ev.Type == "ReleaseEvent" && ev.Payload.Release != nil {
+			releases = append(releases, ev)
+		}
+	}
+	sort.SliceStable(releases, func(i, j int) bool { return releases[i].CreatedAt.Before(releases[j].CreatedAt) })
+	highest := map[string]semver{}
+	for name, v := range seeds {
+		highest[name] = v
+	}
+	for i, ev := range releases {
+		r := *ev.Payload.Release
+		r.Bump = ""
+		v, ok := parseSemver(r.TagName)
+		if ok {
+			if prev, seen := highest[ev.Repo.Name]; !seen {
+				highest[ev.Repo.Name] = v
+			} else if r.Bump = bumpOf(prev, v); r.Bump != "" {
+				highest[ev.Repo.Name] = v
+			}
+		}
+		releases[i].Payload.Release = &r
+	}
+	sort.SliceStable(releases, func(i, j int) bool { return releases[i].CreatedAt.After(releases[j].CreatedAt) })
+	return releases
+}
+
+// majorBumps keeps the releases bumping the major version.
+func majorBumps(releases []ghEvent) []ghEvent {
+	var out []ghEvent
+	for _, ev := range releases {
+		if ev.Payload.Release.Bump == "major" {
+			out = append(out, ev)
+		}
+	}
+	return out
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestUnitParseSemver(t *testing.T) {
+	testCases := []struct {
+		tag    string
+		want   semver
+		wantOK bool
+	}{
+		{tag: "v1.2.3", want: semver{major: 1, minor: 2, patch: 3}, wantOK: true},
+		{tag: "2.0", want: semver{major: 2}, wantOK: true},
+		{tag: "v2.0.0-rc.1+build.5", want: semver{major: 2, pre: "rc.1"}, wantOK: true},
+		{tag: "nightly"},
+		{tag: "v1.2.3.4"},
+		{tag: "v1..2"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.tag, func(t *testing.T) {
+			// Act
+			got, ok := parseSemver(tc.tag)
+			// Assert
+			assertEqual(t, ok, tc.wantOK)
+			assertEqual(t, got, tc.want)
+		})
+	}
+}
+
+func TestUnitBumpOf(t *testing.T) {
+	testCases := []struct {
+		prev string
+		next string
+		want string
+	}{
+		{prev: "v1.4.2", next: "v2.0.0", want: "major"},
+		{prev: "v1.4.2", next: "v1.5.0", want: "minor"},
+		{prev: "v1.4.2", next: "v1.4.3", want: "patch"},
+		{prev: "v2.0.0-rc.1", next: "v2.0.0", want: "patch"},
+		{prev: "v2.0.0", next: "v2.0.0-rc.1", want: ""},
+		{prev: "v2.0.0-rc.9", next: "v2.0.0-rc.10", want: "patch"},
+		{prev: "v2.0.0-rc.10", next: "v2.0.0-rc.9", want: ""},
+		{prev: "v2.0.0-alpha", next: "v2.0.0-alpha.1", want: "patch"},
+		{prev: "v2.0.0-alpha.1", next: "v2.0.0-alpha.beta", want: "patch"},
+		{prev: "v2.0.0-beta.11", next: "v2.0.0-rc.1", want: "patch"},
+		{prev: "v2.0.0", next: "v1.9.9", want: ""},
+		{prev: "v2.0.0", next: "v2.0.0", want: ""},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.prev+" to "+tc.next, func(t *testing.T) {
+			// Arrange
+			prev, _ := parseSemver(tc.prev)
+			next, _ := parseSemver(tc.next)
+			// Act
+			got := bumpOf(prev, next)
+			// Assert
+			assertEqual(t, got, tc.want)
+		})
+	}
+}
+
+func TestUnitMarkBumps(t *testing.T) {
+	// Arrange
+	day := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	published := func(name, tag string, days int) ghEvent {
+		return ghEvent{
+			ID: name + "@" + tag, Type: "ReleaseEvent", Repo: repo{Name: name}, CreatedAt: day.AddDate(0, 0, days),
+			Payload: payload{Action: "published", Release: &release{TagName: tag}},
+		}
+	}
+	drafted := published("octo/api", "v9.0.0", 5)
+	drafted.Payload.Action = "created"
+	events := []ghEvent{
+		published("octo/api", "v2.0.0", 4),
+		{ID: "push", Type: "PushEvent", Repo: repo{Name: "octo/api"}, CreatedAt: day},
+		drafted,
+		published("octo/api", "v1.4.3", 3),
+		published("octo/web", "v0.3.0", 2),
+		published("octo/api", "nightly", 2),
+		published("octo/api", "v1.4.2", 1),
+		published("octo/web", "v0.2.0", 0),
+	}
+	// Act
+	got := markBumps(events, nil)
+	seeded := markBumps(events, map[string]semver{"octo/web": {minor: 1}})
+	// Assert
+	var lines []string
+	for _, ev := range got {
+		lines = append(lines, ev.ID+"="+ev.Payload.Release.Bump)
+	}
+	assertEqual(t, fmt.Sprint(lines),
+		"[octo/api@v2.0.0=major octo/api@v1.4.3=patch octo/web@v0.3.0=minor octo/api@nightly= "+
+			"octo/api@v1.4.2= octo/web@v0.2.0=]")
+	assertEqual(t, len(majorBumps(got)), 1)
+	assertEqual(t, events[0].Payload.Release.Bump, "")
+	assertEqual(t, seeded[len(seeded)-1].Payload.Release.Bump, "minor")
+}
+
+func TestUnitReleaseSeeds(t *testing.T) {
+	// Arrange
+	day := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	events := []ghEvent{
+		{
+			Type: "ReleaseEvent", Repo: repo{Name: "octo/api"}, CreatedAt: day,
+			Payload: payload{Action: "published", Release: &release{TagName: "v2.0.0"}},
+		},
+		{
+			Type: "ReleaseEvent", Repo: repo{Name: "octo/gone"}, CreatedAt: day,
+			Payload: payload{Action: "published", Release: &release{TagName: "v1.0.0"}},
+		},
+		{Type: "PushEvent", Repo: repo{Name: "octo/web"}, CreatedAt: day},
+	}
+	var fetched []string
+	historyOf := func(name string) ([]publishedRelease, error) {
+		fetched = append(fetched, name)
+		if name == "octo/gone" {
+			return nil, &apiError{StatusCode: 404, Status: "404 Not Found"}
+		}
+		return []publishedRelease{
+			{TagName: "v2.0.0", PublishedAt: day},
+			{TagName: "v1.5.0", Draft: true},
+			{TagName: "v1.4.2", PublishedAt: day.AddDate(0, 0, -7)},
+			{TagName: "v1.10.0-rc.1", PublishedAt: day.AddDate(0, 0, -3)},
+			{TagName: "nightly", PublishedAt: day.AddDate(0, 0, -1)},
+		}, nil
+	}
+	// Act
+	seeds, err := releaseSeeds(events, historyOf)
+	// Assert
+	assertNoError(t, err)
+	assertEqual(t, fmt.Sprint(fetched), "[octo/api octo/gone]")
+	assertEqual(t, len(seeds), 1)
+	assertEqual(t, seeds["octo/api"], semver{major: 1, minor: 10, pre: "rc.1"})
+	assertEqual(t, bumpOf(seeds["octo/api"], semver{major: 2}), "major")
+}
+
+func TestUnitSummarizeRelease(t *testing.T) {
+	testCases := []struct {
+		name    string
+		release *release
+		want    string
+	}{
+		{name: "no details", want: "Published a release in octo/api"},
+		{name: "tag", release: &release{TagName: "v1.5.0", Bump: "minor"}, want: "Published v1.5.0 in octo/api"},
+		{
+			name:    "major bump",
+			release: &release{TagName: "v2.0.0", Bump: "major"},
+			want:    "Published v2.0.0 in octo/api, a major version bump",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			ev := ghEvent{Type: "ReleaseEvent", Repo: repo{Name: "octo/api"}, Payload: payload{Release: tc.release}}
+			// Act
+			got := summarize(catalogs[defaultLang], ev)
+			// Assert
+			assertEqual(t, got, tc.want)
+		})
+	}
+}
+
+// FuzzParseSemver checks that no tag makes the parser panic, and that the
+// order of the versions it parses is strict.
+func FuzzParseSemver(f *testing.F) {
+	for _, seed := range []string{"v1.2.3", "1.2", "v2.0.0-rc.1+build.5", "V10", "v-1", "1..2", ""} {
+		f.Add(seed, "v1.2.4")
+	}
+	f.Fuzz(func(t *testing.T, a, b string) {
+		v, okA := parseSemver(a)
+		w, okB := parseSemver(b)
+		if !okA || !okB {
+			return
+		}
+		if v.less(w) && w.less(v) {
+			t.Errorf("%q and %q precede each other", a, b)
+		}
+		if v.less(v) {
+			t.Errorf("%q precedes itself", a)
+		}
+	})
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+)
+
+type (
+	// configReloader applies the configuration file again when it changes,
+	// and restores the last good one when the new one does not apply.
+	configReloader struct {
+		path string
+		last []byte
+		// apply builds and installs the settings of the running command
+		// from the configuration, or fails leaving them untouched.
+		apply func() error
+		// changes signals a change of the file, coalescing bursts of
+		// writes; it is nil when the file is not watched.
+		changes <-chan struct{}
+		stop    func()
+	}
+	// swapHandler serves with the handler last stored, so that a reload
+	// replaces it without restarting the server.
+	swapHandler struct {
+		h atomic.Pointer[http.Handler]
+	}
+)
+
+// configMu guards the configuration while serve runs: a reload writes it
+// while the requests read it.
+var configMu sync.RWMutex
+
+// reloadDelay is how long the reloader waits for more writes after a change
+// of the file, as editors often write it in several steps.
+const reloadDelay = 200 * time.Millisecond
+
+// newConfigReloader returns a reloader of the configuration file of the
+// application directory, watching it until closed.
+func newConfigReloader(apply func() error) (*configReloader, error) {
+	dir, err := appDir(&defaultUserHome{})
+	if err != nil {
+		return nil, err
+	}
+	r := &configReloader{path: filepath.Join(dir, "config.yaml"), apply: apply, stop: func() {}}
+	r.last, _ = os.ReadFile(r.path)
+	ctx, cancel := context.WithCancel(context.Background())
+	changes, err := watchFile(ctx, r.path)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	r.changes, r.stop = changes, cancel
+	return r, nil
+}
+
+// close stops watching the file.
+func (r *configReloader) close() { r.stop() }
+
+// reload reads the file and applies it when it changed. When it does not
+// parse or apply, the previous configuration is restored and the error
+// returned.
+func (r *configReloader) reload() (bool, error) {
+	byt, changed, err := r.read()
+	if err != nil || !changed {
+		return false, err
+	}
+	if err := r.install(byt); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// read reads the file and reports whether it differs from the last good
+// one, failing when it does not parse; the configuration is left untouched.
+func (r *configReloader) read() ([]byte, bool, error) {
+	byt, err := os.ReadFile(r.path)
+	if err != nil {
+		return nil, false, fmt.Errorf("read the configuration file: %w", err)
+	}
+	if bytes.Equal(byt, r.last) {
+		return nil, false, nil
+	}
+	v := viper.New()
+	v.SetConfigType("yaml")
+	if err := v.ReadConfig(bytes.NewReader(byt)); err != nil {
+		return nil, false, fmt.Errorf("parse the configuration file: %w", err)
+	}
+	return byt, true, nil
+}
+
+// install replaces the configuration with byt and applies it, holding
+// configMu. When it does not apply, the previous configuration is restored.
+func (r *configReloader) install(byt []byte) error {
+	configMu.Lock()
+	defer configMu.Unlock()
+	if err := readConfig(byt); err != nil {
+		r.restore()
+		return err
+	}
+	if err := r.apply(); err != nil {
+		r.restore()
+		return err
+	}
+	r.last = byt
+	return nil
+}
+
+// next waits for a change of the file that parses, logging the rejected
+// ones, and reports false when ctx ends first.
+func (r *configReloader) next(ctx context.Context) ([]byte, bool) {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, false
+		case _, ok := <-r.changes:
+			if !ok {
+				r.changes = nil
+				continue
+			}
+			byt, changed, err := r.read()
+			if err != nil {
+				log.Printf("configuration rejected, keeping the previous one: %v", err)
+			} else if changed {
+				return byt, true
+			}
+		}
+	}
+}
+
+// restore reads the last good configuration again.
+func (r *configReloader) restore() {
+	if err := readConfig(r.last); err != nil {
+		log.Printf("restore the configuration: %v", err)
+	}
+}
+
+// readConfig replaces the settings of the configuration file with byt and
+// applies the active profile over them.
+func readConfig(byt []byte) error {
+	viper.SetConfigType("yaml")
+	if err := viper.ReadConfig(bytes.NewReader(byt)); err != nil {
+		return fmt.Errorf("parse the configuration file: %w", err)
+	}
+	return applyProfile()
+}
+
+// logReload reloads the configuration, logs the outcome and reports
+// whether a new configuration applies.
+func (r *configReloader) logReload() bool {
+	changed, err := r.reload()
+	switch {
+	case err != nil:
+		log.Printf("configuration rejected, keeping the previous one: %v", err)
+	case changed:
+		log.Printf("configuration reloaded")
+	}
+	return changed
+}
+
+// watchFile signals the changes of a file until ctx is done. The directory
+// is watched rather than the file, so that editors replacing the file by a
+// rename are followed.
+func watchFile(ctx context.Context, path string) (<-chan struct{}, error) {
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("watch the configuration file: %w", err)
+	}
+	if err := w.Add(filepath.Dir(path)); err != nil {
+		w.Close()
+		return nil, fmt.Errorf("watch the configuration file: %w", err)
+	}
+	changes := make(chan struct{}, 1)
+	go func() {
+		defer close(changes)
+		defer w.Close()
+		var settle <-chan time.Time
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case ev, ok := <-w.Events:
+				if !ok {
+					return
+				}
+				if filepath.Clean(ev.Name) == filepath.Clean(path) && !ev.Has(fsnotify.Chmod) {
+					settle = time.After(reloadDelay)
+				}
+			case err, ok := <-w.Errors:
+				if !ok {
+					return
+				}
+				log.Printf("watch the configuration file: %v", err)
+			case <-settle:
+				settle = nil
+				select {
+				case changes <- struct{}{}:
+				default:
+				}
+			}
+		}
+	}()
+	return changes, nil
+}
+
+func (s *swapHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	(*s.h.Load()).ServeHTTP(w, r)
+}
+
+// store makes h serve the next requests.
+func (s *swapHandler) store(h http.Handler) { s.h.Store(&h) }
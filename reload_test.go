@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func TestUnitConfigReloaderReload(t *testing.T) {
+	testCases := []struct {
+		name        string
+		next        string
+		applyErr    error
+		wantChanged bool
+		wantErr     bool
+		wantLang    string
+	}{
+		{name: "changed", next: "lang: fr\n", wantChanged: true, wantLang: "fr"},
+		{name: "unchanged", next: "lang: en\n", wantLang: "en"},
+		{name: "invalid yaml", next: "lang: [fr\n", wantErr: true, wantLang: "en"},
+		{name: "rejected", next: "lang: fr\n", applyErr: errors.New("bad rule"), wantErr: true, wantLang: "en"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			t.Cleanup(viper.Reset)
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			assertNoError(t, os.WriteFile(path, []byte("lang: en\n"), 0o600))
+			assertNoError(t, readConfig([]byte("lang: en\n")))
+			r := &configReloader{path: path, last: []byte("lang: en\n"), apply: func() error { return tc.applyErr }}
+			assertNoError(t, os.WriteFile(path, []byte(tc.next), 0o600))
+			// Act
+			changed, err := r.reload()
+			// Assert
+			if tc.wantErr {
+				assertNotNil(t, err)
+			} else {
+				assertNoError(t, err)
+			}
+			assertEqual(t, changed, tc.wantChanged)
+			assertEqual(t, viper.GetString("lang"), tc.wantLang)
+		})
+	}
+}
+
+func TestUnitWatchFile(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes, err := watchFile(ctx, path)
+	assertNoError(t, err)
+	// Act
+	assertNoError(t, os.WriteFile(path, []byte("lang: fr\n"), 0o600))
+	// Assert
+	select {
+	case <-changes:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no change signaled")
+	}
+	cancel()
+	for range changes {
+	}
+}
+
+func TestUnitSwapHandler(t *testing.T) {
+	// Arrange
+	var h swapHandler
+	h.store(http.NotFoundHandler())
+	h.store(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusTeapot) }))
+	rec := httptest.NewRecorder()
+	// Act
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	// Assert
+	assertEqual(t, rec.Code, http.StatusTeapot)
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// summarizer builds the message key and its arguments for one event type.
+type summarizer func(ev ghEvent) (string, []any)
+
+// summarizers covers the event types with a dedicated sentence; anything
+// else is rendered with the generic "other" message.
+var summarizers = map[string]summarizer{
+	"PushEvent": func(ev ghEvent) (string, []any) {
+		n := pushSize(ev)
+		if n == 1 {
+			return "push.one", []any{n, ev.Repo.Name}
+		}
+		return "push.other", []any{n, ev.Repo.Name}
+	},
+	"CreateEvent": func(ev ghEvent) (string, []any) {
+		if ev.Payload.RefType == "repository" || ev.Payload.Ref == "" {
+			return "create.repository", []any{ev.Repo.Name}
+		}
+		return "create." + ev.Payload.RefType, []any{ev.Payload.Ref, ev.Repo.Name}
+	},
+	"DeleteEvent": func(ev ghEvent) (string, []any) {
+		return "delete." + ev.Payload.RefType, []any{ev.Payload.Ref, ev.Repo.Name}
+	},
+	"IssuesEvent":                   withAction("issues"),
+	"IssueCommentEvent":             repoOnly("issue_comment"),
+	"PullRequestEvent":              withAction("pull_request"),
+	"PullRequestReviewEvent":        repoOnly("pull_request_review"),
+	"PullRequestReviewCommentEvent": repoOnly("pull_request_review"),
+	"WatchEvent":                    repoOnly("watch"),
+	"ForkEvent":                     repoOnly("fork"),
+	"PublicEvent":                   repoOnly("public"),
+	"MemberEvent":                   repoOnly("member"),
+	"DiscussionEvent":               withAction("discussion"),
+	"DiscussionCommentEvent":        repoOnly("discussion_comment"),
+	"DependabotAlertEvent":          withAction("dependabot_alert"),
+	"CodeScanningAlertEvent":        withAction("code_scanning_alert"),
+	"WorkflowRunEvent": func(ev ghEvent) (string, []any) {
+		run := ev.Payload.WorkflowRun
+		if run == nil {
+			return "workflow_run", []any{"", ev.Repo.Name}
+		}
+		if _, ok := catalogs[defaultLang]["workflow_run."+run.Conclusion]; ok {
+			return "workflow_run." + run.Conclusion, []any{run.Name, ev.Repo.Name}
+		}
+		return "workflow_run", []any{run.Name, ev.Repo.Name}
+	},
+	"SponsorshipEvent": func(ev ghEvent) (string, []any) {
+		if s := ev.Payload.Sponsorship; s != nil && s.Sponsor != "" {
+			return "sponsorship." + ev.Payload.Action, []any{s.Sponsor}
+		}
+		return "sponsorship", []any{ev.Repo.Name}
+	},
+	"ProjectsV2ItemEvent": func(ev ghEvent) (string, []any) {
+		p := ev.Payload.Project
+		switch {
+		case p != nil && ev.Payload.Action == "edited":
+			return "project_item.edited", []any{p.Title, p.Value, p.Project}
+		case p != nil && ev.Payload.Action == "assigned":
+			return "project_item.assigned", []any{p.Title, p.Project}
+		}
+		return "project_item", []any{ev.Repo.Name}
+	},
+	"ReleaseEvent": func(ev ghEvent) (string, []any) {
+		r := ev.Payload.Release
+		switch {
+		case r == nil:
+			return "release", []any{ev.Repo.Name}
+		case r.Bump == "major":
+			return "release.major", []any{r.TagName, ev.Repo.Name}
+		}
+		return "release.tag", []any{r.TagName, ev.Repo.Name}
+	},
+	"SecurityAdvisoryEvent": func(ev ghEvent) (string, []any) {
+		a := ev.Payload.Alert
+		switch {
+		case a == nil:
+			return "security_advisory", []any{"", ev.Repo.Name}
+		case a.Severity != "":
+			return "security_advisory.severity", []any{a.Advisory, a.Severity, ev.Repo.Name}
+		}
+		return "security_advisory", []any{a.Advisory, ev.Repo.Name}
+	},
+	"AuditLogEvent": func(ev ghEvent) (string, []any) {
+		return "audit", []any{ev.Payload.Action, ev.Actor.Login, ev.Repo.Name}
+	},
+}
+
+// repoOnly summarizes events whose sentence only names the repository.
+func repoOnly(key string) summarizer {
+	return func(ev ghEvent) (string, []any) {
+		return key, []any{ev.Repo.Name}
+	}
+}
+
+// withAction picks an action-specific message when one exists.
+func withAction(key string) summarizer {
+	return func(ev ghEvent) (string, []any) {
+		if _, ok := catalogs[defaultLang][key+"."+ev.Payload.Action]; ok {
+			return key + "." + ev.Payload.Action, []any{ev.Repo.Name}
+		}
+		return key, []any{ev.Repo.Name}
+	}
+}
+
+// summarize describes an event as a single natural-language sentence.
+func summarize(cat catalog, ev ghEvent) string {
+	s, ok := summarizers[ev.Type]
+	if !ok {
+		return cat.sprintf("other", strings.TrimSuffix(ev.Type, "Event"), ev.Repo.Name)
+	}
+	key, args := s(ev)
+	if _, ok := catalogs[defaultLang][key]; !ok {
+		return cat.sprintf("other", strings.TrimSuffix(ev.Type, "Event"), ev.Repo.Name)
+	}
+	return cat.sprintf(key, args...)
+}
+
+// renderer writes a list of events in one output format, redacted with
+// --redact.
+type renderer interface {
+	render(w io.Writer, events []ghEvent) error
+}
+
+// textRenderer holds the presentation options of the human-readable output.
+type textRenderer struct {
+	cat     catalog
+	icons   iconSet
+	tickets *ticketMatcher
+}
+
+// render writes one summary line per event, prefixed by its icon and
+// followed by repository details when the event was enriched, by the
+// signature status of checked commits and by the referenced tickets when a
+// tracker is configured.
+func (r textRenderer) render(w io.Writer, events []ghEvent) error {
+	events = redactedCopy(events)
+	for _, ev := range events {
+		line := r.icons.icon(ev.Type) + " " + summarize(r.cat, ev)
+		if details := repoDetails(ev.Repo.Meta); details != "" {
+			line += " (" + details + ")"
+		}
+		line += verificationAnnotation(ev)
+		line += checksAnnotation(r.icons, ev)
+		if ev.Local {
+			line += " (local only)"
+		}
+		if ev.Source != "" {
+			line += " [" + ev.Source + "]"
+		}
+		line += r.tickets.annotation(ev)
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return fmt.Errorf("write summary: %w", err)
+		}
+	}
+	return nil
+}
+
+// repoDetails describes enriched repository metadata, e.g. "Go, ★42, fork".
+func repoDetails(meta *repoMeta) string {
+	if meta == nil {
+		return ""
+	}
+	var parts []string
+	if meta.Language != "" {
+		parts = append(parts, meta.Language)
+	}
+	parts = append(parts, fmt.Sprintf("★%d", meta.Stars))
+	return strings.Join(append(parts, repoLabels(meta)...), ", ")
+}
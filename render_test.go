@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestUnitSummarize(t *testing.T) {
+	testCases := []struct {
+		name string
+		lang string
+		ev   ghEvent
+		want string
+	}{
+		{
+			name: "push plural",
+			lang: "en",
+			ev:   ghEvent{Type: "PushEvent", Repo: repo{Name: "octo/repo"}, Payload: payload{Size: 3}},
+			want: "Pushed 3 commits to octo/repo",
+		},
+		{
+			name: "push singular from commits",
+			lang: "en",
+			ev:   ghEvent{Type: "PushEvent", Repo: repo{Name: "octo/repo"}, Payload: payload{Commits: []commit{{}}}},
+			want: "Pushed 1 commit to octo/repo",
+		},
+		{
+			name: "push reordered arguments",
+			lang: "ja",
+			ev:   ghEvent{Type: "PushEvent", Repo: repo{Name: "octo/repo"}, Payload: payload{Size: 2}},
+			want: "octo/repo に 2 件のコミットをプッシュしました",
+		},
+		{
+			name: "issue opened in french",
+			lang: "fr",
+			ev:   ghEvent{Type: "IssuesEvent", Repo: repo{Name: "octo/repo"}, Payload: payload{Action: "opened"}},
+			want: "A ouvert un nouveau ticket dans octo/repo",
+		},
+		{
+			name: "unknown issue action",
+			lang: "en",
+			ev:   ghEvent{Type: "IssuesEvent", Repo: repo{Name: "octo/repo"}, Payload: payload{Action: "labeled"}},
+			want: "Updated an issue in octo/repo",
+		},
+		{
+			name: "branch creation in spanish",
+			lang: "es",
+			ev: ghEvent{
+				Type: "CreateEvent", Repo: repo{Name: "octo/repo"},
+				Payload: payload{Ref: "main", RefType: "branch"},
+			},
+			want: "Creó la rama main en octo/repo",
+		},
+		{
+			name: "unknown event type",
+			lang: "en",
+			ev:   ghEvent{Type: "GollumEvent", Repo: repo{Name: "octo/repo"}},
+			want: "Gollum in octo/repo",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			got := summarize(lookupCatalog(tc.lang), tc.ev)
+			// Assert
+			assertEqual(t, got, tc.want)
+		})
+	}
+}
+
+func TestUnitTextRenderer(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+	events := []ghEvent{
+		{Type: "WatchEvent", Repo: repo{Name: "octo/a"}},
+		{Type: "ForkEvent", Repo: repo{Name: "octo/b"}, Source: "work"},
+	}
+	// Act
+	r := textRenderer{cat: lookupCatalog("en"), icons: asciiIcons}
+	err := r.render(&buf, append(events, ghEvent{Type: "GollumEvent", Repo: repo{Name: "octo/c"}}))
+	// Assert
+	assertNoError(t, err)
+	assertEqual(t, buf.String(), "[star] Starred octo/a\n[fork] Forked octo/b [work]\n- Gollum in octo/c\n")
+}
+
+func TestUnitRepoDetails(t *testing.T) {
+	testCases := []struct {
+		name string
+		meta *repoMeta
+		want string
+	}{
+		{name: "not enriched", meta: nil, want: ""},
+		{name: "plain repository", meta: &repoMeta{Language: "Go", Stars: 42}, want: "Go, ★42"},
+		{
+			name: "archived fork without language",
+			meta: &repoMeta{Stars: 0, Fork: true, Archived: true},
+			want: "★0, fork, archived",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			got := repoDetails(tc.meta)
+			// Assert
+			assertEqual(t, got, tc.want)
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+type (
+	// clientConfig maps a client to its repositories; entries are owners
+	// such as "acme" or repository globs such as "bigco/web-*".
+	clientConfig struct {
+		Name  string   `mapstructure:"name"`
+		Repos []string `mapstructure:"repos"`
+	}
+	// clientReport is a client's activity over a month.
+	clientReport struct {
+		Client string
+		Month  string
+		Total  reportLine
+		Repos  []reportLine
+	}
+	// reportLine is the activity in one repository, or the total.
+	reportLine struct {
+		Repo     string
+		Sessions int
+		Hours    float64
+		Commits  int
+		PRs      int
+		Issues   int
+	}
+)
+
+// clientReportPage is a print-friendly page, ready to save as PDF.
+var clientReportPage = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>{{.Client}} – {{.Month}}</title>
+<style>
+body { font-family: sans-serif; margin: 2cm; }
+table { border-collapse: collapse; width: 100%; }
+th, td { border-bottom: 1px solid #ccc; padding: 4px 8px; text-align: right; }
+th:first-child, td:first-child { text-align: left; }
+@page { size: A4; margin: 2cm; }
+</style></head>
+<body>
+<h1>Activity report for {{.Client}}</h1>
+<p>{{.Month}}. Hours are estimated from activity sessions.</p>
+<table>
+<tr><th>Repository</th><th>Sessions</th><th>Estimated hours</th>
+<th>Commits</th><th>Pull requests</th><th>Issues</th></tr>
+{{- range .Repos}}
+<tr><td>{{.Repo}}</td><td>{{.Sessions}}</td><td>{{printf "%.1f" .Hours}}</td>
+<td>{{.Commits}}</td><td>{{.PRs}}</td><td>{{.Issues}}</td></tr>
+{{- end}}
+<tr><th>Total</th><th>{{.Total.Sessions}}</th><th>{{printf "%.1f" .Total.Hours}}</th>
+<th>{{.Total.Commits}}</th><th>{{.Total.PRs}}</th><th>{{.Total.Issues}}</th></tr>
+</table>
+</body>
+</html>
+`))
+
+// runReport prints the monthly activity report of each configured client.
+func runReport(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("report", flag.ContinueOnError)
+	month := flags.String("month", time.Now().AddDate(0, -1, 0).Format("2006-01"), "month to report, as YYYY-MM")
+	only := flags.String("client", "", "report only this client")
+	format := flags.String("format", "markdown", "output format: markdown or html")
+	gap := flags.Duration("gap", 90*time.Minute, "idle time splitting two work sessions")
+	lead := flags.Duration("lead", 30*time.Minute, "time credited before the first event of a session")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: go-github-activity report [flags] <username>")
+	}
+	if *format != "markdown" && *format != "html" {
+		return fmt.Errorf("unknown report format %q", *format)
+	}
+	start, err := time.ParseInLocation("2006-01", *month, time.Local)
+	if err != nil {
+		return fmt.Errorf("invalid month %q: %w", *month, err)
+	}
+	if err := loadConfig(); err != nil {
+		return err
+	}
+	var clients []clientConfig
+	if err := viper.UnmarshalKey("clients", &clients); err != nil {
+		return fmt.Errorf("parse clients: %w", err)
+	}
+	events, err := archivedEvents(flags.Arg(0), start)
+	if err != nil {
+		return err
+	}
+	events = eventsBetween(events, start, start.AddDate(0, 1, 0))
+	found := false
+	for _, c := range clients {
+		if *only != "" && !strings.EqualFold(c.Name, *only) {
+			continue
+		}
+		found = true
+		r := buildClientReport(c, events, *gap, *lead)
+		r.Month = start.Format("January 2006")
+		if err := writeClientReport(stdout, r, *format); err != nil {
+			return err
+		}
+	}
+	if !found {
+		return errors.New("no matching client in the clients config")
+	}
+	return nil
+}
+
+// owns reports whether a repository belongs to the client.
+func (c clientConfig) owns(repoName string) bool {
+	for _, pattern := range c.Repos {
+		if !strings.Contains(pattern, "/") {
+			pattern += "/*"
+		}
+		if globMatch(pattern, repoName) {
+			return true
+		}
+	}
+	return false
+}
+
+// buildClientReport totals the sessions and metrics of the client's
+// repositories, by repository name.
+func buildClientReport(c clientConfig, events []ghEvent, gap, lead time.Duration) clientReport {
+	var mine []ghEvent
+	for _, ev := range events {
+		if c.owns(ev.Repo.Name) {
+			mine = append(mine, ev)
+		}
+	}
+	lines := map[string]*reportLine{}
+	line := func(name string) *reportLine {
+		if lines[name] == nil {
+			lines[name] = &reportLine{Repo: name}
+		}
+		return lines[name]
+	}
+	for _, s := range clusterSessions(mine, gap) {
+		l := line(s.repo)
+		l.Sessions++
+		l.Hours += s.duration(lead).Hours()
+	}
+	for _, ev := range mine {
+		l := line(ev.Repo.Name)
+		l.Commits += metrics["commits"](ev)
+		l.PRs += metrics["pull-requests"](ev)
+		l.Issues += metrics["issues"](ev)
+	}
+	r := clientReport{Client: c.Name, Total: reportLine{Repo: "Total"}}
+	for _, name := range sortedKeys(lines) {
+		l := *lines[name]
+		r.Repos = append(r.Repos, l)
+		r.Total.Sessions += l.Sessions
+		r.Total.Hours += l.Hours
+		r.Total.Commits += l.Commits
+		r.Total.PRs += l.PRs
+		r.Total.Issues += l.Issues
+	}
+	return r
+}
+
+// writeClientReport renders the report as a Markdown table or an HTML page.
+func writeClientReport(w io.Writer, r clientReport, format string) error {
+	if format == "html" {
+		if err := clientReportPage.Execute(w, r); err != nil {
+			return fmt.Errorf("render report: %w", err)
+		}
+		return nil
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Activity report for %s\n\n%s. Hours are estimated from activity sessions.\n\n", r.Client, r.Month)
+	b.WriteString("| Repository | Sessions | Estimated hours | Commits | Pull requests | Issues |\n")
+	b.WriteString("|---|---:|---:|---:|---:|---:|\n")
+	for _, l := range append(r.Repos, r.Total) {
+		fmt.Fprintf(&b, "| %s | %d | %.1f | %d | %d | %d |\n", l.Repo, l.Sessions, l.Hours, l.Commits, l.PRs, l.Issues)
+	}
+	b.WriteString("\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
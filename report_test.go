@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUnitClientOwns(t *testing.T) {
+	c := clientConfig{Name: "Acme", Repos: []string{"acme", "bigco/web-*"}}
+	testCases := []struct {
+		repo string
+		want bool
+	}{
+		{repo: "acme/api", want: true},
+		{repo: "ACME/api", want: true},
+		{repo: "bigco/web-shop", want: true},
+		{repo: "bigco/payments", want: false},
+		{repo: "acmecorp/api", want: false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.repo, func(t *testing.T) {
+			// Act
+			got := c.owns(tc.repo)
+			// Assert
+			assertEqual(t, got, tc.want)
+		})
+	}
+}
+
+func TestUnitClientReport(t *testing.T) {
+	// Arrange
+	base := time.Date(2025, 3, 4, 9, 0, 0, 0, time.UTC)
+	events := []ghEvent{
+		{Type: "PushEvent", Repo: repo{Name: "acme/api"}, Payload: payload{Size: 3}, CreatedAt: base.Add(time.Hour)},
+		{
+			Type: "PullRequestEvent", Repo: repo{Name: "acme/api"},
+			Payload: payload{Action: "opened"}, CreatedAt: base,
+		},
+		{Type: "PushEvent", Repo: repo{Name: "other/x"}, Payload: payload{Size: 5}, CreatedAt: base},
+	}
+	c := clientConfig{Name: "Acme", Repos: []string{"acme"}}
+	// Act
+	r := buildClientReport(c, events, 90*time.Minute, 30*time.Minute)
+	r.Month = "March 2025"
+	var md, page bytes.Buffer
+	errMarkdown := writeClientReport(&md, r, "markdown")
+	errHTML := writeClientReport(&page, r, "html")
+	// Assert
+	assertNoError(t, errMarkdown)
+	assertNoError(t, errHTML)
+	assertEqual(t, len(r.Repos), 1)
+	assertEqual(t, r.Total, reportLine{Repo: "Total", Sessions: 1, Hours: 1.5, Commits: 3, PRs: 1})
+	assertEqual(t, strings.Contains(md.String(), "| acme/api | 1 | 1.5 | 3 | 1 | 0 |\n"), true)
+	assertEqual(t, strings.Contains(page.String(), "<td>acme/api</td><td>1</td><td>1.5</td>"), true)
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+type (
+	// repoFetcher resolves repository data from the GitHub API through a cache.
+	repoFetcher struct {
+		hc    *client
+		base  string
+		cache *fileCache
+	}
+	// repoMeta holds the repository fields used to enrich events.
+	repoMeta struct {
+		FullName    string `json:"full_name"`
+		Description string `json:"description"`
+		Stars       int    `json:"stargazers_count"`
+		Fork        bool   `json:"fork"`
+		Language    string `json:"language"`
+		Archived    bool   `json:"archived"`
+		Private     bool   `json:"private"`
+	}
+)
+
+// repoURL builds the API URL of a repository resource, e.g. "languages".
+func (f *repoFetcher) repoURL(name string, resource ...string) (string, error) {
+	owner, repoName, ok := strings.Cut(name, "/")
+	if !ok || owner == "" || repoName == "" {
+		return "", fmt.Errorf("invalid repository name %q", name)
+	}
+	parts := append([]string{f.base, "repos", url.PathEscape(owner), url.PathEscape(repoName)}, resource...)
+	return strings.Join(parts, "/"), nil
+}
+
+// repoOwner returns the user or organization part of "owner/name".
+func repoOwner(name string) string {
+	owner, _, _ := strings.Cut(name, "/")
+	return owner
+}
+
+// recentRepos returns the n repositories of a user pushed to last.
+func recentRepos(hc *client, base, login string, n int) ([]string, error) {
+	var owned []struct {
+		FullName string `json:"full_name"`
+	}
+	u := fmt.Sprintf("%s/users/%s/repos?sort=pushed&per_page=%d", base, url.PathEscape(login), n)
+	if err := fetchJSON(hc, u, &owned); err != nil {
+		return nil, fmt.Errorf("fetch repositories of %s: %w", login, err)
+	}
+	names := make([]string, 0, len(owned))
+	for _, r := range owned {
+		names = append(names, r.FullName)
+	}
+	return names, nil
+}
+
+// languages returns the bytes of code per language of a repository.
+func (f *repoFetcher) languages(name string) (map[string]int, error) {
+	return cached(f.cache, "languages/"+name, func() (map[string]int, error) {
+		u, err := f.repoURL(name, "languages")
+		if err != nil {
+			return nil, err
+		}
+		langs := map[string]int{}
+		if err := fetchJSON(f.hc, u, &langs); err != nil {
+			return nil, fmt.Errorf("fetch languages of %s: %w", name, err)
+		}
+		return langs, nil
+	})
+}
+
+// metadata returns the description, stars, fork and archived flags and
+// primary language of a repository.
+func (f *repoFetcher) metadata(name string) (repoMeta, error) {
+	return cached(f.cache, "repos/"+name, func() (repoMeta, error) {
+		u, err := f.repoURL(name)
+		if err != nil {
+			return repoMeta{}, err
+		}
+		var meta repoMeta
+		if err := fetchJSON(f.hc, u, &meta); err != nil {
+			return repoMeta{}, fmt.Errorf("fetch metadata of %s: %w", name, err)
+		}
+		return meta, nil
+	})
+}
+
+// enrichRepos attaches repository metadata to the events not enriched yet,
+// resolving each repository once. Repositories that no longer exist are left
+// without it.
+func enrichRepos(events []ghEvent, metadataOf func(name string) (repoMeta, error)) error {
+	resolved := map[string]*repoMeta{}
+	for i := range events {
+		if events[i].Repo.Meta != nil {
+			continue
+		}
+		name := events[i].Repo.Name
+		meta, ok := resolved[name]
+		if !ok {
+			m, err := metadataOf(name)
+			switch {
+			case err == nil:
+				meta = &m
+			case !isStatus(err, http.StatusNotFound):
+				return err
+			}
+			resolved[name] = meta
+		}
+		events[i].Repo.Meta = meta
+	}
+	return nil
+}
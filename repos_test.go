@@ -0,0 +1,99 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestUnitRepoFetcherMetadata(t *testing.T) {
+	// Arrange
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		assertEqual(t, r.URL.Path, "/repos/octo/repo")
+		w.Write([]byte(`{"full_name": "octo/repo", "stargazers_count": 42, "fork": true, "language": "Go"}`))
+	}))
+	t.Cleanup(srv.Close)
+	cache := &fileCache{dir: t.TempDir(), ttl: time.Hour, clock: systemClock{}}
+	f := &repoFetcher{hc: newClient(""), base: srv.URL, cache: cache}
+	// Act
+	meta, err := f.metadata("octo/repo")
+	again, _ := f.metadata("octo/repo")
+	// Assert
+	assertNoError(t, err)
+	assertEqual(t, meta, repoMeta{FullName: "octo/repo", Stars: 42, Fork: true, Language: "Go"})
+	assertEqual(t, again, meta)
+	assertEqual(t, calls, 1)
+}
+
+func TestUnitRepoURL(t *testing.T) {
+	f := &repoFetcher{base: "https://api.github.com"}
+	testCases := []struct {
+		name     string
+		repo     string
+		resource []string
+		want     string
+		wantErr  bool
+	}{
+		{name: "repository", repo: "octo/repo", want: "https://api.github.com/repos/octo/repo"},
+		{
+			name: "resource", repo: "octo/repo", resource: []string{"languages"},
+			want: "https://api.github.com/repos/octo/repo/languages",
+		},
+		{name: "missing owner", repo: "repo", wantErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			got, err := f.repoURL(tc.repo, tc.resource...)
+			// Assert
+			if tc.wantErr {
+				assertNotNil(t, err)
+				return
+			}
+			assertNoError(t, err)
+			assertEqual(t, got, tc.want)
+		})
+	}
+}
+
+func TestUnitEnrichRepos(t *testing.T) {
+	testCases := []struct {
+		name     string
+		fetchErr error
+		wantMeta bool
+		wantErr  bool
+	}{
+		{name: "resolved", wantMeta: true},
+		{name: "deleted repository", fetchErr: &apiError{StatusCode: 404}},
+		{name: "api failure", fetchErr: errors.New("boom"), wantErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			calls := 0
+			events := []ghEvent{
+				{Repo: repo{Name: "octo/a"}},
+				{Repo: repo{Name: "octo/a"}},
+				{Repo: repo{Name: "octo/b", Meta: &repoMeta{}}},
+			}
+			metadataOf := func(string) (repoMeta, error) {
+				calls++
+				return repoMeta{Stars: 1}, tc.fetchErr
+			}
+			// Act
+			err := enrichRepos(events, metadataOf)
+			// Assert
+			if tc.wantErr {
+				assertNotNil(t, err)
+				return
+			}
+			assertNoError(t, err)
+			assertEqual(t, calls, 1)
+			assertEqual(t, events[1].Repo.Meta != nil, tc.wantMeta)
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+type (
+	// retention holds the creation time before which events are pruned, by
+	// lowercased event type; the "default" entry applies to the other types.
+	retention map[string]time.Time
+	// compaction counts what compacting an archive kept and removed.
+	compaction struct {
+		kept       int
+		duplicates int
+		expired    int
+	}
+)
+
+// defaultRetention is the retention key applying to unlisted event types.
+const defaultRetention = "default"
+
+// agePattern matches an age such as 90d, 6w, 18m or 2y.
+var agePattern = regexp.MustCompile(`^(\d+)([dwmy])$`)
+
+// runPrune drops the archived events older than a given age, or than the
+// configured retention.
+func runPrune(args []string, stdout io.Writer) error {
+	return runCompaction("prune", systemClock{}, args, stdout)
+}
+
+// runCompact removes the duplicate and expired events of the archive, and
+// encrypts again the lines in clear or of earlier versions when a key is
+// configured.
+func runCompact(args []string, stdout io.Writer) error {
+	return runCompaction("compact", systemClock{}, args, stdout)
+}
+
+// runCompaction compacts the archive of the user named in args, dating the
+// retention cutoffs on the clock.
+func runCompaction(name string, clk clock, args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("archive "+name, flag.ContinueOnError)
+	olderThan := ""
+	if name == "prune" {
+		flags.StringVar(&olderThan, "older-than", "",
+			"drop every event older than this age, e.g. 90d, 6w, 18m or 2y, instead of the configured retention")
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: go-github-activity archive %s [flags] <username>", name)
+	}
+	if err := loadConfig(); err != nil {
+		return err
+	}
+	policy, err := retentionPolicy(viper.GetStringMapString("archive.retention"), olderThan, clk.Now())
+	if err != nil {
+		return err
+	}
+	a, err := openArchiveUpgrade(flags.Arg(0), name == "compact")
+	if err != nil {
+		return err
+	}
+	c, err := a.compact(policy)
+	if err != nil {
+		return err
+	}
+	return infof(stdout, "kept %d events, removed %d duplicates and %d expired events\n",
+		c.kept, c.duplicates, c.expired)
+}
+
+// retentionPolicy turns the configured ages by event type into cutoffs. A
+// non-empty olderThan applies to every event type instead.
+func retentionPolicy(ages map[string]string, olderThan string, now time.Time) (retention, error) {
+	if olderThan != "" {
+		ages = map[string]string{defaultRetention: olderThan}
+	}
+	policy := retention{}
+	for kind, age := range ages {
+		cutoff, err := ageCutoff(age, now)
+		if err != nil {
+			return nil, fmt.Errorf("retention of %s: %w", kind, err)
+		}
+		policy[strings.ToLower(kind)] = cutoff
+	}
+	return policy, nil
+}
+
+// ageCutoff returns the time the given age before now.
+func ageCutoff(age string, now time.Time) (time.Time, error) {
+	m := agePattern.FindStringSubmatch(age)
+	if m == nil {
+		return time.Time{}, fmt.Errorf("invalid age %q, want a number followed by d, w, m or y", age)
+	}
+	n, err := strconv.Atoi(m[1])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid age %q: %w", age, err)
+	}
+	switch m[2] {
+	case "d":
+		return now.AddDate(0, 0, -n), nil
+	case "w":
+		return now.AddDate(0, 0, -7*n), nil
+	case "m":
+		return now.AddDate(0, -n, 0), nil
+	default:
+		return now.AddDate(-n, 0, 0), nil
+	}
+}
+
+// expired reports whether the policy prunes the event.
+func (r retention) expired(ev ghEvent) bool {
+	cutoff, ok := r[strings.ToLower(ev.Type)]
+	if !ok {
+		cutoff = r[defaultRetention]
+	}
+	return ev.CreatedAt.Before(cutoff)
+}
+
+// unexpired returns the events the policy keeps, e.g. the fetched events
+// worth archiving.
+func (r retention) unexpired(events []ghEvent) []ghEvent {
+	kept := make([]ghEvent, 0, len(events))
+	for _, ev := range events {
+		if !r.expired(ev) {
+			kept = append(kept, ev)
+		}
+	}
+	return kept
+}
+
+// compact rewrites the archive without duplicate events, blank lines and
+// events the policy prunes. Unless archive.keep_raw is set, it also drops
+// the raw payloads.
+func (a *archive) compact(policy retention) (compaction, error) {
+	var c compaction
+	seen := map[string]bool{}
+	keepRaw := viper.GetBool("archive.keep_raw")
+	err := a.rewrite(func(ev *ghEvent) bool {
+		if !keepRaw {
+			ev.Raw = nil
+		}
+		switch {
+		case seen[ev.ID]:
+			c.duplicates++
+			return false
+		case policy.expired(*ev):
+			c.expired++
+			return false
+		}
+		seen[ev.ID] = true
+		c.kept++
+		return true
+	})
+	if err != nil {
+		return compaction{}, err
+	}
+	return c, nil
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestUnitAgeCutoff(t *testing.T) {
+	now := time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name    string
+		age     string
+		want    time.Time
+		wantErr bool
+	}{
+		{name: "days", age: "90d", want: now.AddDate(0, 0, -90)},
+		{name: "weeks", age: "6w", want: now.AddDate(0, 0, -42)},
+		{name: "months", age: "18m", want: now.AddDate(0, -18, 0)},
+		{name: "years", age: "2y", want: now.AddDate(-2, 0, 0)},
+		{name: "missing unit", age: "30", wantErr: true},
+		{name: "unknown unit", age: "3h", wantErr: true},
+		{name: "negative", age: "-1y", wantErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			got, err := ageCutoff(tc.age, now)
+			// Assert
+			if tc.wantErr {
+				assertNotNil(t, err)
+				return
+			}
+			assertNoError(t, err)
+			assertEqual(t, got, tc.want)
+		})
+	}
+}
+
+func TestUnitRetentionExpired(t *testing.T) {
+	now := time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC)
+	ages := map[string]string{"default": "1y", "watchevent": "30d"}
+	testCases := []struct {
+		name      string
+		olderThan string
+		ev        ghEvent
+		want      bool
+	}{
+		{name: "recent push", ev: ghEvent{Type: "PushEvent", CreatedAt: now.AddDate(0, -6, 0)}, want: false},
+		{name: "old push", ev: ghEvent{Type: "PushEvent", CreatedAt: now.AddDate(-2, 0, 0)}, want: true},
+		{name: "star past its retention", ev: ghEvent{Type: "WatchEvent", CreatedAt: now.AddDate(0, -2, 0)}, want: true},
+		{name: "recent star", ev: ghEvent{Type: "WatchEvent", CreatedAt: now.AddDate(0, 0, -7)}, want: false},
+		{
+			name: "older than overrides the types", olderThan: "3y",
+			ev: ghEvent{Type: "WatchEvent", CreatedAt: now.AddDate(-2, 0, 0)}, want: false,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			policy, err := retentionPolicy(ages, tc.olderThan, now)
+			assertNoError(t, err)
+			// Act
+			got := policy.expired(tc.ev)
+			// Assert
+			assertEqual(t, got, tc.want)
+		})
+	}
+}
+
+func TestUnitRetentionPolicyInvalid(t *testing.T) {
+	// Act
+	_, err := retentionPolicy(map[string]string{"pushevent": "forever"}, "", time.Now())
+	// Assert
+	assertNotNil(t, err)
+}
+
+func TestUnitArchiveCompact(t *testing.T) {
+	// Arrange
+	now := time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC)
+	path := filepath.Join(t.TempDir(), "octocat.jsonl")
+	lines := `{"id":"1","type":"PushEvent","created_at":"2022-01-01T00:00:00Z"}` + "\n\n" +
+		`{"id":"2","type":"PushEvent","created_at":"2025-03-01T00:00:00Z"}` + "\n" +
+		`{"id":"2","type":"PushEvent","created_at":"2025-03-01T00:00:00Z"}` + "\n" +
+		`{"id":"3","type":"WatchEvent","created_at":"2025-03-10T00:00:00Z"}` + "\n"
+	os.WriteFile(path, []byte(lines), 0o600)
+	a := &archive{path: path}
+	policy, _ := retentionPolicy(map[string]string{"default": "1y"}, "", now)
+	// Act
+	c, err := a.compact(policy)
+	events, errLoad := a.load()
+	// Assert
+	assertNoError(t, err)
+	assertNoError(t, errLoad)
+	assertEqual(t, c, compaction{kept: 2, duplicates: 1, expired: 1})
+	assertEqual(t, len(events), 2)
+	assertEqual(t, events[0].ID, "3")
+	assertEqual(t, events[1].ID, "2")
+	leftovers, _ := filepath.Glob(filepath.Join(filepath.Dir(path), ".rewrite-*"))
+	assertEqual(t, len(leftovers), 0)
+}
+
+func TestUnitArchiveCompactMissing(t *testing.T) {
+	// Arrange
+	a := &archive{path: filepath.Join(t.TempDir(), "octocat.jsonl")}
+	// Act
+	c, err := a.compact(retention{})
+	// Assert
+	assertNoError(t, err)
+	assertEqual(t, c, compaction{})
+	_, statErr := os.Stat(a.path)
+	assertEqual(t, os.IsNotExist(statErr), true)
+}
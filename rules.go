@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// routeRule sends the events matching every set criterion to the named
+// notifiers. Repo and actor are case-insensitive glob patterns, e.g.
+// "org/payments" or "alnah/*".
+type routeRule struct {
+	Types  []string `mapstructure:"types"`
+	Repo   string   `mapstructure:"repo"`
+	Actor  string   `mapstructure:"actor"`
+	Notify []string `mapstructure:"notify"`
+}
+
+// loadRules reads a rules config list, e.g. rules, and checks it against
+// the targets.
+func loadRules(key string, targets map[string]notifier) ([]routeRule, error) {
+	var rules []routeRule
+	if err := viper.UnmarshalKey(key, &rules); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", key, err)
+	}
+	for i, r := range rules {
+		if len(r.Notify) == 0 {
+			return nil, fmt.Errorf("rule %d: no notify target", i+1)
+		}
+		for _, name := range r.Notify {
+			if _, ok := targets[name]; !ok {
+				return nil, fmt.Errorf("rule %d: unknown notifier %q", i+1, name)
+			}
+		}
+		for _, pattern := range []string{r.Repo, r.Actor} {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("rule %d: invalid pattern %q: %w", i+1, pattern, err)
+			}
+		}
+	}
+	return rules, nil
+}
+
+// matches reports whether the event satisfies every criterion of the rule.
+func (r routeRule) matches(ev ghEvent) bool {
+	if len(r.Types) > 0 && !slices.Contains(r.Types, ev.Type) {
+		return false
+	}
+	return globMatch(r.Repo, ev.Repo.Name) && globMatch(r.Actor, ev.Actor.Login)
+}
+
+// globMatch matches s against a case-insensitive pattern; an empty pattern
+// matches anything.
+func globMatch(pattern, s string) bool {
+	if pattern == "" {
+		return true
+	}
+	ok, _ := path.Match(strings.ToLower(pattern), strings.ToLower(s))
+	return ok
+}
+
+// route returns the names of the targets an event goes to, in rule order.
+// Without rules every target receives every event.
+func route(rules []routeRule, targets map[string]notifier, ev ghEvent) []string {
+	if len(rules) == 0 {
+		return sortedKeys(targets)
+	}
+	var names []string
+	for _, r := range rules {
+		if !r.matches(ev) {
+			continue
+		}
+		for _, name := range r.Notify {
+			if !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
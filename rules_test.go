@@ -0,0 +1,95 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestUnitRoute(t *testing.T) {
+	targets := map[string]notifier{"payments": nil, "phone": nil, "all": nil}
+	rules := []routeRule{
+		{Types: []string{"PullRequestEvent"}, Repo: "org/payments", Notify: []string{"payments"}},
+		{Types: []string{"WatchEvent"}, Repo: "Alnah/*", Notify: []string{"phone"}},
+		{Actor: "dependabot*", Notify: []string{"all", "payments"}},
+	}
+	testCases := []struct {
+		name  string
+		rules []routeRule
+		ev    ghEvent
+		want  string
+	}{
+		{
+			name:  "pull request in payments",
+			rules: rules,
+			ev:    ghEvent{Type: "PullRequestEvent", Repo: repo{Name: "org/payments"}},
+			want:  "payments",
+		},
+		{
+			name:  "star on own repository",
+			rules: rules,
+			ev:    ghEvent{Type: "WatchEvent", Repo: repo{Name: "alnah/tool"}},
+			want:  "phone",
+		},
+		{
+			name:  "star elsewhere",
+			rules: rules,
+			ev:    ghEvent{Type: "WatchEvent", Repo: repo{Name: "octo/tool"}},
+			want:  "",
+		},
+		{
+			name:  "several rules without duplicates",
+			rules: rules,
+			ev:    ghEvent{Type: "PullRequestEvent", Actor: actor{Login: "dependabot[bot]"}, Repo: repo{Name: "org/payments"}},
+			want:  "payments,all",
+		},
+		{
+			name: "no rules",
+			ev:   ghEvent{Type: "PushEvent"},
+			want: "all,payments,phone",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			got := route(tc.rules, targets, tc.ev)
+			// Assert
+			assertEqual(t, strings.Join(got, ","), tc.want)
+		})
+	}
+}
+
+func TestUnitLoadRules(t *testing.T) {
+	targets := map[string]notifier{"phone": nil}
+	testCases := []struct {
+		name    string
+		rules   []map[string]any
+		wantErr bool
+	}{
+		{
+			name:  "valid",
+			rules: []map[string]any{{"types": []string{"WatchEvent"}, "repo": "alnah/*", "notify": []string{"phone"}}},
+		},
+		{name: "no rules"},
+		{name: "unknown target", rules: []map[string]any{{"notify": []string{"slack"}}}, wantErr: true},
+		{name: "no target", rules: []map[string]any{{"repo": "a/b"}}, wantErr: true},
+		{name: "bad pattern", rules: []map[string]any{{"repo": "[", "notify": []string{"phone"}}}, wantErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			viper.Set("rules", tc.rules)
+			t.Cleanup(func() { viper.Set("rules", nil) })
+			// Act
+			got, err := loadRules("rules", targets)
+			// Assert
+			if tc.wantErr {
+				assertNotNil(t, err)
+				return
+			}
+			assertNoError(t, err)
+			assertEqual(t, len(got), len(tc.rules))
+		})
+	}
+}
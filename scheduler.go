@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+type (
+	// scheduledJob runs a subcommand on a cron schedule, e.g. a nightly
+	// sync; its output is written to a file or logged.
+	scheduledJob struct {
+		Name     string   `mapstructure:"name"`
+		Cron     string   `mapstructure:"cron"`
+		Args     []string `mapstructure:"args"`
+		Output   string   `mapstructure:"output"`
+		schedule cronSchedule
+		running  atomic.Bool
+	}
+	// scheduler starts the jobs due at each minute.
+	scheduler struct {
+		jobs  []*scheduledJob
+		clock clock
+		// running counts the jobs started and not ended yet.
+		running sync.WaitGroup
+	}
+	// jobCommand runs the subcommand of a job until ctx ends.
+	jobCommand func(ctx context.Context, args []string, stdout io.Writer) error
+)
+
+// schedulableCommands lists the subcommands a job may run; long-running ones
+// such as watch and serve are left out.
+var schedulableCommands = map[string]jobCommand{
+	"sync":           syncContext,
+	"digest":         unstoppable(runDigest),
+	"readme-section": unstoppable(runReadmeSection),
+	"badge":          unstoppable(runBadge),
+	"stats":          unstoppable(runStats),
+	"heatmap":        unstoppable(runHeatmap),
+}
+
+// unstoppable runs a command short enough to be left to end once started,
+// reading the archive or a page of events; it does not start once ctx ended.
+func unstoppable(cmd command) jobCommand {
+	return func(ctx context.Context, args []string, stdout io.Writer) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		return cmd(args, stdout)
+	}
+}
+
+// loadSchedule reads and checks the schedule config list.
+func loadSchedule() ([]*scheduledJob, error) {
+	var jobs []*scheduledJob
+	if err := viper.UnmarshalKey("schedule", &jobs); err != nil {
+		return nil, fmt.Errorf("parse schedule: %w", err)
+	}
+	for i, j := range jobs {
+		if j.Name == "" {
+			j.Name = fmt.Sprintf("job %d", i+1)
+		}
+		s, err := parseCron(j.Cron)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", j.Name, err)
+		}
+		j.schedule = s
+		if len(j.Args) == 0 {
+			return nil, fmt.Errorf("%s: no command", j.Name)
+		}
+		if _, ok := schedulableCommands[j.Args[0]]; !ok {
+			return nil, fmt.Errorf("%s: command %q cannot be scheduled", j.Name, j.Args[0])
+		}
+	}
+	return jobs, nil
+}
+
+// newScheduler returns a scheduler on the wall clock.
+func newScheduler(jobs []*scheduledJob) *scheduler {
+	return &scheduler{jobs: jobs, clock: systemClock{}}
+}
+
+// start checks the jobs at the beginning of every minute until ctx ends,
+// then waits for the running jobs, stopped by ctx, to end.
+func (s *scheduler) start(ctx context.Context) {
+	for {
+		now := s.clock.Now()
+		next := now.Truncate(time.Minute).Add(time.Minute)
+		if !sleepContext(ctx, s.clock, next.Sub(now)) {
+			s.running.Wait()
+			return
+		}
+		s.tick(ctx, next)
+	}
+}
+
+// tick starts the jobs due at t. A job still running from a previous tick
+// is skipped rather than started twice.
+func (s *scheduler) tick(ctx context.Context, t time.Time) []*scheduledJob {
+	var started []*scheduledJob
+	for _, j := range s.jobs {
+		if !j.schedule.matches(t) {
+			continue
+		}
+		if !j.running.CompareAndSwap(false, true) {
+			log.Printf("schedule: %s still running, skipped", j.Name)
+			continue
+		}
+		started = append(started, j)
+		s.running.Add(1)
+		go func() {
+			defer s.running.Done()
+			defer j.running.Store(false)
+			if err := j.run(ctx); err != nil {
+				log.Printf("schedule: %s: %v", j.Name, err)
+			}
+		}()
+	}
+	return started
+}
+
+// run executes the job command until ctx ends, writing its output to the
+// output file.
+func (j *scheduledJob) run(ctx context.Context) error {
+	var w io.Writer = log.Writer()
+	if j.Output != "" {
+		f, err := os.Create(j.Output)
+		if err != nil {
+			return fmt.Errorf("create job output: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+	log.Printf("schedule: running %s (%s)", j.Name, strings.Join(j.Args, " "))
+	return schedulableCommands[j.Args[0]](ctx, j.Args[1:], w)
+}
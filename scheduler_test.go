@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"io"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func TestUnitLoadSchedule(t *testing.T) {
+	testCases := []struct {
+		name     string
+		schedule []map[string]any
+		wantName string
+		wantErr  bool
+	}{
+		{
+			name:     "valid job",
+			schedule: []map[string]any{{"cron": "0 * * * *", "args": []string{"sync", "octocat"}}},
+			wantName: "job 1",
+		},
+		{name: "bad cron", schedule: []map[string]any{{"cron": "hourly", "args": []string{"sync"}}}, wantErr: true},
+		{name: "no command", schedule: []map[string]any{{"cron": "@hourly"}}, wantErr: true},
+		{
+			name:     "long-running command",
+			schedule: []map[string]any{{"cron": "@hourly", "args": []string{"watch", "octocat"}}},
+			wantErr:  true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			viper.Set("schedule", tc.schedule)
+			t.Cleanup(func() { viper.Set("schedule", nil) })
+			// Act
+			got, err := loadSchedule()
+			// Assert
+			if tc.wantErr {
+				assertNotNil(t, err)
+				return
+			}
+			assertNoError(t, err)
+			assertEqual(t, got[0].Name, tc.wantName)
+		})
+	}
+}
+
+func TestUnitSchedulerTick(t *testing.T) {
+	hourly, _ := parseCron("0 * * * *")
+	everyMinute, _ := parseCron("* * * * *")
+	testCases := []struct {
+		name        string
+		busy        bool
+		wantStarted string
+	}{
+		{name: "due jobs start", wantStarted: "minutely,hourly"},
+		{name: "running job skipped", busy: true, wantStarted: "hourly"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			jobs := []*scheduledJob{
+				{Name: "minutely", Args: []string{"digest", "--days", "-1", "octocat"}, schedule: everyMinute},
+				{Name: "hourly", Args: []string{"digest", "--days", "-1", "octocat"}, schedule: hourly},
+			}
+			jobs[0].running.Store(tc.busy)
+			s := &scheduler{jobs: jobs}
+			// Act
+			started := s.tick(context.Background(), time.Date(2025, 3, 3, 10, 0, 0, 0, time.UTC))
+			s.running.Wait()
+			// Assert
+			var names []string
+			for _, j := range started {
+				names = append(names, j.Name)
+			}
+			assertEqual(t, strings.Join(names, ","), tc.wantStarted)
+		})
+	}
+}
+
+func TestUnitSchedulerStart(t *testing.T) {
+	// Arrange
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	clk := &fakeClock{now: time.Date(2025, 3, 3, 10, 0, 30, 0, time.UTC), limit: 3, cancel: cancel}
+	s := &scheduler{clock: clk}
+	// Act
+	s.start(ctx)
+	// Assert
+	assertEqual(t, len(clk.waits), 3)
+	assertEqual(t, clk.waits[0], 30*time.Second)
+	assertEqual(t, clk.waits[1], time.Minute)
+}
+
+func TestUnitSchedulerStopsJobs(t *testing.T) {
+	// Arrange
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var stopped atomic.Bool
+	schedulableCommands["blocking"] = func(ctx context.Context, _ []string, _ io.Writer) error {
+		<-ctx.Done()
+		stopped.Store(true)
+		return ctx.Err()
+	}
+	t.Cleanup(func() { delete(schedulableCommands, "blocking") })
+	everyMinute, _ := parseCron("* * * * *")
+	clk := &fakeClock{now: time.Date(2025, 3, 3, 10, 0, 30, 0, time.UTC), limit: 2, cancel: cancel}
+	s := &scheduler{
+		jobs:  []*scheduledJob{{Name: "blocking", Args: []string{"blocking"}, schedule: everyMinute}},
+		clock: clk,
+	}
+	// Act
+	s.start(ctx)
+	// Assert
+	assertEqual(t, stopped.Load(), true)
+	assertEqual(t, s.jobs[0].running.Load(), false)
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+)
+
+type (
+	// migration upgrades the archived events to a schema version. apply
+	// modifies an event in place and returns false to drop it.
+	migration struct {
+		version     int
+		description string
+		apply       func(ev *ghEvent) bool
+	}
+	// integrityReport lists the problems found in an archive.
+	integrityReport struct {
+		events   int
+		version  int
+		problems []string
+	}
+)
+
+// migrations lists the schema changes of the archive by increasing version.
+// Version 1 is the layout of the archives written before versioning; a
+// change to the archived model adds a migration here.
+var migrations []migration
+
+// latestSchema returns the schema version after every migration.
+func latestSchema(ms []migration) int {
+	if len(ms) == 0 {
+		return 1
+	}
+	return ms[len(ms)-1].version
+}
+
+// schemaPath is the file holding the schema version of the archive.
+func (a *archive) schemaPath() string { return a.path + ".schema" }
+
+// schemaVersion returns the schema version of the archive. Archives without
+// a version file are at version 1, missing archives at the latest version.
+func (a *archive) schemaVersion(latest int) (int, error) {
+	byt, err := os.ReadFile(a.schemaPath())
+	if errors.Is(err, fs.ErrNotExist) {
+		if _, err := os.Stat(a.path); errors.Is(err, fs.ErrNotExist) {
+			return latest, nil
+		}
+		return 1, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("read the archive schema: %w", err)
+	}
+	v, err := strconv.Atoi(strings.TrimSpace(string(byt)))
+	if err != nil {
+		return 0, fmt.Errorf("parse the archive schema: %w", err)
+	}
+	return v, nil
+}
+
+// setSchemaVersion records the schema version of the archive.
+func (a *archive) setSchemaVersion(v int) error {
+	if err := os.WriteFile(a.schemaPath(), []byte(strconv.Itoa(v)+"\n"), 0o600); err != nil {
+		return fmt.Errorf("write the archive schema: %w", err)
+	}
+	return nil
+}
+
+// migrate applies the migrations newer than the archive, one rewrite each,
+// recording the version after every step so an interrupted migration
+// resumes where it stopped. It refuses archives written by a newer release.
+func (a *archive) migrate(ms []migration) ([]migration, error) {
+	latest := latestSchema(ms)
+	v, err := a.schemaVersion(latest)
+	if err != nil {
+		return nil, err
+	}
+	if v > latest {
+		return nil, fmt.Errorf("archive schema version %d is newer than the supported version %d", v, latest)
+	}
+	var applied []migration
+	for _, m := range ms {
+		if m.version <= v {
+			continue
+		}
+		if err := a.rewrite(m.apply); err != nil {
+			return applied, fmt.Errorf("migrate the archive to version %d: %w", m.version, err)
+		}
+		if err := a.setSchemaVersion(m.version); err != nil {
+			return applied, err
+		}
+		applied = append(applied, m)
+	}
+	return applied, nil
+}
+
+// runCheck verifies the integrity of the archive of a user.
+func runCheck(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("archive check", flag.ContinueOnError)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: go-github-activity archive check <username>")
+	}
+	if err := loadConfig(); err != nil {
+		return err
+	}
+	a, err := openArchive(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	r, err := a.check(latestSchema(migrations))
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(stdout, "checked %d events at schema version %d\n", r.events, r.version); err != nil {
+		return err
+	}
+	for _, p := range r.problems {
+		if _, err := fmt.Fprintln(stdout, p); err != nil {
+			return err
+		}
+	}
+	if len(r.problems) > 0 {
+		return fmt.Errorf("the archive has %d problem%s", len(r.problems), plural(len(r.problems)))
+	}
+	return nil
+}
+
+// check reads every line of the archive and reports the lines that do not
+// decode, duplicate events, events without ID or creation time, and a
+// schema version other than latest.
+func (a *archive) check(latest int) (integrityReport, error) {
+	var r integrityReport
+	v, err := a.schemaVersion(latest)
+	if err != nil {
+		return r, err
+	}
+	r.version = v
+	if v != latest {
+		r.problems = append(r.problems, fmt.Sprintf("schema version %d, want %d", v, latest))
+	}
+	f, err := os.Open(a.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return r, nil
+	}
+	if err != nil {
+		return r, fmt.Errorf("open the archive: %w", err)
+	}
+	defer f.Close()
+	seen := map[string]int{}
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; sc.Scan(); line++ {
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+		var ev ghEvent
+		if err := a.decode(sc.Bytes(), &ev); err != nil {
+			r.problems = append(r.problems, fmt.Sprintf("line %d: %v", line, err))
+			continue
+		}
+		r.events++
+		switch {
+		case ev.ID == "":
+			r.problems = append(r.problems, fmt.Sprintf("line %d: event without ID", line))
+		case seen[ev.ID] > 0:
+			r.problems = append(r.problems, fmt.Sprintf("line %d: duplicate of event %s on line %d", line, ev.ID, seen[ev.ID]))
+		default:
+			seen[ev.ID] = line
+		}
+		if ev.CreatedAt.IsZero() {
+			r.problems = append(r.problems, fmt.Sprintf("line %d: event %s without creation time", line, ev.ID))
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return r, fmt.Errorf("read the archive: %w", err)
+	}
+	return r, nil
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUnitArchiveMigrate(t *testing.T) {
+	ms := []migration{
+		{version: 2, description: "rename repositories", apply: func(ev *ghEvent) bool {
+			ev.Repo.Name = strings.ToLower(ev.Repo.Name)
+			return true
+		}},
+		{version: 3, description: "drop stars", apply: func(ev *ghEvent) bool { return ev.Type != "WatchEvent" }},
+	}
+	lines := `{"id":"1","type":"PushEvent","repo":{"name":"Octocat/Hello"},"created_at":"2025-03-01T00:00:00Z"}` + "\n" +
+		`{"id":"2","type":"WatchEvent","repo":{"name":"Octocat/Hello"},"created_at":"2025-03-02T00:00:00Z"}` + "\n"
+	testCases := []struct {
+		name     string
+		version  string
+		applied  int
+		events   int
+		repoName string
+		wantErr  bool
+	}{
+		{name: "unversioned archive", applied: 2, events: 1, repoName: "octocat/hello"},
+		{name: "resumes after version 2", version: "2", applied: 1, events: 1, repoName: "Octocat/Hello"},
+		{name: "up to date", version: "3", applied: 0, events: 2, repoName: "Octocat/Hello"},
+		{name: "newer release", version: "4", wantErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			a := &archive{path: filepath.Join(t.TempDir(), "octocat.jsonl")}
+			os.WriteFile(a.path, []byte(lines), 0o600)
+			if tc.version != "" {
+				os.WriteFile(a.schemaPath(), []byte(tc.version), 0o600)
+			}
+			// Act
+			applied, err := a.migrate(ms)
+			// Assert
+			if tc.wantErr {
+				assertNotNil(t, err)
+				return
+			}
+			assertNoError(t, err)
+			assertEqual(t, len(applied), tc.applied)
+			version, _ := a.schemaVersion(3)
+			assertEqual(t, version, 3)
+			events, _ := a.load()
+			assertEqual(t, len(events), tc.events)
+			assertEqual(t, events[len(events)-1].Repo.Name, tc.repoName)
+		})
+	}
+}
+
+func TestUnitArchiveMigrateMissing(t *testing.T) {
+	// Arrange
+	a := &archive{path: filepath.Join(t.TempDir(), "octocat.jsonl")}
+	ms := []migration{{version: 2, apply: func(*ghEvent) bool { return false }}}
+	// Act
+	applied, err := a.migrate(ms)
+	// Assert
+	assertNoError(t, err)
+	assertEqual(t, len(applied), 0)
+}
+
+func TestUnitArchiveSchemaOnCreation(t *testing.T) {
+	// Arrange
+	a := &archive{path: filepath.Join(t.TempDir(), "octocat.jsonl")}
+	// Act
+	_, err := a.add([]ghEvent{{ID: "1", CreatedAt: time.Now()}})
+	version, errVersion := a.schemaVersion(99)
+	// Assert
+	assertNoError(t, err)
+	assertNoError(t, errVersion)
+	assertEqual(t, version, latestSchema(migrations))
+}
+
+func TestUnitArchiveCheck(t *testing.T) {
+	// Arrange
+	a := &archive{path: filepath.Join(t.TempDir(), "octocat.jsonl")}
+	lines := `{"id":"1","created_at":"2025-03-01T00:00:00Z"}` + "\n" +
+		"not json\n" +
+		`{"id":"1","created_at":"2025-03-01T00:00:00Z"}` + "\n" +
+		`{"created_at":"2025-03-01T00:00:00Z"}` + "\n" +
+		`{"id":"2"}` + "\n"
+	os.WriteFile(a.path, []byte(lines), 0o600)
+	os.WriteFile(a.schemaPath(), []byte("1"), 0o600)
+	// Act
+	r, err := a.check(1)
+	// Assert
+	assertNoError(t, err)
+	assertEqual(t, r.events, 4)
+	assertEqual(t, r.version, 1)
+	assertEqual(t, len(r.problems), 4)
+	assertEqual(t, strings.HasPrefix(r.problems[0], "line 2:"), true)
+	assertEqual(t, r.problems[1], "line 3: duplicate of event 1 on line 1")
+	assertEqual(t, r.problems[2], "line 4: event without ID")
+	assertEqual(t, r.problems[3], "line 5: event 2 without creation time")
+}
+
+func TestUnitArchiveCheckOutdated(t *testing.T) {
+	// Arrange
+	a := &archive{path: filepath.Join(t.TempDir(), "octocat.jsonl")}
+	os.WriteFile(a.path, []byte(`{"id":"1","created_at":"2025-03-01T00:00:00Z"}`+"\n"), 0o600)
+	// Act
+	r, err := a.check(2)
+	// Assert
+	assertNoError(t, err)
+	assertEqual(t, r.version, 1)
+	assertEqual(t, len(r.problems), 1)
+	assertEqual(t, r.problems[0], "schema version 1, want 2")
+}
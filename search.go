@@ -0,0 +1,171 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+type (
+	// apiCommit is a commit of the commits and commit search APIs.
+	apiCommit struct {
+		SHA     string `json:"sha"`
+		HTMLURL string `json:"html_url"`
+		Commit  struct {
+			Message string `json:"message"`
+			Author  struct {
+				Name  string    `json:"name"`
+				Email string    `json:"email"`
+				Date  time.Time `json:"date"`
+			} `json:"author"`
+		} `json:"commit"`
+		Repository struct {
+			FullName string `json:"full_name"`
+		} `json:"repository"`
+	}
+	// searchIssue is an issue or pull request of the issue search results.
+	searchIssue struct {
+		Number        int       `json:"number"`
+		Title         string    `json:"title"`
+		HTMLURL       string    `json:"html_url"`
+		State         string    `json:"state"`
+		RepositoryURL string    `json:"repository_url"`
+		CreatedAt     time.Time `json:"created_at"`
+		PullRequest   *struct{} `json:"pull_request"`
+	}
+	// searchResults is a page of search results.
+	searchResults[T any] struct {
+		TotalCount int `json:"total_count"`
+		Items      []T `json:"items"`
+	}
+)
+
+// searchPages is the number of pages of 100 results read per search; the
+// Search API never returns more than 1000 results.
+const searchPages = 10
+
+// searchAll reads every page of a search.
+func searchAll[T any](hc *client, base, kind, query string) ([]T, error) {
+	var items []T
+	p := newProgress("search "+kind, hc.budget)
+	defer p.done()
+	for page := 1; page <= searchPages; page++ {
+		var res searchResults[T]
+		u := fmt.Sprintf("%s/search/%s?q=%s&per_page=100&page=%d", base, kind, url.QueryEscape(query), page)
+		if err := fetchJSON(hc, u, &res); err != nil {
+			return nil, fmt.Errorf("search %s: %w", kind, err)
+		}
+		items = append(items, res.Items...)
+		p.add(len(res.Items))
+		if len(res.Items) < 100 || len(items) >= res.TotalCount {
+			break
+		}
+	}
+	return items, nil
+}
+
+// searchEvents reconstructs the commits authored and the pull requests and
+// issues opened by the user since the given day from the Search API, as
+// push, pull request and issue events.
+func searchEvents(hc *client, base, user string, since time.Time) ([]ghEvent, error) {
+	day := since.Format(time.DateOnly)
+	commits, err := searchAll[apiCommit](hc, base, "commits", fmt.Sprintf("author:%s author-date:>=%s", user, day))
+	if err != nil {
+		return nil, err
+	}
+	issues, err := searchAll[searchIssue](hc, base, "issues", fmt.Sprintf("author:%s created:>=%s", user, day))
+	if err != nil {
+		return nil, err
+	}
+	events := make([]ghEvent, 0, len(commits)+len(issues))
+	who := actor{Login: user, DisplayLogin: user}
+	for _, c := range commits {
+		events = append(events, c.event(c.Repository.FullName, who))
+	}
+	for _, is := range issues {
+		ev := ghEvent{
+			ID: fmt.Sprintf("search-issue-%s-%d", repoFromAPIURL(is.RepositoryURL), is.Number), Actor: who,
+			Repo: repo{Name: repoFromAPIURL(is.RepositoryURL)}, Payload: payload{Action: "opened"}, CreatedAt: is.CreatedAt,
+		}
+		if is.PullRequest != nil {
+			ev.Type = "PullRequestEvent"
+			ev.Payload.PullRequest = &pullRequest{Number: is.Number, Title: is.Title, HTMLURL: is.HTMLURL, State: is.State}
+		} else {
+			ev.Type = "IssuesEvent"
+			ev.Payload.Issue = &issue{Number: is.Number, Title: is.Title, HTMLURL: is.HTMLURL, State: is.State}
+		}
+		events = append(events, ev)
+	}
+	return events, nil
+}
+
+// event normalizes a commit as a push of that single commit, dated by its
+// author date.
+func (c apiCommit) event(repoName string, who actor) ghEvent {
+	return ghEvent{
+		ID: "commit-" + c.SHA, Type: "PushEvent", Actor: who, Repo: repo{Name: repoName},
+		Payload: payload{Size: 1, Commits: []commit{{
+			SHA: c.SHA, Message: c.Commit.Message, URL: c.HTMLURL, Distinct: true,
+			Author: author{Name: c.Commit.Author.Name, Email: c.Commit.Author.Email},
+		}}},
+		CreatedAt: c.Commit.Author.Date,
+	}
+}
+
+// repoFromAPIURL returns the owner/name of a repository API URL.
+func repoFromAPIURL(u string) string {
+	parts := strings.Split(strings.TrimSuffix(u, "/"), "/")
+	if len(parts) < 2 {
+		return u
+	}
+	return parts[len(parts)-2] + "/" + parts[len(parts)-1]
+}
+
+// mergeDeep adds the search results older than the oldest event, which the
+// events API no longer returns, and sorts the result newest first.
+func mergeDeep(events, found []ghEvent) []ghEvent {
+	merged := slices.Clone(events)
+	oldest := time.Now()
+	for _, ev := range events {
+		if ev.CreatedAt.Before(oldest) {
+			oldest = ev.CreatedAt
+		}
+	}
+	for _, ev := range found {
+		if ev.CreatedAt.Before(oldest) {
+			merged = append(merged, ev)
+		}
+	}
+	slices.SortStableFunc(merged, func(a, b ghEvent) int { return b.CreatedAt.Compare(a.CreatedAt) })
+	return merged
+}
+
+// fetchDeepEvents fetches the user's events and, when deep is set,
+// supplements them with the Search API back to the given time.
+func fetchDeepEvents(user string, deep bool, since time.Time) ([]ghEvent, error) {
+	events, err := fetchUserEvents(user)
+	if err != nil || !deep {
+		return events, err
+	}
+	logins, err := identities(user)
+	if err != nil {
+		return nil, err
+	}
+	hc := configuredClient()
+	var found []ghEvent
+	for _, login := range logins {
+		events, err := searchEvents(hc, viper.GetString("api_url"), login, since)
+		if err != nil {
+			return nil, err
+		}
+		found = append(found, events...)
+	}
+	if found, err = ignoreEvents(found); err != nil {
+		return nil, err
+	}
+	return mergeDeep(events, redactEvents(found)), nil
+}
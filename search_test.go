@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestUnitSearchEvents(t *testing.T) {
+	// Arrange
+	var queries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Path+" "+r.URL.Query().Get("q"))
+		switch r.URL.Path {
+		case "/search/commits":
+			fmt.Fprint(w, `{"total_count": 1, "items": [{"sha": "abc", "html_url": "https://github.com/octo/a/commit/abc",
+				"commit": {"message": "Fix login", "author": {"name": "Octo", "date": "2024-01-02T10:00:00Z"}},
+				"repository": {"full_name": "octo/a"}}]}`)
+		case "/search/issues":
+			fmt.Fprint(w, `{"total_count": 2, "items": [
+				{"number": 4, "title": "Add badges", "state": "closed", "pull_request": {},
+				 "repository_url": "https://api.github.com/repos/octo/b", "created_at": "2024-01-03T10:00:00Z"},
+				{"number": 5, "title": "Broken footer", "state": "open",
+				 "repository_url": "https://api.github.com/repos/octo/b", "created_at": "2024-01-04T10:00:00Z"}]}`)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	// Act
+	events, err := searchEvents(newClient(""), srv.URL, "octocat", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	// Assert
+	assertNoError(t, err)
+	assertEqual(t, len(queries), 2)
+	assertEqual(t, queries[0], "/search/commits author:octocat author-date:>=2024-01-01")
+	assertEqual(t, queries[1], "/search/issues author:octocat created:>=2024-01-01")
+	assertEqual(t, len(events), 3)
+	assertEqual(t, events[0].Type, "PushEvent")
+	assertEqual(t, events[0].Repo.Name, "octo/a")
+	assertEqual(t, events[0].Payload.Commits[0].Message, "Fix login")
+	assertEqual(t, events[1].Type, "PullRequestEvent")
+	assertEqual(t, *events[1].Payload.PullRequest, pullRequest{Number: 4, Title: "Add badges", State: "closed"})
+	assertEqual(t, events[2].Type, "IssuesEvent")
+	assertEqual(t, events[2].ID, "search-issue-octo/b-5")
+}
+
+func TestUnitMergeDeep(t *testing.T) {
+	// Arrange
+	day := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	events := []ghEvent{{ID: "2", CreatedAt: day.AddDate(0, 0, 1)}, {ID: "1", CreatedAt: day}}
+	found := []ghEvent{
+		{ID: "recent", CreatedAt: day.AddDate(0, 0, 1)},
+		{ID: "old", CreatedAt: day.AddDate(0, 0, -30)},
+		{ID: "older", CreatedAt: day.AddDate(0, 0, -60)},
+	}
+	// Act
+	merged := mergeDeep(events, found)
+	// Assert
+	ids := make([]string, 0, len(merged))
+	for _, ev := range merged {
+		ids = append(ids, ev.ID)
+	}
+	assertEqual(t, fmt.Sprint(ids), "[2 1 old older]")
+}
+
+func TestUnitRepoFromAPIURL(t *testing.T) {
+	testCases := []struct {
+		url  string
+		want string
+	}{
+		{url: "https://api.github.com/repos/octo/b", want: "octo/b"},
+		{url: "https://ghe.example.com/api/v3/repos/octo/b/", want: "octo/b"},
+		{url: "b", want: "b"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.url, func(t *testing.T) {
+			// Act
+			got := repoFromAPIURL(tc.url)
+			// Assert
+			assertEqual(t, got, tc.want)
+		})
+	}
+}
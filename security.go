@@ -0,0 +1,198 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+type (
+	// securityAlert is the alert of a DependabotAlertEvent or
+	// CodeScanningAlertEvent, or the advisory of a SecurityAdvisoryEvent.
+	securityAlert struct {
+		Number int `json:"number"`
+		// Advisory is the CVE or GHSA identifier of a security advisory.
+		Advisory string `json:"advisory,omitempty"`
+		Summary  string `json:"summary"`
+		Severity string `json:"severity,omitempty"`
+		Package  string `json:"package,omitempty"`
+		HTMLURL  string `json:"html_url"`
+	}
+	// apiAlert is a Dependabot or code scanning alert as served by the API.
+	apiAlert struct {
+		Number      int        `json:"number"`
+		HTMLURL     string     `json:"html_url"`
+		CreatedAt   time.Time  `json:"created_at"`
+		FixedAt     *time.Time `json:"fixed_at"`
+		DismissedAt *time.Time `json:"dismissed_at"`
+		// Advisory and Dependency are set on Dependabot alerts.
+		Advisory *struct {
+			Summary  string `json:"summary"`
+			Severity string `json:"severity"`
+		} `json:"security_advisory"`
+		Dependency *struct {
+			Package struct {
+				Name string `json:"name"`
+			} `json:"package"`
+		} `json:"dependency"`
+		// Rule is set on code scanning alerts.
+		Rule *struct {
+			Description string `json:"description"`
+			Severity    string `json:"security_severity_level"`
+		} `json:"rule"`
+	}
+	// alertKind is a kind of security alert with its API resource.
+	alertKind struct {
+		eventType string
+		prefix    string
+		resource  string
+	}
+)
+
+// alertKinds lists the security alerts read for each repository.
+var alertKinds = []alertKind{
+	{eventType: "DependabotAlertEvent", prefix: "dependabot", resource: "dependabot/alerts"},
+	{eventType: "CodeScanningAlertEvent", prefix: "code-scanning", resource: "code-scanning/alerts"},
+}
+
+// securityRecentRepos is the number of latest pushed repositories of the
+// user read when security.repos is not set.
+const securityRecentRepos = 10
+
+// runSecurity lists the Dependabot and code scanning alerts of the user's
+// repositories, or watches them. The feed is kept apart from the activity:
+// it must be enabled with security.enabled, needs a token with the
+// security_events or repo scope, and notifies only through security.rules.
+func runSecurity(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("security", flag.ContinueOnError)
+	watch := flags.Bool("watch", false, "poll the alerts and notify new ones through security.rules")
+	interval := flags.Duration("interval", 10*time.Minute, "with --watch, delay between two polls")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: go-github-activity security [flags] <username>")
+	}
+	if *interval < time.Second {
+		return fmt.Errorf("invalid interval: %s", *interval)
+	}
+	if err := loadConfig(); err != nil {
+		return err
+	}
+	if !viper.GetBool("security.enabled") {
+		return errors.New("the security feed is disabled: set security.enabled to true to read security alerts")
+	}
+	hc := configuredClient()
+	base := viper.GetString("api_url")
+	if err := checkSecurityScopes(hc, base); err != nil {
+		return err
+	}
+	user := flags.Arg(0)
+	fetch := func() ([]ghEvent, error) {
+		events, err := fetchSecurityAlerts(hc, base, user, viper.GetStringSlice("security.repos"))
+		return redactEvents(events), err
+	}
+	if !*watch {
+		events, err := fetch()
+		if err != nil {
+			return err
+		}
+		cat := lookupCatalog(resolveLang(viper.GetString("lang"), os.Getenv("LANG")))
+		return textRenderer{cat: cat, icons: loadIcons(false, nil)}.render(stdout, events)
+	}
+	return watchLive(&poller{fetch: fetch}, func() (watchSettings, error) {
+		s, err := loadWatchSettings("security.rules", *interval)
+		if len(s.rules) == 0 {
+			s.targets = nil
+		}
+		return s, err
+	}, stdout)
+}
+
+// checkSecurityScopes fails when a classic token has none of the scopes of
+// the security operation.
+func checkSecurityScopes(hc *client, base string) error {
+	if hc.Token == "" {
+		return errors.New("the security feed needs a token with the security_events or repo scope")
+	}
+	st, err := checkToken(hc, base)
+	if err != nil {
+		return err
+	}
+	for _, op := range operations {
+		if op.name == "security" && !st.allows(op) {
+			return errors.New(op.hint)
+		}
+	}
+	return nil
+}
+
+// fetchSecurityAlerts returns the alert events of the repositories, or of
+// the user's latest pushed ones, newest first. Repositories where an alert
+// kind is disabled or not visible are skipped.
+func fetchSecurityAlerts(hc *client, base, login string, repos []string) ([]ghEvent, error) {
+	if len(repos) == 0 {
+		var err error
+		if repos, err = recentRepos(hc, base, login, securityRecentRepos); err != nil {
+			return nil, err
+		}
+	}
+	var events []ghEvent
+	for _, name := range repos {
+		name = strings.Trim(name, "/")
+		for _, kind := range alertKinds {
+			var alerts []apiAlert
+			u := fmt.Sprintf("%s/repos/%s/%s?sort=updated&per_page=50", base, name, kind.resource)
+			err := fetchJSON(hc, u, &alerts)
+			switch {
+			case isStatus(err, http.StatusForbidden) || isStatus(err, http.StatusNotFound):
+				continue
+			case err != nil:
+				return nil, fmt.Errorf("fetch %s of %s: %w", strings.ReplaceAll(kind.resource, "/", " "), name, err)
+			}
+			for _, a := range alerts {
+				events = append(events, a.events(kind, name)...)
+			}
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].CreatedAt.After(events[j].CreatedAt) })
+	return events, nil
+}
+
+// events returns the creation of the alert and, once fixed or dismissed,
+// its resolution. Alerts are private to the repository admins, so the
+// events are private too.
+func (a apiAlert) events(kind alertKind, name string) []ghEvent {
+	alert := &securityAlert{Number: a.Number, HTMLURL: a.HTMLURL}
+	switch {
+	case a.Advisory != nil:
+		alert.Summary, alert.Severity = a.Advisory.Summary, a.Advisory.Severity
+	case a.Rule != nil:
+		alert.Summary, alert.Severity = a.Rule.Description, a.Rule.Severity
+	}
+	if a.Dependency != nil {
+		alert.Package = a.Dependency.Package.Name
+	}
+	event := func(action string, at time.Time) ghEvent {
+		return ghEvent{
+			ID: fmt.Sprintf("%s-%s-%d-%s", kind.prefix, name, a.Number, action), Type: kind.eventType,
+			Repo: repo{Name: name}, Payload: payload{Action: action, Alert: alert}, CreatedAt: at,
+		}
+	}
+	events := []ghEvent{event("created", a.CreatedAt)}
+	switch {
+	case a.FixedAt != nil:
+		events = append(events, event("fixed", *a.FixedAt))
+	case a.DismissedAt != nil:
+		events = append(events, event("dismissed", *a.DismissedAt))
+	}
+	return events
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestUnitFetchSecurityAlerts(t *testing.T) {
+	testCases := []struct {
+		name          string
+		codeScanning  int
+		wantIDs       []string
+		wantErr       bool
+		wantSummaries []string
+	}{
+		{
+			name: "code scanning disabled", codeScanning: http.StatusForbidden,
+			wantIDs:       []string{"dependabot-octo/api-3-fixed", "dependabot-octo/api-3-created"},
+			wantSummaries: []string{"ReDoS in minimatch", "ReDoS in minimatch"},
+		},
+		{
+			name: "code scanning alerts", codeScanning: http.StatusOK,
+			wantIDs: []string{
+				"code-scanning-octo/api-9-dismissed", "dependabot-octo/api-3-fixed",
+				"dependabot-octo/api-3-created", "code-scanning-octo/api-9-created",
+			},
+			wantSummaries: []string{"SQL injection", "ReDoS in minimatch", "ReDoS in minimatch", "SQL injection"},
+		},
+		{name: "server error", codeScanning: http.StatusInternalServerError, wantErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/users/octocat/repos":
+					fmt.Fprint(w, `[{"full_name":"octo/api"}]`)
+				case "/repos/octo/api/dependabot/alerts":
+					fmt.Fprint(w, `[{"number":3,"html_url":"https://github.com/octo/api/security/dependabot/3",
+						"created_at":"2025-03-01T00:00:00Z","fixed_at":"2025-03-03T00:00:00Z",
+						"security_advisory":{"summary":"ReDoS in minimatch","severity":"high"},
+						"dependency":{"package":{"name":"minimatch"}}}]`)
+				case "/repos/octo/api/code-scanning/alerts":
+					if tc.codeScanning != http.StatusOK {
+						w.WriteHeader(tc.codeScanning)
+						return
+					}
+					fmt.Fprint(w, `[{"number":9,"created_at":"2025-02-28T00:00:00Z",
+						"dismissed_at":"2025-03-04T00:00:00Z",
+						"rule":{"description":"SQL injection","security_severity_level":"critical"}}]`)
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+			defer srv.Close()
+			hc := newClient("")
+			hc.Client.Timeout = time.Second
+			// Act
+			events, err := fetchSecurityAlerts(hc, srv.URL, "octocat", nil)
+			// Assert
+			if tc.wantErr {
+				assertNotNil(t, err)
+				return
+			}
+			assertNoError(t, err)
+			assertEqual(t, len(events), len(tc.wantIDs))
+			for i, id := range tc.wantIDs {
+				assertEqual(t, events[i].ID, id)
+				assertEqual(t, events[i].Payload.Alert.Summary, tc.wantSummaries[i])
+				assertEqual(t, events[i].Public, false)
+			}
+		})
+	}
+}
+
+func TestUnitAlertEvents(t *testing.T) {
+	// Arrange
+	created := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	a := apiAlert{Number: 3, HTMLURL: "https://github.com/octo/api/security/dependabot/3", CreatedAt: created}
+	a.Advisory = &struct {
+		Summary  string `json:"summary"`
+		Severity string `json:"severity"`
+	}{Summary: "ReDoS in minimatch", Severity: "high"}
+	// Act
+	events := a.events(alertKinds[0], "octo/api")
+	// Assert
+	assertEqual(t, len(events), 1)
+	assertEqual(t, events[0].Type, "DependabotAlertEvent")
+	assertEqual(t, events[0].Payload.Action, "created")
+	assertEqual(t, *events[0].Payload.Alert, securityAlert{
+		Number: 3, Summary: "ReDoS in minimatch", Severity: "high",
+		HTMLURL: "https://github.com/octo/api/security/dependabot/3",
+	})
+	assertEqual(t, summarize(lookupCatalog("en"), events[0]), "New Dependabot alert in octo/api")
+}
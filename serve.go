@@ -0,0 +1,259 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// server exposes the activity of GitHub users as a JSON API, fetching each
+// user with the credentials of their tenant.
+type server struct {
+	tenants     *tenantSet
+	fetch       func(t *tenant, user string) ([]ghEvent, error)
+	clock       clock
+	apiKeys     []string
+	corsOrigins []string
+	cache       *responseCache
+	hub         *streamHub
+	swaggerUI   bool
+}
+
+// runServe serves the JSON API and runs the scheduled jobs until
+// interrupted.
+func runServe(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := flags.String("addr", "localhost:8080", "address to listen on")
+	grpcAddr := flags.String("grpc-addr", "", "address to serve the gRPC API on (disabled when empty)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		return errors.New("usage: go-github-activity serve [flags]")
+	}
+	if err := loadConfig(); err != nil {
+		return err
+	}
+	configLoaded.Store(true)
+	defer configLoaded.Store(false)
+	jobs, err := loadSchedule()
+	if err != nil {
+		return err
+	}
+	tenants, err := loadTenants()
+	if err != nil {
+		return err
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	var current atomic.Pointer[tenantSet]
+	current.Store(tenants)
+	fetchUser := func(user string) ([]ghEvent, error) { return fetchTenantEvents(current.Load().lookup(user), user) }
+	s := &server{
+		tenants:     tenants,
+		fetch:       fetchTenantEvents,
+		clock:       systemClock{},
+		apiKeys:     viper.GetStringSlice("serve.api_keys"),
+		corsOrigins: viper.GetStringSlice("serve.cors_origins"),
+		cache:       newResponseCache(viper.GetDuration("serve.cache_ttl"), viper.GetDuration("serve.stale_ttl")),
+		hub:         newStreamHub(viper.GetDuration("serve.stream_interval"), fetchUser),
+		swaggerUI:   viper.GetBool("serve.swagger_ui"),
+	}
+	if s.hub.interval < time.Second {
+		return fmt.Errorf("invalid stream interval: %s", s.hub.interval)
+	}
+	handler := &swapHandler{}
+	handler.store(s.routes())
+	var api atomic.Pointer[server]
+	api.Store(s)
+	// A reload replaces the tenants, the access settings and the scheduled
+	// jobs; the cache and the open streams are kept.
+	scheduled := jobs
+	r, err := newConfigReloader(func() error {
+		jobs, err := loadSchedule()
+		if err != nil {
+			return err
+		}
+		tenants, err := loadTenants()
+		if err != nil {
+			return err
+		}
+		next := *s
+		next.tenants = tenants
+		next.apiKeys = viper.GetStringSlice("serve.api_keys")
+		next.corsOrigins = viper.GetStringSlice("serve.cors_origins")
+		next.swaggerUI = viper.GetBool("serve.swagger_ui")
+		current.Store(tenants)
+		api.Store(&next)
+		handler.store(next.routes())
+		scheduled = jobs
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	defer r.close()
+	jobsDone := make(chan struct{})
+	go func() {
+		defer close(jobsDone)
+		runJobs(ctx, r, func() *scheduler { return newScheduler(scheduled) })
+	}()
+	// On return, the jobs are stopped and waited for.
+	defer func() { <-jobsDone }()
+	defer stop()
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext:       func(net.Listener) context.Context { return ctx }, // ends open streams on shutdown
+	}
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdown)
+	}()
+	if *grpcAddr != "" {
+		lis, err := net.Listen("tcp", *grpcAddr)
+		if err != nil {
+			return fmt.Errorf("serve grpc: %w", err)
+		}
+		gs := newGRPCServer(&grpcServer{api: &api, done: ctx.Done()})
+		go func() {
+			<-ctx.Done()
+			gs.GracefulStop()
+		}()
+		go func() {
+			if err := gs.Serve(lis); err != nil {
+				log.Printf("serve grpc: %v", err)
+			}
+		}()
+		if err := infof(stdout, "serving gRPC on %s\n", *grpcAddr); err != nil {
+			return err
+		}
+	}
+	if err := infof(stdout, "listening on %s with %d scheduled jobs\n", *addr, len(jobs)); err != nil {
+		return err
+	}
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("serve: %w", err)
+	}
+	return nil
+}
+
+// runJobs runs the scheduler of the configuration until ctx ends. On a
+// change of the configuration, the running jobs are stopped and waited for
+// before it is reloaded and the new scheduler started, so that no job
+// overlaps the reload nor another run of itself.
+func runJobs(ctx context.Context, r *configReloader, schedule func() *scheduler) {
+	for {
+		jobsCtx, stopJobs := context.WithCancel(ctx)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			schedule().start(jobsCtx)
+		}()
+		byt, ok := r.next(ctx)
+		stopJobs()
+		<-done
+		if !ok {
+			return
+		}
+		if err := r.install(byt); err != nil {
+			log.Printf("configuration rejected, keeping the previous one: %v", err)
+			continue
+		}
+		log.Printf("configuration reloaded")
+	}
+}
+
+// endpoints returns the handlers of the JSON API by pattern, each documented
+// in web/openapi.json.
+func (s *server) endpoints() map[string]http.HandlerFunc {
+	return map[string]http.HandlerFunc{
+		"GET /healthz": func(w http.ResponseWriter, _ *http.Request) {
+			writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+		},
+		"GET /users/{user}/activity": s.cache.wrap(s.activity),
+		"GET /users/{user}/stream":   s.stream,
+	}
+}
+
+// routes returns the handler of every endpoint and of the dashboard, behind
+// CORS and API key checks.
+func (s *server) routes() http.Handler {
+	mux := http.NewServeMux()
+	for pattern, handler := range s.endpoints() {
+		mux.HandleFunc(pattern, handler)
+	}
+	mux.Handle("GET /", dashboard())
+	if !s.swaggerUI {
+		mux.Handle("GET /docs.html", http.NotFoundHandler())
+		mux.Handle("GET /swagger-ui/", http.NotFoundHandler())
+	}
+	return s.cors(s.requireAPIKey(mux))
+}
+
+// fetchTenantEvents fetches the events of a user with a tenant's token,
+// reading the configuration under configMu as a reload may replace it.
+func fetchTenantEvents(t *tenant, user string) ([]ghEvent, error) {
+	configMu.RLock()
+	defer configMu.RUnlock()
+	events, err := fetchGitHubResponse(t.client(), eventsURL(viper.GetString("api_url"), user))
+	return redactEvents(events), err
+}
+
+// activity serves the events of a user with their summaries. Requests for a
+// tenant whose budget is exhausted are refused until its reset, leaving the
+// other tenants unaffected.
+func (s *server) activity(w http.ResponseWriter, r *http.Request) {
+	user := r.PathValue("user")
+	t := s.tenants.lookup(user)
+	if reset, ok := t.budget.exhausted(s.clock.Now()); ok {
+		w.Header().Set("Retry-After", strconv.Itoa(int(reset.Sub(s.clock.Now()).Seconds())+1))
+		writeJSON(w, http.StatusTooManyRequests, map[string]string{"error": "rate limit exhausted for " + t.Name})
+		return
+	}
+	events, err := s.fetch(t, user)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	events = hideSponsorships(events)
+	w.Header().Set("Content-Type", "application/json")
+	if err := (jsonRenderer{cat: catalogs[defaultLang]}).render(w, events); err != nil {
+		log.Printf("write response: %v", err)
+	}
+}
+
+// writeJSON writes v as the JSON body of a response with the given status.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("write response: %v", err)
+	}
+}
+
+// writeError maps an error to a JSON error response; unknown users are
+// reported as not found and other upstream failures as a bad gateway.
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusBadGateway
+	if isStatus(err, http.StatusNotFound) {
+		status = http.StatusNotFound
+	}
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
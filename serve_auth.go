@@ -0,0 +1,81 @@
+package main
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// publicPaths lists the paths served without an API key: the health check
+// and the dashboard and docs assets, which send the key themselves when
+// calling the API. Every other path needs one, including the routes added
+// later.
+var publicPaths = map[string]bool{
+	"/":             true,
+	"/index.html":   true,
+	"/app.js":       true,
+	"/style.css":    true,
+	"/docs.html":    true,
+	"/openapi.json": true,
+	"/healthz":      true,
+	// The Swagger UI of docs.html, vendored to serve it without a CDN.
+	"/swagger-ui/swagger-ui.css":       true,
+	"/swagger-ui/swagger-ui-bundle.js": true,
+}
+
+// requireAPIKey rejects requests outside the public paths without one of
+// the configured keys, given as a bearer token or in X-API-Key. Without
+// keys the API is open.
+func (s *server) requireAPIKey(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(s.apiKeys) == 0 || publicPaths[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+		key := r.Header.Get("X-API-Key")
+		if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+			key = bearer
+		}
+		if !validKey(s.apiKeys, key) {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="go-github-activity"`)
+			writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "missing or invalid API key"})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// validKey compares key with every configured key in constant time.
+func validKey(keys []string, key string) bool {
+	ok := 0
+	for _, k := range keys {
+		ok |= subtle.ConstantTimeCompare([]byte(k), []byte(key))
+	}
+	return key != "" && ok == 1
+}
+
+// cors allows browser requests from the configured origins ("*" allows any)
+// and answers their preflight requests before authentication.
+func (s *server) cors(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		allowed := origin != "" && (slices.Contains(s.corsOrigins, "*") || slices.Contains(s.corsOrigins, origin))
+		if allowed {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Add("Vary", "Origin")
+		}
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			if !allowed {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+			w.Header().Set("Access-Control-Allow-Headers", "Authorization, X-API-Key, Content-Type")
+			w.Header().Set("Access-Control-Max-Age", "600")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestUnitServerAuthAndCORS(t *testing.T) {
+	testCases := []struct {
+		name       string
+		method     string
+		path       string
+		headers    map[string]string
+		wantStatus int
+		wantOrigin string
+	}{
+		{name: "no key", method: http.MethodGet, path: "/users/octocat/activity", wantStatus: http.StatusUnauthorized},
+		{
+			name:       "wrong key",
+			method:     http.MethodGet,
+			path:       "/users/octocat/activity",
+			headers:    map[string]string{"X-API-Key": "guess"},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "bearer key",
+			method:     http.MethodGet,
+			path:       "/users/octocat/activity",
+			headers:    map[string]string{"Authorization": "Bearer k2"},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "header key from allowed origin",
+			method:     http.MethodGet,
+			path:       "/users/octocat/activity",
+			headers:    map[string]string{"X-API-Key": "k1", "Origin": "https://dash.example.com"},
+			wantStatus: http.StatusOK,
+			wantOrigin: "https://dash.example.com",
+		},
+		{
+			name:       "other origin",
+			method:     http.MethodGet,
+			path:       "/users/octocat/activity",
+			headers:    map[string]string{"X-API-Key": "k1", "Origin": "https://evil.example.com"},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "preflight",
+			method: http.MethodOptions,
+			path:   "/users/octocat/activity",
+			headers: map[string]string{
+				"Origin":                        "https://dash.example.com",
+				"Access-Control-Request-Method": "GET",
+			},
+			wantStatus: http.StatusNoContent,
+			wantOrigin: "https://dash.example.com",
+		},
+		{
+			name:   "preflight from other origin",
+			method: http.MethodOptions,
+			path:   "/users/octocat/activity",
+			headers: map[string]string{
+				"Origin":                        "https://evil.example.com",
+				"Access-Control-Request-Method": "GET",
+			},
+			wantStatus: http.StatusForbidden,
+		},
+		{name: "health without key", method: http.MethodGet, path: "/healthz", wantStatus: http.StatusOK},
+		{name: "dashboard without key", method: http.MethodGet, path: "/", wantStatus: http.StatusOK},
+		{name: "spec without key", method: http.MethodGet, path: "/openapi.json", wantStatus: http.StatusOK},
+		{name: "unknown route without key", method: http.MethodGet, path: "/metrics", wantStatus: http.StatusUnauthorized},
+		{
+			name:       "unknown route with key",
+			method:     http.MethodGet,
+			path:       "/metrics",
+			headers:    map[string]string{"X-API-Key": "k1"},
+			wantStatus: http.StatusNotFound,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			s := &server{
+				tenants:     &tenantSet{fallback: &tenant{Name: "default"}},
+				fetch:       func(*tenant, string) ([]ghEvent, error) { return nil, nil },
+				clock:       systemClock{},
+				apiKeys:     []string{"k1", "k2"},
+				corsOrigins: []string{"https://dash.example.com"},
+			}
+			req := httptest.NewRequest(tc.method, tc.path, nil)
+			for k, v := range tc.headers {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			// Act
+			s.routes().ServeHTTP(rec, req)
+			// Assert
+			assertEqual(t, rec.Code, tc.wantStatus)
+			assertEqual(t, rec.Header().Get("Access-Control-Allow-Origin"), tc.wantOrigin)
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+type (
+	// responseCache keeps successful responses per request for ttl, then
+	// serves them stale for up to stale more while refreshing them in the
+	// background. Expired responses are evicted as new ones are stored.
+	responseCache struct {
+		mu         sync.Mutex
+		ttl        time.Duration
+		stale      time.Duration
+		clock      clock
+		entries    map[string]*cachedResponse
+		refreshing map[string]bool
+	}
+	// cachedResponse is a recorded response.
+	cachedResponse struct {
+		header http.Header
+		status int
+		body   []byte
+		stored time.Time
+	}
+	// responseRecorder captures a response in memory.
+	responseRecorder struct {
+		header http.Header
+		status int
+		body   bytes.Buffer
+	}
+)
+
+// newResponseCache returns an empty cache on the wall clock.
+func newResponseCache(ttl, stale time.Duration) *responseCache {
+	return &responseCache{
+		ttl:        ttl,
+		stale:      stale,
+		clock:      systemClock{},
+		entries:    map[string]*cachedResponse{},
+		refreshing: map[string]bool{},
+	}
+}
+
+// cacheKey identifies a request by path and the query parameters the
+// handler reads, e.g. "/users/octocat/activity?type=PushEvent"; the other
+// parameters do not make new entries.
+func cacheKey(r *http.Request, params []string) string {
+	query := url.Values{}
+	for _, p := range params {
+		if v, ok := r.URL.Query()[p]; ok {
+			query[p] = v
+		}
+	}
+	return r.URL.Path + "?" + query.Encode()
+}
+
+// wrap serves next, which reads the given query parameters, through the
+// cache and reports HIT, STALE or MISS in X-Cache. A nil cache or a zero
+// TTL disables caching.
+func (c *responseCache) wrap(next http.HandlerFunc, params ...string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if c == nil || c.ttl <= 0 {
+			next(w, r)
+			return
+		}
+		key := cacheKey(r, params)
+		c.mu.Lock()
+		entry := c.entries[key]
+		age := time.Duration(0)
+		if entry != nil {
+			age = c.clock.Now().Sub(entry.stored)
+		}
+		refresh := entry != nil && age >= c.ttl && age < c.ttl+c.stale && !c.refreshing[key]
+		if refresh {
+			c.refreshing[key] = true
+		}
+		c.mu.Unlock()
+		switch {
+		case entry != nil && age < c.ttl:
+			entry.write(w, "HIT")
+		case entry != nil && age < c.ttl+c.stale:
+			if refresh {
+				bg := r.Clone(context.Background())
+				go func() {
+					c.record(key, next, bg)
+					c.mu.Lock()
+					delete(c.refreshing, key)
+					c.mu.Unlock()
+				}()
+			}
+			entry.write(w, "STALE")
+		default:
+			c.record(key, next, r).write(w, "MISS")
+		}
+	}
+}
+
+// record runs next and stores its response when successful, evicting the
+// expired ones.
+func (c *responseCache) record(key string, next http.HandlerFunc, r *http.Request) *cachedResponse {
+	rec := &responseRecorder{header: http.Header{}, status: http.StatusOK}
+	next(rec, r)
+	resp := &cachedResponse{header: rec.header, status: rec.status, body: rec.body.Bytes(), stored: c.clock.Now()}
+	if resp.status == http.StatusOK {
+		c.mu.Lock()
+		for k, entry := range c.entries {
+			if resp.stored.Sub(entry.stored) >= c.ttl+c.stale {
+				delete(c.entries, k)
+			}
+		}
+		c.entries[key] = resp
+		c.mu.Unlock()
+	}
+	return resp
+}
+
+// write replays the response with its cache status.
+func (cr *cachedResponse) write(w http.ResponseWriter, status string) {
+	for k, v := range cr.header {
+		w.Header()[k] = v
+	}
+	w.Header().Set("X-Cache", status)
+	w.WriteHeader(cr.status)
+	w.Write(cr.body)
+}
+
+func (r *responseRecorder) Header() http.Header         { return r.header }
+func (r *responseRecorder) Write(p []byte) (int, error) { return r.body.Write(p) }
+func (r *responseRecorder) WriteHeader(status int)      { r.status = status }
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestUnitResponseCache(t *testing.T) {
+	testCases := []struct {
+		name      string
+		age       time.Duration
+		status    int
+		wantCache string
+		wantBody  string
+		wantCalls int32
+	}{
+		{name: "fresh", age: 30 * time.Second, status: http.StatusOK, wantCache: "HIT", wantBody: "1", wantCalls: 1},
+		{name: "stale", age: 2 * time.Minute, status: http.StatusOK, wantCache: "STALE", wantBody: "1", wantCalls: 2},
+		{name: "expired", age: time.Hour, status: http.StatusOK, wantCache: "MISS", wantBody: "2", wantCalls: 2},
+		{name: "errors not cached", status: http.StatusBadGateway, wantCache: "MISS", wantBody: "2", wantCalls: 2},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			var calls atomic.Int32
+			refreshed := make(chan struct{}, 1)
+			handler := func(w http.ResponseWriter, _ *http.Request) {
+				n := calls.Add(1)
+				if n > 1 {
+					refreshed <- struct{}{}
+				}
+				w.WriteHeader(tc.status)
+				w.Write([]byte(strconv.Itoa(int(n))))
+			}
+			now := time.Unix(1_700_000_000, 0)
+			c := newResponseCache(time.Minute, 10*time.Minute)
+			clk := &fakeClock{now: now}
+			c.clock = clk
+			h := c.wrap(handler, "a", "b")
+			h(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/octocat/activity?b=2&a=1", nil))
+			clk.advance(tc.age)
+			rec := httptest.NewRecorder()
+			// Act
+			h(rec, httptest.NewRequest(http.MethodGet, "/users/octocat/activity?a=1&b=2", nil))
+			// Assert
+			if tc.wantCalls > 1 {
+				<-refreshed
+			}
+			assertEqual(t, rec.Header().Get("X-Cache"), tc.wantCache)
+			assertEqual(t, rec.Body.String(), tc.wantBody)
+			assertEqual(t, calls.Load(), tc.wantCalls)
+		})
+	}
+}
+
+func TestUnitResponseCacheDisabled(t *testing.T) {
+	// Arrange
+	var calls int
+	h := newResponseCache(0, 0).wrap(func(http.ResponseWriter, *http.Request) { calls++ })
+	// Act
+	for range 3 {
+		h(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/octocat/activity", nil))
+	}
+	// Assert
+	assertEqual(t, calls, 3)
+}
+
+func TestUnitCacheKey(t *testing.T) {
+	testCases := []struct {
+		name   string
+		target string
+		params []string
+		want   string
+	}{
+		{name: "no parameter read", target: "/users/octocat/activity?nocache=1", want: "/users/octocat/activity?"},
+		{
+			name:   "parameters read in order",
+			target: "/users/octocat/activity?type=PushEvent&nocache=1&lang=fr",
+			params: []string{"type", "lang"},
+			want:   "/users/octocat/activity?lang=fr&type=PushEvent",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			r := httptest.NewRequest(http.MethodGet, tc.target, nil)
+			// Act
+			got := cacheKey(r, tc.params)
+			// Assert
+			assertEqual(t, got, tc.want)
+		})
+	}
+}
+
+func TestUnitResponseCacheEvicts(t *testing.T) {
+	// Arrange
+	now := time.Unix(1_700_000_000, 0)
+	c := newResponseCache(time.Minute, 10*time.Minute)
+	clk := &fakeClock{now: now}
+	c.clock = clk
+	h := c.wrap(func(w http.ResponseWriter, _ *http.Request) { w.Write([]byte("ok")) })
+	h(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/octocat/activity", nil))
+	h(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/hubot/activity", nil))
+	clk.advance(11 * time.Minute)
+	// Act
+	h(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/monalisa/activity", nil))
+	// Assert
+	assertEqual(t, len(c.entries), 1)
+	assertEqual(t, c.entries["/users/monalisa/activity?"] != nil, true)
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func TestUnitServerActivity(t *testing.T) {
+	testCases := []struct {
+		name       string
+		path       string
+		err        error
+		wantStatus int
+	}{
+		{name: "events", path: "/users/octocat/activity", wantStatus: http.StatusOK},
+		{
+			name:       "unknown user",
+			path:       "/users/ghost/activity",
+			err:        &apiError{StatusCode: 404, Status: "404 Not Found"},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "upstream failure",
+			path:       "/users/octocat/activity",
+			err:        &apiError{StatusCode: 500, Status: "500 Internal Server Error"},
+			wantStatus: http.StatusBadGateway,
+		},
+		{name: "health", path: "/healthz", wantStatus: http.StatusOK},
+		{name: "unknown route", path: "/nope", wantStatus: http.StatusNotFound},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			var gotUser string
+			s := &server{
+				tenants: &tenantSet{fallback: &tenant{Name: "default"}},
+				fetch: func(_ *tenant, user string) ([]ghEvent, error) {
+					gotUser = user
+					return []ghEvent{
+						{ID: "1", Type: "WatchEvent", Repo: repo{Name: "octo/repo"}},
+						{ID: "sponsorship-1", Type: "SponsorshipEvent", Repo: repo{Name: "octocat"}},
+					}, tc.err
+				},
+				clock: systemClock{},
+			}
+			rec := httptest.NewRecorder()
+			// Act
+			s.routes().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.path, nil))
+			// Assert
+			assertEqual(t, rec.Code, tc.wantStatus)
+			if tc.name != "events" {
+				return
+			}
+			var got []jsonEvent
+			assertNoError(t, json.NewDecoder(rec.Body).Decode(&got))
+			assertEqual(t, gotUser, "octocat")
+			assertEqual(t, len(got), 1)
+			assertEqual(t, got[0].Summary, "Starred octo/repo")
+		})
+	}
+}
+
+func TestUnitRunJobs(t *testing.T) {
+	// Arrange
+	t.Cleanup(viper.Reset)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var (
+		active  atomic.Int32
+		started = make(chan struct{})
+	)
+	schedulableCommands["blocking"] = func(ctx context.Context, _ []string, _ io.Writer) error {
+		active.Add(1)
+		defer active.Add(-1)
+		started <- struct{}{}
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	t.Cleanup(func() { delete(schedulableCommands, "blocking") })
+	everyMinute, _ := parseCron("* * * * *")
+	schedule := func() *scheduler {
+		// The clock reaches the next minute, then never again.
+		clk := &fakeClock{now: time.Date(2025, 3, 3, 10, 0, 30, 0, time.UTC), limit: 2, cancel: func() {}}
+		job := &scheduledJob{Name: "blocking", Args: []string{"blocking"}, schedule: everyMinute}
+		return &scheduler{jobs: []*scheduledJob{job}, clock: clk}
+	}
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	assertNoError(t, os.WriteFile(path, []byte("lang: en\n"), 0o600))
+	assertNoError(t, readConfig([]byte("lang: en\n")))
+	changes := make(chan struct{})
+	activeAtApply := int32(-1)
+	r := &configReloader{
+		path:    path,
+		last:    []byte("lang: en\n"),
+		apply:   func() error { activeAtApply = active.Load(); return nil },
+		changes: changes,
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		runJobs(ctx, r, schedule)
+	}()
+	<-started
+	// Act
+	assertNoError(t, os.WriteFile(path, []byte("lang: fr\n"), 0o600))
+	changes <- struct{}{}
+	<-started
+	cancel()
+	<-done
+	// Assert
+	assertEqual(t, activeAtApply, int32(0))
+	assertEqual(t, active.Load(), int32(0))
+	assertEqual(t, viper.GetString("lang"), "fr")
+}
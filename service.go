@@ -0,0 +1,271 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+type (
+	// serviceSpec describes a background job running watch or sync.
+	serviceSpec struct {
+		command  string
+		user     string
+		exe      string
+		interval time.Duration
+		logDir   string
+		env      map[string]string
+	}
+	// serviceFile is a unit or agent definition written under the home.
+	serviceFile struct {
+		path    string
+		content string
+	}
+	// servicePlatform generates the service files of an init system and the
+	// commands loading and unloading them.
+	servicePlatform struct {
+		files  func(home string, s serviceSpec) []serviceFile
+		load   func(s serviceSpec, files []serviceFile) [][]string
+		unload func(s serviceSpec, files []serviceFile) [][]string
+	}
+	// commandRunner enables testable invocations of the init system tools.
+	commandRunner interface {
+		run(name string, args ...string) error
+	}
+	// execCommand implements commandRunner with the binaries of the system.
+	execCommand struct{}
+)
+
+// serviceEnv lists the variables copied into the service environment, since
+// init systems start jobs with a minimal one.
+var serviceEnv = []string{"PATH", "LANG", "LC_ALL", "HTTPS_PROXY", "HTTP_PROXY", "NO_PROXY", "SSL_CERT_FILE"}
+
+// servicePlatforms lists the supported init systems by GOOS.
+var servicePlatforms = map[string]servicePlatform{
+	"linux": {
+		files: systemdFiles,
+		load: func(s serviceSpec, _ []serviceFile) [][]string {
+			return [][]string{
+				{"systemctl", "--user", "daemon-reload"},
+				{"systemctl", "--user", "enable", "--now", s.systemdUnit()},
+			}
+		},
+		unload: func(s serviceSpec, _ []serviceFile) [][]string {
+			return [][]string{{"systemctl", "--user", "disable", "--now", s.systemdUnit()}}
+		},
+	},
+	"darwin": {
+		files: launchdFiles,
+		load: func(_ serviceSpec, files []serviceFile) [][]string {
+			return [][]string{{"launchctl", "load", "-w", files[0].path}}
+		},
+		unload: func(_ serviceSpec, files []serviceFile) [][]string {
+			return [][]string{{"launchctl", "unload", "-w", files[0].path}}
+		},
+	},
+}
+
+func (execCommand) run(name string, args ...string) error {
+	cmd := exec.Command(name, args...)
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s %s: %w", name, strings.Join(args, " "), err)
+	}
+	return nil
+}
+
+// runService installs or uninstalls a background watch or sync job.
+func runService(args []string, stdout io.Writer) error {
+	if len(args) == 0 || (args[0] != "install" && args[0] != "uninstall") {
+		return errors.New("usage: go-github-activity service install|uninstall [flags] <username>")
+	}
+	flags := flag.NewFlagSet("service "+args[0], flag.ContinueOnError)
+	spec := serviceSpec{env: map[string]string{}}
+	flags.StringVar(&spec.command, "command", "sync", "job to run: sync or watch")
+	flags.DurationVar(&spec.interval, "interval", time.Hour, "delay between two syncs")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+	if spec.command != "sync" && spec.command != "watch" {
+		return fmt.Errorf("unknown service command %q", spec.command)
+	}
+	platform, ok := servicePlatforms[runtime.GOOS]
+	if !ok {
+		return fmt.Errorf("services are not supported on %s", runtime.GOOS)
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return fmt.Errorf("get user home directory: %w", err)
+	}
+	if args[0] == "uninstall" {
+		return uninstallService(platform, home, spec, execCommand{}, stdout)
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: go-github-activity service install [flags] <username>")
+	}
+	if spec.interval < time.Minute {
+		return fmt.Errorf("invalid interval: %s", spec.interval)
+	}
+	spec.user = flags.Arg(0)
+	if spec.exe, err = os.Executable(); err != nil {
+		return fmt.Errorf("locate executable: %w", err)
+	}
+	if spec.logDir, err = appDir(&defaultUserHome{}); err != nil {
+		return err
+	}
+	for _, k := range serviceEnv {
+		if v, ok := os.LookupEnv(k); ok {
+			spec.env[k] = v
+		}
+	}
+	return installService(platform, home, spec, execCommand{}, stdout)
+}
+
+// installService writes the service files and loads them.
+func installService(p servicePlatform, home string, s serviceSpec, r commandRunner, stdout io.Writer) error {
+	files := p.files(home, s)
+	for _, f := range files {
+		if err := os.MkdirAll(filepath.Dir(f.path), 0o755); err != nil {
+			return fmt.Errorf("create service directory: %w", err)
+		}
+		if err := os.WriteFile(f.path, []byte(f.content), 0o644); err != nil {
+			return fmt.Errorf("write service file: %w", err)
+		}
+		if err := infof(stdout, "wrote %s\n", f.path); err != nil {
+			return err
+		}
+	}
+	return runAll(r, p.load(s, files))
+}
+
+// uninstallService unloads the service and removes its files.
+func uninstallService(p servicePlatform, home string, s serviceSpec, r commandRunner, stdout io.Writer) error {
+	files := p.files(home, s)
+	if err := runAll(r, p.unload(s, files)); err != nil {
+		return err
+	}
+	for _, f := range files {
+		if err := os.Remove(f.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("remove service file: %w", err)
+		}
+		if err := infof(stdout, "removed %s\n", f.path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runAll runs the commands in order and stops at the first failure.
+func runAll(r commandRunner, cmds [][]string) error {
+	for _, c := range cmds {
+		if err := r.run(c[0], c[1:]...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// name is the service name shared by every init system.
+func (s serviceSpec) name() string {
+	return "go-github-activity-" + s.command
+}
+
+// systemdUnit is the unit systemd enables: a timer for sync, the service
+// itself for watch.
+func (s serviceSpec) systemdUnit() string {
+	if s.command == "sync" {
+		return s.name() + ".timer"
+	}
+	return s.name() + ".service"
+}
+
+// argv is the command line of the job.
+func (s serviceSpec) argv() []string {
+	return []string{s.exe, s.command, s.user}
+}
+
+// systemdFiles returns a user service, plus a timer for sync jobs.
+func systemdFiles(home string, s serviceSpec) []serviceFile {
+	dir := filepath.Join(home, ".config", "systemd", "user")
+	var b strings.Builder
+	fmt.Fprintf(&b, "[Unit]\nDescription=go-github-activity %s\n\n[Service]\n", s.command)
+	for _, k := range sortedKeys(s.env) {
+		fmt.Fprintf(&b, "Environment=%s\n", systemdQuote(k+"="+s.env[k]))
+	}
+	quoted := make([]string, 0, len(s.argv()))
+	for _, a := range s.argv() {
+		quoted = append(quoted, systemdQuote(a))
+	}
+	fmt.Fprintf(&b, "ExecStart=%s\n", strings.Join(quoted, " "))
+	if s.command == "sync" {
+		b.WriteString("Type=oneshot\n")
+		timer := fmt.Sprintf("[Unit]\nDescription=Run go-github-activity sync every %s\n\n"+
+			"[Timer]\nOnBootSec=5min\nOnUnitActiveSec=%s\nPersistent=true\n\n[Install]\nWantedBy=timers.target\n",
+			s.interval, s.interval)
+		return []serviceFile{
+			{path: filepath.Join(dir, s.name()+".service"), content: b.String()},
+			{path: filepath.Join(dir, s.name()+".timer"), content: timer},
+		}
+	}
+	b.WriteString("Restart=on-failure\nRestartSec=30\n\n[Install]\nWantedBy=default.target\n")
+	return []serviceFile{{path: filepath.Join(dir, s.name()+".service"), content: b.String()}}
+}
+
+// systemdQuote double-quotes a word holding spaces, quotes or backslashes.
+func systemdQuote(s string) string {
+	if !strings.ContainsAny(s, " \t\"\\") {
+		return s
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// launchdFiles returns a user agent kept alive for watch jobs and started
+// every interval for sync jobs.
+func launchdFiles(home string, s serviceSpec) []serviceFile {
+	label := "io.github.alnah." + s.name()
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+		`<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">` + "\n" +
+		`<plist version="1.0">` + "\n<dict>\n")
+	fmt.Fprintf(&b, "  <key>Label</key>\n  <string>%s</string>\n", plistEscape(label))
+	b.WriteString("  <key>ProgramArguments</key>\n  <array>\n")
+	for _, a := range s.argv() {
+		fmt.Fprintf(&b, "    <string>%s</string>\n", plistEscape(a))
+	}
+	b.WriteString("  </array>\n")
+	if len(s.env) > 0 {
+		b.WriteString("  <key>EnvironmentVariables</key>\n  <dict>\n")
+		for _, k := range sortedKeys(s.env) {
+			fmt.Fprintf(&b, "    <key>%s</key>\n    <string>%s</string>\n", plistEscape(k), plistEscape(s.env[k]))
+		}
+		b.WriteString("  </dict>\n")
+	}
+	if s.command == "sync" {
+		fmt.Fprintf(&b, "  <key>StartInterval</key>\n  <integer>%d</integer>\n", int(s.interval.Seconds()))
+	} else {
+		b.WriteString("  <key>KeepAlive</key>\n  <true/>\n")
+	}
+	b.WriteString("  <key>RunAtLoad</key>\n  <true/>\n")
+	logFile := filepath.Join(s.logDir, s.name()+".log")
+	fmt.Fprintf(&b, "  <key>StandardOutPath</key>\n  <string>%s</string>\n", plistEscape(logFile))
+	fmt.Fprintf(&b, "  <key>StandardErrorPath</key>\n  <string>%s</string>\n", plistEscape(logFile))
+	b.WriteString("</dict>\n</plist>\n")
+	return []serviceFile{{path: filepath.Join(home, "Library", "LaunchAgents", label+".plist"), content: b.String()}}
+}
+
+// plistEscape escapes s for an XML text node.
+func plistEscape(s string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type mockRunner struct {
+	calls  []string
+	failOn string
+}
+
+func (m *mockRunner) run(name string, args ...string) error {
+	call := strings.Join(append([]string{name}, args...), " ")
+	m.calls = append(m.calls, call)
+	if m.failOn != "" && strings.Contains(call, m.failOn) {
+		return fmt.Errorf("%s failed", name)
+	}
+	return nil
+}
+
+func TestUnitServiceFiles(t *testing.T) {
+	spec := serviceSpec{
+		user:     "octocat",
+		exe:      "/opt/my tools/go-github-activity",
+		interval: 30 * time.Minute,
+		logDir:   "/home/octo/.go-github-activity",
+		env:      map[string]string{"LANG": "fr_FR.UTF-8", "PATH": "/usr/bin"},
+	}
+	testCases := []struct {
+		name      string
+		files     func(home string, s serviceSpec) []serviceFile
+		command   string
+		wantPaths []string
+		want      []string
+	}{
+		{
+			name:      "systemd sync",
+			files:     systemdFiles,
+			command:   "sync",
+			wantPaths: []string{"go-github-activity-sync.service", "go-github-activity-sync.timer"},
+			want: []string{
+				`ExecStart="/opt/my tools/go-github-activity" sync octocat`,
+				"Environment=LANG=fr_FR.UTF-8\nEnvironment=PATH=/usr/bin",
+				"Type=oneshot",
+				"OnUnitActiveSec=30m0s",
+			},
+		},
+		{
+			name:      "systemd watch",
+			files:     systemdFiles,
+			command:   "watch",
+			wantPaths: []string{"go-github-activity-watch.service"},
+			want:      []string{"Restart=on-failure", "WantedBy=default.target"},
+		},
+		{
+			name:      "launchd sync",
+			files:     launchdFiles,
+			command:   "sync",
+			wantPaths: []string{"io.github.alnah.go-github-activity-sync.plist"},
+			want: []string{
+				"<string>/opt/my tools/go-github-activity</string>",
+				"<key>LANG</key>\n    <string>fr_FR.UTF-8</string>",
+				"<integer>1800</integer>",
+				"go-github-activity-sync.log",
+			},
+		},
+		{
+			name:      "launchd watch",
+			files:     launchdFiles,
+			command:   "watch",
+			wantPaths: []string{"io.github.alnah.go-github-activity-watch.plist"},
+			want:      []string{"<key>KeepAlive</key>\n  <true/>"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			s := spec
+			s.command = tc.command
+			// Act
+			files := tc.files("/home/octo", s)
+			// Assert
+			assertEqual(t, len(files), len(tc.wantPaths))
+			for i, f := range files {
+				assertEqual(t, filepath.Base(f.path), tc.wantPaths[i])
+			}
+			for _, want := range tc.want {
+				assertEqual(t, strings.Contains(files[0].content+files[len(files)-1].content, want), true)
+			}
+		})
+	}
+}
+
+func TestUnitInstallService(t *testing.T) {
+	testCases := []struct {
+		name      string
+		failOn    string
+		wantCalls []string
+		wantErr   bool
+	}{
+		{
+			name: "installed",
+			wantCalls: []string{
+				"systemctl --user daemon-reload",
+				"systemctl --user enable --now go-github-activity-sync.timer",
+			},
+		},
+		{name: "load failure", failOn: "daemon-reload", wantCalls: []string{"systemctl --user daemon-reload"}, wantErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			home := t.TempDir()
+			r := &mockRunner{failOn: tc.failOn}
+			spec := serviceSpec{command: "sync", user: "octocat", exe: "/bin/gha", interval: time.Hour}
+			// Act
+			err := installService(servicePlatforms["linux"], home, spec, r, &bytes.Buffer{})
+			// Assert
+			assertEqual(t, strings.Join(r.calls, "|"), strings.Join(tc.wantCalls, "|"))
+			if tc.wantErr {
+				assertNotNil(t, err)
+				return
+			}
+			assertNoError(t, err)
+			_, err = os.Stat(filepath.Join(home, ".config", "systemd", "user", "go-github-activity-sync.timer"))
+			assertNoError(t, err)
+		})
+	}
+}
+
+func TestUnitUninstallService(t *testing.T) {
+	// Arrange
+	home := t.TempDir()
+	r := &mockRunner{}
+	spec := serviceSpec{command: "watch"}
+	files := launchdFiles(home, spec)
+	assertNoError(t, os.MkdirAll(filepath.Dir(files[0].path), 0o755))
+	assertNoError(t, os.WriteFile(files[0].path, []byte(files[0].content), 0o644))
+	// Act
+	err := uninstallService(servicePlatforms["darwin"], home, spec, r, &bytes.Buffer{})
+	// Assert
+	assertNoError(t, err)
+	assertEqual(t, strings.Join(r.calls, "|"), "launchctl unload -w "+files[0].path)
+	_, err = os.Stat(files[0].path)
+	assertEqual(t, os.IsNotExist(err), true)
+}
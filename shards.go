@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"text/tabwriter"
+)
+
+type (
+	// shardKey names the shard an event is aggregated in.
+	shardKey func(ev ghEvent) string
+	// shardTotals holds the metric totals of one shard.
+	shardTotals struct {
+		name   string
+		totals map[string]int
+	}
+)
+
+// byMonth shards events by the UTC month they were created in.
+func byMonth(ev ghEvent) string { return ev.CreatedAt.UTC().Format("2006-01") }
+
+// byRepo shards events by repository.
+func byRepo(ev ghEvent) string { return ev.Repo.Name }
+
+// bySource shards events by configured source; untagged events come from
+// GitHub, the only source by default.
+func bySource(ev ghEvent) string { return orDefault(ev.Source, "github") }
+
+// aggregateShards splits the events into shards, totals every metric of
+// each shard on up to workers goroutines and merges the results. It returns
+// the shards sorted by name and the overall totals.
+func aggregateShards(events []ghEvent, key shardKey, workers int) ([]shardTotals, map[string]int) {
+	groups := map[string][]int{}
+	for i, ev := range events {
+		k := key(ev)
+		groups[k] = append(groups[k], i)
+	}
+	shards := make([]shardTotals, 0, len(groups))
+	for _, name := range sortedKeys(groups) {
+		shards = append(shards, shardTotals{name: name})
+	}
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range max(1, min(workers, len(shards))) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				totals := make(map[string]int, len(metrics))
+				for _, j := range groups[shards[i].name] {
+					for name, m := range metrics {
+						totals[name] += m(events[j])
+					}
+				}
+				shards[i].totals = totals
+			}
+		}()
+	}
+	for i := range shards {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	overall := make(map[string]int, len(metrics))
+	for name := range metrics {
+		overall[name] = 0
+	}
+	for _, s := range shards {
+		for name, n := range s.totals {
+			overall[name] += n
+		}
+	}
+	return shards, overall
+}
+
+// sumMetrics totals every metric over the events.
+func sumMetrics(events []ghEvent) map[string]int {
+	totals := make(map[string]int, len(metrics))
+	for name, m := range metrics {
+		for _, ev := range events {
+			totals[name] += m(ev)
+		}
+	}
+	return totals
+}
+
+// monthsView prints the metric totals of each month.
+func monthsView(w io.Writer, events []ghEvent, opts statsOptions) error {
+	shards, _ := aggregateShards(events, byMonth, opts.workers)
+	return writeShards(w, "MONTH", shards)
+}
+
+// reposView prints the metric totals of each repository.
+func reposView(w io.Writer, events []ghEvent, opts statsOptions) error {
+	shards, _ := aggregateShards(events, byRepo, opts.workers)
+	return writeShards(w, "REPO", shards)
+}
+
+// sourcesView prints the metric totals of each configured source.
+func sourcesView(w io.Writer, events []ghEvent, opts statsOptions) error {
+	shards, _ := aggregateShards(events, bySource, opts.workers)
+	return writeShards(w, "SOURCE", shards)
+}
+
+// writeShards prints one row of metric totals per shard.
+func writeShards(w io.Writer, header string, shards []shardTotals) error {
+	names := sortedKeys(metrics)
+	tw := tabwriter.NewWriter(w, 0, 0, tablePadding, ' ', 0)
+	fmt.Fprintf(tw, "%s\t%s\n", header, strings.ToUpper(strings.Join(names, "\t")))
+	for _, s := range shards {
+		cells := make([]string, len(names))
+		for i, name := range names {
+			cells[i] = fmt.Sprint(s.totals[name])
+		}
+		fmt.Fprintf(tw, "%s\t%s\n", s.name, strings.Join(cells, "\t"))
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUnitAggregateShards(t *testing.T) {
+	events := benchCorpus(2_000)
+	want := sumMetrics(events)
+	testCases := []struct {
+		name    string
+		key     shardKey
+		workers int
+	}{
+		{name: "months on one worker", key: byMonth, workers: 1},
+		{name: "months on many workers", key: byMonth, workers: 8},
+		{name: "repositories on many workers", key: byRepo, workers: 8},
+		{name: "sources on many workers", key: bySource, workers: 8},
+		{name: "more workers than shards", key: byMonth, workers: 1_000},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			shards, got := aggregateShards(events, tc.key, tc.workers)
+			// Assert
+			for name, n := range want {
+				assertEqual(t, got[name], n)
+			}
+			events := 0
+			for i, s := range shards {
+				events += s.totals["events"]
+				if i > 0 {
+					assertEqual(t, shards[i-1].name < s.name, true)
+				}
+			}
+			assertEqual(t, events, len(benchCorpus(2_000)))
+		})
+	}
+}
+
+func TestUnitAggregateShardsEmpty(t *testing.T) {
+	// Act
+	shards, totals := aggregateShards(nil, byMonth, 4)
+	// Assert
+	assertEqual(t, len(shards), 0)
+	assertEqual(t, len(totals), len(metrics))
+	assertEqual(t, totals["events"], 0)
+}
+
+func TestUnitWriteShards(t *testing.T) {
+	// Arrange
+	march := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	events := []ghEvent{
+		{Type: "PushEvent", Payload: payload{Size: 2}, CreatedAt: march},
+		{Type: "WatchEvent", CreatedAt: march.AddDate(0, 1, 0)},
+		{Type: "PushEvent", Payload: payload{Size: 1}, CreatedAt: march.AddDate(0, 1, 1)},
+	}
+	shards, _ := aggregateShards(events, byMonth, 2)
+	var b strings.Builder
+	// Act
+	err := writeShards(&b, "MONTH", shards)
+	// Assert
+	assertNoError(t, err)
+	want := "MONTH    COMMITS  DISCUSSIONS  EVENTS  FAILED-RUNS  ISSUES  PULL-REQUESTS  STARS\n" +
+		"2025-03  2        0            1       0            0       0              0\n" +
+		"2025-04  1        0            2       0            0       0              1\n"
+	assertEqual(t, b.String(), want)
+}
+
+func TestUnitBySource(t *testing.T) {
+	testCases := []struct {
+		name string
+		ev   ghEvent
+		want string
+	}{
+		{name: "tagged", ev: ghEvent{Source: "work-gitlab"}, want: "work-gitlab"},
+		{name: "untagged", ev: ghEvent{}, want: "github"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			got := bySource(tc.ev)
+			// Assert
+			assertEqual(t, got, tc.want)
+		})
+	}
+}
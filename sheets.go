@@ -0,0 +1,198 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	// sheetsAPIURL is the Google Sheets API root.
+	sheetsAPIURL = "https://sheets.googleapis.com"
+	// sheetsScope grants read and write access to spreadsheets.
+	sheetsScope = "https://www.googleapis.com/auth/spreadsheets"
+)
+
+type (
+	// serviceAccount holds the fields of a Google service account key file.
+	serviceAccount struct {
+		ClientEmail string `json:"client_email"`
+		PrivateKey  string `json:"private_key"`
+		TokenURI    string `json:"token_uri"`
+	}
+	// sheetsExporter appends events as rows of a Google Sheet.
+	sheetsExporter struct {
+		account       serviceAccount
+		spreadsheetID string
+		sheetRange    string
+		baseURL       string
+		client        *http.Client
+		clock         clock
+	}
+)
+
+// newSheetsExporter reads the service account key referenced by the sheets
+// config section; it returns nil when no spreadsheet is configured.
+func newSheetsExporter(keyFile, spreadsheetID, sheetRange string) (*sheetsExporter, error) {
+	if spreadsheetID == "" {
+		return nil, nil
+	}
+	byt, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("read the service account key: %w", err)
+	}
+	var account serviceAccount
+	if err := json.Unmarshal(byt, &account); err != nil {
+		return nil, fmt.Errorf("parse the service account key: %w", err)
+	}
+	return &sheetsExporter{
+		account:       account,
+		spreadsheetID: spreadsheetID,
+		sheetRange:    sheetRange,
+		baseURL:       sheetsAPIURL,
+		client:        &http.Client{Timeout: 10 * time.Second},
+		clock:         systemClock{},
+	}, nil
+}
+
+// sheetRow flattens an event into spreadsheet cells.
+func sheetRow(ev ghEvent) []string {
+	return []string{
+		ev.ID,
+		ev.CreatedAt.UTC().Format(time.RFC3339),
+		ev.Type,
+		ev.Actor.Login,
+		ev.Repo.Name,
+		summarize(catalogs[defaultLang], ev),
+	}
+}
+
+// export appends one row per event to the configured sheet range.
+func (s *sheetsExporter) export(ctx context.Context, events []ghEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	token, err := s.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+	rows := make([][]string, 0, len(events))
+	for _, ev := range events {
+		rows = append(rows, sheetRow(ev))
+	}
+	body, err := json.Marshal(map[string]any{"values": rows})
+	if err != nil {
+		return fmt.Errorf("encode rows: %w", err)
+	}
+	endpoint := fmt.Sprintf("%s/v4/spreadsheets/%s/values/%s:append?valueInputOption=RAW&insertDataOption=INSERT_ROWS",
+		s.baseURL, url.PathEscape(s.spreadsheetID), url.PathEscape(s.sheetRange))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("request error: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	res, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("append rows: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode >= 300 {
+		return fmt.Errorf("append rows: Google Sheets API error: %q", res.Status)
+	}
+	return nil
+}
+
+// accessToken exchanges a signed JWT assertion for an OAuth2 access token.
+func (s *sheetsExporter) accessToken(ctx context.Context) (string, error) {
+	assertion, err := s.signedJWT()
+	if err != nil {
+		return "", err
+	}
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.account.TokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("request error: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	res, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("get access token: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("get access token: %q", res.Status)
+	}
+	var tok struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&tok); err != nil {
+		return "", fmt.Errorf("decode access token: %w", err)
+	}
+	if tok.AccessToken == "" {
+		return "", errors.New("get access token: empty token")
+	}
+	return tok.AccessToken, nil
+}
+
+// signedJWT builds the RS256 assertion of the service account.
+func (s *sheetsExporter) signedJWT() (string, error) {
+	key, err := parseRSAKey(s.account.PrivateKey)
+	if err != nil {
+		return "", err
+	}
+	now := s.clock.Now()
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	claims, _ := json.Marshal(map[string]any{
+		"iss":   s.account.ClientEmail,
+		"scope": sheetsScope,
+		"aud":   s.account.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	enc := base64.RawURLEncoding
+	unsigned := enc.EncodeToString(header) + "." + enc.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("sign the assertion: %w", err)
+	}
+	return unsigned + "." + enc.EncodeToString(sig), nil
+}
+
+// parseRSAKey decodes a PEM private key in PKCS#8 or PKCS#1 form.
+func parseRSAKey(pemKey string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(pemKey))
+	if block == nil {
+		return nil, errors.New("parse the private key: no PEM block")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse the private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("parse the private key: not an RSA key")
+	}
+	return key, nil
+}
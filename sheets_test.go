@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUnitSheetsExporter(t *testing.T) {
+	testCases := []struct {
+		name        string
+		tokenStatus int
+		wantErr     bool
+	}{
+		{name: "appends rows", tokenStatus: http.StatusOK},
+		{name: "token refused", tokenStatus: http.StatusUnauthorized, wantErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			key, _ := rsa.GenerateKey(rand.Reader, 2048)
+			pemKey := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: mustPKCS8(t, key)})
+			var gotRows [][]string
+			mux := http.NewServeMux()
+			mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+				r.ParseForm()
+				assertEqual(t, len(strings.Split(r.Form.Get("assertion"), ".")), 3)
+				w.WriteHeader(tc.tokenStatus)
+				w.Write([]byte(`{"access_token": "ya29.token"}`))
+			})
+			mux.HandleFunc("/v4/spreadsheets/sheet-id/values/", func(w http.ResponseWriter, r *http.Request) {
+				assertEqual(t, r.Header.Get("Authorization"), "Bearer ya29.token")
+				assertEqual(t, r.URL.Query().Get("valueInputOption"), "RAW")
+				var body struct {
+					Values [][]string `json:"values"`
+				}
+				json.NewDecoder(r.Body).Decode(&body)
+				gotRows = body.Values
+			})
+			srv := httptest.NewServer(mux)
+			t.Cleanup(srv.Close)
+			s := &sheetsExporter{
+				account: serviceAccount{
+					ClientEmail: "bot@example.iam",
+					PrivateKey:  string(pemKey),
+					TokenURI:    srv.URL + "/token",
+				},
+				spreadsheetID: "sheet-id",
+				sheetRange:    "Activity!A1",
+				baseURL:       srv.URL,
+				client:        srv.Client(),
+				clock:         systemClock{},
+			}
+			created := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+			events := []ghEvent{{
+				ID: "42", Type: "WatchEvent", Actor: actor{Login: "octocat"}, Repo: repo{Name: "octo/repo"}, CreatedAt: created,
+			}}
+			// Act
+			err := s.export(context.Background(), events)
+			// Assert
+			if tc.wantErr {
+				assertNotNil(t, err)
+				return
+			}
+			assertNoError(t, err)
+			assertEqual(t, len(gotRows), 1)
+			want := "42|2025-03-01T10:00:00Z|WatchEvent|octocat|octo/repo|Starred octo/repo"
+			assertEqual(t, strings.Join(gotRows[0], "|"), want)
+		})
+	}
+}
+
+func TestUnitParseRSAKey(t *testing.T) {
+	key, _ := rsa.GenerateKey(rand.Reader, 2048)
+	testCases := []struct {
+		name    string
+		pem     string
+		wantErr bool
+	}{
+		{name: "pkcs8", pem: string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: mustPKCS8(t, key)}))},
+		{
+			name: "pkcs1",
+			pem:  string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})),
+		},
+		{name: "not pem", pem: "garbage", wantErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			_, err := parseRSAKey(tc.pem)
+			// Assert
+			if tc.wantErr {
+				assertNotNil(t, err)
+			} else {
+				assertNoError(t, err)
+			}
+		})
+	}
+}
+
+func mustPKCS8(t *testing.T, key *rsa.PrivateKey) []byte {
+	t.Helper()
+	byt, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return byt
+}
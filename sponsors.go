@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+type (
+	// sponsorsProvider reads the new and cancelled sponsorships of a
+	// maintainer. Only the maintainer's own token can read them, so the
+	// source is opt-in and its events are private.
+	sponsorsProvider struct {
+		hc       *client
+		endpoint string
+	}
+	// sponsorship is the sponsorship of a SponsorshipEvent.
+	sponsorship struct {
+		Sponsor        string `json:"sponsor"`
+		Tier           string `json:"tier,omitempty"`
+		MonthlyDollars int    `json:"monthly_dollars,omitempty"`
+	}
+	// sponsorsActivity is a sponsors activity as served by the GraphQL API.
+	sponsorsActivity struct {
+		ID        string    `json:"id"`
+		Action    string    `json:"action"`
+		Timestamp time.Time `json:"timestamp"`
+		Sponsor   *struct {
+			Login string `json:"login"`
+		} `json:"sponsor"`
+		SponsorsTier *struct {
+			Name                  string `json:"name"`
+			MonthlyPriceInDollars int    `json:"monthlyPriceInDollars"`
+		} `json:"sponsorsTier"`
+	}
+)
+
+// sponsorsQuery reads the 100 latest new and cancelled sponsorships of an
+// account.
+const sponsorsQuery = `query($login: String!) {
+  user(login: $login) {
+    sponsorsActivities(first: 100, period: ALL, orderBy: {field: TIMESTAMP, direction: DESC},
+                       actions: [NEW_SPONSORSHIP, CANCELLED_SPONSORSHIP]) {
+      nodes {
+        id
+        action
+        timestamp
+        sponsor {
+          ... on User { login }
+          ... on Organization { login }
+        }
+        sponsorsTier { name monthlyPriceInDollars }
+      }
+    }
+  }
+}`
+
+// sponsorshipActions maps sponsors activity actions to event actions.
+var sponsorshipActions = map[string]string{
+	"NEW_SPONSORSHIP":       "created",
+	"CANCELLED_SPONSORSHIP": "cancelled",
+}
+
+func newSponsorsProvider(hc *client, apiURL string) *sponsorsProvider {
+	return &sponsorsProvider{hc: hc, endpoint: graphqlURL(apiURL)}
+}
+
+func (p *sponsorsProvider) name() string { return "github-sponsors" }
+
+// events returns a private SponsorshipEvent per new or cancelled
+// sponsorship of the user, newest first.
+func (p *sponsorsProvider) events(login string) ([]ghEvent, error) {
+	var data struct {
+		User *struct {
+			SponsorsActivities struct {
+				Nodes []sponsorsActivity `json:"nodes"`
+			} `json:"sponsorsActivities"`
+		} `json:"user"`
+	}
+	if err := fetchGraphQL(p.hc, p.endpoint, sponsorsQuery, map[string]any{"login": login}, &data); err != nil {
+		return nil, fmt.Errorf("fetch sponsorships of %s: %w", login, err)
+	}
+	if data.User == nil {
+		return nil, nil
+	}
+	var events []ghEvent
+	for _, a := range data.User.SponsorsActivities.Nodes {
+		action, ok := sponsorshipActions[a.Action]
+		if !ok {
+			continue
+		}
+		s := &sponsorship{}
+		if a.Sponsor != nil {
+			s.Sponsor = a.Sponsor.Login
+		}
+		if a.SponsorsTier != nil {
+			s.Tier, s.MonthlyDollars = a.SponsorsTier.Name, a.SponsorsTier.MonthlyPriceInDollars
+		}
+		events = append(events, ghEvent{
+			ID: "sponsorship-" + a.ID, Type: "SponsorshipEvent", Actor: actor{Login: login}, Repo: repo{Name: login},
+			Payload: payload{Action: action, Sponsorship: s}, CreatedAt: a.Timestamp,
+		})
+	}
+	return events, nil
+}
+
+// hideSponsorships leaves the private sponsorships out of the events, unless
+// sponsors.public shares them, so that only the digest reads them.
+func hideSponsorships(events []ghEvent) []ghEvent {
+	if viper.GetBool("sponsors.public") {
+		return events
+	}
+	var shown []ghEvent
+	for _, ev := range events {
+		if ev.Type != "SponsorshipEvent" {
+			shown = append(shown, ev)
+		}
+	}
+	return shown
+}
+
+// sponsorsSummary counts the new and cancelled sponsorships and the change
+// of the monthly sponsorship income.
+func sponsorsSummary(events []ghEvent) (created, cancelled, monthly int) {
+	for _, ev := range events {
+		if ev.Type != "SponsorshipEvent" || ev.Payload.Sponsorship == nil {
+			continue
+		}
+		switch ev.Payload.Action {
+		case "created":
+			created++
+			monthly += ev.Payload.Sponsorship.MonthlyDollars
+		case "cancelled":
+			cancelled++
+			monthly -= ev.Payload.Sponsorship.MonthlyDollars
+		}
+	}
+	return created, cancelled, monthly
+}
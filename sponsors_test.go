@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestUnitSponsorsProviderEvents(t *testing.T) {
+	// Arrange
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assertEqual(t, r.URL.Path, "/graphql")
+		fmt.Fprint(w, `{"data":{"user":{"sponsorsActivities":{"nodes":[
+			{"id":"SA2","action":"CANCELLED_SPONSORSHIP","timestamp":"2025-03-02T00:00:00Z",
+			 "sponsor":{"login":"mona"},"sponsorsTier":{"name":"$5 a month","monthlyPriceInDollars":5}},
+			{"id":"SA1","action":"NEW_SPONSORSHIP","timestamp":"2025-03-01T00:00:00Z",
+			 "sponsor":{"login":"octo-org"},"sponsorsTier":{"name":"$25 a month","monthlyPriceInDollars":25}},
+			{"id":"SA0","action":"TIER_CHANGE","timestamp":"2025-02-28T00:00:00Z"}
+		]}}}}`)
+	}))
+	defer srv.Close()
+	p := newSponsorsProvider(newClient(""), srv.URL)
+	// Act
+	events, err := p.events("octocat")
+	// Assert
+	assertNoError(t, err)
+	assertEqual(t, len(events), 2)
+	assertEqual(t, events[0].ID, "sponsorship-SA2")
+	assertEqual(t, events[0].Payload.Action, "cancelled")
+	assertEqual(t, events[0].Public, false)
+	assertEqual(t, *events[1].Payload.Sponsorship, sponsorship{
+		Sponsor: "octo-org", Tier: "$25 a month", MonthlyDollars: 25,
+	})
+	assertEqual(t, summarize(lookupCatalog("en"), events[1]), "Gained octo-org as a sponsor")
+}
+
+func TestUnitSponsorsSummary(t *testing.T) {
+	testCases := []struct {
+		name          string
+		events        []ghEvent
+		wantCreated   int
+		wantCancelled int
+		wantMonthly   int
+	}{
+		{name: "none", events: []ghEvent{{Type: "PushEvent"}}},
+		{
+			name: "new and cancelled",
+			events: []ghEvent{
+				{Type: "SponsorshipEvent", Payload: payload{Action: "created", Sponsorship: &sponsorship{MonthlyDollars: 25}}},
+				{Type: "SponsorshipEvent", Payload: payload{Action: "created", Sponsorship: &sponsorship{MonthlyDollars: 5}}},
+				{Type: "SponsorshipEvent", Payload: payload{Action: "cancelled", Sponsorship: &sponsorship{MonthlyDollars: 10}}},
+			},
+			wantCreated: 2, wantCancelled: 1, wantMonthly: 20,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			created, cancelled, monthly := sponsorsSummary(tc.events)
+			// Assert
+			assertEqual(t, created, tc.wantCreated)
+			assertEqual(t, cancelled, tc.wantCancelled)
+			assertEqual(t, monthly, tc.wantMonthly)
+		})
+	}
+}
+
+func TestUnitHideSponsorships(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/users/octocat/events":
+			fmt.Fprint(w, `[{"id":"1","type":"WatchEvent","repo":{"name":"octo/repo"},
+				"created_at":"2025-03-02T00:00:00Z"}]`)
+		case "/graphql":
+			fmt.Fprint(w, `{"data":{"user":{"sponsorsActivities":{"nodes":[
+				{"id":"SA1","action":"NEW_SPONSORSHIP","timestamp":"2025-03-01T00:00:00Z","sponsor":{"login":"mona"}}
+			]}}}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	testCases := []struct {
+		name       string
+		public     bool
+		wantEvents int
+	}{
+		{name: "private", wantEvents: 1},
+		{name: "shared", public: true, wantEvents: 2},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			t.Setenv("HOME", t.TempDir())
+			viper.Set("github_token", "ghp_test")
+			viper.Set("sources", []map[string]any{
+				{"type": "github", "url": srv.URL},
+				{"type": "github-sponsors", "url": srv.URL},
+			})
+			viper.Set("sponsors.public", tc.public)
+			t.Cleanup(viper.Reset)
+			// Act
+			events, err := fetchUserEvents("octocat")
+			section := markdownSection(catalogs[defaultLang], loadIcons(false, nil), events, 5)
+			// Assert
+			assertNoError(t, err)
+			assertEqual(t, len(events), tc.wantEvents)
+			assertEqual(t, strings.Contains(section, "sponsor"), tc.public)
+		})
+	}
+}
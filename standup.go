@@ -0,0 +1,163 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+type (
+	// standup lists the work of a day, split into finished and ongoing.
+	standup struct {
+		day        time.Time
+		done       []string
+		inProgress []string
+	}
+	// standupItem is the latest state of a pull request, issue or branch.
+	standupItem struct {
+		text string
+		done bool
+	}
+)
+
+// lastWorkday returns the start of the weekday before now, e.g. Friday on
+// a Monday.
+func lastWorkday(now time.Time) time.Time {
+	day := startOfDay(now, now.Location()).AddDate(0, 0, -1)
+	for day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+		day = day.AddDate(0, 0, -1)
+	}
+	return day
+}
+
+// runStandup prints the last workday's activity as a standup update.
+func runStandup(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("standup", flag.ContinueOnError)
+	date := flags.String("date", "", "day to report, as YYYY-MM-DD (default: the last workday)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: go-github-activity standup [flags] <username>")
+	}
+	day := lastWorkday(time.Now())
+	if *date != "" {
+		d, err := time.ParseInLocation(time.DateOnly, *date, time.Local)
+		if err != nil {
+			return fmt.Errorf("invalid date %q: %w", *date, err)
+		}
+		day = d
+	}
+	events, err := fetchUserEvents(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	return writeStandup(stdout, buildStandup(events, day))
+}
+
+// buildStandup keeps the latest state of each pull request, issue and
+// pushed branch of the day. Events are newest first.
+func buildStandup(events []ghEvent, day time.Time) standup {
+	end := day.AddDate(0, 0, 1)
+	items := map[string]*standupItem{}
+	var order []string
+	commits := map[string]int{}
+	for i := len(events) - 1; i >= 0; i-- {
+		ev := events[i]
+		if ev.CreatedAt.Before(day) || !ev.CreatedAt.Before(end) {
+			continue
+		}
+		key, item := standupEntry(ev)
+		if item == nil {
+			continue
+		}
+		if ev.Type == "PushEvent" {
+			commits[key] += pushSize(ev)
+			item.text = fmt.Sprintf("Pushed %d commit%s to %s",
+				commits[key], plural(commits[key]), strings.TrimPrefix(key, "push "))
+		}
+		if _, seen := items[key]; !seen {
+			order = append(order, key)
+		}
+		items[key] = item
+	}
+	s := standup{day: day}
+	for _, key := range order {
+		if items[key].done {
+			s.done = append(s.done, items[key].text)
+		} else {
+			s.inProgress = append(s.inProgress, items[key].text)
+		}
+	}
+	return s
+}
+
+// standupEntry returns the key and state an event contributes, or nil for
+// events irrelevant to a standup.
+func standupEntry(ev ghEvent) (string, *standupItem) {
+	switch ev.Type {
+	case "PullRequestEvent", "PullRequestReviewEvent":
+		pr := ev.Payload.PullRequest
+		if pr == nil {
+			return "", nil
+		}
+		ref := fmt.Sprintf("%s#%d", ev.Repo.Name, pr.Number)
+		switch {
+		case ev.Type == "PullRequestReviewEvent":
+			return "review " + ref, &standupItem{text: fmt.Sprintf("Reviewed %s %s", ref, pr.Title), done: true}
+		case ev.Payload.Action == "closed" && pr.Merged:
+			return ref, &standupItem{text: fmt.Sprintf("Merged %s %s", ref, pr.Title), done: true}
+		case ev.Payload.Action == "closed":
+			return ref, &standupItem{text: fmt.Sprintf("Closed %s %s", ref, pr.Title), done: true}
+		default:
+			return ref, &standupItem{text: fmt.Sprintf("Working on %s %s", ref, pr.Title)}
+		}
+	case "IssuesEvent":
+		is := ev.Payload.Issue
+		if is == nil {
+			return "", nil
+		}
+		ref := fmt.Sprintf("%s#%d", ev.Repo.Name, is.Number)
+		if ev.Payload.Action == "closed" {
+			return ref, &standupItem{text: fmt.Sprintf("Closed %s %s", ref, is.Title), done: true}
+		}
+		return ref, &standupItem{text: fmt.Sprintf("Opened %s %s", ref, is.Title)}
+	case "ReleaseEvent":
+		return "release " + ev.Repo.Name, &standupItem{text: "Published a release of " + ev.Repo.Name, done: true}
+	case "PushEvent":
+		branch := strings.TrimPrefix(ev.Payload.Ref, "refs/heads/")
+		return "push " + ev.Repo.Name + "@" + branch, &standupItem{}
+	}
+	return "", nil
+}
+
+// plural returns the English plural suffix for n items.
+func plural(n int) string {
+	if n == 1 {
+		return ""
+	}
+	return "s"
+}
+
+// writeStandup formats the standup with Slack markup.
+func writeStandup(w io.Writer, s standup) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*Standup for %s*\n", s.day.Format("Monday, January 2"))
+	for _, section := range []struct {
+		title string
+		items []string
+	}{{"Done", s.done}, {"In progress", s.inProgress}} {
+		fmt.Fprintf(&b, "\n*%s*\n", section.title)
+		if len(section.items) == 0 {
+			b.WriteString("• nothing\n")
+		}
+		for _, item := range section.items {
+			fmt.Fprintf(&b, "• %s\n", item)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUnitLastWorkday(t *testing.T) {
+	testCases := []struct {
+		name string
+		now  time.Time
+		want string
+	}{
+		{name: "midweek", now: time.Date(2025, 3, 5, 9, 0, 0, 0, time.UTC), want: "2025-03-04"},
+		{name: "monday", now: time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC), want: "2025-02-28"},
+		{name: "sunday", now: time.Date(2025, 3, 2, 9, 0, 0, 0, time.UTC), want: "2025-02-28"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			got := lastWorkday(tc.now)
+			// Assert
+			assertEqual(t, got.Format(time.DateOnly), tc.want)
+		})
+	}
+}
+
+func TestUnitBuildStandup(t *testing.T) {
+	day := time.Date(2025, 3, 4, 0, 0, 0, 0, time.UTC)
+	event := func(typ, name string, hour int, p payload) ghEvent {
+		return ghEvent{Type: typ, Repo: repo{Name: name}, CreatedAt: day.Add(time.Duration(hour) * time.Hour), Payload: p}
+	}
+	pr := &pullRequest{Number: 7, Title: "Add login", Merged: true}
+	// Newest first, as served by the API.
+	events := []ghEvent{
+		event("PushEvent", "octo/api", 30, payload{Size: 9, Ref: "refs/heads/main"}),
+		event("PullRequestEvent", "octo/api", 16, payload{Action: "closed", PullRequest: pr}),
+		event("PushEvent", "octo/api", 15, payload{Size: 2, Ref: "refs/heads/login"}),
+		event("IssuesEvent", "octo/web", 14, payload{Action: "opened", Issue: &issue{Number: 3, Title: "Broken footer"}}),
+		event("PushEvent", "octo/api", 11, payload{Size: 1, Ref: "refs/heads/login"}),
+		event("PullRequestEvent", "octo/api", 10, payload{Action: "opened", PullRequest: pr}),
+		event("WatchEvent", "octo/web", 9, payload{}),
+		event("PushEvent", "octo/api", -2, payload{Size: 4, Ref: "refs/heads/main"}),
+	}
+	// Act
+	got := buildStandup(events, day)
+	// Assert
+	assertEqual(t, strings.Join(got.done, "|"), "Merged octo/api#7 Add login")
+	assertEqual(t, strings.Join(got.inProgress, "|"), "Pushed 3 commits to octo/api@login|Opened octo/web#3 Broken footer")
+}
+
+func TestUnitWriteStandup(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+	s := standup{day: time.Date(2025, 3, 4, 0, 0, 0, 0, time.UTC), done: []string{"Merged octo/api#7 Add login"}}
+	// Act
+	err := writeStandup(&buf, s)
+	// Assert
+	assertNoError(t, err)
+	want := "*Standup for Tuesday, March 4*\n\n*Done*\n• Merged octo/api#7 Add login\n\n*In progress*\n• nothing\n"
+	assertEqual(t, buf.String(), want)
+}
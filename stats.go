@@ -0,0 +1,353 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+type (
+	// metric counts what one event contributes to an activity total.
+	metric func(ev ghEvent) int
+	// namedCount is the number of events sharing a key, e.g. a repository.
+	namedCount struct {
+		name  string
+		count int
+	}
+	// languageShare is the fraction of activity attributed to a language.
+	languageShare struct {
+		Language string
+		Share    float64
+	}
+)
+
+// unknownLanguage collects activity in repositories without language data.
+const unknownLanguage = "Unknown"
+
+// metrics lists the countable activity totals by name.
+var metrics = map[string]metric{
+	"events":  func(ghEvent) int { return 1 },
+	"commits": pushSize,
+	"pull-requests": func(ev ghEvent) int {
+		return boolToInt(ev.Type == "PullRequestEvent" && ev.Payload.Action == "opened")
+	},
+	"issues": func(ev ghEvent) int {
+		return boolToInt(ev.Type == "IssuesEvent" && ev.Payload.Action == "opened")
+	},
+	"stars": func(ev ghEvent) int {
+		return boolToInt(ev.Type == "WatchEvent")
+	},
+	"discussions": func(ev ghEvent) int {
+		return boolToInt(ev.Type == "DiscussionEvent" || ev.Type == "DiscussionCommentEvent")
+	},
+	"failed-runs": func(ev ghEvent) int {
+		run := ev.Payload.WorkflowRun
+		return boolToInt(ev.Type == "WorkflowRunEvent" && run != nil && run.Conclusion == "failure")
+	},
+}
+
+// statsView prints one breakdown of the events of the window.
+type statsView func(w io.Writer, events []ghEvent, opts statsOptions) error
+
+// statsOptions holds the settings shared by the stats views.
+type statsOptions struct {
+	days        int
+	compare     string
+	workHours   string
+	burnout     float64
+	enrich      bool
+	environment string
+	workers     int
+	showEmails  bool
+	// user is the user or person whose activity is broken down.
+	user string
+}
+
+// statsViews lists the breakdowns selectable with --by; the empty name
+// prints the totals.
+var statsViews = map[string]statsView{
+	"":          totalsView,
+	"language":  languagesView,
+	"org":       orgsView,
+	"ticket":    ticketsView,
+	"hours":     workHoursView,
+	"signed":    signedView,
+	"coauthor":  collaboratorsView,
+	"kind":      commitKindsView,
+	"pr":        prsView,
+	"triage":    triageView,
+	"deploy":    deploysView,
+	"month":     monthsView,
+	"repo":      reposView,
+	"email":     emailsView,
+	"source":    sourcesView,
+	"label":     labelsView,
+	"milestone": milestonesView,
+}
+
+// runStats prints activity statistics over a window of days.
+func runStats(args []string, stdout io.Writer) error {
+	return statsAt(systemClock{}, args, stdout)
+}
+
+// statsAt runs stats with the window ending at the time of the clock.
+func statsAt(c clock, args []string, stdout io.Writer) error {
+	var opts statsOptions
+	flags := flag.NewFlagSet("stats", flag.ContinueOnError)
+	flags.IntVar(&opts.days, "days", 7, "size of the window in days")
+	by := flags.String("by", "", "break activity down by: language, org, ticket, hours, signed, coauthor, kind, "+
+		"pr, triage, deploy, month, repo, email, source, label or milestone")
+	flags.StringVar(&opts.compare, "compare-with", "", "compare the totals with another window: previous")
+	flags.StringVar(&opts.workHours, "work-hours", "9-18", "local working hours for --by hours, as start-end")
+	flags.Float64Var(&opts.burnout, "burnout-threshold", 0,
+		"with --by hours, warn when this percentage of activity is outside working time (0 disables)")
+	flags.BoolVar(&opts.enrich, "enrich", false,
+		"with --by triage or deploy, read issues or deployments from the API")
+	flags.StringVar(&opts.environment, "environment", "",
+		"with --by deploy, count deployments to this environment only")
+	flags.IntVar(&opts.workers, "workers", runtime.NumCPU(), "goroutines aggregating the monthly or repository shards")
+	flags.BoolVar(&opts.showEmails, "show-emails", false, "with --by email, print full addresses instead of masked ones")
+	failOnEmpty := flags.Bool("fail-on-empty", false, "exit with code 6 when the window has no activity")
+	deep := flags.Bool("deep", false, "supplement the events with the Search API beyond the events window")
+	fromArchive := flags.Bool("archive", false, "read the events from the local archive instead of the API")
+	output := flags.String("output", "text", "output format: text or pdf")
+	var repoFilters repoFilterFlags
+	repoFilters.register(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: go-github-activity stats [flags] <username>")
+	}
+	opts.user = flags.Arg(0)
+	if *output != "text" && *output != "pdf" {
+		return fmt.Errorf("unknown output format %q", *output)
+	}
+	if opts.workers <= 0 {
+		return fmt.Errorf("invalid worker count: %d", opts.workers)
+	}
+	if opts.days <= 0 {
+		return fmt.Errorf("invalid window: %d days", opts.days)
+	}
+	view, ok := statsViews[*by]
+	if !ok {
+		return fmt.Errorf("unknown breakdown %q", *by)
+	}
+	if opts.compare != "" && opts.compare != "previous" {
+		return fmt.Errorf("unknown comparison window %q", opts.compare)
+	}
+	window := opts.days
+	if opts.compare != "" {
+		window *= 2
+	}
+	if *deep && *fromArchive {
+		return errors.New("--deep and --archive are mutually exclusive")
+	}
+	now := c.Now()
+	since := now.AddDate(0, 0, -window)
+	var events []ghEvent
+	var err error
+	if *fromArchive {
+		events, err = archivedEvents(flags.Arg(0), since)
+	} else {
+		events, err = fetchDeepEvents(flags.Arg(0), *deep, since)
+	}
+	if err != nil {
+		return err
+	}
+	if events, err = repoFilters.apply(events); err != nil {
+		return err
+	}
+	if *failOnEmpty && len(eventsSince(events, now.AddDate(0, 0, -opts.days))) == 0 {
+		return errEmpty
+	}
+	w, done := reportOutput(stdout, *output == "pdf")
+	if opts.compare != "" {
+		err = writeComparison(w, compareWindows(events, now, opts.days))
+	} else {
+		err = view(w, eventsSince(events, now.AddDate(0, 0, -opts.days)), opts)
+	}
+	if err != nil {
+		return err
+	}
+	return done()
+}
+
+// totalsView prints every metric total, aggregated by month in parallel.
+func totalsView(w io.Writer, events []ghEvent, opts statsOptions) error {
+	_, totals := aggregateShards(events, byMonth, opts.workers)
+	return writeTotals(w, totals)
+}
+
+// languagesView prints the share of activity per language.
+func languagesView(w io.Writer, events []ghEvent, opts statsOptions) error {
+	fetcher, err := newRepoFetcher()
+	if err != nil {
+		return err
+	}
+	shares, err := languageBreakdown(events, fetcher.languages)
+	if err != nil {
+		return err
+	}
+	return writeLanguages(w, shares, opts.days)
+}
+
+// orgsView prints the share of activity per repository owner.
+func orgsView(w io.Writer, events []ghEvent, _ statsOptions) error {
+	for _, c := range countBy(events, func(ev ghEvent) string { return repoOwner(ev.Repo.Name) }) {
+		share := float64(c.count) * 100 / float64(len(events))
+		if _, err := fmt.Fprintf(w, "%-20s %5d %5.1f%%\n", c.name, c.count, share); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// countBy counts the events per key, by decreasing count then name.
+func countBy(events []ghEvent, key func(ev ghEvent) string) []namedCount {
+	counts := map[string]int{}
+	for _, ev := range events {
+		counts[key(ev)]++
+	}
+	return rankCounts(counts)
+}
+
+// rankCounts sorts counts by decreasing count then name.
+func rankCounts(counts map[string]int) []namedCount {
+	out := make([]namedCount, 0, len(counts))
+	for name, n := range counts {
+		out = append(out, namedCount{name: name, count: n})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].count != out[j].count {
+			return out[i].count > out[j].count
+		}
+		return out[i].name < out[j].name
+	})
+	return out
+}
+
+// eventsSince keeps the events created at or after t.
+func eventsSince(events []ghEvent, t time.Time) []ghEvent {
+	kept := make([]ghEvent, 0, len(events))
+	for _, ev := range events {
+		if !ev.CreatedAt.Before(t) {
+			kept = append(kept, ev)
+		}
+	}
+	return kept
+}
+
+// pushSize is the number of commits of a push event, 0 for other events.
+func pushSize(ev ghEvent) int {
+	if ev.Type != "PushEvent" {
+		return 0
+	}
+	if ev.Payload.Size > 0 {
+		return ev.Payload.Size
+	}
+	return len(ev.Payload.Commits)
+}
+
+// countMetric totals the named metric over the events created since the given time.
+func countMetric(events []ghEvent, name string, since time.Time) (int, error) {
+	m, ok := metrics[name]
+	if !ok {
+		return 0, fmt.Errorf("unknown metric %q", name)
+	}
+	total := 0
+	for _, ev := range eventsSince(events, since) {
+		total += m(ev)
+	}
+	return total, nil
+}
+
+// writeTotals prints every metric total, one per line.
+func writeTotals(w io.Writer, totals map[string]int) error {
+	for _, name := range sortedKeys(totals) {
+		if _, err := fmt.Fprintf(w, "%-14s %d\n", name+":", totals[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// languageBreakdown splits each event across the languages of its
+// repository, weighted by bytes of code, and returns the shares by
+// decreasing weight. Deleted or empty repositories count as unknown.
+func languageBreakdown(
+	events []ghEvent, languagesOf func(repo string) (map[string]int, error),
+) ([]languageShare, error) {
+	weights := map[string]float64{}
+	for _, ev := range events {
+		langs, err := languagesOf(ev.Repo.Name)
+		if err != nil && !isStatus(err, http.StatusNotFound) {
+			return nil, err
+		}
+		total := 0
+		for _, n := range langs {
+			total += n
+		}
+		if total == 0 {
+			weights[unknownLanguage]++
+			continue
+		}
+		for lang, n := range langs {
+			weights[lang] += float64(n) / float64(total)
+		}
+	}
+	shares := make([]languageShare, 0, len(weights))
+	for lang, w := range weights {
+		shares = append(shares, languageShare{Language: lang, Share: w / float64(len(events))})
+	}
+	sort.Slice(shares, func(i, j int) bool {
+		if shares[i].Share != shares[j].Share {
+			return shares[i].Share > shares[j].Share
+		}
+		return shares[i].Language < shares[j].Language
+	})
+	return shares, nil
+}
+
+// describeLanguages summarizes the two main languages in one sentence.
+func describeLanguages(shares []languageShare, days int) string {
+	period := fmt.Sprintf("In the last %d days", days)
+	if days == 7 {
+		period = "This week"
+	}
+	if len(shares) == 0 {
+		return period + " you had no activity."
+	}
+	top := make([]string, 0, 2)
+	for _, s := range shares[:min(2, len(shares))] {
+		top = append(top, fmt.Sprintf("%s (%d%%)", s.Language, int(math.Round(s.Share*100))))
+	}
+	return fmt.Sprintf("%s you mostly worked in %s.", period, strings.Join(top, " and "))
+}
+
+// writeLanguages prints the summary sentence followed by every share.
+func writeLanguages(w io.Writer, shares []languageShare, days int) error {
+	if _, err := fmt.Fprintln(w, describeLanguages(shares, days)); err != nil {
+		return err
+	}
+	for _, s := range shares {
+		if _, err := fmt.Fprintf(w, "%-14s %5.1f%%\n", s.Language, s.Share*100); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
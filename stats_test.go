@@ -0,0 +1,306 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/quick"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func TestUnitCountMetric(t *testing.T) {
+	since := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	events := []ghEvent{
+		{Type: "PushEvent", Payload: payload{Size: 2}, CreatedAt: since},
+		{Type: "PushEvent", Payload: payload{Commits: []commit{{}, {}, {}}}, CreatedAt: since.Add(time.Hour)},
+		{Type: "PullRequestEvent", Payload: payload{Action: "opened"}, CreatedAt: since.Add(time.Hour)},
+		{Type: "PullRequestEvent", Payload: payload{Action: "closed"}, CreatedAt: since.Add(time.Hour)},
+		{Type: "PushEvent", Payload: payload{Size: 5}, CreatedAt: since.Add(-time.Hour)},
+	}
+	testCases := []struct {
+		name    string
+		metric  string
+		want    int
+		wantErr bool
+	}{
+		{name: "commits", metric: "commits", want: 5},
+		{name: "events", metric: "events", want: 4},
+		{name: "pull requests", metric: "pull-requests", want: 1},
+		{name: "unknown", metric: "lines", wantErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			got, err := countMetric(events, tc.metric, since)
+			// Assert
+			if tc.wantErr {
+				assertNotNil(t, err)
+				return
+			}
+			assertNoError(t, err)
+			assertEqual(t, got, tc.want)
+		})
+	}
+}
+
+func TestUnitLanguageBreakdown(t *testing.T) {
+	languages := map[string]map[string]int{
+		"octo/go":    {"Go": 300, "Shell": 100},
+		"octo/ts":    {"TypeScript": 100},
+		"octo/empty": {},
+	}
+	languagesOf := func(name string) (map[string]int, error) {
+		if name == "octo/gone" {
+			return nil, &apiError{StatusCode: 404, Status: "404 Not Found"}
+		}
+		if name == "octo/limited" {
+			return nil, &apiError{StatusCode: 403, Status: "403 Forbidden"}
+		}
+		return languages[name], nil
+	}
+	testCases := []struct {
+		name    string
+		repos   []string
+		want    []languageShare
+		wantErr bool
+	}{
+		{
+			name:  "weighted by bytes",
+			repos: []string{"octo/go", "octo/go", "octo/ts", "octo/ts"},
+			want: []languageShare{
+				{Language: "TypeScript", Share: 0.5},
+				{Language: "Go", Share: 0.375},
+				{Language: "Shell", Share: 0.125},
+			},
+		},
+		{
+			name:  "deleted and empty repositories",
+			repos: []string{"octo/gone", "octo/empty"},
+			want:  []languageShare{{Language: unknownLanguage, Share: 1}},
+		},
+		{name: "api failure", repos: []string{"octo/limited"}, wantErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			events := make([]ghEvent, 0, len(tc.repos))
+			for _, r := range tc.repos {
+				events = append(events, ghEvent{Repo: repo{Name: r}})
+			}
+			// Act
+			got, err := languageBreakdown(events, languagesOf)
+			// Assert
+			if tc.wantErr {
+				assertNotNil(t, err)
+				return
+			}
+			assertNoError(t, err)
+			assertEqual(t, len(got), len(tc.want))
+			for i := range tc.want {
+				assertEqual(t, got[i], tc.want[i])
+			}
+		})
+	}
+}
+
+func TestUnitDescribeLanguages(t *testing.T) {
+	testCases := []struct {
+		name   string
+		shares []languageShare
+		days   int
+		want   string
+	}{
+		{
+			name:   "two main languages",
+			shares: []languageShare{{"Go", 0.62}, {"TypeScript", 0.21}, {"Shell", 0.17}},
+			days:   7,
+			want:   "This week you mostly worked in Go (62%) and TypeScript (21%).",
+		},
+		{
+			name:   "single language",
+			shares: []languageShare{{"Go", 1}},
+			days:   30,
+			want:   "In the last 30 days you mostly worked in Go (100%).",
+		},
+		{name: "no activity", days: 7, want: "This week you had no activity."},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			got := describeLanguages(tc.shares, tc.days)
+			// Assert
+			assertEqual(t, got, tc.want)
+		})
+	}
+}
+
+func TestUnitCountBy(t *testing.T) {
+	// Arrange
+	events := []ghEvent{
+		{Repo: repo{Name: "work/api"}},
+		{Repo: repo{Name: "me/dotfiles"}},
+		{Repo: repo{Name: "work/web"}},
+		{Repo: repo{Name: "acme/lib"}},
+	}
+	// Act
+	got := countBy(events, func(ev ghEvent) string { return repoOwner(ev.Repo.Name) })
+	// Assert
+	assertEqual(t, len(got), 3)
+	assertEqual(t, got[0], namedCount{name: "work", count: 2})
+	assertEqual(t, got[1], namedCount{name: "acme", count: 1})
+	assertEqual(t, got[2], namedCount{name: "me", count: 1})
+}
+
+// eventSet is a random set of events for the property tests: a few owners,
+// repositories and types, with pushes of random sizes over 60 days.
+type eventSet []ghEvent
+
+// propertyStart is the first day of the generated events.
+var propertyStart = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func (eventSet) Generate(rng *rand.Rand, size int) reflect.Value {
+	types := []string{"PushEvent", "PullRequestEvent", "IssuesEvent", "WatchEvent", "CreateEvent", "ReleaseEvent"}
+	actions := []string{"opened", "closed", "started", ""}
+	events := make(eventSet, rng.Intn(size+1))
+	for i := range events {
+		ev := ghEvent{
+			ID:        fmt.Sprint(i),
+			Type:      types[rng.Intn(len(types))],
+			Repo:      repo{Name: fmt.Sprintf("owner%d/repo%d", rng.Intn(3), rng.Intn(4))},
+			Payload:   payload{Action: actions[rng.Intn(len(actions))]},
+			CreatedAt: propertyStart.Add(time.Duration(rng.Int63n(int64(60 * 24 * time.Hour)))),
+		}
+		if ev.Type == "PushEvent" {
+			ev.Payload.Size = rng.Intn(5)
+			ev.Payload.Commits = make([]commit, rng.Intn(5))
+		}
+		events[i] = ev
+	}
+	return reflect.ValueOf(events)
+}
+
+func TestUnitStatsProperties(t *testing.T) {
+	byType := func(ev ghEvent) string { return ev.Type }
+	byRepo := func(ev ghEvent) string { return ev.Repo.Name }
+	testCases := []struct {
+		name     string
+		property any
+	}{
+		{
+			name: "totals by type sum to the number of events",
+			property: func(events eventSet) bool {
+				total := 0
+				for _, c := range countBy(events, byType) {
+					total += c.count
+				}
+				return total == len(events)
+			},
+		},
+		{
+			name: "counts are ranked by decreasing count then name",
+			property: func(events eventSet) bool {
+				counts := countBy(events, byRepo)
+				for i := 1; i < len(counts); i++ {
+					a, b := counts[i-1], counts[i]
+					if a.count < b.count || a.count == b.count && a.name >= b.name {
+						return false
+					}
+				}
+				return true
+			},
+		},
+		{
+			name: "filtering then counting equals counting then filtering",
+			property: func(events eventSet, owner uint8) bool {
+				name := fmt.Sprintf("owner%d", owner%3)
+				filtered := countBy(applyFilters(events, onlyOwners([]string{name})), byRepo)
+				var kept []namedCount
+				for _, c := range countBy(events, byRepo) {
+					if strings.EqualFold(repoOwner(c.name), name) {
+						kept = append(kept, c)
+					}
+				}
+				return fmt.Sprint(filtered) == fmt.Sprint(kept)
+			},
+		},
+		{
+			name: "metrics are additive over a split of the events",
+			property: func(events eventSet, at uint8) bool {
+				split := int(at) % (len(events) + 1)
+				for name := range metrics {
+					whole, _ := countMetric(events, name, time.Time{})
+					head, _ := countMetric(events[:split], name, time.Time{})
+					tail, _ := countMetric(events[split:], name, time.Time{})
+					if whole != head+tail {
+						return false
+					}
+				}
+				return true
+			},
+		},
+		{
+			name: "adjacent windows partition the events",
+			property: func(events eventSet, a, b uint8) bool {
+				from := propertyStart.AddDate(0, 0, int(min(a, b)%61))
+				to := propertyStart.AddDate(0, 0, int(max(a, b)%61))
+				if to.Before(from) {
+					from, to = to, from
+				}
+				end := propertyStart.AddDate(0, 0, 61)
+				return len(eventsBetween(events, propertyStart, from))+len(eventsBetween(events, from, to))+
+					len(eventsBetween(events, to, end)) == len(events)
+			},
+		},
+		{
+			name: "daily counts sum to the number of events",
+			property: func(events eventSet) bool {
+				total := 0
+				for _, n := range dailyCounts(events, time.UTC) {
+					total += n
+				}
+				return total == len(events)
+			},
+		},
+		{
+			name: "merging a list with itself keeps it",
+			property: func(events eventSet) bool {
+				merged := mergeEvents(events, events)
+				return len(merged) == len(events) && len(diffSnapshots(events, merged).added) == 0
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			err := quick.Check(tc.property, &quick.Config{MaxCount: 200, Rand: rand.New(rand.NewSource(1))})
+			// Assert
+			assertNoError(t, err)
+		})
+	}
+}
+
+func TestUnitStatsAt(t *testing.T) {
+	// Arrange
+	now := time.Date(2025, 3, 31, 12, 0, 0, 0, time.UTC)
+	t.Setenv("HOME", t.TempDir())
+	viper.Set("github_token", "ghp_test")
+	t.Cleanup(viper.Reset)
+	a, err := openArchive("octocat")
+	assertNoError(t, err)
+	_, err = a.add([]ghEvent{
+		{ID: "1", Type: "PushEvent", Repo: repo{Name: "octo/a"}, Payload: payload{Size: 2}, CreatedAt: now.Add(-time.Hour)},
+		{ID: "2", Type: "PushEvent", Repo: repo{Name: "octo/a"}, Payload: payload{Size: 5}, CreatedAt: now.AddDate(0, 0, -8)},
+	})
+	assertNoError(t, err)
+	var buf bytes.Buffer
+	// Act
+	err = statsAt(&fakeClock{now: now}, []string{"--archive", "--days", "7", "octocat"}, &buf)
+	// Assert
+	assertNoError(t, err)
+	assertEqual(t, strings.Contains(buf.String(), "commits:"+strings.Repeat(" ", 7)+"2\n"), true)
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	// streamHub shares one poller per user between the clients streaming
+	// that user's events.
+	streamHub struct {
+		mu       sync.Mutex
+		interval time.Duration
+		clock    clock
+		fetch    func(user string) ([]ghEvent, error)
+		feeds    map[string]*feed
+	}
+	// feed is the poller of one user and its subscribers.
+	feed struct {
+		subs   map[chan ghEvent]bool
+		cancel context.CancelFunc
+	}
+)
+
+// streamHeartbeat is the delay between keep-alive comments on idle streams.
+const streamHeartbeat = 30 * time.Second
+
+// newStreamHub returns a hub polling every interval of the wall clock.
+func newStreamHub(interval time.Duration, fetch func(user string) ([]ghEvent, error)) *streamHub {
+	return &streamHub{interval: interval, clock: systemClock{}, fetch: fetch, feeds: map[string]*feed{}}
+}
+
+// subscribe returns a channel of the user's new events, starting the poller
+// of the first subscriber; unsubscribe stops it after the last one.
+func (h *streamHub) subscribe(user string) (events <-chan ghEvent, unsubscribe func()) {
+	key := strings.ToLower(user)
+	ch := make(chan ghEvent, 16)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	f, ok := h.feeds[key]
+	if !ok {
+		ctx, cancel := context.WithCancel(context.Background())
+		f = &feed{subs: map[chan ghEvent]bool{}, cancel: cancel}
+		h.feeds[key] = f
+		go h.run(ctx, user, f)
+	}
+	f.subs[ch] = true
+	return ch, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		delete(f.subs, ch)
+		if len(f.subs) == 0 {
+			f.cancel()
+			delete(h.feeds, key)
+		}
+	}
+}
+
+// run polls the user's events and broadcasts the new ones; slow clients
+// miss events rather than holding the others back.
+func (h *streamHub) run(ctx context.Context, user string, f *feed) {
+	p := &poller{fetch: func() ([]ghEvent, error) {
+		events, err := h.fetch(user)
+		return hideSponsorships(events), err
+	}}
+	for {
+		fresh, err := p.poll()
+		if err != nil {
+			log.Printf("stream %s: %v", user, err)
+		}
+		h.mu.Lock()
+		for _, ev := range fresh {
+			for ch := range f.subs {
+				select {
+				case ch <- ev:
+				default:
+				}
+			}
+		}
+		h.mu.Unlock()
+		if !sleepContext(ctx, h.clock, h.interval) {
+			return
+		}
+	}
+}
+
+// stream sends the user's new events as Server-Sent Events until the client
+// disconnects.
+func (s *server) stream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "streaming unsupported"})
+		return
+	}
+	events, unsubscribe := s.hub.subscribe(r.PathValue("user"))
+	defer unsubscribe()
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	heartbeat := s.clock.After(streamHeartbeat)
+	cat := catalogs[defaultLang]
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat:
+			fmt.Fprint(w, ": ping\n\n")
+			heartbeat = s.clock.After(streamHeartbeat)
+		case ev := <-events:
+			byt, err := json.Marshal(jsonEvent{ghEvent: ev, Summary: summarize(cat, ev), Labels: repoLabels(ev.Repo.Meta)})
+			if err != nil {
+				log.Printf("encode event %s: %v", ev.ID, err)
+				continue
+			}
+			fmt.Fprintf(w, "id: %s\nevent: activity\ndata: %s\n\n", ev.ID, byt)
+		}
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestUnitStreamHub(t *testing.T) {
+	// Arrange
+	var mu sync.Mutex
+	polls := map[string]int{}
+	hub := newStreamHub(20*time.Millisecond, func(user string) ([]ghEvent, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		polls[user]++
+		events := []ghEvent{{ID: "1"}}
+		if polls[user] > 1 {
+			events = append([]ghEvent{{ID: "2"}}, events...)
+		}
+		return events, nil
+	})
+	// Act
+	first, unsubscribeFirst := hub.subscribe("octocat")
+	second, unsubscribeSecond := hub.subscribe("OctoCat")
+	// Assert
+	assertEqual(t, (<-first).ID, "2")
+	assertEqual(t, (<-second).ID, "2")
+	assertEqual(t, len(hub.feeds), 1)
+	unsubscribeFirst()
+	assertEqual(t, len(hub.feeds), 1)
+	unsubscribeSecond()
+	assertEqual(t, len(hub.feeds), 0)
+}
+
+func TestUnitStreamHubClock(t *testing.T) {
+	// Arrange
+	polls := 0
+	hub := newStreamHub(time.Minute, func(string) ([]ghEvent, error) {
+		polls++
+		return chaosEvents(polls), nil
+	})
+	clk := &fakeClock{now: time.Date(2025, 3, 3, 10, 0, 0, 0, time.UTC), limit: 3, cancel: func() {}}
+	hub.clock = clk
+	// Act
+	events, unsubscribe := hub.subscribe("octocat")
+	defer unsubscribe()
+	got := []string{(<-events).ID, (<-events).ID}
+	// Assert
+	assertEqual(t, fmt.Sprint(got), "[2 3]")
+	clk.mu.Lock()
+	defer clk.mu.Unlock()
+	assertEqual(t, clk.waits[0], time.Minute)
+	assertEqual(t, clk.waits[1], time.Minute)
+}
+
+func TestUnitServerStream(t *testing.T) {
+	// Arrange
+	var mu sync.Mutex
+	calls := 0
+	s := &server{
+		tenants: &tenantSet{fallback: &tenant{}},
+		clock:   systemClock{},
+		hub: newStreamHub(5*time.Millisecond, func(string) ([]ghEvent, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			calls++
+			if calls == 1 {
+				return nil, nil
+			}
+			return []ghEvent{{ID: "42", Type: "WatchEvent", Repo: repo{Name: "octo/repo"}}}, nil
+		}),
+	}
+	srv := httptest.NewServer(s.routes())
+	t.Cleanup(srv.Close)
+	// Act
+	res, err := http.Get(srv.URL + "/users/octocat/stream")
+	assertNoError(t, err)
+	defer res.Body.Close()
+	sc := bufio.NewScanner(res.Body)
+	var lines []string
+	for sc.Scan() && sc.Text() != "" {
+		lines = append(lines, sc.Text())
+	}
+	// Assert
+	assertEqual(t, res.Header.Get("Content-Type"), "text/event-stream")
+	assertEqual(t, len(lines), 3)
+	assertEqual(t, lines[0], "id: 42")
+	assertEqual(t, lines[1], "event: activity")
+	assertEqual(t, strings.Contains(lines[2], `"summary":"Starred octo/repo"`), true)
+}
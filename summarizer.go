@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+type (
+	// llmSummarizer writes a prose summary of activity with a language model.
+	llmSummarizer struct {
+		provider string
+		url      string
+		model    string
+		apiKey   string
+		hc       *http.Client
+		// salt salts the hashes of the redacted names, see redactSalt.
+		salt string
+	}
+	// chatMessage is a message of the chat APIs of both providers.
+	chatMessage struct {
+		Role    string `json:"role"`
+		Content string `json:"content"`
+	}
+)
+
+// summaryPrompt instructs the model; the activity follows as the user
+// message.
+const summaryPrompt = "You summarize a developer's GitHub activity for a weekly digest. " +
+	"Write one short paragraph in plain prose, highlighting the main projects and accomplishments. " +
+	"Do not invent facts; only use the events given."
+
+// llmDefaults holds the default endpoint and model of each provider.
+var llmDefaults = map[string][2]string{
+	"openai": {"https://api.openai.com/v1", "gpt-4o-mini"},
+	"ollama": {"http://localhost:11434", "llama3.1"},
+}
+
+// newLLMSummarizer reads the summarizer config section.
+func newLLMSummarizer() (*llmSummarizer, error) {
+	provider := viper.GetString("summarizer.provider")
+	defaults, ok := llmDefaults[provider]
+	if !ok {
+		return nil, fmt.Errorf("unknown or missing summarizer.provider %q, want openai or ollama", provider)
+	}
+	s := &llmSummarizer{
+		provider: provider,
+		url:      strings.TrimRight(viper.GetString("summarizer.url"), "/"),
+		model:    viper.GetString("summarizer.model"),
+		apiKey:   viper.GetString("summarizer.api_key"),
+		hc:       &http.Client{Timeout: 2 * time.Minute},
+	}
+	if s.url == "" {
+		s.url = defaults[0]
+	}
+	if s.model == "" {
+		s.model = defaults[1]
+	}
+	salt, err := redactSalt(&defaultUserHome{})
+	if err != nil {
+		return nil, err
+	}
+	s.salt = salt
+	return s, nil
+}
+
+// summarize asks the model for a summary of the events, redacted as with
+// --redact so that nothing of the private repositories leaves the machine.
+func (s *llmSummarizer) summarize(ctx context.Context, events []ghEvent) (string, error) {
+	redacted := slices.Clone(events)
+	for i := range redacted {
+		redactEvent(&redacted[i], s.salt)
+	}
+	messages := []chatMessage{
+		{Role: "system", Content: summaryPrompt},
+		{Role: "user", Content: describeEvents(redacted)},
+	}
+	endpoint, body := s.url+"/chat/completions", map[string]any{"model": s.model, "messages": messages}
+	if s.provider == "ollama" {
+		endpoint, body = s.url+"/api/chat", map[string]any{"model": s.model, "messages": messages, "stream": false}
+	}
+	byt, err := json.Marshal(body)
+	if err != nil {
+		return "", fmt.Errorf("encode summary request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(byt))
+	if err != nil {
+		return "", fmt.Errorf("request error: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+s.apiKey)
+	}
+	res, err := s.hc.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("request summary: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("request summary: %q", res.Status)
+	}
+	var out struct {
+		Choices []struct {
+			Message chatMessage `json:"message"`
+		} `json:"choices"`
+		Message chatMessage `json:"message"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&out); err != nil {
+		return "", fmt.Errorf("decode summary: %w", err)
+	}
+	text := out.Message.Content
+	if len(out.Choices) > 0 {
+		text = out.Choices[0].Message.Content
+	}
+	if strings.TrimSpace(text) == "" {
+		return "", errors.New("empty summary")
+	}
+	return strings.TrimSpace(text), nil
+}
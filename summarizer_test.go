@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestUnitLLMSummarizerRedacts(t *testing.T) {
+	// Arrange
+	var gotPrompt string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Messages []chatMessage `json:"messages"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		gotPrompt = body.Messages[1].Content
+		w.Write([]byte(`{"message":{"role":"assistant","content":"Busy week."}}`))
+	}))
+	t.Cleanup(srv.Close)
+	s := &llmSummarizer{provider: "ollama", url: srv.URL, model: "m", hc: srv.Client()}
+	private := payload{
+		Action:      "published",
+		Ref:         "refs/heads/secret-branch",
+		RefType:     "branch",
+		Commits:     []commit{{Message: "secret commit", Author: author{Email: "me@secret.example"}}},
+		PullRequest: &pullRequest{Title: "secret pull request", HTMLURL: "https://github.com/acme/secret/pull/1"},
+		Issue:       &issue{Title: "secret issue", HTMLURL: "https://github.com/acme/secret/issues/2"},
+		Labels:      []label{{Name: "secret-label"}},
+		Milestone:   &milestone{Title: "secret milestone"},
+		Release:     &release{TagName: "secret-v1", Name: "secret release"},
+		WorkflowRun: &workflowRun{Name: "secret workflow", HeadBranch: "secret-branch", Conclusion: "failure"},
+		Project:     &projectItem{Project: "secret board", Title: "secret card"},
+		Discussion:  &discussion{Title: "secret discussion"},
+		Alert:       &securityAlert{Advisory: "GHSA-secret", Summary: "secret alert", Package: "secret-pkg"},
+		Deployment:  &deployment{Environment: "secret-env", Ref: "secret-branch"},
+	}
+	var events []ghEvent
+	for typ := range summarizers {
+		events = append(events, ghEvent{Type: typ, Repo: repo{Name: "acme/secret"}, Payload: private})
+	}
+	events = append(events, ghEvent{Type: "ProjectsV2ItemEvent", Repo: repo{Name: "acme/secret"}, Payload: private})
+	// Act
+	_, err := s.summarize(context.Background(), events)
+	// Assert
+	assertNoError(t, err)
+	assertEqual(t, strings.Contains(strings.ToLower(gotPrompt), "secret"), false)
+	assertEqual(t, events[0].Payload.Commits[0].Message, "secret commit")
+	assertEqual(t, events[0].Payload.Labels[0].Name, "secret-label")
+}
+
+func TestUnitLLMSummarizer(t *testing.T) {
+	testCases := []struct {
+		name     string
+		provider string
+		wantPath string
+		response string
+		status   int
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "openai",
+			provider: "openai",
+			wantPath: "/chat/completions",
+			response: `{"choices":[{"message":{"role":"assistant","content":" Shipped the API. "}}]}`,
+			status:   http.StatusOK,
+			want:     "Shipped the API.",
+		},
+		{
+			name:     "ollama",
+			provider: "ollama",
+			wantPath: "/api/chat",
+			response: `{"message":{"role":"assistant","content":"Shipped the API."}}`,
+			status:   http.StatusOK,
+			want:     "Shipped the API.",
+		},
+		{name: "failure", provider: "openai", wantPath: "/chat/completions", status: http.StatusUnauthorized, wantErr: true},
+		{name: "empty", provider: "ollama", wantPath: "/api/chat", response: `{}`, status: http.StatusOK, wantErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			var gotPrompt string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assertEqual(t, r.URL.Path, tc.wantPath)
+				var body struct {
+					Messages []chatMessage `json:"messages"`
+				}
+				json.NewDecoder(r.Body).Decode(&body)
+				gotPrompt = body.Messages[1].Content
+				w.WriteHeader(tc.status)
+				w.Write([]byte(tc.response))
+			}))
+			t.Cleanup(srv.Close)
+			s := &llmSummarizer{provider: tc.provider, url: srv.URL, model: "m", hc: srv.Client()}
+			events := []ghEvent{{Type: "WatchEvent", Repo: repo{Name: "acme/secret"}}}
+			// Act
+			got, err := s.summarize(context.Background(), events)
+			// Assert
+			assertEqual(t, strings.Contains(gotPrompt, "acme/secret"), false)
+			if tc.wantErr {
+				assertNotNil(t, err)
+				return
+			}
+			assertNoError(t, err)
+			assertEqual(t, got, tc.want)
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/spf13/viper"
+)
+
+// syncBatch holds the new events of one archive, appended to it once
+// exported so that events failing to export are fetched as new again by the
+// next sync.
+type syncBatch struct {
+	archive *archive
+	events  []ghEvent
+	// label names the events in the sync report, e.g. the login.
+	label string
+}
+
+// runSync fetches the user's latest events, exports the new ones to the
+// configured Google Sheet and then appends them to the local archive. A
+// person of the people setting is synced account by account, each to its
+// own archive.
+func runSync(args []string, stdout io.Writer) error {
+	return syncContext(context.Background(), args, stdout)
+}
+
+// syncContext runs sync until ctx ends, e.g. as a scheduled job stopped by
+// a shutdown; an interrupted sync archives nothing, leaving its events to
+// the next one.
+func syncContext(ctx context.Context, args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("sync", flag.ContinueOnError)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: go-github-activity sync <username>")
+	}
+	if redact {
+		return errors.New("--redact cannot be used with sync, which archives the events as fetched")
+	}
+	if err := loadConfig(); err != nil {
+		return err
+	}
+	logins, err := identities(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	hc := configuredClient()
+	hc.ctx = ctx
+	policy, err := syncRetention(hc.clock)
+	if err != nil {
+		return err
+	}
+	var batches []syncBatch
+	for _, login := range logins {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		b, err := syncLogin(hc, login, policy)
+		if err != nil {
+			return err
+		}
+		batches = append(batches, b)
+	}
+	b, err := syncSources(flags.Arg(0), logins[0], policy)
+	if err != nil {
+		return err
+	}
+	batches = append(batches, b)
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	sheets, err := newSheetsExporter(
+		viper.GetString("sheets.credentials_file"),
+		viper.GetString("sheets.spreadsheet_id"),
+		viper.GetString("sheets.range"),
+	)
+	if err != nil {
+		return err
+	}
+	export := func(context.Context, []ghEvent) error { return nil }
+	if sheets != nil {
+		export = sheets.export
+	}
+	if err := archiveSynced(ctx, batches, export, stdout); err != nil {
+		return err
+	}
+	return pruneSynced(logins, policy, stdout)
+}
+
+// syncRetention returns the archive.retention policy dated on the clock, or
+// nil without the setting.
+func syncRetention(c clock) (retention, error) {
+	if !viper.IsSet("archive.retention") {
+		return nil, nil
+	}
+	return retentionPolicy(viper.GetStringMapString("archive.retention"), "", c.Now())
+}
+
+// syncSources returns the new events of the configured sources other than
+// GitHub, e.g. GitLab or discussions, to archive with the events of the
+// first account of the user. The events the policy prunes are left out.
+func syncSources(user, login string, policy retention) (syncBatch, error) {
+	b := syncBatch{label: "other sources"}
+	sources, err := configuredSources()
+	if err != nil {
+		return b, err
+	}
+	var others []source
+	for _, src := range sources {
+		if _, ok := src.provider.(githubProvider); !ok {
+			others = append(others, src)
+		}
+	}
+	if len(others) == 0 {
+		return b, nil
+	}
+	events, err := fetchSourceEvents(others, user)
+	if err != nil {
+		return b, err
+	}
+	if events, err = ignoreEvents(events); err != nil {
+		return b, err
+	}
+	if b.archive, err = openArchive(login); err != nil {
+		return b, err
+	}
+	b.events, err = b.archive.unseen(policy.unexpired(events))
+	return b, err
+}
+
+// syncLogin returns the latest events of one account not archived yet. The
+// events the policy prunes are left out, as they would be archived and
+// exported again by every sync.
+func syncLogin(hc *client, login string, policy retention) (syncBatch, error) {
+	b := syncBatch{label: login}
+	fetch := fetchLoginEvents
+	if viper.GetBool("archive.keep_raw") {
+		fetch = fetchRawLoginEvents
+	}
+	events, err := fetch(hc, login)
+	if err != nil {
+		return b, err
+	}
+	if events, err = ignoreEvents(events); err != nil {
+		return b, err
+	}
+	if b.archive, err = openArchive(login); err != nil {
+		return b, err
+	}
+	b.events, err = b.archive.unseen(policy.unexpired(events))
+	return b, err
+}
+
+// archiveSynced exports the new events of every batch, then appends them to
+// their archives. A failed export archives nothing.
+func archiveSynced(
+	ctx context.Context, batches []syncBatch, export func(context.Context, []ghEvent) error, stdout io.Writer,
+) error {
+	var events []ghEvent
+	for _, b := range batches {
+		events = append(events, b.events...)
+	}
+	if err := export(ctx, hideSponsorships(events)); err != nil {
+		return fmt.Errorf("export to Google Sheets: %w", err)
+	}
+	for _, b := range batches {
+		if b.archive == nil {
+			continue
+		}
+		added, err := b.archive.add(b.events)
+		if err != nil {
+			return err
+		}
+		if err := infof(stdout, "archived %d new events of %s\n", len(added), b.label); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pruneSynced compacts the archives of the accounts with the policy, when
+// there is one.
+func pruneSynced(logins []string, policy retention, stdout io.Writer) error {
+	if policy == nil {
+		return nil
+	}
+	for _, login := range logins {
+		a, err := openArchive(login)
+		if err != nil {
+			return err
+		}
+		c, err := a.compact(policy)
+		if err != nil {
+			return err
+		}
+		if err := infof(stdout, "pruned %d expired events of %s\n", c.expired, login); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func TestUnitArchiveSynced(t *testing.T) {
+	testCases := []struct {
+		name         string
+		exportErr    error
+		wantArchived int
+		wantOut      string
+	}{
+		{
+			name:         "exported",
+			wantArchived: 3,
+			wantOut:      "archived 2 new events of octocat\narchived 1 new events of other sources\n",
+		},
+		{name: "export failed", exportErr: errors.New("401 Unauthorized")},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			a := &archive{path: filepath.Join(t.TempDir(), "octocat.jsonl")}
+			batches := []syncBatch{
+				{archive: a, events: []ghEvent{{ID: "1"}, {ID: "2"}}, label: "octocat"},
+				{archive: a, events: []ghEvent{{ID: "gitlab:3"}}, label: "other sources"},
+				{label: "unconfigured"},
+			}
+			var exported []ghEvent
+			export := func(_ context.Context, events []ghEvent) error {
+				exported = events
+				return tc.exportErr
+			}
+			var out bytes.Buffer
+			// Act
+			err := archiveSynced(context.Background(), batches, export, &out)
+			// Assert
+			assertEqual(t, len(exported), 3)
+			if tc.exportErr != nil {
+				assertNotNil(t, err)
+			} else {
+				assertNoError(t, err)
+			}
+			events, err := a.load()
+			assertNoError(t, err)
+			assertEqual(t, len(events), tc.wantArchived)
+			assertEqual(t, out.String(), tc.wantOut)
+		})
+	}
+}
+
+func TestUnitSyncRetention(t *testing.T) {
+	now := time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC)
+	fetched := []ghEvent{
+		{ID: "1", Type: "WatchEvent", CreatedAt: now.AddDate(0, 0, -60)},
+		{ID: "2", Type: "PushEvent", CreatedAt: now.AddDate(0, 0, -60)},
+		{ID: "3", Type: "WatchEvent", CreatedAt: now.AddDate(0, 0, -1)},
+	}
+	testCases := []struct {
+		name      string
+		retention map[string]string
+		want      string
+	}{
+		{name: "no retention", want: "[1 2 3]"},
+		{name: "shorter than the events window", retention: map[string]string{"watchevent": "30d"}, want: "[2 3]"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			if tc.retention != nil {
+				viper.Set("archive.retention", tc.retention)
+			}
+			t.Cleanup(viper.Reset)
+			a := &archive{path: filepath.Join(t.TempDir(), "octocat.jsonl")}
+			// Act
+			policy, err := syncRetention(&fakeClock{now: now})
+			assertNoError(t, err)
+			first, errFirst := a.unseen(policy.unexpired(fetched))
+			_, errAdd := a.add(first)
+			_, errCompact := a.compact(policy)
+			second, errSecond := a.unseen(policy.unexpired(fetched))
+			// Assert
+			assertNoError(t, errFirst)
+			assertNoError(t, errAdd)
+			assertNoError(t, errCompact)
+			assertNoError(t, errSecond)
+			var ids []string
+			for _, ev := range first {
+				ids = append(ids, ev.ID)
+			}
+			assertEqual(t, fmt.Sprint(ids), tc.want)
+			assertEqual(t, len(second), 0)
+		})
+	}
+}
+
+func TestUnitSyncSourcesGitLab(t *testing.T) {
+	// Arrange
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v4/users/octocat/events":
+			fmt.Fprint(w, `[{"id":1,"project_id":7,"action_name":"opened","target_type":"MergeRequest",
+				"target_iid":3,"created_at":"2025-03-01T00:00:00Z"}]`)
+		case "/api/v4/projects/7":
+			fmt.Fprint(w, `{"id":7,"path_with_namespace":"group/app","visibility":"public"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	t.Setenv("HOME", t.TempDir())
+	viper.Set("gitlab.url", srv.URL+"/")
+	viper.Set("gitlab.token", "glpat")
+	t.Cleanup(viper.Reset)
+	noExport := func(context.Context, []ghEvent) error { return nil }
+	// Act
+	first, errFirst := syncSources("octocat", "octocat", nil)
+	errArchive := archiveSynced(context.Background(), []syncBatch{first}, noExport, &bytes.Buffer{})
+	second, errSecond := syncSources("octocat", "octocat", nil)
+	// Assert
+	assertNoError(t, errFirst)
+	assertNoError(t, errArchive)
+	assertNoError(t, errSecond)
+	assertEqual(t, len(first.events), 1)
+	assertEqual(t, first.events[0].ID, "gitlab-1")
+	assertEqual(t, len(second.events), 0)
+	archived, err := second.archive.load()
+	assertNoError(t, err)
+	assertEqual(t, len(archived), 1)
+}
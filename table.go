@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"unicode/utf8"
+)
+
+const (
+	// tableTimeLayout is the timestamp format of the table's first column.
+	tableTimeLayout = "2006-01-02 15:04"
+	// tablePadding is the space tabwriter puts between columns.
+	tablePadding = 2
+	// minSummaryWidth keeps summaries readable on very narrow terminals.
+	minSummaryWidth = 10
+)
+
+// tableRenderer writes events as a column-aligned table. A zero width
+// disables truncation.
+type tableRenderer struct {
+	cat   catalog
+	width int
+}
+
+// render writes the header and one row per event.
+func (r tableRenderer) render(w io.Writer, events []ghEvent) error {
+	events = redactedCopy(events)
+	rows := make([][4]string, 0, len(events))
+	widths := [3]int{len("TIME"), len("TYPE"), len("REPO")}
+	for _, ev := range events {
+		repoCell := ev.Repo.Name
+		if labels := repoLabels(ev.Repo.Meta); len(labels) > 0 {
+			repoCell += " [" + strings.Join(labels, ",") + "]"
+		}
+		row := [4]string{
+			ev.CreatedAt.Local().Format(tableTimeLayout),
+			strings.TrimSuffix(ev.Type, "Event"),
+			repoCell,
+			summarize(r.cat, ev),
+		}
+		for i := range widths {
+			widths[i] = max(widths[i], utf8.RuneCountInString(row[i]))
+		}
+		rows = append(rows, row)
+	}
+	summaryWidth := 0
+	if r.width > 0 {
+		summaryWidth = max(r.width-widths[0]-widths[1]-widths[2]-3*tablePadding, minSummaryWidth)
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, tablePadding, ' ', 0)
+	fmt.Fprintln(tw, "TIME\tTYPE\tREPO\tSUMMARY")
+	for _, row := range rows {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", row[0], row[1], row[2], truncate(row[3], summaryWidth))
+	}
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("write table: %w", err)
+	}
+	return nil
+}
+
+// truncate shortens s to width runes, marking the cut with an ellipsis.
+// A zero width leaves s untouched.
+func truncate(s string, width int) string {
+	if width <= 0 || utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	return string(runes[:width-1]) + "…"
+}
+
+// terminalWidth returns $COLUMNS when set, else the width of stdout when it
+// is a terminal, else 0 (no truncation).
+func terminalWidth(getenv func(string) string) int {
+	if n, err := strconv.Atoi(getenv("COLUMNS")); err == nil && n > 0 {
+		return n
+	}
+	return ttyWidth(os.Stdout)
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUnitTruncate(t *testing.T) {
+	testCases := []struct {
+		name  string
+		s     string
+		width int
+		want  string
+	}{
+		{name: "fits", s: "short", width: 10, want: "short"},
+		{name: "exact", s: "exact", width: 5, want: "exact"},
+		{name: "cut", s: "a longer summary", width: 8, want: "a longe…"},
+		{name: "multibyte", s: "リポジトリを作成しました", width: 5, want: "リポジト…"},
+		{name: "disabled", s: "a longer summary", width: 0, want: "a longer summary"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			got := truncate(tc.s, tc.width)
+			// Assert
+			assertEqual(t, got, tc.want)
+		})
+	}
+}
+
+func TestUnitTableRenderer(t *testing.T) {
+	created := time.Date(2025, 3, 1, 10, 30, 0, 0, time.UTC)
+	events := []ghEvent{
+		{
+			Type: "PushEvent", Repo: repo{Name: "octo/repo"},
+			Payload: payload{Size: 2}, CreatedAt: created,
+		},
+	}
+	testCases := []struct {
+		name        string
+		width       int
+		wantSummary string
+	}{
+		{name: "no truncation", width: 0, wantSummary: "Pushed 2 commits to octo/repo"},
+		{name: "narrow terminal", width: 50, wantSummary: "Pushed 2 commi…"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			var buf bytes.Buffer
+			r := tableRenderer{cat: lookupCatalog("en"), width: tc.width}
+			// Act
+			err := r.render(&buf, events)
+			// Assert
+			assertNoError(t, err)
+			lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+			assertEqual(t, len(lines), 2)
+			assertEqual(t, strings.Fields(lines[0])[3], "SUMMARY")
+			want := created.Local().Format(tableTimeLayout) + "  Push  octo/repo  " + tc.wantSummary
+			assertEqual(t, lines[1], want)
+		})
+	}
+}
+
+func TestUnitTerminalWidth(t *testing.T) {
+	// Act
+	got := terminalWidth(func(string) string { return "42" })
+	// Assert
+	assertEqual(t, got, 42)
+}
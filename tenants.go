@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+type (
+	// tenant is a GitHub identity serving the activity of some users with
+	// its own token and rate-limit budget.
+	tenant struct {
+		Name   string   `mapstructure:"name"`
+		Token  string   `mapstructure:"token"`
+		Users  []string `mapstructure:"users"`
+		budget rateBudget
+	}
+	// rateBudget tracks the rate limit reported for a token.
+	rateBudget struct {
+		mu        sync.Mutex
+		known     bool
+		remaining int
+		reset     time.Time
+	}
+	// tenantSet routes users to their tenant, or to the default identity of
+	// github_token.
+	tenantSet struct {
+		byUser   map[string]*tenant
+		fallback *tenant
+	}
+)
+
+// loadTenants reads the tenants config list.
+func loadTenants() (*tenantSet, error) {
+	var tenants []*tenant
+	if err := viper.UnmarshalKey("tenants", &tenants); err != nil {
+		return nil, fmt.Errorf("parse tenants: %w", err)
+	}
+	ts := &tenantSet{
+		byUser:   map[string]*tenant{},
+		fallback: &tenant{Name: "default", Token: viper.GetString("github_token")},
+	}
+	for i, t := range tenants {
+		if t.Name == "" {
+			t.Name = fmt.Sprintf("tenant %d", i+1)
+		}
+		if t.Token == "" {
+			return nil, fmt.Errorf("%s: no token", t.Name)
+		}
+		for _, u := range t.Users {
+			key := strings.ToLower(u)
+			if other, dup := ts.byUser[key]; dup {
+				return nil, fmt.Errorf("user %q is served by both %s and %s", u, other.Name, t.Name)
+			}
+			ts.byUser[key] = t
+		}
+	}
+	return ts, nil
+}
+
+// lookup returns the tenant serving a user; logins are case-insensitive.
+func (ts *tenantSet) lookup(user string) *tenant {
+	if t, ok := ts.byUser[strings.ToLower(user)]; ok {
+		return t
+	}
+	return ts.fallback
+}
+
+// client returns an API client using the tenant token and budget.
+func (t *tenant) client() *client {
+	hc := newClient(t.Token)
+	hc.budget = &t.budget
+	return hc
+}
+
+// update records the X-RateLimit headers of a response.
+func (b *rateBudget) update(h http.Header) {
+	if b == nil {
+		return
+	}
+	remaining, errRemaining := strconv.Atoi(h.Get("X-RateLimit-Remaining"))
+	reset, errReset := strconv.ParseInt(h.Get("X-RateLimit-Reset"), 10, 64)
+	if errRemaining != nil || errReset != nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.known, b.remaining, b.reset = true, remaining, time.Unix(reset, 0)
+}
+
+// exhausted reports whether no request is left before the reset time, and
+// that time.
+func (b *rateBudget) exhausted(now time.Time) (time.Time, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.reset, b.known && b.remaining <= 0 && now.Before(b.reset)
+}
+
+// left returns the requests left before the reset time, when known.
+func (b *rateBudget) left(now time.Time) (int, bool) {
+	if b == nil {
+		return 0, false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.remaining, b.known && now.Before(b.reset)
+}
+
+// below reports whether at most reserve requests are left before the reset
+// time. A nil or unknown budget is never below.
+func (b *rateBudget) below(reserve int, now time.Time) bool {
+	if b == nil {
+		return false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.known && b.remaining <= reserve && now.Before(b.reset)
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func TestUnitLoadTenants(t *testing.T) {
+	testCases := []struct {
+		name       string
+		tenants    []map[string]any
+		user       string
+		wantTenant string
+		wantErr    bool
+	}{
+		{
+			name:       "mapped user",
+			tenants:    []map[string]any{{"name": "work", "token": "ghp_work", "users": []string{"Octocat"}}},
+			user:       "octocat",
+			wantTenant: "work",
+		},
+		{
+			name:       "unmapped user",
+			tenants:    []map[string]any{{"name": "work", "token": "ghp_work", "users": []string{"octocat"}}},
+			user:       "hubot",
+			wantTenant: "default",
+		},
+		{name: "missing token", tenants: []map[string]any{{"name": "work"}}, wantErr: true},
+		{
+			name: "user in two tenants",
+			tenants: []map[string]any{
+				{"token": "a", "users": []string{"octocat"}},
+				{"token": "b", "users": []string{"octocat"}},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			viper.Set("tenants", tc.tenants)
+			t.Cleanup(func() { viper.Set("tenants", nil) })
+			// Act
+			got, err := loadTenants()
+			// Assert
+			if tc.wantErr {
+				assertNotNil(t, err)
+				return
+			}
+			assertNoError(t, err)
+			assertEqual(t, got.lookup(tc.user).Name, tc.wantTenant)
+		})
+	}
+}
+
+func TestUnitRateBudget(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	testCases := []struct {
+		name      string
+		remaining string
+		reset     time.Time
+		want      bool
+	}{
+		{name: "requests left", remaining: "12", reset: now.Add(time.Hour), want: false},
+		{name: "exhausted", remaining: "0", reset: now.Add(time.Hour), want: true},
+		{name: "reset passed", remaining: "0", reset: now.Add(-time.Minute), want: false},
+		{name: "no headers", want: false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			var b rateBudget
+			h := http.Header{}
+			if tc.remaining != "" {
+				h.Set("X-RateLimit-Remaining", tc.remaining)
+				h.Set("X-RateLimit-Reset", strconv.FormatInt(tc.reset.Unix(), 10))
+			}
+			// Act
+			b.update(h)
+			_, got := b.exhausted(now)
+			// Assert
+			assertEqual(t, got, tc.want)
+		})
+	}
+}
+
+func TestUnitTenantClientBudget(t *testing.T) {
+	// Arrange
+	reset := time.Now().Add(time.Hour).Unix()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assertEqual(t, r.Header.Get("Authorization"), "Bearer ghp_work")
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset, 10))
+		w.Write([]byte("[]"))
+	}))
+	t.Cleanup(srv.Close)
+	work, other := &tenant{Name: "work", Token: "ghp_work"}, &tenant{Name: "other", Token: "ghp_other"}
+	// Act
+	_, err := fetchGitHubResponse(work.client(), srv.URL)
+	// Assert
+	assertNoError(t, err)
+	_, exhausted := work.budget.exhausted(time.Now())
+	assertEqual(t, exhausted, true)
+	_, exhausted = other.budget.exhausted(time.Now())
+	assertEqual(t, exhausted, false)
+}
+
+func TestUnitServerTenantBudget(t *testing.T) {
+	testCases := []struct {
+		name       string
+		user       string
+		wantStatus int
+	}{
+		{name: "exhausted tenant", user: "octocat", wantStatus: http.StatusTooManyRequests},
+		{name: "other tenant", user: "hubot", wantStatus: http.StatusOK},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			work := &tenant{Name: "work", Token: "ghp_work"}
+			work.budget.update(http.Header{
+				"X-Ratelimit-Remaining": {"0"},
+				"X-Ratelimit-Reset":     {strconv.FormatInt(time.Now().Add(time.Minute).Unix(), 10)},
+			})
+			s := &server{
+				tenants: &tenantSet{byUser: map[string]*tenant{"octocat": work}, fallback: &tenant{Name: "default"}},
+				fetch:   func(*tenant, string) ([]ghEvent, error) { return nil, nil },
+				clock:   systemClock{},
+			}
+			rec := httptest.NewRecorder()
+			// Act
+			s.routes().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/"+tc.user+"/activity", nil))
+			// Assert
+			assertEqual(t, rec.Code, tc.wantStatus)
+		})
+	}
+}
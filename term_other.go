@@ -0,0 +1,17 @@
+//go:build !unix
+
+package main
+
+import (
+	"os"
+	"os/exec"
+)
+
+// ttyWidth is not implemented on this platform; $COLUMNS still applies.
+func ttyWidth(_ *os.File) int {
+	return 0
+}
+
+// detachTerminal is not implemented on this platform; the standard input
+// of cmd is already empty.
+func detachTerminal(_ *exec.Cmd) {}
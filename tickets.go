@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+type (
+	// ticketMatcher finds issue-tracker keys such as ABC-123 in events.
+	ticketMatcher struct {
+		baseURL  string
+		projects map[string]bool
+	}
+	// ticket is an issue-tracker key with its link.
+	ticket struct {
+		Key string `json:"key"`
+		URL string `json:"url,omitempty"`
+	}
+)
+
+// ticketPattern matches Jira-style keys: an upper-case project and a number.
+var ticketPattern = regexp.MustCompile(`\b[A-Z][A-Z0-9]+-[0-9]+\b`)
+
+// newTicketMatcher links keys to baseURL+key (e.g. a Jira ".../browse/"
+// URL). When projects are given, only keys of these projects match, which
+// avoids false positives such as "UTF-8".
+func newTicketMatcher(baseURL string, projects []string) *ticketMatcher {
+	m := &ticketMatcher{baseURL: baseURL}
+	if len(projects) > 0 {
+		m.projects = make(map[string]bool, len(projects))
+		for _, p := range projects {
+			m.projects[strings.ToUpper(strings.TrimSpace(p))] = true
+		}
+	}
+	return m
+}
+
+// eventTexts returns the free texts of an event that may reference tickets.
+func eventTexts(ev ghEvent) []string {
+	texts := make([]string, 0, len(ev.Payload.Commits)+1)
+	for _, c := range ev.Payload.Commits {
+		texts = append(texts, c.Message)
+	}
+	if ev.Payload.PullRequest != nil {
+		texts = append(texts, ev.Payload.PullRequest.Title)
+	}
+	return texts
+}
+
+// tickets returns the distinct tickets referenced by an event, in order of
+// appearance.
+func (m *ticketMatcher) tickets(ev ghEvent) []ticket {
+	var found []ticket
+	seen := map[string]bool{}
+	for _, text := range eventTexts(ev) {
+		for _, key := range ticketPattern.FindAllString(text, -1) {
+			project, _, _ := strings.Cut(key, "-")
+			if seen[key] || (m.projects != nil && !m.projects[project]) {
+				continue
+			}
+			seen[key] = true
+			t := ticket{Key: key}
+			if m.baseURL != "" {
+				t.URL = m.baseURL + key
+			}
+			found = append(found, t)
+		}
+	}
+	return found
+}
+
+// annotation formats the tickets of an event for the text output, e.g.
+// " [ABC-1 https://jira/browse/ABC-1]".
+func (m *ticketMatcher) annotation(ev ghEvent) string {
+	if m == nil {
+		return ""
+	}
+	var b strings.Builder
+	for _, t := range m.tickets(ev) {
+		b.WriteString(" [" + t.Key)
+		if t.URL != "" {
+			b.WriteString(" " + t.URL)
+		}
+		b.WriteString("]")
+	}
+	return b.String()
+}
+
+// ticketsView prints the number of events referencing each ticket.
+func ticketsView(w io.Writer, events []ghEvent, _ statsOptions) error {
+	m := configuredTickets()
+	if m == nil {
+		m = newTicketMatcher("", nil)
+	}
+	counts := map[string]int{}
+	links := map[string]string{}
+	for _, ev := range events {
+		for _, t := range m.tickets(ev) {
+			counts[t.Key]++
+			links[t.Key] = t.URL
+		}
+	}
+	keys := sortedKeys(counts)
+	sort.SliceStable(keys, func(i, j int) bool { return counts[keys[i]] > counts[keys[j]] })
+	for _, key := range keys {
+		line := strings.TrimRight(fmt.Sprintf("%-12s %5d  %s", key, counts[key], links[key]), " ")
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestUnitTicketMatcher(t *testing.T) {
+	ev := ghEvent{
+		Type: "PushEvent",
+		Payload: payload{
+			Commits: []commit{
+				{Message: "ABC-12 fix the login page"},
+				{Message: "Encode as UTF-8, see ABC-12 and OPS-7"},
+			},
+			PullRequest: &pullRequest{Title: "[WEB-3] New header"},
+		},
+	}
+	testCases := []struct {
+		name     string
+		baseURL  string
+		projects []string
+		want     string
+	}{
+		{
+			name:    "every key with links",
+			baseURL: "https://acme.atlassian.net/browse/",
+			want: " [ABC-12 https://acme.atlassian.net/browse/ABC-12] [UTF-8 https://acme.atlassian.net/browse/UTF-8]" +
+				" [OPS-7 https://acme.atlassian.net/browse/OPS-7] [WEB-3 https://acme.atlassian.net/browse/WEB-3]",
+		},
+		{name: "restricted projects without links", projects: []string{"abc", " ops"}, want: " [ABC-12] [OPS-7]"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			m := newTicketMatcher(tc.baseURL, tc.projects)
+			// Act
+			got := m.annotation(ev)
+			// Assert
+			assertEqual(t, got, tc.want)
+		})
+	}
+}
+
+func TestUnitTicketAnnotationDisabled(t *testing.T) {
+	// Arrange
+	var m *ticketMatcher
+	// Act
+	got := m.annotation(ghEvent{Payload: payload{Commits: []commit{{Message: "ABC-1"}}}})
+	// Assert
+	assertEqual(t, got, "")
+}
+
+func TestUnitTicketsView(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+	events := []ghEvent{
+		{Payload: payload{Commits: []commit{{Message: "OPS-7 deploy"}, {Message: "ABC-1 fix"}}}},
+		{Payload: payload{PullRequest: &pullRequest{Title: "ABC-1 login"}}},
+	}
+	// Act
+	err := ticketsView(&buf, events, statsOptions{})
+	// Assert
+	assertNoError(t, err)
+	assertEqual(t, buf.String(), "ABC-1            2\nOPS-7            1\n")
+}
@@ -0,0 +1,104 @@
+package main
+
+// unifyEvents makes one timeline of the events of several sources, where a
+// commit seen by more than one source, e.g. in a local clone and in a GitHub
+// push, is kept once in its richest record. A push left without commits is
+// dropped, what it knows of the push merged into the events keeping its
+// commits; the order of the events is kept.
+func unifyEvents(events []ghEvent) []ghEvent {
+	best := map[string]int{}
+	for i, ev := range events {
+		for _, c := range ev.Payload.Commits {
+			j, ok := best[c.SHA]
+			if !ok || commitRichness(ev, c) > commitRichness(events[j], findCommit(events[j], c.SHA)) {
+				best[c.SHA] = i
+			}
+		}
+	}
+	unified := make([]ghEvent, 0, len(events))
+	at := map[int]int{}
+	var emptied []int
+	for i, ev := range events {
+		if len(ev.Payload.Commits) == 0 {
+			unified = append(unified, ev)
+			continue
+		}
+		var kept []commit
+		for _, c := range ev.Payload.Commits {
+			if best[c.SHA] == i {
+				kept = append(kept, c)
+			}
+		}
+		if len(kept) == 0 {
+			emptied = append(emptied, i)
+			continue
+		}
+		ev.Payload.Size -= len(ev.Payload.Commits) - len(kept)
+		ev.Payload.Commits = kept
+		at[i] = len(unified)
+		unified = append(unified, ev)
+	}
+	for _, i := range emptied {
+		for _, c := range events[i].Payload.Commits {
+			mergePush(&unified[at[best[c.SHA]]], events[i])
+		}
+	}
+	return unified
+}
+
+// mergePush fills in what an event keeping commits of a push lacks with
+// what the push knows: its ref, head, checks, visibility and links.
+func mergePush(dst *ghEvent, push ghEvent) {
+	p, from := &dst.Payload, push.Payload
+	if p.Ref == "" {
+		p.Ref, p.RefType = from.Ref, from.RefType
+	}
+	if p.Head == "" {
+		p.Head, p.Before = from.Head, from.Before
+	}
+	if p.PushID == 0 {
+		p.PushID = from.PushID
+	}
+	if p.Checks == "" {
+		p.Checks = from.Checks
+	}
+	dst.Public = dst.Public || push.Public
+	if dst.Repo.URL == "" {
+		dst.Repo.ID, dst.Repo.URL = push.Repo.ID, push.Repo.URL
+	}
+	if dst.Repo.Meta == nil {
+		dst.Repo.Meta = push.Repo.Meta
+	}
+	if dst.Actor.ID == 0 && push.Actor.ID != 0 {
+		dst.Actor = push.Actor
+	}
+}
+
+// findCommit returns the commit of an event with a SHA.
+func findCommit(ev ghEvent, sha string) commit {
+	for _, c := range ev.Payload.Commits {
+		if c.SHA == sha {
+			return c
+		}
+	}
+	return commit{}
+}
+
+// commitRichness scores how much a source knows about a commit: a pushed
+// commit beats a local one, then every link, verification and author field
+// counts.
+func commitRichness(ev ghEvent, c commit) int {
+	score := 0
+	if !ev.Local {
+		score += 10
+	}
+	for _, known := range []bool{
+		c.URL != "", c.Verification != nil, c.Message != "", c.Author.Email != "", c.Author.Name != "",
+		ev.Repo.URL != "", ev.Actor.ID != 0, ev.Payload.Ref != "",
+	} {
+		if known {
+			score++
+		}
+	}
+	return score
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUnitUnifyEvents(t *testing.T) {
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	local := func(sha string, at time.Time) ghEvent {
+		return ghEvent{
+			ID: "commit-" + sha, Type: "PushEvent", Local: true, CreatedAt: at,
+			Payload: payload{Size: 1, Commits: []commit{{SHA: sha, Message: "local " + sha}}},
+		}
+	}
+	push := ghEvent{
+		ID: "1", Type: "PushEvent", Actor: actor{ID: 1}, CreatedAt: now,
+		Payload: payload{Ref: "refs/heads/main", Size: 2, Commits: []commit{
+			{SHA: "a1", Message: "pushed a1", URL: "https://api/a1"},
+			{SHA: "b2", Message: "pushed b2", URL: "https://api/b2"},
+		}},
+	}
+	searched := ghEvent{
+		ID: "commit-a1", Type: "PushEvent", Repo: repo{URL: "https://github.com/octo/a"}, CreatedAt: now,
+		Payload: payload{Size: 1, Commits: []commit{{
+			SHA: "a1", Message: "searched a1", URL: "https://api/a1", Author: author{Name: "Octo", Email: "o@x"},
+			Verification: &verification{Verified: true},
+		}}},
+	}
+	star := ghEvent{ID: "2", Type: "WatchEvent", CreatedAt: now}
+	testCases := []struct {
+		name     string
+		events   []ghEvent
+		wantIDs  []string
+		wantSHAs [][]string
+	}{
+		{
+			name:     "no overlap",
+			events:   []ghEvent{local("c3", now), push, star},
+			wantIDs:  []string{"commit-c3", "1", "2"},
+			wantSHAs: [][]string{{"c3"}, {"a1", "b2"}, nil},
+		},
+		{
+			name:     "local commit pushed later",
+			events:   []ghEvent{local("a1", now.Add(time.Hour)), push},
+			wantIDs:  []string{"1"},
+			wantSHAs: [][]string{{"a1", "b2"}},
+		},
+		{
+			name:     "richer search record",
+			events:   []ghEvent{push, searched},
+			wantIDs:  []string{"1", "commit-a1"},
+			wantSHAs: [][]string{{"b2"}, {"a1"}},
+		},
+		{
+			name: "push left without commits",
+			events: []ghEvent{
+				{ID: "3", Type: "PushEvent", Payload: payload{Size: 1, Commits: []commit{{SHA: "a1"}}}}, searched,
+			},
+			wantIDs:  []string{"commit-a1"},
+			wantSHAs: [][]string{{"a1"}},
+		},
+		{
+			name:     "same record twice",
+			events:   []ghEvent{local("c3", now), local("c3", now)},
+			wantIDs:  []string{"commit-c3"},
+			wantSHAs: [][]string{{"c3"}},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			got := unifyEvents(tc.events)
+			// Assert
+			assertEqual(t, len(got), len(tc.wantIDs))
+			for i, id := range tc.wantIDs {
+				assertEqual(t, got[i].ID, id)
+				assertEqual(t, len(got[i].Payload.Commits), len(tc.wantSHAs[i]))
+				assertEqual(t, got[i].Payload.Size, len(tc.wantSHAs[i]))
+				for j, sha := range tc.wantSHAs[i] {
+					assertEqual(t, got[i].Payload.Commits[j].SHA, sha)
+				}
+			}
+		})
+	}
+}
+
+func TestUnitUnifyEventsKeepsInput(t *testing.T) {
+	// Arrange
+	push := ghEvent{ID: "1", Type: "PushEvent", Payload: payload{Size: 2, Commits: []commit{{SHA: "a1"}, {SHA: "b2"}}}}
+	searched := ghEvent{ID: "commit-a1", Type: "PushEvent", Payload: payload{Size: 1, Commits: []commit{
+		{SHA: "a1", URL: "https://api/a1"},
+	}}}
+	events := []ghEvent{push, searched}
+	// Act
+	unifyEvents(events)
+	// Assert
+	assertEqual(t, len(events[0].Payload.Commits), 2)
+	assertEqual(t, events[0].Payload.Size, 2)
+}
+
+func TestUnitUnifyEventsMergesPush(t *testing.T) {
+	// Arrange
+	push := ghEvent{
+		ID: "1", Type: "PushEvent", Public: true, Repo: repo{ID: 7, URL: "https://api/repos/octo/a"},
+		Payload: payload{
+			Ref: "refs/heads/main", Head: "a1", Before: "z0", Checks: "success", Size: 1,
+			Commits: []commit{{SHA: "a1"}},
+		},
+	}
+	searched := ghEvent{
+		ID: "commit-a1", Type: "PushEvent", Repo: repo{URL: "https://github.com/octo/a"},
+		Payload: payload{Size: 1, Commits: []commit{{SHA: "a1", Message: "fix", URL: "https://api/a1"}}},
+	}
+	// Act
+	got := unifyEvents([]ghEvent{push, searched})
+	// Assert
+	assertEqual(t, len(got), 1)
+	assertEqual(t, got[0].ID, "commit-a1")
+	assertEqual(t, got[0].Payload.Ref, "refs/heads/main")
+	assertEqual(t, got[0].Payload.Head, "a1")
+	assertEqual(t, got[0].Payload.Checks, "success")
+	assertEqual(t, got[0].Public, true)
+	assertEqual(t, got[0].Repo.URL, "https://github.com/octo/a")
+	assertEqual(t, searched.Payload.Ref, "")
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+)
+
+type (
+	// session is a run of events in one repository with no gap longer than
+	// the clustering gap.
+	session struct {
+		repo   string
+		start  time.Time
+		end    time.Time
+		events []ghEvent
+	}
+	// timesheetRow is the estimated time spent in a repository on a day.
+	timesheetRow struct {
+		day      time.Time
+		repo     string
+		sessions int
+		events   int
+		spent    time.Duration
+	}
+)
+
+// runTimesheet prints a CSV timesheet estimated from the archived activity.
+func runTimesheet(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("timesheet", flag.ContinueOnError)
+	days := flags.Int("days", 7, "size of the window in days")
+	gap := flags.Duration("gap", 90*time.Minute, "idle time splitting two work sessions")
+	lead := flags.Duration("lead", 30*time.Minute, "time credited before the first event of a session")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: go-github-activity timesheet [flags] <username>")
+	}
+	if *days <= 0 || *gap <= 0 || *lead < 0 {
+		return errors.New("the window, gap and lead must be positive")
+	}
+	if err := loadConfig(); err != nil {
+		return err
+	}
+	from := startOfDay(time.Now(), time.Local).AddDate(0, 0, 1-*days)
+	events, err := archivedEvents(flags.Arg(0), from)
+	if err != nil {
+		return err
+	}
+	sessions := clusterSessions(events, *gap)
+	return writeTimesheet(stdout, buildTimesheet(sessions, *lead, time.Local))
+}
+
+// clusterSessions groups the events of each repository into sessions,
+// ordered by start.
+func clusterSessions(events []ghEvent, gap time.Duration) []session {
+	sorted := append([]ghEvent(nil), events...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].CreatedAt.Before(sorted[j].CreatedAt) })
+	open := map[string]*session{}
+	var sessions []*session
+	for _, ev := range sorted {
+		s, ok := open[ev.Repo.Name]
+		if !ok || ev.CreatedAt.Sub(s.end) > gap {
+			s = &session{repo: ev.Repo.Name, start: ev.CreatedAt}
+			open[ev.Repo.Name] = s
+			sessions = append(sessions, s)
+		}
+		s.end = ev.CreatedAt
+		s.events = append(s.events, ev)
+	}
+	out := make([]session, len(sessions))
+	for i, s := range sessions {
+		out[i] = *s
+	}
+	return out
+}
+
+// duration estimates the time spent in a session: its span plus the lead
+// time before its first event.
+func (s session) duration(lead time.Duration) time.Duration {
+	return s.end.Sub(s.start) + lead
+}
+
+// buildTimesheet sums the sessions per day of their start and repository,
+// by day then repository.
+func buildTimesheet(sessions []session, lead time.Duration, loc *time.Location) []timesheetRow {
+	rows := map[string]*timesheetRow{}
+	for _, s := range sessions {
+		day := startOfDay(s.start, loc)
+		key := day.Format(time.DateOnly) + " " + s.repo
+		r, ok := rows[key]
+		if !ok {
+			r = &timesheetRow{day: day, repo: s.repo}
+			rows[key] = r
+		}
+		r.sessions++
+		r.events += len(s.events)
+		r.spent += s.duration(lead)
+	}
+	out := make([]timesheetRow, 0, len(rows))
+	for _, key := range sortedKeys(rows) {
+		out = append(out, *rows[key])
+	}
+	return out
+}
+
+// writeTimesheet writes the rows as CSV; the hours column is named as an
+// estimate since it is inferred from event timestamps.
+func writeTimesheet(w io.Writer, rows []timesheetRow) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"date", "repository", "sessions", "events", "estimated_hours"})
+	for _, r := range rows {
+		cw.Write([]string{
+			r.day.Format(time.DateOnly),
+			r.repo,
+			strconv.Itoa(r.sessions),
+			strconv.Itoa(r.events),
+			strconv.FormatFloat(r.spent.Hours(), 'f', 2, 64),
+		})
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("write timesheet: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestUnitClusterSessions(t *testing.T) {
+	base := time.Date(2025, 3, 4, 9, 0, 0, 0, time.UTC)
+	at := func(name string, minutes int) ghEvent {
+		return ghEvent{Repo: repo{Name: name}, CreatedAt: base.Add(time.Duration(minutes) * time.Minute)}
+	}
+	testCases := []struct {
+		name         string
+		events       []ghEvent
+		gap          time.Duration
+		wantSessions int
+		wantFirst    time.Duration
+	}{
+		{
+			name:         "close events",
+			events:       []ghEvent{at("octo/a", 60), at("octo/a", 30), at("octo/a", 0)},
+			gap:          time.Hour,
+			wantSessions: 1,
+			wantFirst:    time.Hour,
+		},
+		{
+			name:         "gap splits",
+			events:       []ghEvent{at("octo/a", 200), at("octo/a", 20), at("octo/a", 0)},
+			gap:          time.Hour,
+			wantSessions: 2,
+			wantFirst:    20 * time.Minute,
+		},
+		{
+			name:         "repositories apart",
+			events:       []ghEvent{at("octo/b", 10), at("octo/a", 0)},
+			gap:          time.Hour,
+			wantSessions: 2,
+			wantFirst:    0,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			got := clusterSessions(tc.events, tc.gap)
+			// Assert
+			assertEqual(t, len(got), tc.wantSessions)
+			assertEqual(t, got[0].end.Sub(got[0].start), tc.wantFirst)
+		})
+	}
+}
+
+func TestUnitTimesheet(t *testing.T) {
+	// Arrange
+	day := time.Date(2025, 3, 4, 0, 0, 0, 0, time.UTC)
+	sessions := []session{
+		{repo: "octo/a", start: day.Add(9 * time.Hour), end: day.Add(10 * time.Hour), events: make([]ghEvent, 3)},
+		{repo: "octo/a", start: day.Add(14 * time.Hour), end: day.Add(14 * time.Hour), events: make([]ghEvent, 1)},
+		{repo: "octo/b", start: day.Add(33 * time.Hour), end: day.Add(34 * time.Hour), events: make([]ghEvent, 2)},
+	}
+	var buf bytes.Buffer
+	// Act
+	err := writeTimesheet(&buf, buildTimesheet(sessions, 30*time.Minute, time.UTC))
+	// Assert
+	assertNoError(t, err)
+	want := "date,repository,sessions,events,estimated_hours\n" +
+		"2025-03-04,octo/a,2,4,2.00\n" +
+		"2025-03-05,octo/b,1,2,1.50\n"
+	assertEqual(t, buf.String(), want)
+}
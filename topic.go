@@ -0,0 +1,76 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+)
+
+// runTopic aggregates the events of the repositories tagged with a topic,
+// optionally within an organization. With --watch it polls them and
+// delivers new events to the notifiers like watch does.
+func runTopic(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("topic", flag.ContinueOnError)
+	org := flags.String("org", "", "only the repositories of this organization or user")
+	workers := flags.Int("concurrency", 4, "number of repositories fetched at once")
+	list := flags.Bool("list", false, "list the repositories without their events")
+	output := flags.String("output", "text", "output format: text or json")
+	strict := flags.Bool("strict-budget", false, "fail instead of asking when the run would exceed the rate limit")
+	watch := flags.Bool("watch", false, "poll the repositories and notify new events until interrupted")
+	interval := flags.Duration("interval", time.Minute, "delay between two polls with --watch")
+	refresh := flags.Duration("refresh", time.Hour, "delay between two listings of the repositories with --watch")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: go-github-activity topic [flags] <topic>")
+	}
+	if *output != "text" && *output != "json" {
+		return fmt.Errorf("unknown output format %q", *output)
+	}
+	if *watch && *interval < time.Second {
+		return fmt.Errorf("invalid interval: %s", *interval)
+	}
+	if err := loadConfig(); err != nil {
+		return err
+	}
+	f, err := newRepoFetcher()
+	if err != nil {
+		return err
+	}
+	query := topicQuery(flags.Arg(0), *org)
+	search := func() ([]string, error) {
+		repos, err := searchAll[orgRepo](f.hc, f.base, "repositories", query)
+		if err != nil {
+			return nil, err
+		}
+		return filterOrgRepos(repos, "", ""), nil
+	}
+	if *watch {
+		t := &repoTracker{
+			list: search, eventsOf: f.repoEvents, workers: *workers, budget: f.hc.budget,
+			refresh: *refresh, clock: f.hc.clock,
+		}
+		return watchTracked(t, *interval, stdout)
+	}
+	names, err := search()
+	if err != nil {
+		return err
+	}
+	if *list {
+		return writeRepoNames(stdout, names)
+	}
+	return renderTracked(stdout, *output, names, f, *workers, *strict)
+}
+
+// topicQuery returns the repository search query of a topic, restricted to
+// an owner when set.
+func topicQuery(topic, owner string) string {
+	q := "topic:" + topic + " archived:false"
+	if owner != "" {
+		q += " user:" + owner
+	}
+	return q
+}
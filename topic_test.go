@@ -0,0 +1,24 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestUnitTopicQuery(t *testing.T) {
+	testCases := []struct {
+		name  string
+		owner string
+		want  string
+	}{
+		{name: "everywhere", want: "topic:kubernetes-operator archived:false"},
+		{name: "owner", owner: "octo", want: "topic:kubernetes-operator archived:false user:octo"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			got := topicQuery("kubernetes-operator", tc.owner)
+			// Assert
+			assertEqual(t, got, tc.want)
+		})
+	}
+}
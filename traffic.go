@@ -0,0 +1,153 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+type (
+	// trafficDay is the daily traffic of a repository.
+	trafficDay struct {
+		Timestamp time.Time `json:"timestamp"`
+		Count     int       `json:"count"`
+		Uniques   int       `json:"uniques"`
+	}
+	// repoTraffic compares the views and clones of a repository over the
+	// last week with the week before, next to the user's activity in it
+	// during the last week.
+	repoTraffic struct {
+		repo     string
+		views    metricDelta
+		clones   metricDelta
+		commits  int
+		releases int
+	}
+)
+
+// trafficRecentRepos is the number of latest pushed repositories of the
+// user reported when traffic.repos is not set.
+const trafficRecentRepos = 10
+
+// runTraffic reports the traffic of the user's repositories against their
+// activity of the week. Traffic is only visible with push access.
+func runTraffic(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("traffic", flag.ContinueOnError)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: go-github-activity traffic <username>")
+	}
+	if err := loadConfig(); err != nil {
+		return err
+	}
+	user := flags.Arg(0)
+	f, err := newRepoFetcher()
+	if err != nil {
+		return err
+	}
+	repos := viper.GetStringSlice("traffic.repos")
+	if len(repos) == 0 {
+		if repos, err = recentRepos(f.hc, f.base, user, trafficRecentRepos); err != nil {
+			return err
+		}
+	}
+	events, err := fetchUserEvents(user)
+	if err != nil {
+		return err
+	}
+	rows, err := correlateTraffic(repos, events, f.traffic, time.Now())
+	if err != nil {
+		return err
+	}
+	return writeTraffic(stdout, rows)
+}
+
+// traffic returns the daily views and clones of the last 14 days of a
+// repository.
+func (f *repoFetcher) traffic(name string) (views, clones []trafficDay, err error) {
+	for _, kind := range []string{"views", "clones"} {
+		u, err := f.repoURL(name, "traffic", kind)
+		if err != nil {
+			return nil, nil, err
+		}
+		var res struct {
+			Views  []trafficDay `json:"views"`
+			Clones []trafficDay `json:"clones"`
+		}
+		if err := fetchJSON(f.hc, u, &res); err != nil {
+			return nil, nil, fmt.Errorf("fetch %s of %s: %w", kind, name, err)
+		}
+		views, clones = append(views, res.Views...), append(clones, res.Clones...)
+	}
+	return views, clones, nil
+}
+
+// correlateTraffic builds the traffic row of each repository whose traffic
+// is visible, with the commits and releases of the events in the week
+// ending today.
+func correlateTraffic(repos []string, events []ghEvent, trafficOf func(name string) ([]trafficDay, []trafficDay, error),
+	now time.Time,
+) ([]repoTraffic, error) {
+	to := startOfDay(now, time.UTC).AddDate(0, 0, 1)
+	from := to.AddDate(0, 0, -7)
+	rows := make([]repoTraffic, 0, len(repos))
+	for _, name := range repos {
+		views, clones, err := trafficOf(name)
+		switch {
+		case isStatus(err, http.StatusForbidden) || isStatus(err, http.StatusNotFound):
+			continue
+		case err != nil:
+			return nil, err
+		}
+		row := repoTraffic{repo: name, views: weekDelta("views", views, to), clones: weekDelta("clones", clones, to)}
+		for _, ev := range eventsBetween(events, from, to) {
+			if !strings.EqualFold(ev.Repo.Name, name) {
+				continue
+			}
+			row.commits += pushSize(ev)
+			row.releases += boolToInt(ev.Type == "ReleaseEvent")
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// weekDelta totals the traffic of the week before to and of the week
+// before it.
+func weekDelta(name string, days []trafficDay, to time.Time) metricDelta {
+	d := metricDelta{name: name}
+	from := to.AddDate(0, 0, -7)
+	for _, day := range days {
+		switch {
+		case !day.Timestamp.Before(from) && day.Timestamp.Before(to):
+			d.current += day.Count
+		case !day.Timestamp.Before(from.AddDate(0, 0, -7)) && day.Timestamp.Before(from):
+			d.previous += day.Count
+		}
+	}
+	return d
+}
+
+// writeTraffic prints one row per repository.
+func writeTraffic(w io.Writer, rows []repoTraffic) error {
+	if len(rows) == 0 {
+		_, err := fmt.Fprintln(w, "no repository traffic visible, which needs push access")
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, tablePadding, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "REPO\tVIEWS\tCHANGE\tCLONES\tCHANGE\tCOMMITS\tRELEASES\t")
+	for _, r := range rows {
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%d\t%s\t%d\t%d\t\n",
+			r.repo, r.views.current, r.views.change(), r.clones.current, r.clones.change(), r.commits, r.releases)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUnitWeekDelta(t *testing.T) {
+	// Arrange
+	to := time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC)
+	days := []trafficDay{
+		{Timestamp: to.AddDate(0, 0, -14), Count: 4},
+		{Timestamp: to.AddDate(0, 0, -8), Count: 6},
+		{Timestamp: to.AddDate(0, 0, -7), Count: 5},
+		{Timestamp: to.AddDate(0, 0, -1), Count: 10},
+		{Timestamp: to, Count: 100},
+	}
+	// Act
+	got := weekDelta("views", days, to)
+	// Assert
+	assertEqual(t, got, metricDelta{name: "views", current: 15, previous: 10})
+}
+
+func TestUnitCorrelateTraffic(t *testing.T) {
+	now := time.Date(2025, 3, 14, 12, 0, 0, 0, time.UTC)
+	events := []ghEvent{
+		{Type: "PushEvent", Repo: repo{Name: "octo/API"}, Payload: payload{Size: 3}, CreatedAt: now},
+		{Type: "ReleaseEvent", Repo: repo{Name: "octo/api"}, CreatedAt: now.AddDate(0, 0, -2)},
+		{Type: "PushEvent", Repo: repo{Name: "octo/api"}, Payload: payload{Size: 9}, CreatedAt: now.AddDate(0, 0, -8)},
+	}
+	testCases := []struct {
+		name     string
+		err      error
+		wantRows int
+		wantErr  bool
+	}{
+		{name: "visible", wantRows: 1},
+		{name: "no push access", err: &apiError{StatusCode: http.StatusForbidden}},
+		{name: "failure", err: errors.New("boom"), wantErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			trafficOf := func(string) ([]trafficDay, []trafficDay, error) {
+				views := []trafficDay{{Timestamp: now.AddDate(0, 0, -1), Count: 40}, {Timestamp: now.AddDate(0, 0, -9), Count: 20}}
+				return views, nil, tc.err
+			}
+			// Act
+			rows, err := correlateTraffic([]string{"octo/api"}, events, trafficOf, now)
+			// Assert
+			if tc.wantErr {
+				assertNotNil(t, err)
+				return
+			}
+			assertNoError(t, err)
+			assertEqual(t, len(rows), tc.wantRows)
+			if tc.wantRows == 0 {
+				return
+			}
+			assertEqual(t, rows[0].views.change(), "+100%")
+			assertEqual(t, rows[0].clones.change(), "n/a")
+			assertEqual(t, rows[0].commits, 3)
+			assertEqual(t, rows[0].releases, 1)
+		})
+	}
+}
+
+func TestUnitRepoFetcherTraffic(t *testing.T) {
+	// Arrange
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/octo/api/traffic/views":
+			w.Write([]byte(`{"count":3,"uniques":2,"views":[{"timestamp":"2025-03-14T00:00:00Z","count":3,"uniques":2}]}`))
+		case "/repos/octo/api/traffic/clones":
+			w.Write([]byte(`{"count":1,"uniques":1,"clones":[{"timestamp":"2025-03-13T00:00:00Z","count":1,"uniques":1}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	f := &repoFetcher{hc: newClient(""), base: srv.URL}
+	// Act
+	views, clones, err := f.traffic("octo/api")
+	// Assert
+	assertNoError(t, err)
+	assertEqual(t, len(views), 1)
+	assertEqual(t, views[0], trafficDay{Timestamp: time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC), Count: 3, Uniques: 2})
+	assertEqual(t, len(clones), 1)
+	assertEqual(t, clones[0].Count, 1)
+}
+
+func TestUnitWriteTraffic(t *testing.T) {
+	testCases := []struct {
+		name string
+		rows []repoTraffic
+		want string
+	}{
+		{name: "none", want: "no repository traffic visible, which needs push access\n"},
+		{
+			name: "rows",
+			rows: []repoTraffic{{
+				repo: "octo/api", views: metricDelta{current: 40, previous: 20}, clones: metricDelta{current: 2},
+				commits: 3, releases: 1,
+			}},
+			want: "      REPO  VIEWS  CHANGE  CLONES  CHANGE  COMMITS  RELEASES\n" +
+				"  octo/api     40   +100%       2     n/a        3         1\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			var b strings.Builder
+			// Act
+			err := writeTraffic(&b, tc.rows)
+			// Assert
+			assertNoError(t, err)
+			assertEqual(t, b.String(), tc.want)
+		})
+	}
+}
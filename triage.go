@@ -0,0 +1,210 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type (
+	// triageIssue is an issue the user opened, commented or closed.
+	triageIssue struct {
+		ref           issueRef
+		author        string
+		createdAt     time.Time
+		closed        bool
+		firstResponse *time.Time
+		seenAt        time.Time
+	}
+	// issueThread is the state and first response of an issue according to
+	// the Issues API.
+	issueThread struct {
+		Author        string     `json:"author"`
+		State         string     `json:"state"`
+		CreatedAt     time.Time  `json:"created_at"`
+		FirstResponse *time.Time `json:"first_response"`
+	}
+	// triageMetric summarizes the triage of the issues of one repository.
+	triageMetric struct {
+		repo           string
+		issues         int
+		closed         int
+		answered       int
+		medianResponse time.Duration
+	}
+)
+
+// issueThread returns the state of an issue and the date of its first
+// comment by someone other than its author, from the first page of
+// comments.
+func (f *repoFetcher) issueThread(name string, number int) (issueThread, error) {
+	return cached(f.cache, "issues/"+name+"/"+strconv.Itoa(number), func() (issueThread, error) {
+		u, err := f.repoURL(name, "issues", strconv.Itoa(number))
+		if err != nil {
+			return issueThread{}, err
+		}
+		var is issue
+		if err := fetchJSON(f.hc, u, &is); err != nil {
+			return issueThread{}, fmt.Errorf("fetch issue %s#%d: %w", name, number, err)
+		}
+		var comments []struct {
+			User      actor     `json:"user"`
+			CreatedAt time.Time `json:"created_at"`
+		}
+		if err := fetchJSON(f.hc, u+"/comments?per_page=100", &comments); err != nil {
+			return issueThread{}, fmt.Errorf("fetch comments of %s#%d: %w", name, number, err)
+		}
+		th := issueThread{State: is.State}
+		if is.User != nil {
+			th.Author = is.User.Login
+		}
+		if is.CreatedAt != nil {
+			th.CreatedAt = *is.CreatedAt
+		}
+		for _, c := range comments {
+			if c.User.Login != th.Author {
+				th.FirstResponse = &c.CreatedAt
+				break
+			}
+		}
+		return th, nil
+	})
+}
+
+// collectIssues returns the issues of the issue and issue comment events,
+// leaving pull requests out. The first response is the earliest comment or
+// close by someone other than the author among the events, and the state
+// comes from the latest event.
+func collectIssues(events []ghEvent) []triageIssue {
+	var refs []issueRef
+	byRef := map[issueRef]*triageIssue{}
+	for _, ev := range events {
+		is := ev.Payload.Issue
+		if (ev.Type != "IssuesEvent" && ev.Type != "IssueCommentEvent") || is == nil || is.PullRequest != nil {
+			continue
+		}
+		ref := issueRef{repo: ev.Repo.Name, number: is.Number}
+		ti, ok := byRef[ref]
+		if !ok {
+			ti = &triageIssue{ref: ref}
+			byRef[ref] = ti
+			refs = append(refs, ref)
+		}
+		if is.User != nil {
+			ti.author = is.User.Login
+		} else if ev.Type == "IssuesEvent" && ev.Payload.Action == "opened" {
+			ti.author = ev.Actor.Login
+		}
+		switch {
+		case is.CreatedAt != nil:
+			ti.createdAt = *is.CreatedAt
+		case ev.Type == "IssuesEvent" && ev.Payload.Action == "opened":
+			ti.createdAt = ev.CreatedAt
+		}
+		if !ev.CreatedAt.Before(ti.seenAt) {
+			ti.seenAt, ti.closed = ev.CreatedAt, is.State == "closed"
+		}
+	}
+	for _, ev := range events {
+		is := ev.Payload.Issue
+		if is == nil {
+			continue
+		}
+		ti, ok := byRef[issueRef{repo: ev.Repo.Name, number: is.Number}]
+		responds := ev.Type == "IssueCommentEvent" || (ev.Type == "IssuesEvent" && ev.Payload.Action == "closed")
+		if !ok || !responds || ev.Actor.Login == ti.author {
+			continue
+		}
+		if ti.firstResponse == nil || ev.CreatedAt.Before(*ti.firstResponse) {
+			at := ev.CreatedAt
+			ti.firstResponse = &at
+		}
+	}
+	out := make([]triageIssue, 0, len(refs))
+	for _, ref := range refs {
+		out = append(out, *byRef[ref])
+	}
+	return out
+}
+
+// enrichIssues replaces what the events tell about the issues with their
+// thread from the Issues API. Deleted issues keep the event data.
+func enrichIssues(issues []triageIssue, threadOf func(repo string, number int) (issueThread, error)) error {
+	for i := range issues {
+		th, err := threadOf(issues[i].ref.repo, issues[i].ref.number)
+		if err != nil {
+			if isStatus(err, http.StatusNotFound) || isStatus(err, http.StatusGone) {
+				continue
+			}
+			return err
+		}
+		issues[i].author, issues[i].createdAt = th.Author, th.CreatedAt
+		issues[i].closed = th.State == "closed"
+		if th.FirstResponse != nil {
+			issues[i].firstResponse = th.FirstResponse
+		}
+	}
+	return nil
+}
+
+// summarizeTriage computes the close rate and the median first response
+// time per repository, by repository name. Issues with an unknown creation
+// date count as unanswered.
+func summarizeTriage(issues []triageIssue) []triageMetric {
+	byRepo := map[string][]triageIssue{}
+	for _, ti := range issues {
+		byRepo[ti.ref.repo] = append(byRepo[ti.ref.repo], ti)
+	}
+	out := make([]triageMetric, 0, len(byRepo))
+	for _, name := range sortedKeys(byRepo) {
+		m := triageMetric{repo: name, issues: len(byRepo[name])}
+		var responses []time.Duration
+		for _, ti := range byRepo[name] {
+			m.closed += boolToInt(ti.closed)
+			if ti.firstResponse != nil && !ti.createdAt.IsZero() {
+				responses = append(responses, ti.firstResponse.Sub(ti.createdAt))
+			}
+		}
+		m.answered, m.medianResponse = len(responses), median(responses)
+		out = append(out, m)
+	}
+	return out
+}
+
+// triageView prints the triage metrics of the issues of the window, read
+// from the Issues API with --enrich.
+func triageView(w io.Writer, events []ghEvent, opts statsOptions) error {
+	issues := collectIssues(events)
+	if opts.enrich {
+		fetcher, err := newRepoFetcher()
+		if err != nil {
+			return err
+		}
+		if err := enrichIssues(issues, fetcher.issueThread); err != nil {
+			return err
+		}
+	}
+	return writeTriage(w, summarizeTriage(issues))
+}
+
+// writeTriage prints one line per repository.
+func writeTriage(w io.Writer, metrics []triageMetric) error {
+	if _, err := fmt.Fprintf(w, "%-30s %6s %6s %10s %8s %14s\n",
+		"repository", "issues", "closed", "close rate", "answered", "first response"); err != nil {
+		return err
+	}
+	for _, m := range metrics {
+		response := "-"
+		if m.answered > 0 {
+			response = m.medianResponse.Round(time.Minute).String()
+		}
+		rate := float64(m.closed) * 100 / float64(m.issues)
+		if _, err := fmt.Fprintf(w, "%-30s %6d %6d %9.1f%% %8d %14s\n",
+			m.repo, m.issues, m.closed, rate, m.answered, response); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestUnitCollectIssues(t *testing.T) {
+	// Arrange
+	opened := time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC)
+	is := func(number int, state string) *issue {
+		return &issue{Number: number, State: state, User: &actor{Login: "mona"}, CreatedAt: &opened}
+	}
+	ev := func(typ, login, action string, is *issue, at time.Duration) ghEvent {
+		return ghEvent{Type: typ, Actor: actor{Login: login}, Repo: repo{Name: "octo/a"},
+			Payload: payload{Action: action, Issue: is}, CreatedAt: opened.Add(at)}
+	}
+	events := []ghEvent{
+		ev("IssuesEvent", "octocat", "closed", is(1, "closed"), 5*time.Hour),
+		ev("IssueCommentEvent", "octocat", "created", is(1, "open"), 3*time.Hour),
+		ev("IssueCommentEvent", "mona", "created", is(1, "open"), time.Hour),
+		ev("IssueCommentEvent", "mona", "created", is(2, "open"), 2*time.Hour),
+		ev("IssueCommentEvent", "octocat", "created", &issue{Number: 3, PullRequest: &struct{}{}}, 0),
+	}
+	// Act
+	issues := collectIssues(events)
+	// Assert
+	assertEqual(t, len(issues), 2)
+	assertEqual(t, issues[0].closed, true)
+	assertEqual(t, issues[0].firstResponse.Sub(opened), 3*time.Hour)
+	assertEqual(t, issues[1].closed, false)
+	assertEqual(t, issues[1].firstResponse == nil, true)
+}
+
+func TestUnitEnrichIssues(t *testing.T) {
+	// Arrange
+	created := time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC)
+	response := created.Add(30 * time.Minute)
+	issues := []triageIssue{
+		{ref: issueRef{repo: "octo/a", number: 1}},
+		{ref: issueRef{repo: "octo/a", number: 2}, author: "mona"},
+	}
+	threadOf := func(_ string, number int) (issueThread, error) {
+		if number == 2 {
+			return issueThread{}, &apiError{StatusCode: http.StatusGone}
+		}
+		return issueThread{Author: "hubot", State: "closed", CreatedAt: created, FirstResponse: &response}, nil
+	}
+	// Act
+	err := enrichIssues(issues, threadOf)
+	// Assert
+	assertNoError(t, err)
+	assertEqual(t, issues[0].closed, true)
+	assertEqual(t, issues[0].firstResponse.Sub(issues[0].createdAt), 30*time.Minute)
+	assertEqual(t, issues[1].author, "mona")
+}
+
+func TestUnitSummarizeTriage(t *testing.T) {
+	// Arrange
+	created := time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC)
+	after := func(d time.Duration) *time.Time { at := created.Add(d); return &at }
+	issues := []triageIssue{
+		{ref: issueRef{repo: "octo/b", number: 1}, createdAt: created, closed: true, firstResponse: after(time.Hour)},
+		{ref: issueRef{repo: "octo/a", number: 1}, createdAt: created, closed: true, firstResponse: after(4 * time.Hour)},
+		{ref: issueRef{repo: "octo/a", number: 2}, createdAt: created, firstResponse: after(2 * time.Hour)},
+		{ref: issueRef{repo: "octo/a", number: 3}, firstResponse: after(time.Hour)},
+	}
+	var buf bytes.Buffer
+	// Act
+	metrics := summarizeTriage(issues)
+	err := writeTriage(&buf, metrics)
+	// Assert
+	assertNoError(t, err)
+	assertEqual(t, metrics[0], triageMetric{
+		repo: "octo/a", issues: 3, closed: 1, answered: 2, medianResponse: 2 * time.Hour,
+	})
+	assertEqual(t, buf.String(),
+		"repository                     issues closed close rate answered first response\n"+
+			"octo/a                              3      1      33.3%        2         2h0m0s\n"+
+			"octo/b                              1      1     100.0%        1         1h0m0s\n")
+}
@@ -0,0 +1,267 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+type (
+	// usageRecord is a line of the usage log: an API request, or a response
+	// served from the cache instead.
+	usageRecord struct {
+		Time     time.Time `json:"time"`
+		Method   string    `json:"method,omitempty"`
+		Endpoint string    `json:"endpoint"`
+		Status   int       `json:"status,omitempty"`
+		// Cost is the number of requests charged to the rate limit: cache
+		// hits, 304 responses and the rate limit API are free.
+		Cost     int    `json:"cost"`
+		Resource string `json:"resource,omitempty"`
+		Cache    bool   `json:"cache,omitempty"`
+	}
+	// usageTotal sums the records of an endpoint, resource or day.
+	usageTotal struct {
+		Key       string `json:"key"`
+		Requests  int    `json:"requests"`
+		Cost      int    `json:"cost"`
+		CacheHits int    `json:"cache_hits"`
+		Errors    int    `json:"errors"`
+	}
+)
+
+// usageFileName is the usage log of the application directory, written when
+// usage_log is set.
+const usageFileName = "usage.jsonl"
+
+// usageMu serializes the writes of concurrent fetches to the usage log.
+var usageMu sync.Mutex
+
+// usageGroups maps the --by values to the key of a record.
+var usageGroups = map[string]func(usageRecord) string{
+	"endpoint": func(r usageRecord) string { return endpointRoute(r.Endpoint) },
+	"resource": func(r usageRecord) string { return orDefault(r.Resource, "-") },
+	"day":      func(r usageRecord) string { return r.Time.Local().Format(time.DateOnly) },
+}
+
+// usagePath returns the path of the usage log.
+func usagePath() (string, error) {
+	dir, err := appDir(&defaultUserHome{})
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, usageFileName), nil
+}
+
+// requestUsage returns the record of an API response received at the given
+// time.
+func requestUsage(req *http.Request, res *http.Response, at time.Time) usageRecord {
+	rec := usageRecord{
+		Time: at.UTC(), Method: req.Method, Endpoint: req.URL.Path, Status: res.StatusCode, Cost: 1,
+		Resource: res.Header.Get("X-RateLimit-Resource"),
+	}
+	if res.StatusCode == http.StatusNotModified || strings.HasSuffix(req.URL.Path, "/rate_limit") {
+		rec.Cost = 0
+	}
+	return rec
+}
+
+// logUsage appends a record to the usage log when usage_log is set. A log
+// that cannot be written is reported without failing the request.
+func logUsage(rec usageRecord) {
+	if !viper.GetBool("usage_log") {
+		return
+	}
+	if err := appendUsage(rec); err != nil {
+		log.Printf("write usage log: %v", err)
+	}
+}
+
+// appendUsage writes a record at the end of the usage log.
+func appendUsage(rec usageRecord) error {
+	path, err := usagePath()
+	if err != nil {
+		return err
+	}
+	byt, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	usageMu.Lock()
+	defer usageMu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(byt, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// runUsage sums the usage log of the last days by endpoint, rate limit
+// resource or day, costliest first, to show where the quota goes.
+func runUsage(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("usage", flag.ContinueOnError)
+	days := flags.Int("days", 7, "size of the window in days")
+	by := flags.String("by", "endpoint", "grouping: "+strings.Join(sortedKeys(usageGroups), ", "))
+	output := flags.String("output", "text", "output format: text or json")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		return errors.New("usage: go-github-activity usage [flags]")
+	}
+	group, ok := usageGroups[*by]
+	if !ok {
+		return fmt.Errorf("unknown grouping %q", *by)
+	}
+	if *output != "text" && *output != "json" {
+		return fmt.Errorf("unknown output format %q", *output)
+	}
+	if *days < 1 {
+		return fmt.Errorf("invalid number of days: %d", *days)
+	}
+	path, err := usagePath()
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return infof(stdout, "no usage logged yet: set usage_log: true in the configuration\n")
+	}
+	if err != nil {
+		return fmt.Errorf("open usage log: %w", err)
+	}
+	defer f.Close()
+	records, err := readUsage(f, time.Now().AddDate(0, 0, -*days))
+	if err != nil {
+		return err
+	}
+	totals := sumUsage(records, group)
+	if *output == "json" {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(totals)
+	}
+	return writeUsage(stdout, totals)
+}
+
+// readUsage decodes the records of the usage log from since on, skipping
+// the lines that do not decode, e.g. a line cut by a crash.
+func readUsage(r io.Reader, since time.Time) ([]usageRecord, error) {
+	var records []usageRecord
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		var rec usageRecord
+		if json.Unmarshal(sc.Bytes(), &rec) != nil || rec.Time.Before(since) {
+			continue
+		}
+		records = append(records, rec)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("read usage log: %w", err)
+	}
+	return records, nil
+}
+
+// sumUsage totals the records by key, by decreasing cost then requests.
+func sumUsage(records []usageRecord, key func(usageRecord) string) []usageTotal {
+	byKey := map[string]*usageTotal{}
+	for _, rec := range records {
+		k := key(rec)
+		t, ok := byKey[k]
+		if !ok {
+			t = &usageTotal{Key: k}
+			byKey[k] = t
+		}
+		t.Requests++
+		t.Cost += rec.Cost
+		if rec.Cache {
+			t.CacheHits++
+		}
+		if rec.Status >= 400 {
+			t.Errors++
+		}
+	}
+	totals := make([]usageTotal, 0, len(byKey))
+	for _, t := range byKey {
+		totals = append(totals, *t)
+	}
+	sort.Slice(totals, func(i, j int) bool {
+		a, b := totals[i], totals[j]
+		if a.Cost != b.Cost {
+			return a.Cost > b.Cost
+		}
+		if a.Requests != b.Requests {
+			return a.Requests > b.Requests
+		}
+		return a.Key < b.Key
+	})
+	return totals
+}
+
+// writeUsage prints the totals as a table.
+func writeUsage(w io.Writer, totals []usageTotal) error {
+	tw := tabwriter.NewWriter(w, 0, 0, tablePadding, ' ', 0)
+	fmt.Fprintln(tw, "KEY\tREQUESTS\tCOST\tCACHE HITS\tERRORS")
+	for _, t := range totals {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\n", t.Key, t.Requests, t.Cost, t.CacheHits, t.Errors)
+	}
+	return tw.Flush()
+}
+
+// routeParams lists the placeholders of the segments following the first
+// one of an endpoint, or of a cache key.
+var routeParams = map[string][]string{
+	"repos":       {"{owner}", "{repo}"},
+	"users":       {"{user}"},
+	"orgs":        {"{org}"},
+	"commits":     {"{owner}", "{repo}"},
+	"labels":      {"{owner}", "{repo}"},
+	"pulls":       {"{owner}", "{repo}"},
+	"issues":      {"{owner}", "{repo}"},
+	"languages":   {"{owner}", "{repo}"},
+	"deployments": {"{owner}", "{repo}"},
+}
+
+// idSegment matches the path segments identifying a resource: numbers and
+// commit hashes.
+var idSegment = regexp.MustCompile(`^(\d+|[0-9a-f]{40})$`)
+
+// endpointRoute replaces the owners, repositories and identifiers of an
+// endpoint by placeholders, so that "/repos/octo/api/pulls/12" counts as
+// "/repos/{owner}/{repo}/pulls/{id}".
+func endpointRoute(endpoint string) string {
+	segments := strings.Split(strings.Trim(endpoint, "/"), "/")
+	params := routeParams[segments[0]]
+	for i := 1; i < len(segments); i++ {
+		switch {
+		case i-1 < len(params):
+			segments[i] = params[i-1]
+		case idSegment.MatchString(segments[i]):
+			segments[i] = "{id}"
+		}
+	}
+	return "/" + strings.Join(segments, "/")
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func TestUnitEndpointRoute(t *testing.T) {
+	testCases := []struct {
+		endpoint string
+		want     string
+	}{
+		{endpoint: "/users/octocat/events", want: "/users/{user}/events"},
+		{endpoint: "/repos/octo/api/pulls/12", want: "/repos/{owner}/{repo}/pulls/{id}"},
+		{endpoint: "/repos/octo/api/commits/0123456789abcdef0123456789abcdef01234567/status",
+			want: "/repos/{owner}/{repo}/commits/{id}/status"},
+		{endpoint: "/labels/octo/api/7", want: "/labels/{owner}/{repo}/{id}"},
+		{endpoint: "/search/issues", want: "/search/issues"},
+		{endpoint: "/rate_limit", want: "/rate_limit"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.endpoint, func(t *testing.T) {
+			// Act
+			got := endpointRoute(tc.endpoint)
+			// Assert
+			assertEqual(t, got, tc.want)
+		})
+	}
+}
+
+func TestUnitReadUsage(t *testing.T) {
+	// Arrange
+	log := `{"time":"2025-03-01T10:00:00Z","endpoint":"/users/a/events","status":200,"cost":1}
+{"time":"2025-03-09T10:00:00Z","endpoint":"/users/b/events","status":200,"cost":1}
+{"time":"2025-03-09T10:00:01Z","endp
+{"time":"2025-03-10T10:00:00Z","endpoint":"/repos/a","cost":0,"cache":true}
+`
+	since := time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC)
+	// Act
+	got, err := readUsage(strings.NewReader(log), since)
+	// Assert
+	assertNoError(t, err)
+	assertEqual(t, len(got), 2)
+	assertEqual(t, got[0].Endpoint, "/users/b/events")
+	assertEqual(t, got[1].Cache, true)
+}
+
+func TestUnitSumUsage(t *testing.T) {
+	// Arrange
+	records := []usageRecord{
+		{Endpoint: "/users/a/events", Status: 200, Cost: 1, Resource: "core"},
+		{Endpoint: "/users/b/events", Status: 404, Cost: 1, Resource: "core"},
+		{Endpoint: "/search/issues", Status: 200, Cost: 1, Resource: "search"},
+		{Endpoint: "/repos/a/b", Cache: true},
+		{Endpoint: "/repos/a/b", Status: 304, Resource: "core"},
+	}
+	testCases := []struct {
+		by   string
+		want string
+	}{
+		{by: "endpoint", want: "[{/users/{user}/events 2 2 0 1} {/search/issues 1 1 0 0} {/repos/{owner}/{repo} 2 0 1 0}]"},
+		{by: "resource", want: "[{core 3 2 0 1} {search 1 1 0 0} {- 1 0 1 0}]"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.by, func(t *testing.T) {
+			// Act
+			got := sumUsage(records, usageGroups[tc.by])
+			// Assert
+			assertEqual(t, fmt.Sprint(got), tc.want)
+		})
+	}
+}
+
+func TestUnitLogUsage(t *testing.T) {
+	testCases := []struct {
+		name    string
+		enabled bool
+		want    string
+	}{
+		{name: "disabled"},
+		{name: "enabled", enabled: true, want: "GET /users/octocat/events 200 1 core"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			home := t.TempDir()
+			t.Setenv("HOME", home)
+			t.Cleanup(viper.Reset)
+			viper.Set("usage_log", tc.enabled)
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("X-RateLimit-Resource", "core")
+				w.Write([]byte("[]"))
+			}))
+			t.Cleanup(srv.Close)
+			// Act
+			_, err := fetchGitHubResponse(newClient(""), srv.URL+"/users/octocat/events?page=2")
+			// Assert
+			assertNoError(t, err)
+			f, err := os.Open(filepath.Join(home, ".go-github-activity", usageFileName))
+			if !tc.enabled {
+				assertNotNil(t, err)
+				return
+			}
+			assertNoError(t, err)
+			defer f.Close()
+			records, err := readUsage(f, time.Time{})
+			assertNoError(t, err)
+			assertEqual(t, len(records), 1)
+			r := records[0]
+			assertEqual(t, fmt.Sprint(r.Method, " ", r.Endpoint, " ", r.Status, " ", r.Cost, " ", r.Resource), tc.want)
+		})
+	}
+}
+
+func TestUnitWriteUsage(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+	// Act
+	err := writeUsage(&buf, []usageTotal{{Key: "/users/{user}/events", Requests: 3, Cost: 2, CacheHits: 1}})
+	// Assert
+	assertNoError(t, err)
+	assertEqual(t, buf.String(), "KEY                   REQUESTS  COST  CACHE HITS  ERRORS\n"+
+		"/users/{user}/events  3         2     1           0\n")
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+type (
+	// userFetcher resolves user profiles from the GitHub API through a cache.
+	userFetcher struct {
+		hc    *client
+		base  string
+		cache *fileCache
+	}
+	// userProfile holds the public profile fields used to enrich actors.
+	userProfile struct {
+		Login     string `json:"login"`
+		Name      string `json:"name"`
+		Company   string `json:"company"`
+		AvatarURL string `json:"avatar_url"`
+	}
+)
+
+// profile returns the public profile of a user.
+func (f *userFetcher) profile(login string) (userProfile, error) {
+	return cached(f.cache, "users/"+login, func() (userProfile, error) {
+		var p userProfile
+		if err := fetchJSON(f.hc, f.base+"/users/"+url.PathEscape(login), &p); err != nil {
+			return userProfile{}, fmt.Errorf("fetch profile of %s: %w", login, err)
+		}
+		return p, nil
+	})
+}
+
+// enrichActors attaches profiles to the event actors, resolving each login
+// once. Deleted accounts are left without a profile.
+func enrichActors(events []ghEvent, profileOf func(login string) (userProfile, error)) error {
+	resolved := map[string]*userProfile{}
+	for i := range events {
+		login := events[i].Actor.Login
+		p, ok := resolved[login]
+		if !ok {
+			profile, err := profileOf(login)
+			switch {
+			case err == nil:
+				p = &profile
+			case !isStatus(err, http.StatusNotFound):
+				return err
+			}
+			resolved[login] = p
+		}
+		events[i].Actor.Profile = p
+	}
+	return nil
+}
+
+// displayName is the profile name of an actor, falling back to its login.
+func (a actor) displayName() string {
+	if a.Profile != nil && a.Profile.Name != "" {
+		return a.Profile.Name
+	}
+	if a.DisplayLogin != "" {
+		return a.DisplayLogin
+	}
+	return a.Login
+}
+
+// avatar is the avatar URL of an actor, preferring the resolved profile.
+func (a actor) avatar() string {
+	if a.Profile != nil && a.Profile.AvatarURL != "" {
+		return a.Profile.AvatarURL
+	}
+	return a.AvatarURL
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestUnitUserFetcherProfile(t *testing.T) {
+	// Arrange
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		assertEqual(t, r.URL.Path, "/users/octocat")
+		w.Write([]byte(`{"login": "octocat", "name": "The Octocat", "company": "@github", "avatar_url": "https://a/1"}`))
+	}))
+	t.Cleanup(srv.Close)
+	cache := &fileCache{dir: t.TempDir(), ttl: time.Hour, clock: systemClock{}}
+	f := &userFetcher{hc: newClient(""), base: srv.URL, cache: cache}
+	// Act
+	p, err := f.profile("octocat")
+	f.profile("octocat")
+	// Assert
+	assertNoError(t, err)
+	assertEqual(t, p, userProfile{Login: "octocat", Name: "The Octocat", Company: "@github", AvatarURL: "https://a/1"})
+	assertEqual(t, calls, 1)
+}
+
+func TestUnitEnrichActors(t *testing.T) {
+	testCases := []struct {
+		name     string
+		fetchErr error
+		wantName string
+		wantErr  bool
+	}{
+		{name: "resolved", wantName: "The Octocat"},
+		{name: "deleted account", fetchErr: &apiError{StatusCode: 404}, wantName: "octocat"},
+		{name: "api failure", fetchErr: errors.New("boom"), wantErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			events := []ghEvent{{Actor: actor{Login: "octocat"}}, {Actor: actor{Login: "octocat"}}}
+			calls := 0
+			profileOf := func(string) (userProfile, error) {
+				calls++
+				return userProfile{Name: "The Octocat"}, tc.fetchErr
+			}
+			// Act
+			err := enrichActors(events, profileOf)
+			// Assert
+			if tc.wantErr {
+				assertNotNil(t, err)
+				return
+			}
+			assertNoError(t, err)
+			assertEqual(t, calls, 1)
+			assertEqual(t, events[1].Actor.displayName(), tc.wantName)
+		})
+	}
+}
+
+func TestUnitActorAvatar(t *testing.T) {
+	// Arrange
+	a := actor{Login: "octocat", AvatarURL: "https://event/avatar"}
+	// Act
+	before := a.avatar()
+	a.Profile = &userProfile{AvatarURL: "https://profile/avatar"}
+	after := a.avatar()
+	// Assert
+	assertEqual(t, before, "https://event/avatar")
+	assertEqual(t, after, "https://profile/avatar")
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// verification is the signature check GitHub ran on a commit, with GPG,
+// SSH or S/MIME signatures and gitsign (sigstore) ones alike.
+type verification struct {
+	Verified bool   `json:"verified"`
+	Reason   string `json:"reason"`
+}
+
+// verification returns the signature status of a commit. Commits never
+// change, so the cache entry is only refreshed with the cache TTL.
+func (f *repoFetcher) verification(name, sha string) (verification, error) {
+	return cached(f.cache, "commits/"+name+"/"+sha, func() (verification, error) {
+		u, err := f.repoURL(name, "commits", sha)
+		if err != nil {
+			return verification{}, err
+		}
+		var details struct {
+			Commit struct {
+				Verification verification `json:"verification"`
+			} `json:"commit"`
+		}
+		if err := fetchJSON(f.hc, u, &details); err != nil {
+			return verification{}, fmt.Errorf("fetch commit %.7s of %s: %w", sha, name, err)
+		}
+		return details.Commit.Verification, nil
+	})
+}
+
+// verifyCommits attaches the signature status to the commits of the push
+// events. Commits that no longer exist, e.g. after a force push or in a
+// deleted repository, are left without it.
+func verifyCommits(events []ghEvent, verificationOf func(repo, sha string) (verification, error)) error {
+	for i := range events {
+		if events[i].Type != "PushEvent" {
+			continue
+		}
+		commits := events[i].Payload.Commits
+		for j := range commits {
+			if commits[j].Verification != nil {
+				continue
+			}
+			v, err := verificationOf(events[i].Repo.Name, commits[j].SHA)
+			switch {
+			case err == nil:
+				commits[j].Verification = &v
+			case !isStatus(err, http.StatusNotFound) && !isStatus(err, http.StatusUnprocessableEntity):
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// signedCommits counts the verified commits among the checked ones.
+func signedCommits(events []ghEvent) (signed, checked int) {
+	for _, ev := range events {
+		for _, c := range ev.Payload.Commits {
+			if c.Verification == nil {
+				continue
+			}
+			checked++
+			signed += boolToInt(c.Verification.Verified)
+		}
+	}
+	return signed, checked
+}
+
+// verificationAnnotation formats the signature status of a push for the
+// text output, e.g. " (2/3 verified)", or nothing when it was not checked.
+func verificationAnnotation(ev ghEvent) string {
+	signed, checked := signedCommits([]ghEvent{ev})
+	if checked == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (%d/%d verified)", signed, checked)
+}
+
+// signedView prints the share of verified commits per repository and
+// overall.
+func signedView(w io.Writer, events []ghEvent, _ statsOptions) error {
+	fetcher, err := newRepoFetcher()
+	if err != nil {
+		return err
+	}
+	if err := verifyCommits(events, fetcher.verification); err != nil {
+		return err
+	}
+	return writeSigned(w, events)
+}
+
+// writeSigned prints the verified commit counts and percentages, by
+// repository then in total.
+func writeSigned(w io.Writer, events []ghEvent) error {
+	byRepo := map[string][]ghEvent{}
+	for _, ev := range events {
+		byRepo[ev.Repo.Name] = append(byRepo[ev.Repo.Name], ev)
+	}
+	line := func(name string, signed, checked int) error {
+		_, err := fmt.Fprintf(w, "%-30s %5d/%-5d %5.1f%%\n", name, signed, checked, float64(signed)*100/float64(checked))
+		return err
+	}
+	for _, name := range sortedKeys(byRepo) {
+		if signed, checked := signedCommits(byRepo[name]); checked > 0 {
+			if err := line(name, signed, checked); err != nil {
+				return err
+			}
+		}
+	}
+	signed, checked := signedCommits(events)
+	if checked == 0 {
+		_, err := fmt.Fprintln(w, "no commits to verify")
+		return err
+	}
+	return line("total", signed, checked)
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+)
+
+func TestUnitVerifyCommits(t *testing.T) {
+	// Arrange
+	events := []ghEvent{
+		{Type: "PushEvent", Repo: repo{Name: "octo/a"}, Payload: payload{Commits: []commit{
+			{SHA: "signed"}, {SHA: "unsigned"}, {SHA: "gone"},
+		}}},
+		{Type: "WatchEvent", Repo: repo{Name: "octo/b"}},
+	}
+	var calls int
+	verificationOf := func(_, sha string) (verification, error) {
+		calls++
+		switch sha {
+		case "signed":
+			return verification{Verified: true, Reason: "valid"}, nil
+		case "gone":
+			return verification{}, &apiError{StatusCode: http.StatusUnprocessableEntity}
+		}
+		return verification{Reason: "unsigned"}, nil
+	}
+	// Act
+	err := verifyCommits(events, verificationOf)
+	// Assert
+	assertNoError(t, err)
+	assertEqual(t, calls, 3)
+	commits := events[0].Payload.Commits
+	assertEqual(t, *commits[0].Verification, verification{Verified: true, Reason: "valid"})
+	assertEqual(t, commits[1].Verification.Verified, false)
+	assertEqual(t, commits[2].Verification == nil, true)
+	assertEqual(t, verificationAnnotation(events[0]), " (1/2 verified)")
+	assertEqual(t, verificationAnnotation(events[1]), "")
+}
+
+func TestUnitVerifyCommitsError(t *testing.T) {
+	// Arrange
+	events := []ghEvent{{Type: "PushEvent", Payload: payload{Commits: []commit{{SHA: "a"}}}}}
+	verificationOf := func(string, string) (verification, error) {
+		return verification{}, &apiError{StatusCode: http.StatusInternalServerError}
+	}
+	// Act
+	err := verifyCommits(events, verificationOf)
+	// Assert
+	assertNotNil(t, err)
+}
+
+func TestUnitWriteSigned(t *testing.T) {
+	verified, unverified := &verification{Verified: true}, &verification{}
+	testCases := []struct {
+		name   string
+		events []ghEvent
+		want   string
+	}{
+		{
+			name: "per repository and total",
+			events: []ghEvent{
+				{Repo: repo{Name: "octo/b"}, Payload: payload{Commits: []commit{
+					{Verification: verified}, {Verification: unverified},
+				}}},
+				{Repo: repo{Name: "octo/a"}, Payload: payload{Commits: []commit{{Verification: verified}}}},
+				{Repo: repo{Name: "octo/c"}, Payload: payload{Commits: []commit{{}}}},
+			},
+			want: "octo/a                             1/1     100.0%\n" +
+				"octo/b                             1/2      50.0%\n" +
+				"total                              2/3      66.7%\n",
+		},
+		{name: "nothing checked", events: []ghEvent{{Repo: repo{Name: "octo/a"}}}, want: "no commits to verify\n"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			var buf bytes.Buffer
+			// Act
+			err := writeSigned(&buf, tc.events)
+			// Assert
+			assertNoError(t, err)
+			assertEqual(t, buf.String(), tc.want)
+		})
+	}
+}
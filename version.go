@@ -0,0 +1,121 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"runtime/debug"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// buildInfo describes the running binary and the API version it requests.
+type buildInfo struct {
+	Version    string `json:"version"`
+	Commit     string `json:"commit,omitempty"`
+	Modified   bool   `json:"modified,omitempty"`
+	BuiltAt    string `json:"built_at,omitempty"`
+	GoVersion  string `json:"go_version"`
+	APIVersion string `json:"api_version"`
+}
+
+// defaultAPIVersion is the REST API version sent as X-GitHub-Api-Version.
+// Pinning it keeps the responses stable when GitHub releases a new one;
+// api_version moves to another version without a rebuild.
+const defaultAPIVersion = "2022-11-28"
+
+// apiVersion returns the REST API version to request.
+func apiVersion() string {
+	return cmp.Or(viper.GetString("api_version"), defaultAPIVersion)
+}
+
+// checkAPIVersion fails when api_version is not a date, the format of the
+// REST API versions.
+func checkAPIVersion(v string) error {
+	if _, err := time.Parse(time.DateOnly, v); err != nil {
+		return fmt.Errorf("invalid api_version %q: expected a date such as %s", v, defaultAPIVersion)
+	}
+	return nil
+}
+
+// runVersion prints the version, commit and Go version of the binary, and
+// the API version it requests. Only the configuration file is read: no
+// token is needed.
+func runVersion(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("version", flag.ContinueOnError)
+	output := flags.String("output", "text", "output format: text or json")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		return errors.New("usage: go-github-activity version [flags]")
+	}
+	if *output != "text" && *output != "json" {
+		return fmt.Errorf("unknown output format %q", *output)
+	}
+	if err := initialize(&defaultUserHome{}, "config.yaml"); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if err := applyProfile(); err != nil {
+		return err
+	}
+	bi, _ := debug.ReadBuildInfo()
+	info := newBuildInfo(bi, version, apiVersion())
+	if *output == "json" {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(info)
+	}
+	return writeBuildInfo(stdout, info)
+}
+
+// newBuildInfo reads the build settings of the binary. The version set at
+// release time wins over the module version, known when the binary was
+// installed with go install.
+func newBuildInfo(bi *debug.BuildInfo, released, api string) buildInfo {
+	info := buildInfo{Version: released, APIVersion: api}
+	if bi == nil {
+		return info
+	}
+	info.GoVersion = bi.GoVersion
+	if released == "dev" && bi.Main.Version != "" && bi.Main.Version != "(devel)" {
+		info.Version = bi.Main.Version
+	}
+	for _, s := range bi.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			info.Commit = s.Value
+		case "vcs.time":
+			info.BuiltAt = s.Value
+		case "vcs.modified":
+			info.Modified = s.Value == "true"
+		}
+	}
+	return info
+}
+
+// writeBuildInfo prints one line per field, omitting the unknown ones.
+func writeBuildInfo(w io.Writer, info buildInfo) error {
+	commit := info.Commit
+	if commit != "" && info.Modified {
+		commit += " (modified)"
+	}
+	lines := [][2]string{
+		{"version", info.Version}, {"commit", commit}, {"built", info.BuiltAt},
+		{"go", info.GoVersion}, {"api version", info.APIVersion},
+	}
+	for _, l := range lines {
+		if l[1] == "" {
+			continue
+		}
+		if _, err := fmt.Fprintf(w, "%-12s %s\n", l[0]+":", l[1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
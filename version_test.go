@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"runtime/debug"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestUnitNewBuildInfo(t *testing.T) {
+	testCases := []struct {
+		name     string
+		bi       *debug.BuildInfo
+		released string
+		want     buildInfo
+	}{
+		{
+			name:     "no build info",
+			released: "dev",
+			want:     buildInfo{Version: "dev", APIVersion: defaultAPIVersion},
+		},
+		{
+			name: "released",
+			bi: &debug.BuildInfo{GoVersion: "go1.23.6", Main: debug.Module{Version: "(devel)"}, Settings: []debug.BuildSetting{
+				{Key: "vcs.revision", Value: "abc123"}, {Key: "vcs.time", Value: "2025-02-01T10:00:00Z"},
+				{Key: "vcs.modified", Value: "false"},
+			}},
+			released: "1.4.0",
+			want: buildInfo{
+				Version: "1.4.0", Commit: "abc123", BuiltAt: "2025-02-01T10:00:00Z", GoVersion: "go1.23.6",
+				APIVersion: defaultAPIVersion,
+			},
+		},
+		{
+			name:     "go install",
+			bi:       &debug.BuildInfo{GoVersion: "go1.23.6", Main: debug.Module{Version: "v1.4.0"}},
+			released: "dev",
+			want:     buildInfo{Version: "v1.4.0", GoVersion: "go1.23.6", APIVersion: defaultAPIVersion},
+		},
+		{
+			name: "local build",
+			bi: &debug.BuildInfo{GoVersion: "go1.23.6", Main: debug.Module{Version: "(devel)"}, Settings: []debug.BuildSetting{
+				{Key: "vcs.revision", Value: "abc123"}, {Key: "vcs.modified", Value: "true"},
+			}},
+			released: "dev",
+			want: buildInfo{
+				Version: "dev", Commit: "abc123", Modified: true, GoVersion: "go1.23.6", APIVersion: defaultAPIVersion,
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			got := newBuildInfo(tc.bi, tc.released, defaultAPIVersion)
+			// Assert
+			assertEqual(t, got, tc.want)
+		})
+	}
+}
+
+func TestUnitWriteBuildInfo(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+	info := buildInfo{Version: "dev", Commit: "abc123", Modified: true, GoVersion: "go1.23.6", APIVersion: "2022-11-28"}
+	// Act
+	err := writeBuildInfo(&buf, info)
+	// Assert
+	assertNoError(t, err)
+	assertEqual(t, buf.String(), "version:     dev\ncommit:      abc123 (modified)\ngo:          go1.23.6\n"+
+		"api version: 2022-11-28\n")
+}
+
+func TestUnitCheckAPIVersion(t *testing.T) {
+	testCases := []struct {
+		version string
+		wantErr bool
+	}{
+		{version: "2022-11-28"},
+		{version: "2026-03-10"},
+		{version: "v3", wantErr: true},
+		{version: "2022-13-01", wantErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.version, func(t *testing.T) {
+			// Act
+			err := checkAPIVersion(tc.version)
+			// Assert
+			if tc.wantErr {
+				assertNotNil(t, err)
+			} else {
+				assertNoError(t, err)
+			}
+		})
+	}
+}
+
+func TestUnitClientAPIVersionHeader(t *testing.T) {
+	testCases := []struct {
+		name       string
+		configured string
+		want       string
+	}{
+		{name: "pinned", want: defaultAPIVersion},
+		{name: "configured", configured: "2026-03-10", want: "2026-03-10"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			t.Cleanup(viper.Reset)
+			if tc.configured != "" {
+				viper.Set("api_version", tc.configured)
+			}
+			var got string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.Header.Get("X-GitHub-Api-Version")
+				w.Write([]byte("[]"))
+			}))
+			t.Cleanup(srv.Close)
+			// Act
+			_, err := fetchGitHubResponse(newClient(""), srv.URL)
+			// Assert
+			assertNoError(t, err)
+			assertEqual(t, got, tc.want)
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"sort"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// watchSettings are the settings of a polling loop that a reload of the
+// configuration replaces.
+type watchSettings struct {
+	interval time.Duration
+	targets  map[string]notifier
+	rules    []routeRule
+}
+
+// poller remembers seen events to report only new ones.
+type poller struct {
+	fetch func() ([]ghEvent, error)
+	seen  map[string]bool
+}
+
+// poll returns the events not seen by a previous poll, oldest first even
+// when the fetched events are out of order. The first poll only records the
+// current events.
+func (p *poller) poll() ([]ghEvent, error) {
+	events, err := p.fetch()
+	if err != nil {
+		return nil, err
+	}
+	first := p.seen == nil
+	if first {
+		p.seen = make(map[string]bool, len(events))
+	}
+	var fresh []ghEvent
+	for i := len(events) - 1; i >= 0; i-- {
+		if p.seen[events[i].ID] {
+			continue
+		}
+		p.seen[events[i].ID] = true
+		if !first {
+			fresh = append(fresh, events[i])
+		}
+	}
+	sort.SliceStable(fresh, func(i, j int) bool { return fresh[i].CreatedAt.Before(fresh[j].CreatedAt) })
+	return fresh, nil
+}
+
+// notification formats an event for chat and push backends.
+func notification(ev ghEvent) string {
+	return fmt.Sprintf("%s: %s", ev.Actor.Login, summarize(catalogs[defaultLang], ev))
+}
+
+// runWatch polls the user's events and delivers new ones to the notifiers
+// selected by the routing rules until interrupted.
+func runWatch(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("watch", flag.ContinueOnError)
+	interval := flags.Duration("interval", 0, "delay between two polls, overriding watch.interval (default 1m)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: go-github-activity watch [flags] <username>")
+	}
+	if err := loadConfig(); err != nil {
+		return err
+	}
+	user := flags.Arg(0)
+	p := &poller{fetch: func() ([]ghEvent, error) { return fetchUserEvents(user) }}
+	return watchLive(p, func() (watchSettings, error) {
+		return loadWatchSettings("rules", cmp.Or(*interval, viper.GetDuration("watch.interval")))
+	}, stdout)
+}
+
+// loadWatchSettings checks the interval and builds the notifiers and the
+// routing rules of a rules config list, e.g. rules.
+func loadWatchSettings(rulesKey string, interval time.Duration) (watchSettings, error) {
+	if interval < time.Second {
+		return watchSettings{}, fmt.Errorf("invalid interval: %s", interval)
+	}
+	targets, err := loadNotifiers()
+	if err != nil {
+		return watchSettings{}, err
+	}
+	rules, err := loadRules(rulesKey, targets)
+	if err != nil {
+		return watchSettings{}, err
+	}
+	return watchSettings{interval: interval, targets: targets, rules: rules}, nil
+}
+
+// watchLive polls with the settings load returns, and loads them again
+// whenever the configuration file changes. A configuration they do not load
+// from is rejected and the previous one kept.
+func watchLive(p *poller, load func() (watchSettings, error), stdout io.Writer) error {
+	s, err := load()
+	if err != nil {
+		return err
+	}
+	r, err := newConfigReloader(reloadWatch(&s, load))
+	if err != nil {
+		return err
+	}
+	defer r.close()
+	return pollAndNotify(p, &s, r, stdout)
+}
+
+// reloadWatch returns the reload of the settings s with load. The notices
+// the replaced notifiers still hold are handed over to the new ones rather
+// than dropped with them.
+func reloadWatch(s *watchSettings, load func() (watchSettings, error)) func() error {
+	return func() error {
+		next, err := load()
+		if err != nil {
+			return err
+		}
+		handOverPending(context.Background(), s.targets, next.targets)
+		*s = next
+		return nil
+	}
+}
+
+// pollAndNotify polls events every interval and delivers new ones to the
+// notifiers selected by the routing rules until interrupted. With a
+// reloader, the configuration is applied again between two polls when its
+// file changes.
+func pollAndNotify(p *poller, s *watchSettings, r *configReloader, stdout io.Writer) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	return pollLoop(ctx, p, s, r, systemClock{}, stdout)
+}
+
+// pollLoop runs the polls of pollAndNotify on the clock until ctx ends.
+func pollLoop(ctx context.Context, p *poller, s *watchSettings, r *configReloader, c clock, stdout io.Writer) error {
+	var changes <-chan struct{}
+	if r != nil {
+		changes = r.changes
+	}
+	for {
+		next := c.After(s.interval)
+		fresh, err := p.poll()
+		if err != nil {
+			log.Printf("poll events: %v", err)
+		}
+		for _, ev := range fresh {
+			msg := notification(ev)
+			fmt.Fprintln(stdout, msg)
+			for _, name := range route(s.rules, s.targets, ev) {
+				if err := s.targets[name].notify(ctx, notice{Message: msg, Events: []ghEvent{ev}}); err != nil {
+					log.Printf("notify %s: %v", name, err)
+				}
+			}
+		}
+		for name, n := range s.targets {
+			if fl, ok := n.(flusher); ok {
+				if err := fl.flush(ctx); err != nil {
+					log.Printf("notify %s: %v", name, err)
+				}
+			}
+		}
+	wait:
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-next:
+				break wait
+			case <-changes:
+				if r.logReload() {
+					next = c.After(s.interval)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestUnitPoller(t *testing.T) {
+	// Arrange
+	pages := [][]ghEvent{
+		{{ID: "2"}, {ID: "1"}},
+		{{ID: "4"}, {ID: "3"}, {ID: "2"}},
+		{{ID: "4"}},
+	}
+	calls := 0
+	p := &poller{fetch: func() ([]ghEvent, error) {
+		defer func() { calls++ }()
+		if calls == len(pages) {
+			return nil, errors.New("boom")
+		}
+		return pages[calls], nil
+	}}
+	// Act
+	first, err1 := p.poll()
+	second, err2 := p.poll()
+	third, err3 := p.poll()
+	_, err4 := p.poll()
+	// Assert
+	assertNoError(t, err1)
+	assertNoError(t, err2)
+	assertNoError(t, err3)
+	assertNotNil(t, err4)
+	assertEqual(t, len(first), 0)
+	assertEqual(t, len(second), 2)
+	assertEqual(t, second[0].ID, "3")
+	assertEqual(t, second[1].ID, "4")
+	assertEqual(t, len(third), 0)
+}
+
+func TestUnitPollLoop(t *testing.T) {
+	// Arrange
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	clk := &fakeClock{now: time.Date(2025, 3, 3, 23, 58, 0, 0, time.UTC), limit: 3, cancel: cancel}
+	star := func(id string) ghEvent {
+		return ghEvent{ID: id, Type: "WatchEvent", Actor: actor{Login: "octo"}, Repo: repo{Name: "octo/r" + id}}
+	}
+	pages := [][]ghEvent{{star("1")}, {star("3"), star("2"), star("1")}, {star("3")}}
+	polls := 0
+	p := &poller{fetch: func() ([]ghEvent, error) {
+		polls++
+		return pages[polls-1], nil
+	}}
+	var buf bytes.Buffer
+	// Act
+	err := pollLoop(ctx, p, &watchSettings{interval: time.Minute}, nil, clk, &buf)
+	// Assert
+	assertNoError(t, err)
+	assertEqual(t, polls, 3)
+	assertEqual(t, fmt.Sprint(clk.waits), "[1m0s 1m0s 1m0s]")
+	assertEqual(t, buf.String(), notification(star("2"))+"\n"+notification(star("3"))+"\n")
+}
+
+func TestUnitNotification(t *testing.T) {
+	// Arrange
+	ev := ghEvent{Type: "WatchEvent", Actor: actor{Login: "octocat"}, Repo: repo{Name: "octo/repo"}}
+	// Act
+	got := notification(ev)
+	// Assert
+	assertEqual(t, got, "octocat: Starred octo/repo")
+}
+
+func TestUnitReloadWatchPending(t *testing.T) {
+	start := time.Date(2025, 3, 3, 12, 0, 0, 0, time.Local)
+	push := notice{Message: "octo: pushed", Events: []ghEvent{{Type: "PushEvent"}}}
+	testCases := []struct {
+		name string
+		// replacement is the config of the notifier replacing chat, nil
+		// when the reload removes it.
+		replacement *notifierConfig
+		wantOld     int
+		wantNew     int
+	}{
+		{name: "still batching since the first notice", replacement: &notifierConfig{Batch: 15 * time.Minute}, wantNew: 1},
+		{name: "no longer batching", replacement: &notifierConfig{}, wantNew: 1},
+		{name: "removed", wantOld: 1},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			clk := &fakeClock{now: start}
+			oldBackend, newBackend := &recordingNotifier{}, &recordingNotifier{}
+			old, err := newBatchingNotifier(oldBackend, notifierConfig{Batch: 15 * time.Minute})
+			assertNoError(t, err)
+			old.clock = clk
+			assertNoError(t, old.notify(context.Background(), push))
+			clk.advance(10 * time.Minute)
+			targets := map[string]notifier{}
+			if tc.replacement != nil {
+				targets["chat"] = newBackend
+				if tc.replacement.Batch > 0 {
+					b, err := newBatchingNotifier(newBackend, *tc.replacement)
+					assertNoError(t, err)
+					b.clock = clk
+					targets["chat"] = b
+				}
+			}
+			s := watchSettings{interval: time.Minute, targets: map[string]notifier{"chat": old}}
+			load := func() (watchSettings, error) { return watchSettings{interval: time.Minute, targets: targets}, nil }
+			// Act
+			err = reloadWatch(&s, load)()
+			assertNoError(t, err)
+			clk.advance(5 * time.Minute)
+			if fl, ok := s.targets["chat"].(flusher); ok {
+				assertNoError(t, fl.flush(context.Background()))
+			}
+			// Assert
+			assertEqual(t, len(oldBackend.got), tc.wantOld)
+			assertEqual(t, len(newBackend.got), tc.wantNew)
+		})
+	}
+}
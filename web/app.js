@@ -0,0 +1,83 @@
+// Dashboard for the JSON API of go-github-activity serve.
+const $ = (id) => document.getElementById(id);
+const weeks = 13;
+const refreshMs = 60000;
+let events = [];
+
+$("key").value = localStorage.getItem("apiKey") || "";
+$("user").value = new URLSearchParams(location.search).get("user") || localStorage.getItem("user") || "";
+
+async function load() {
+  const user = $("user").value.trim();
+  if (!user) return;
+  localStorage.setItem("user", user);
+  localStorage.setItem("apiKey", $("key").value);
+  const headers = $("key").value ? { "X-API-Key": $("key").value } : {};
+  $("status").textContent = "Loading…";
+  try {
+    const res = await fetch(`users/${encodeURIComponent(user)}/activity`, { headers });
+    const body = await res.json();
+    if (!res.ok) throw new Error(body.error || res.statusText);
+    events = body;
+    $("status").textContent = `${events.length} events, updated ${new Date().toLocaleTimeString()}`;
+    fillRepos();
+    draw();
+  } catch (err) {
+    $("status").textContent = `Error: ${err.message}`;
+  }
+}
+
+function fillRepos() {
+  const select = $("repo");
+  const current = select.value;
+  const repos = [...new Set(events.map((ev) => ev.repo.name))].sort();
+  select.replaceChildren(new Option("All repositories", ""), ...repos.map((r) => new Option(r, r)));
+  select.value = repos.includes(current) ? current : "";
+}
+
+function draw() {
+  const repo = $("repo").value;
+  const shown = events.filter((ev) => !repo || ev.repo.name === repo);
+  drawTimeline(shown);
+  drawHeatmap(shown);
+}
+
+function drawTimeline(shown) {
+  $("timeline").replaceChildren(...shown.map((ev) => {
+    const li = document.createElement("li");
+    const time = document.createElement("time");
+    time.dateTime = ev.created_at;
+    time.textContent = new Date(ev.created_at).toLocaleString();
+    const link = document.createElement("a");
+    link.href = `https://github.com/${ev.repo.name}`;
+    link.textContent = ev.summary;
+    li.append(time, link);
+    return li;
+  }));
+}
+
+function drawHeatmap(shown) {
+  const day = (d) => new Date(d.getFullYear(), d.getMonth(), d.getDate()).getTime();
+  const counts = new Map();
+  for (const ev of shown) {
+    const k = day(new Date(ev.created_at));
+    counts.set(k, (counts.get(k) || 0) + 1);
+  }
+  const max = Math.max(1, ...counts.values());
+  const today = new Date();
+  const start = new Date(today.getFullYear(), today.getMonth(), today.getDate() - today.getDay() - 7 * (weeks - 1));
+  const cells = [];
+  for (let d = new Date(start); d <= today; d.setDate(d.getDate() + 1)) {
+    const n = counts.get(day(d)) || 0;
+    const cell = document.createElement("div");
+    if (n > 0) cell.className = `l${Math.ceil((n / max) * 4)}`;
+    cell.title = `${d.toDateString()}: ${n} events`;
+    cells.push(cell);
+  }
+  $("heatmap").replaceChildren(...cells);
+}
+
+$("controls").addEventListener("submit", (e) => { e.preventDefault(); load(); });
+$("repo").addEventListener("change", draw);
+setInterval(() => { if ($("refresh").checked) load(); }, refreshMs);
+load();
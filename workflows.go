@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+type (
+	// actionsProvider reads the completed GitHub Actions workflow runs of
+	// watched repositories, or those triggered by the user in their latest
+	// pushed repositories.
+	actionsProvider struct {
+		hc    *client
+		base  string
+		repos []string
+	}
+	// workflowRun is the workflow run of a WorkflowRunEvent.
+	workflowRun struct {
+		ID         int64     `json:"id"`
+		Name       string    `json:"name"`
+		Status     string    `json:"status"`
+		Conclusion string    `json:"conclusion"`
+		HTMLURL    string    `json:"html_url"`
+		HeadBranch string    `json:"head_branch"`
+		HeadSHA    string    `json:"head_sha"`
+		UpdatedAt  time.Time `json:"updated_at"`
+		Actor      *actor    `json:"triggering_actor,omitempty"`
+		Repository *struct {
+			FullName string `json:"full_name"`
+			HTMLURL  string `json:"html_url"`
+			Private  bool   `json:"private"`
+		} `json:"repository,omitempty"`
+	}
+)
+
+// actionsRecentRepos is the number of latest pushed repositories of the
+// user scanned when no repository is watched.
+const actionsRecentRepos = 10
+
+func newActionsProvider(hc *client, base string, repos []string) *actionsProvider {
+	return &actionsProvider{hc: hc, base: strings.TrimSuffix(base, "/"), repos: repos}
+}
+
+func (p *actionsProvider) name() string { return "github-actions" }
+
+// events returns a WorkflowRunEvent per completed run, newest first.
+func (p *actionsProvider) events(login string) ([]ghEvent, error) {
+	repos, query := p.repos, url.Values{"status": {"completed"}, "per_page": {"50"}}
+	if len(repos) == 0 {
+		var err error
+		if repos, err = recentRepos(p.hc, p.base, login, actionsRecentRepos); err != nil {
+			return nil, err
+		}
+		query.Set("actor", login)
+	}
+	var events []ghEvent
+	for _, name := range repos {
+		var page struct {
+			WorkflowRuns []workflowRun `json:"workflow_runs"`
+		}
+		u := fmt.Sprintf("%s/repos/%s/actions/runs?%s", p.base, strings.Trim(name, "/"), query.Encode())
+		if err := fetchJSON(p.hc, u, &page); err != nil {
+			return nil, fmt.Errorf("fetch workflow runs of %s: %w", name, err)
+		}
+		for _, run := range page.WorkflowRuns {
+			events = append(events, run.event(name))
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].CreatedAt.After(events[j].CreatedAt) })
+	return events, nil
+}
+
+// event returns the completion of the run as a WorkflowRunEvent.
+func (run workflowRun) event(name string) ghEvent {
+	ev := ghEvent{
+		ID: fmt.Sprintf("workflow-run-%d", run.ID), Type: "WorkflowRunEvent", Repo: repo{Name: name},
+		Payload: payload{Action: "completed", Ref: run.HeadBranch}, CreatedAt: run.UpdatedAt,
+	}
+	if run.Actor != nil {
+		ev.Actor = *run.Actor
+	}
+	if run.Repository != nil {
+		ev.Repo = repo{Name: run.Repository.FullName, URL: run.Repository.HTMLURL}
+		ev.Public = !run.Repository.Private
+	}
+	run.Actor, run.Repository = nil, nil
+	ev.Payload.WorkflowRun = &run
+	return ev
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestUnitActionsProviderEvents(t *testing.T) {
+	runs := `{"workflow_runs":[
+		{"id":2,"name":"CI","status":"completed","conclusion":"failure","head_branch":"main",
+		 "html_url":"https://github.com/%[1]s/actions/runs/2","updated_at":"2025-03-02T00:00:00Z",
+		 "triggering_actor":{"login":"octocat"},"repository":{"full_name":"%[1]s","private":false}},
+		{"id":1,"name":"CI","status":"completed","conclusion":"success","head_branch":"main",
+		 "updated_at":"2025-03-01T00:00:00Z","triggering_actor":{"login":"mona"},
+		 "repository":{"full_name":"%[1]s","private":true}}
+	]}`
+	testCases := []struct {
+		name      string
+		repos     []string
+		wantActor string
+		wantRepos []string
+	}{
+		{name: "watched repositories", repos: []string{"octo/api"}, wantRepos: []string{"octo/api", "octo/api"}},
+		{name: "latest pushed repositories", wantActor: "octocat", wantRepos: []string{"octocat/hello", "octocat/hello"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/users/octocat/repos":
+					assertEqual(t, r.URL.Query().Get("sort"), "pushed")
+					fmt.Fprint(w, `[{"full_name":"octocat/hello"}]`)
+				case "/repos/" + tc.wantRepos[0] + "/actions/runs":
+					assertEqual(t, r.URL.Query().Get("status"), "completed")
+					assertEqual(t, r.URL.Query().Get("actor"), tc.wantActor)
+					fmt.Fprintf(w, runs, tc.wantRepos[0])
+				default:
+					t.Errorf("unexpected request %s", r.URL.Path)
+					http.NotFound(w, r)
+				}
+			}))
+			defer srv.Close()
+			p := newActionsProvider(newClient(""), srv.URL+"/", tc.repos)
+			// Act
+			events, err := p.events("octocat")
+			// Assert
+			assertNoError(t, err)
+			assertEqual(t, len(events), len(tc.wantRepos))
+			failed := events[0]
+			assertEqual(t, failed.ID, "workflow-run-2")
+			assertEqual(t, failed.Type, "WorkflowRunEvent")
+			assertEqual(t, failed.Actor.Login, "octocat")
+			assertEqual(t, failed.Repo.Name, tc.wantRepos[0])
+			assertEqual(t, failed.Public, true)
+			assertEqual(t, failed.Payload.WorkflowRun.Conclusion, "failure")
+			assertEqual(t, metrics["failed-runs"](failed), 1)
+			assertEqual(t, events[1].Public, false)
+			assertEqual(t, metrics["failed-runs"](events[1]), 0)
+		})
+	}
+}
+
+func TestUnitSummarizeWorkflowRuns(t *testing.T) {
+	testCases := []struct {
+		name       string
+		conclusion string
+		want       string
+	}{
+		{name: "success", conclusion: "success", want: "Workflow CI succeeded in octo/api"},
+		{name: "failure", conclusion: "failure", want: "Workflow CI failed in octo/api"},
+		{name: "cancelled", conclusion: "cancelled", want: "Workflow CI finished in octo/api"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			ev := ghEvent{Type: "WorkflowRunEvent", Repo: repo{Name: "octo/api"}, Payload: payload{
+				WorkflowRun: &workflowRun{Name: "CI", Conclusion: tc.conclusion},
+			}}
+			// Act
+			got := summarize(lookupCatalog("en"), ev)
+			// Assert
+			assertEqual(t, got, tc.want)
+		})
+	}
+}
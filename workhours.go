@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// workPattern buckets activity by local hour and weekday.
+type workPattern struct {
+	byHour    [24]int
+	byWeekday [7]int
+	total     int
+	offHours  int
+	weekend   int
+	outside   int
+}
+
+// parseWorkHours parses a "start-end" range of hours, e.g. "9-18".
+func parseWorkHours(s string) (int, int, error) {
+	from, to, ok := strings.Cut(s, "-")
+	start, errStart := strconv.Atoi(strings.TrimSpace(from))
+	end, errEnd := strconv.Atoi(strings.TrimSpace(to))
+	if !ok || errStart != nil || errEnd != nil || start < 0 || end > 24 || start >= end {
+		return 0, 0, fmt.Errorf("invalid working hours %q, want start-end such as 9-18", s)
+	}
+	return start, end, nil
+}
+
+// analyzeWorkHours buckets events in loc; activity before start or from end
+// onwards is off-hours, and off-hours or weekend activity is outside work.
+func analyzeWorkHours(events []ghEvent, loc *time.Location, start, end int) workPattern {
+	var p workPattern
+	for _, ev := range events {
+		t := ev.CreatedAt.In(loc)
+		p.total++
+		p.byHour[t.Hour()]++
+		p.byWeekday[t.Weekday()]++
+		off := t.Hour() < start || t.Hour() >= end
+		weekend := t.Weekday() == time.Saturday || t.Weekday() == time.Sunday
+		p.offHours += boolToInt(off)
+		p.weekend += boolToInt(weekend)
+		p.outside += boolToInt(off || weekend)
+	}
+	return p
+}
+
+// share returns n as a percentage of the total.
+func (p workPattern) share(n int) float64 {
+	if p.total == 0 {
+		return 0
+	}
+	return float64(n) * 100 / float64(p.total)
+}
+
+// workHoursView prints hour and weekday histograms, the off-hours and
+// weekend shares, and a burnout warning above the opt-in threshold.
+func workHoursView(w io.Writer, events []ghEvent, opts statsOptions) error {
+	start, end, err := parseWorkHours(opts.workHours)
+	if err != nil {
+		return err
+	}
+	p := analyzeWorkHours(events, time.Local, start, end)
+	var b strings.Builder
+	b.WriteString("By hour:\n")
+	for h, n := range p.byHour {
+		fmt.Fprintf(&b, "  %02d  %s %d\n", h, bar(n, p.total), n)
+	}
+	b.WriteString("By weekday:\n")
+	for i := 1; i <= 7; i++ {
+		day := time.Weekday(i % 7) // Monday first
+		fmt.Fprintf(&b, "  %s %s %d\n", day.String()[:3], bar(p.byWeekday[day], p.total), p.byWeekday[day])
+	}
+	fmt.Fprintf(&b, "Outside %d–%dh: %.0f%%\n", start, end, p.share(p.offHours))
+	fmt.Fprintf(&b, "Weekend: %.0f%%\n", p.share(p.weekend))
+	if opts.burnout > 0 && p.share(p.outside) > opts.burnout {
+		fmt.Fprintf(&b, "Burnout warning: %.0f%% of your activity happened outside working time (threshold %.0f%%)\n",
+			p.share(p.outside), opts.burnout)
+	}
+	_, err = io.WriteString(w, b.String())
+	return err
+}
+
+// bar draws n relative to total as a bar of up to 30 cells.
+func bar(n, total int) string {
+	const width = 30
+	if total == 0 {
+		return ""
+	}
+	return strings.Repeat("█", n*width/total)
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUnitParseWorkHours(t *testing.T) {
+	testCases := []struct {
+		name      string
+		s         string
+		wantStart int
+		wantEnd   int
+		wantErr   bool
+	}{
+		{name: "office hours", s: "9-18", wantStart: 9, wantEnd: 18},
+		{name: "spaces", s: " 8 - 17 ", wantStart: 8, wantEnd: 17},
+		{name: "missing dash", s: "9", wantErr: true},
+		{name: "reversed", s: "18-9", wantErr: true},
+		{name: "out of range", s: "0-25", wantErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			start, end, err := parseWorkHours(tc.s)
+			// Assert
+			if tc.wantErr {
+				assertNotNil(t, err)
+				return
+			}
+			assertNoError(t, err)
+			assertEqual(t, start, tc.wantStart)
+			assertEqual(t, end, tc.wantEnd)
+		})
+	}
+}
+
+func TestUnitAnalyzeWorkHours(t *testing.T) {
+	// Arrange
+	monday := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	events := []ghEvent{
+		{CreatedAt: monday.Add(10 * time.Hour)},                  // working time
+		{CreatedAt: monday.Add(18 * time.Hour)},                  // evening
+		{CreatedAt: monday.Add(7 * time.Hour)},                   // early morning
+		{CreatedAt: monday.AddDate(0, 0, 5).Add(11 * time.Hour)}, // Saturday daytime
+	}
+	// Act
+	p := analyzeWorkHours(events, time.UTC, 9, 18)
+	// Assert
+	assertEqual(t, p.total, 4)
+	assertEqual(t, p.offHours, 2)
+	assertEqual(t, p.weekend, 1)
+	assertEqual(t, p.outside, 3)
+	assertEqual(t, p.byHour[10], 1)
+	assertEqual(t, p.byWeekday[time.Saturday], 1)
+	assertEqual(t, p.share(p.outside), 75.0)
+}
+
+func TestUnitWorkHoursView(t *testing.T) {
+	// Arrange
+	night := time.Date(2025, 3, 10, 23, 0, 0, 0, time.Local)
+	events := []ghEvent{{CreatedAt: night}, {CreatedAt: night.Add(time.Hour)}}
+	testCases := []struct {
+		name        string
+		threshold   float64
+		wantWarning bool
+	}{
+		{name: "warning disabled", threshold: 0},
+		{name: "above threshold", threshold: 50, wantWarning: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			// Act
+			err := workHoursView(&buf, events, statsOptions{workHours: "9-18", burnout: tc.threshold})
+			// Assert
+			assertNoError(t, err)
+			assertEqual(t, strings.Contains(buf.String(), "Outside 9–18h: 100%"), true)
+			assertEqual(t, strings.Contains(buf.String(), "Burnout warning"), tc.wantWarning)
+		})
+	}
+}
+
+// FuzzParseWorkHours checks that no working hours setting makes the parser
+// panic.
+func FuzzParseWorkHours(f *testing.F) {
+	for _, seed := range []string{"9-18", "22-6", "9-9", "24-1", "-", ""} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		parseWorkHours(s)
+	})
+}